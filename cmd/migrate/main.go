@@ -0,0 +1,130 @@
+// Command migrate applies the embedded database migrations.
+//
+//	migrate up              apply every pending migration
+//	migrate down [N|all]    revert the last N migrations (default 1)
+//	migrate version         print the applied version
+//	migrate force VERSION   mark VERSION as applied after fixing a dirty database
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/joho/godotenv"
+	"github.com/yourusername/notinoteapp/internal/adapters/secondary/database/postgres"
+	"github.com/yourusername/notinoteapp/internal/adapters/secondary/database/postgres/migrations"
+	"github.com/yourusername/notinoteapp/pkg/config"
+)
+
+const usage = `usage: migrate <command>
+
+  up              apply every pending migration
+  down [N|all]    revert the last N migrations (default 1)
+  version         print the applied version
+  force VERSION   mark VERSION as applied after fixing a dirty database`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	// Load .env file if it exists
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	db, err := postgres.NewConnection(postgres.Config{
+		Host:            cfg.Database.Host,
+		Port:            cfg.Database.Port,
+		User:            cfg.Database.User,
+		Password:        cfg.Database.Password,
+		DBName:          cfg.Database.Name,
+		SSLMode:         cfg.Database.SSLMode,
+		MaxOpenConns:    1,
+		MaxIdleConns:    1,
+		ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
+		LogLevel:        "warn",
+	})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer postgres.Close(db)
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatalf("Failed to get database instance: %v", err)
+	}
+	migrator, err := postgres.NewMigrator(sqlDB, migrations.FS)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+
+	if err := run(context.Background(), migrator, os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, migrator *postgres.Migrator, args []string) error {
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		log.Printf("Applied %d migration(s)", applied)
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if args[1] == "all" {
+				steps = 0
+			} else if n, err := strconv.Atoi(args[1]); err == nil && n > 0 {
+				steps = n
+			} else {
+				return fmt.Errorf("down takes a positive number of steps or \"all\", got %q", args[1])
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		log.Printf("Reverted %d migration(s)", reverted)
+
+	case "version":
+		version, dirty, err := migrator.Version(ctx)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			fmt.Printf("%d (dirty)\n", version)
+		} else {
+			fmt.Println(version)
+		}
+
+	case "force":
+		if len(args) < 2 {
+			return fmt.Errorf("force needs a version")
+		}
+		version, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		if err := migrator.Force(ctx, uint(version)); err != nil {
+			return err
+		}
+		log.Printf("Forced version %d", version)
+
+	default:
+		return fmt.Errorf("unknown command %q\n\n%s", args[0], usage)
+	}
+	return nil
+}
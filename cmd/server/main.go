@@ -16,10 +16,14 @@ import (
 	"github.com/yourusername/notinoteapp/internal/adapters/primary/http/handlers"
 	redisCache "github.com/yourusername/notinoteapp/internal/adapters/secondary/cache/redis"
 	"github.com/yourusername/notinoteapp/internal/adapters/secondary/database/postgres"
+	"github.com/yourusername/notinoteapp/internal/adapters/secondary/database/postgres/migrations"
 	"github.com/yourusername/notinoteapp/internal/adapters/secondary/database/postgres/repositories"
+	"github.com/yourusername/notinoteapp/internal/adapters/secondary/messaging/email"
 	"github.com/yourusername/notinoteapp/internal/adapters/secondary/messaging/fcm"
+	"github.com/yourusername/notinoteapp/internal/adapters/secondary/messaging/webhook"
 	"github.com/yourusername/notinoteapp/internal/adapters/secondary/oauth"
 	"github.com/yourusername/notinoteapp/internal/application/services"
+	"github.com/yourusername/notinoteapp/internal/core/domain"
 	"github.com/yourusername/notinoteapp/internal/core/ports"
 	coreServices "github.com/yourusername/notinoteapp/internal/core/services"
 	"github.com/yourusername/notinoteapp/pkg/config"
@@ -40,7 +44,22 @@ func main() {
 	}
 
 	// Initialize logger
-	logger.Init(cfg.Log.Level, cfg.Log.Format)
+	if _, err := logger.InitWithOptions(logger.Options{
+		Level:       cfg.Log.Level,
+		Format:      cfg.Log.Format,
+		Output:      cfg.Log.Output,
+		FilePath:    cfg.Log.FilePath,
+		ForceColors: cfg.Log.ForceColors,
+
+		RedactFields: cfg.Log.RedactFields,
+
+		MaxSizeMB:  cfg.Log.MaxSizeMB,
+		MaxBackups: cfg.Log.MaxBackups,
+		MaxAgeDays: cfg.Log.MaxAgeDays,
+	}); err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer logger.Close()
 	logger.Info("Starting NotiNoteApp server...")
 
 	// Connect to database
@@ -54,7 +73,11 @@ func main() {
 		MaxOpenConns:    cfg.Database.MaxOpenConns,
 		MaxIdleConns:    cfg.Database.MaxIdleConns,
 		ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
-		LogLevel:        cfg.Log.Level,
+		LogLevel:        cfg.Database.LogLevel,
+
+		SlowQueryThreshold: cfg.Database.SlowQueryThreshold,
+		Logger:             logger.Get(),
+		ReplicaDSN:         cfg.Database.ReplicaDSN,
 	}
 
 	db, err := postgres.NewConnection(dbConfig)
@@ -67,16 +90,81 @@ func main() {
 		}
 	}()
 
+	sqlDB, err := db.DB()
+	if err != nil {
+		logger.Fatalf("Failed to get database instance: %v", err)
+	}
+
+	if cfg.Database.AutoMigrate {
+		migrator, err := postgres.NewMigrator(sqlDB, migrations.FS)
+		if err != nil {
+			logger.Fatalf("Failed to load migrations: %v", err)
+		}
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			logger.Fatalf("Failed to run migrations: %v", err)
+		}
+		logger.Infof("Applied %d database migration(s)", applied)
+	}
+
 	// Initialize repositories
 	userRepo := repositories.NewUserRepository(db)
 	noteRepo := repositories.NewNoteRepository(db)
+	noteRepo.SetTitleCollation(cfg.Database.TitleCollation)
+
+	// Send note reads to the replica if there is one
+	replica, err := postgres.NewReplicaConnection(dbConfig)
+	if err != nil {
+		logger.Fatalf("Failed to connect to read replica: %v", err)
+	}
+	if replica != nil {
+		defer func() {
+			if err := postgres.Close(replica); err != nil {
+				logger.Errorf("Error closing read replica: %v", err)
+			}
+		}()
+		noteRepo.SetReplica(replica, cfg.Database.ReplicaMaxLag)
+	}
 	deviceRepo := repositories.NewDeviceRepository(db)
 	reminderRepo := repositories.NewReminderRepository(db)
 	notificationLogRepo := repositories.NewNotificationLogRepository(db)
+	deadLetterRepo := repositories.NewDeadLetterRepository(db)
+	notificationPrefsRepo := repositories.NewNotificationPreferencesRepository(db)
+	webhookRepo := repositories.NewWebhookRepository(db)
+	sharedLinkRepo := repositories.NewSharedLinkRepository(db)
+	collaboratorRepo := repositories.NewNoteCollaboratorRepository(db)
 
 	// Initialize utilities
-	passwordHasher := utils.NewBcryptPasswordHasher()
+	var passwordHasher ports.PasswordHasher = utils.NewBcryptPasswordHasherWithCost(cfg.Password.BcryptCost)
+	if cfg.Password.Algorithm == "argon2id" {
+		passwordHasher = utils.NewArgon2idPasswordHasher(utils.Argon2idParams{
+			Memory:      uint32(cfg.Password.Argon2Memory),
+			Iterations:  uint32(cfg.Password.Argon2Iterations),
+			Parallelism: uint8(cfg.Password.Argon2Parallelism),
+		})
+	}
 	tokenService := utils.NewJWTService(cfg.JWT.Secret, "notinoteapp", cfg.JWT.Expiration, cfg.JWT.RefreshExpiration)
+	if cfg.JWT.Algorithm == "RS256" {
+		privateKey, publicKey, err := utils.LoadRSAKeys(cfg.JWT.PrivateKeyPath, cfg.JWT.PublicKeyPath)
+		if err != nil {
+			logger.Fatalf("Failed to load JWT RSA keys: %v", err)
+		}
+		tokenService, err = utils.NewRS256JWTService(privateKey, publicKey, cfg.JWT.CurrentKeyID, "notinoteapp", cfg.JWT.Expiration, cfg.JWT.RefreshExpiration)
+		if err != nil {
+			logger.Fatalf("Failed to configure RS256 JWT signing: %v", err)
+		}
+		logger.Info("JWT signing with RS256")
+	} else if len(cfg.JWT.Keys) > 0 {
+		signingKeys := make([]utils.SigningKey, 0, len(cfg.JWT.Keys))
+		for id, secret := range cfg.JWT.Keys {
+			signingKeys = append(signingKeys, utils.SigningKey{ID: id, Secret: secret})
+		}
+		tokenService, err = utils.NewJWTServiceWithKeys(signingKeys, cfg.JWT.CurrentKeyID, "notinoteapp", cfg.JWT.Expiration, cfg.JWT.RefreshExpiration)
+		if err != nil {
+			logger.Fatalf("Failed to configure JWT keys: %v", err)
+		}
+		logger.Infof("JWT keyring loaded with %d keys, signing with %q", len(signingKeys), cfg.JWT.CurrentKeyID)
+	}
 
 	// Connect to Redis for OAuth state management
 	redisClient, err := redisCache.NewClient(redisCache.Config{
@@ -88,7 +176,7 @@ func main() {
 	})
 	if err != nil {
 		logger.Warnf("Failed to connect to Redis: %v. OAuth may not work properly.", err)
-		// Continue without Redis for now, OAuth will fail if used
+		// Continue without Redis; the OAuth redirect flow answers 503 until it's back
 	}
 	defer func() {
 		if redisClient != nil {
@@ -98,7 +186,11 @@ func main() {
 		}
 	}()
 
-	stateGenerator := utils.NewRedisStateGenerator(redisClient)
+	// Without Redis the redirect OAuth flow reports itself unavailable
+	var stateGenerator ports.StateGenerator
+	if redisClient != nil {
+		stateGenerator = utils.NewRedisStateGenerator(redisClient)
+	}
 
 	// Initialize services
 	authService := services.NewAuthService(
@@ -107,9 +199,24 @@ func main() {
 		tokenService,
 		stateGenerator,
 	)
+	authService.SetOAuthStateTTL(cfg.OAuth.State.TTL)
 
 	// Import core services package for note service
-	noteService := coreServices.NewNoteService(noteRepo)
+	noteService := coreServices.NewNoteService(noteRepo, sharedLinkRepo, collaboratorRepo, userRepo)
+	noteService.SetHub(coreServices.NewNoteHub())
+	noteService.SetLimits(domain.NewNoteLimits(cfg.Note.MaxTitleLength, cfg.Note.MaxBlocksPerNote))
+	noteService.SetQuota(domain.StorageQuota{
+		MaxNotes:      int64(cfg.Note.QuotaMaxNotes),
+		MaxBlockBytes: int64(cfg.Note.QuotaMaxBlockBytes),
+	})
+	if cfg.Redis.NoteCacheEnabled {
+		if redisClient != nil {
+			noteService.SetCache(redisCache.NewNoteCache(redisClient, cfg.Redis.NoteCacheTTL, logger.Get()))
+			logger.Info("Note cache enabled")
+		} else {
+			logger.Warn("Note cache requested but Redis is unavailable; serving notes uncached")
+		}
+	}
 
 	// Register OAuth providers
 	if cfg.OAuth.Google.ClientID != "" && cfg.OAuth.Google.ClientSecret != "" {
@@ -137,19 +244,32 @@ func main() {
 		logger.Info("Facebook OAuth provider registered")
 	}
 
-	// Initialize FCM sender (optional - only if credentials file exists)
+	// Initialize FCM sender (optional - only if credentials JSON or a credentials file is configured)
 	var fcmSender ports.NotificationSender
 	var notificationScheduler *services.NotificationScheduler
+	var deviceReaper *services.DeviceReaper
 
-	if cfg.FCM.CredentialsFile != "" {
+	if cfg.FCM.CredentialsJSON != "" {
+		logrusLogger := logrus.New()
+		logrusLogger.SetLevel(logrus.InfoLevel)
+
+		sender, err := fcm.NewFCMSenderFromJSON([]byte(cfg.FCM.CredentialsJSON), logrusLogger)
+		if err != nil {
+			logger.Warnf("Failed to initialize FCM sender from FCM_CREDENTIALS_JSON: %v. Push notifications will not work.", err)
+		} else {
+			fcmSender = sender
+			logger.Info("FCM sender initialized successfully")
+		}
+	} else if cfg.FCM.CredentialsFile != "" {
 		if _, err := os.Stat(cfg.FCM.CredentialsFile); err == nil {
 			logrusLogger := logrus.New()
 			logrusLogger.SetLevel(logrus.InfoLevel)
 
-			fcmSender, err = fcm.NewFCMSender(cfg.FCM.CredentialsFile, logrusLogger)
+			sender, err := fcm.NewFCMSender(cfg.FCM.CredentialsFile, logrusLogger)
 			if err != nil {
 				logger.Warnf("Failed to initialize FCM sender: %v. Push notifications will not work.", err)
 			} else {
+				fcmSender = sender
 				logger.Info("FCM sender initialized successfully")
 			}
 		} else {
@@ -160,55 +280,128 @@ func main() {
 	// Initialize notification services
 	logrusLogger := logrus.New()
 	logrusLogger.SetLevel(logrus.InfoLevel)
+	authService.SetLogger(logrusLogger)
 
 	deviceService := services.NewDeviceService(deviceRepo, logrusLogger)
-	reminderService := services.NewReminderService(reminderRepo, noteRepo, logrusLogger)
-
-	// Initialize notification service and scheduler (only if FCM is available)
-	var notificationService *services.NotificationService
-	if fcmSender != nil {
-		notificationService = services.NewNotificationService(
-			deviceRepo,
-			notificationLogRepo,
-			fcmSender,
-			logrusLogger,
-		)
+	reminderService := services.NewReminderService(reminderRepo, noteRepo, cfg.Notification.MaxSnoozes, logrusLogger)
+	reminderService.SetScheduleTolerance(cfg.Notification.ScheduleTolerance)
+	reminderService.SetCalendarFeedRepository(repositories.NewCalendarFeedRepository(db))
+	noteService.SetReminderRescheduler(reminderService)
+
+	// Notification history is always available; sending requires FCM
+	notificationService := services.NewNotificationService(
+		deviceRepo,
+		notificationLogRepo,
+		notificationPrefsRepo,
+		fcmSender,
+		logrusLogger,
+	)
+	notificationService.SetUserRepository(userRepo)
+
+	// Failed deliveries are kept for admins to inspect and replay
+	notificationService.SetDeadLetterRepository(deadLetterRepo)
+
+	// Email channel (optional - only if SMTP is configured or in dry-run mode)
+	emailEnabled := false
+	if cfg.SMTP.Host != "" || cfg.SMTP.DryRun {
+		emailSender, err := email.NewEmailSender(email.Config{
+			Host:        cfg.SMTP.Host,
+			Port:        cfg.SMTP.Port,
+			Username:    cfg.SMTP.Username,
+			Password:    cfg.SMTP.Password,
+			From:        cfg.SMTP.From,
+			TLSMode:     cfg.SMTP.TLSMode,
+			DryRun:      cfg.SMTP.DryRun,
+			LinkBaseURL: cfg.SMTP.LinkBaseURL,
+		}, logrusLogger)
+		if err != nil {
+			logger.Warnf("Failed to initialize email sender: %v. Email notifications will not work.", err)
+		} else {
+			notificationService.SetEmailChannel(emailSender, userRepo)
+			emailEnabled = true
+			logger.Info("Email notification channel enabled")
+		}
+	}
 
-		// Initialize and start notification scheduler
-		notificationScheduler = services.NewNotificationScheduler(
-			reminderRepo,
-			notificationService,
-			&cfg.Notification,
-			logrusLogger,
-		)
-		notificationScheduler.Start()
+	// Webhook channel is always available; users opt in by registering a URL
+	webhookSender := webhook.NewWebhookSender(webhookRepo, webhook.Config{
+		MaxRetries:   cfg.Notification.MaxRetries,
+		RetryBackoff: cfg.Notification.RetryBackoff,
+	}, logrusLogger)
+	notificationService.SetWebhookChannel(webhookSender, webhookRepo)
+
+	// Real-time stream for connected web clients
+	notificationService.SetBroker(services.NewNotificationBroker())
+
+	// Initialize and start notification scheduler
+	notificationScheduler = services.NewNotificationScheduler(
+		reminderRepo,
+		notificationService,
+		&cfg.Notification,
+		logrusLogger,
+	)
+	notificationScheduler.Start()
+	if fcmSender != nil || emailEnabled {
 		logger.Info("Notification scheduler started")
 	} else {
-		logger.Warn("Notification service not initialized - FCM sender unavailable")
+		logger.Warn("Notification scheduler started without push or email - only webhooks will be delivered")
+	}
+
+	// Deactivate and eventually delete devices that stop being used
+	deviceReaper = services.NewDeviceReaper(deviceService, &cfg.Device, logrusLogger)
+	deviceReaper.Start()
+
+	// Warn when the connection pool is close to exhausted
+	var poolMonitor *postgres.PoolMonitor
+	if cfg.Database.PoolMonitorInterval > 0 {
+		poolMonitor = postgres.NewPoolMonitor(sqlDB, cfg.Database.PoolMonitorInterval, logrusLogger)
+		poolMonitor.Start()
 	}
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService)
+	authHandler.SetOAuthRedirectURL(cfg.OAuth.FrontendURL)
+	if cookie := cfg.JWT.RefreshCookie; cookie.Enabled {
+		authHandler.SetRefreshCookie(&handlers.RefreshCookie{
+			Name:     cookie.Name,
+			Path:     cookie.Path,
+			Domain:   cookie.Domain,
+			Secure:   cookie.Secure,
+			SameSite: cookie.SameSiteMode(),
+			MaxAge:   cfg.JWT.RefreshExpiration,
+		})
+	}
+	adminHandler := handlers.NewAdminHandler(authService, noteService, notificationService, logrusLogger)
+	adminHandler.SetScheduler(notificationScheduler)
+	adminHandler.SetDatabase(sqlDB)
 	noteHandler := handlers.NewNoteHandler(noteService)
 	deviceHandler := handlers.NewDeviceHandler(deviceService, logrusLogger)
 	reminderHandler := handlers.NewReminderHandler(reminderService, logrusLogger)
+	notificationHandler := handlers.NewNotificationHandler(notificationService, logrusLogger)
 
 	// Setup router
 	router := httpAdapter.SetupRouter(httpAdapter.RouterConfig{
-		AuthHandler:     authHandler,
-		NoteHandler:     noteHandler,
-		DeviceHandler:   deviceHandler,
-		ReminderHandler: reminderHandler,
-		Config:          cfg,
+		AuthHandler:         authHandler,
+		AdminHandler:        adminHandler,
+		NoteHandler:         noteHandler,
+		DeviceHandler:       deviceHandler,
+		ReminderHandler:     reminderHandler,
+		NotificationHandler: notificationHandler,
+		TokenValidator:      tokenService,
+		UserStatus:          authService,
+		CalendarFeeds:       reminderService,
+		Config:              cfg,
 	})
 
 	// Create HTTP server
 	addr := fmt.Sprintf(":%s", cfg.Server.Port)
 	server := &http.Server{
-		Addr:         addr,
-		Handler:      router,
-		ReadTimeout:  cfg.Server.ReadTimeout,
-		WriteTimeout: cfg.Server.WriteTimeout,
+		Addr:              addr,
+		Handler:           router,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
 	}
 
 	// Start server in a goroutine
@@ -233,6 +426,17 @@ func main() {
 		logger.Info("Notification scheduler stopped")
 	}
 
+	// Cancel webhook retries still in flight so they are dead-lettered
+	notificationService.Close()
+
+	if deviceReaper != nil {
+		deviceReaper.Stop()
+	}
+
+	if poolMonitor != nil {
+		poolMonitor.Stop()
+	}
+
 	// Graceful shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
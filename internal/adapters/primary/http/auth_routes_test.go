@@ -0,0 +1,351 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/notinoteapp/internal/adapters/primary/http/handlers"
+	"github.com/yourusername/notinoteapp/internal/application/services"
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+	"github.com/yourusername/notinoteapp/internal/core/ports"
+	"github.com/yourusername/notinoteapp/pkg/config"
+)
+
+// stubUserRepository serves users from memory; unimplemented methods panic
+type stubUserRepository struct {
+	ports.UserRepository
+	users map[int64]*domain.User
+}
+
+func (r *stubUserRepository) FindByID(ctx context.Context, id int64) (*domain.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (r *stubUserRepository) FindByProvider(ctx context.Context, provider domain.AuthProvider, providerID string) (*domain.User, error) {
+	for _, user := range r.users {
+		if user.Provider == provider && user.ProviderID == providerID {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+func (r *stubUserRepository) List(ctx context.Context, limit, offset int) ([]*domain.User, int64, error) {
+	users := make([]*domain.User, 0, len(r.users))
+	for _, user := range r.users {
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	total := int64(len(users))
+
+	if offset >= len(users) {
+		return []*domain.User{}, total, nil
+	}
+	users = users[offset:]
+	if len(users) > limit {
+		users = users[:limit]
+	}
+	return users, total, nil
+}
+
+// newAuthTestRouter serves user 7, signed up with Google, and admin 1 through
+// the real service and handlers
+func newAuthTestRouter() http.Handler {
+	userRepo := &stubUserRepository{users: map[int64]*domain.User{
+		1: {ID: 1, Email: "admin@example.com", Name: "Admin", Provider: domain.AuthProviderEmail, Role: domain.RoleAdmin, IsActive: true},
+		7: {ID: 7, Email: "user@example.com", Name: "Test User", Provider: domain.AuthProviderGoogle, Role: domain.RoleUser, IsActive: true},
+	}}
+	authService := services.NewAuthService(userRepo, nil, testTokens, nil)
+
+	return SetupRouter(RouterConfig{
+		AuthHandler:    handlers.NewAuthHandler(authService),
+		AdminHandler:   handlers.NewAdminHandler(authService, nil, nil, logrus.New()),
+		TokenValidator: testTokens,
+		Config: &config.Config{
+			Server: config.ServerConfig{Mode: "test"},
+			CORS:   config.CORSConfig{AllowedOrigins: []string{"http://localhost:3000"}},
+		},
+	})
+}
+
+func TestAuthRoutes_Me(t *testing.T) {
+	router := newAuthTestRouter()
+
+	w := doNoteRequest(t, router, http.MethodGet, "/api/v1/auth/me", "", 7)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Data struct {
+			ID            int64  `json:"id"`
+			Email         string `json:"email"`
+			EmailVerified bool   `json:"email_verified"`
+			Provider      string `json:"provider"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(7), resp.Data.ID)
+	assert.Equal(t, "user@example.com", resp.Data.Email)
+	assert.True(t, resp.Data.EmailVerified)
+	assert.Equal(t, "google", resp.Data.Provider)
+
+	w = doNoteRequest(t, router, http.MethodGet, "/api/v1/auth/me", "", 99)
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+}
+
+func TestAuthRoutes_MeRequiresToken(t *testing.T) {
+	router := newAuthTestRouter()
+
+	req, err := http.NewRequest(http.MethodGet, "/api/v1/auth/me", nil)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAuthRoutes_AdminListUsers(t *testing.T) {
+	router := newAuthTestRouter()
+
+	w := doNoteRequest(t, router, http.MethodGet, "/api/v1/admin/users?limit=1", "", 7)
+	assert.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+
+	adminToken, err := testTokens.GenerateTokenWithRole(1, "admin@example.com", string(domain.RoleAdmin))
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/users?limit=1", nil)
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Data struct {
+			Users []struct {
+				ID int64 `json:"id"`
+			} `json:"users"`
+			Total      int64 `json:"total"`
+			TotalPages int   `json:"total_pages"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.Data.Users, 1)
+	assert.Equal(t, int64(2), resp.Data.Total)
+	assert.Equal(t, 2, resp.Data.TotalPages)
+}
+
+// stubOAuthProvider signs everyone in as userInfo
+type stubOAuthProvider struct {
+	ports.OAuthProvider
+	name     domain.AuthProvider
+	userInfo *domain.OAuthUserInfo
+}
+
+func (p *stubOAuthProvider) GetProviderName() domain.AuthProvider {
+	return p.name
+}
+
+func (p *stubOAuthProvider) ExchangeCode(ctx context.Context, code, codeVerifier string) (*domain.OAuthUserInfo, error) {
+	return p.userInfo, nil
+}
+
+// stubStateGenerator accepts the states it was created with
+type stubStateGenerator struct {
+	ports.StateGenerator
+	verifiers map[string]string
+}
+
+func (g *stubStateGenerator) GetState(ctx context.Context, state string) (string, bool, error) {
+	verifier, ok := g.verifiers[state]
+	delete(g.verifiers, state)
+	return verifier, ok, nil
+}
+
+func TestAuthRoutes_OAuthUnavailableWithoutStateStore(t *testing.T) {
+	authService := services.NewAuthService(&stubUserRepository{}, nil, testTokens, nil)
+	authService.RegisterOAuthProvider(&stubOAuthProvider{name: domain.AuthProviderGoogle})
+	router := SetupRouter(RouterConfig{
+		AuthHandler:    handlers.NewAuthHandler(authService),
+		TokenValidator: testTokens,
+		Config:         &config.Config{Server: config.ServerConfig{Mode: "test"}},
+	})
+
+	for _, path := range []string{
+		"/api/v1/auth/google",
+		"/api/v1/auth/google/callback?code=auth-code&state=some-state",
+	} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), "temporarily unavailable")
+	}
+}
+
+func TestAuthRoutes_OAuthCallbackRedirectsToFrontend(t *testing.T) {
+	newRouter := func() http.Handler {
+		userRepo := &stubUserRepository{users: map[int64]*domain.User{
+			7: {ID: 7, Email: "user@example.com", Provider: domain.AuthProviderGoogle, ProviderID: "google-7", Role: domain.RoleUser, IsActive: true},
+		}}
+		authService := services.NewAuthService(userRepo, nil, testTokens, &stubStateGenerator{
+			verifiers: map[string]string{"good-state": "verifier"},
+		})
+		authService.RegisterOAuthProvider(&stubOAuthProvider{
+			name:     domain.AuthProviderGoogle,
+			userInfo: &domain.OAuthUserInfo{Provider: domain.AuthProviderGoogle, ProviderID: "google-7", Email: "user@example.com"},
+		})
+		authHandler := handlers.NewAuthHandler(authService)
+		authHandler.SetOAuthRedirectURL("https://app.example.com/auth/done")
+
+		return SetupRouter(RouterConfig{
+			AuthHandler:    authHandler,
+			TokenValidator: testTokens,
+			Config:         &config.Config{Server: config.ServerConfig{Mode: "test"}},
+		})
+	}
+	callback := func(router http.Handler, query, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/google/callback?"+query, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("success", func(t *testing.T) {
+		w := callback(newRouter(), "code=auth-code&state=good-state", "text/html")
+		require.Equal(t, http.StatusFound, w.Code, w.Body.String())
+
+		location, err := url.Parse(w.Header().Get("Location"))
+		require.NoError(t, err)
+		assert.Equal(t, "app.example.com", location.Host)
+		assert.Equal(t, "/auth/done", location.Path)
+		assert.Empty(t, location.RawQuery)
+
+		fragment, err := url.ParseQuery(location.Fragment)
+		require.NoError(t, err)
+		assert.NotEmpty(t, fragment.Get("access_token"))
+		assert.NotEmpty(t, fragment.Get("refresh_token"))
+		assert.Equal(t, "Bearer", fragment.Get("token_type"))
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		w := callback(newRouter(), "code=auth-code&state=forged-state", "")
+		require.Equal(t, http.StatusFound, w.Code, w.Body.String())
+
+		location, err := url.Parse(w.Header().Get("Location"))
+		require.NoError(t, err)
+		fragment, err := url.ParseQuery(location.Fragment)
+		require.NoError(t, err)
+		assert.Equal(t, "invalid_state", fragment.Get("error"))
+		assert.Empty(t, fragment.Get("access_token"))
+	})
+
+	t.Run("consent denied", func(t *testing.T) {
+		w := callback(newRouter(), "error=access_denied&state=good-state", "")
+		require.Equal(t, http.StatusFound, w.Code, w.Body.String())
+		assert.Contains(t, w.Header().Get("Location"), "#error=access_denied")
+	})
+
+	t.Run("JSON on request", func(t *testing.T) {
+		w := callback(newRouter(), "code=auth-code&state=good-state", "application/json")
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), "access_token")
+
+		w = callback(newRouter(), "code=auth-code&state=good-state&response=json", "")
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		w = callback(newRouter(), "code=auth-code&state=forged-state&response=json", "")
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	})
+}
+
+func TestAuthRoutes_RefreshTokenCookie(t *testing.T) {
+	userRepo := &stubUserRepository{users: map[int64]*domain.User{
+		7: {ID: 7, Email: "user@example.com", Provider: domain.AuthProviderGoogle, Role: domain.RoleUser, IsActive: true},
+	}}
+	authHandler := handlers.NewAuthHandler(services.NewAuthService(userRepo, nil, testTokens, nil))
+	authHandler.SetRefreshCookie(&handlers.RefreshCookie{
+		Name:     "refresh_token",
+		Path:     "/api/v1/auth",
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+		MaxAge:   time.Hour,
+	})
+	router := SetupRouter(RouterConfig{
+		AuthHandler:    authHandler,
+		TokenValidator: testTokens,
+		Config:         &config.Config{Server: config.ServerConfig{Mode: "test"}},
+	})
+
+	refreshToken, err := testTokens.GenerateRefreshToken(7, "user@example.com")
+	require.NoError(t, err)
+
+	refresh := func(cookie *http.Cookie, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/refresh", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	issuedCookie := func(w *httptest.ResponseRecorder) *http.Cookie {
+		for _, cookie := range w.Result().Cookies() {
+			if cookie.Name == "refresh_token" {
+				return cookie
+			}
+		}
+		return nil
+	}
+
+	t.Run("from cookie", func(t *testing.T) {
+		w := refresh(&http.Cookie{Name: "refresh_token", Value: refreshToken}, "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		cookie := issuedCookie(w)
+		require.NotNil(t, cookie)
+		assert.NotEmpty(t, cookie.Value)
+		assert.True(t, cookie.HttpOnly)
+		assert.True(t, cookie.Secure)
+		assert.Equal(t, http.SameSiteStrictMode, cookie.SameSite)
+		assert.Equal(t, "/api/v1/auth", cookie.Path)
+
+		assert.Contains(t, w.Body.String(), "access_token")
+		assert.NotContains(t, w.Body.String(), "refresh_token")
+	})
+
+	t.Run("falls back to body", func(t *testing.T) {
+		w := refresh(nil, `{"refresh_token":"`+refreshToken+`"}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.NotNil(t, issuedCookie(w))
+	})
+
+	t.Run("logout clears it", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/logout", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		cookie := issuedCookie(w)
+		require.NotNil(t, cookie)
+		assert.Empty(t, cookie.Value)
+		assert.Negative(t, cookie.MaxAge)
+	})
+}
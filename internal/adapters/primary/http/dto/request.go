@@ -27,3 +27,22 @@ type GoogleTokenRequest struct {
 type FacebookTokenRequest struct {
 	AccessToken string `json:"access_token" binding:"required"`
 }
+
+// UpdateProfileRequest represents the profile update request body.
+// Omitted fields are left unchanged.
+type UpdateProfileRequest struct {
+	Name      *string `json:"name" binding:"omitempty,min=1,max=255"`
+	AvatarURL *string `json:"avatar_url"`
+	PinName   *bool   `json:"pin_name"` // Keep this name on future OAuth sign-ins
+	Language  *string `json:"language"` // Language of notifications: "en" or "th"
+}
+
+// ReactivateRequest represents the account reactivation request body. Email
+// accounts send their credentials; OAuth accounts send the provider and a
+// fresh token from its SDK (Google ID token or Facebook access token).
+type ReactivateRequest struct {
+	Email    string `json:"email" binding:"required_without=Provider,omitempty,email"`
+	Password string `json:"password" binding:"required_without=Provider"`
+	Provider string `json:"provider" binding:"omitempty,oneof=google facebook"`
+	Token    string `json:"token" binding:"required_with=Provider"`
+}
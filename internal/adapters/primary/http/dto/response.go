@@ -1,10 +1,14 @@
 package dto
 
 import (
+	"database/sql"
 	"time"
 
 	appdto "github.com/yourusername/notinoteapp/internal/application/dto"
+	"github.com/yourusername/notinoteapp/internal/application/services"
 	"github.com/yourusername/notinoteapp/internal/core/domain"
+	"github.com/yourusername/notinoteapp/internal/core/ports"
+	coreServices "github.com/yourusername/notinoteapp/internal/core/services"
 )
 
 // AuthResponse represents the authentication response sent to clients
@@ -21,7 +25,7 @@ type AuthResponse struct {
 			CreatedAt time.Time           `json:"created_at"`
 		} `json:"user"`
 		AccessToken  string `json:"access_token"`
-		RefreshToken string `json:"refresh_token"`
+		RefreshToken string `json:"refresh_token,omitempty"` // omitted when sent as a cookie
 		TokenType    string `json:"token_type"`
 		ExpiresIn    int    `json:"expires_in"` // seconds
 	} `json:"data,omitempty"`
@@ -42,14 +46,18 @@ type SuccessResponse struct {
 
 // UserResponse represents a user profile response
 type UserResponse struct {
-	ID        int64               `json:"id"`
-	Email     string              `json:"email"`
-	Name      string              `json:"name"`
-	Provider  domain.AuthProvider `json:"provider"`
-	AvatarURL string              `json:"avatar_url,omitempty"`
-	IsActive  bool                `json:"is_active"`
-	CreatedAt time.Time           `json:"created_at"`
-	UpdatedAt time.Time           `json:"updated_at"`
+	ID            int64               `json:"id"`
+	Email         string              `json:"email"`
+	EmailVerified bool                `json:"email_verified"`
+	Name          string              `json:"name"`
+	Provider      domain.AuthProvider `json:"provider"`
+	AvatarURL     string              `json:"avatar_url,omitempty"`
+	NamePinned    bool                `json:"name_pinned"`
+	Language      string              `json:"language"`
+	Role          domain.Role         `json:"role"`
+	IsActive      bool                `json:"is_active"`
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
 }
 
 // NewAuthResponse creates an HTTP AuthResponse from application layer AuthResponse
@@ -66,7 +74,7 @@ func NewAuthResponse(appResp *appdto.AuthResponse, expiresIn int) AuthResponse {
 				CreatedAt time.Time           `json:"created_at"`
 			} `json:"user"`
 			AccessToken  string `json:"access_token"`
-			RefreshToken string `json:"refresh_token"`
+			RefreshToken string `json:"refresh_token,omitempty"`
 			TokenType    string `json:"token_type"`
 			ExpiresIn    int    `json:"expires_in"`
 		}{},
@@ -90,13 +98,180 @@ func NewAuthResponse(appResp *appdto.AuthResponse, expiresIn int) AuthResponse {
 // NewUserResponse creates a UserResponse from domain User
 func NewUserResponse(user *domain.User) UserResponse {
 	return UserResponse{
-		ID:        user.ID,
-		Email:     user.Email,
-		Name:      user.Name,
-		Provider:  user.Provider,
-		AvatarURL: user.AvatarURL,
-		IsActive:  user.IsActive,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		ID:            user.ID,
+		Email:         user.Email,
+		EmailVerified: user.IsEmailVerified(),
+		Name:          user.Name,
+		Provider:      user.Provider,
+		AvatarURL:     user.AvatarURL,
+		NamePinned:    user.NamePinned,
+		Language:      user.Language,
+		Role:          user.Role,
+		IsActive:      user.IsActive,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+	}
+}
+
+// UserListResponse represents a page of users
+type UserListResponse struct {
+	Users      []UserResponse `json:"users"`
+	Page       int            `json:"page"`
+	Limit      int            `json:"limit"`
+	Total      int64          `json:"total"`
+	TotalPages int            `json:"total_pages"`
+}
+
+// NewUserListResponse creates a UserListResponse from domain users
+func NewUserListResponse(users []*domain.User, page, limit int, total int64) UserListResponse {
+	userResponses := make([]UserResponse, len(users))
+	for i, user := range users {
+		userResponses[i] = NewUserResponse(user)
+	}
+
+	totalPages := int(total) / limit
+	if int(total)%limit != 0 {
+		totalPages++
+	}
+
+	return UserListResponse{
+		Users:      userResponses,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+	}
+}
+
+// PathRebuildResponse reports the outcome of rebuilding a user's note paths
+type PathRebuildResponse struct {
+	UserID      int64   `json:"user_id"`
+	Checked     int     `json:"checked"`
+	Corrected   []int64 `json:"corrected"`
+	Unreachable []int64 `json:"unreachable"`
+}
+
+// NewPathRebuildResponse creates a PathRebuildResponse from a rebuild result
+func NewPathRebuildResponse(userID int64, result *ports.PathRebuildResult) PathRebuildResponse {
+	resp := PathRebuildResponse{
+		UserID:      userID,
+		Checked:     result.Checked,
+		Corrected:   result.Corrected,
+		Unreachable: result.Unreachable,
+	}
+	if resp.Corrected == nil {
+		resp.Corrected = []int64{}
+	}
+	if resp.Unreachable == nil {
+		resp.Unreachable = []int64{}
+	}
+	return resp
+}
+
+// OrphanRepairResponse reports the notes found by an orphan repair
+type OrphanRepairResponse struct {
+	DryRun bool                `json:"dry_run"`
+	Notes  []OrphanRepairEntry `json:"notes"`
+}
+
+// OrphanRepairEntry is one orphaned note and the action taken on it
+type OrphanRepairEntry struct {
+	NoteID   int64  `json:"note_id"`
+	UserID   int64  `json:"user_id"`
+	ParentID int64  `json:"parent_id"`
+	Action   string `json:"action"`
+}
+
+// NewOrphanRepairResponse creates an OrphanRepairResponse from repair results
+func NewOrphanRepairResponse(repairs []coreServices.OrphanRepair, dryRun bool) OrphanRepairResponse {
+	notes := make([]OrphanRepairEntry, len(repairs))
+	for i, repair := range repairs {
+		notes[i] = OrphanRepairEntry{
+			NoteID:   repair.NoteID,
+			UserID:   repair.UserID,
+			ParentID: repair.ParentID,
+			Action:   string(repair.Action),
+		}
+	}
+	return OrphanRepairResponse{DryRun: dryRun, Notes: notes}
+}
+
+// DeadLetterListResponse represents a page of dead letters
+type DeadLetterListResponse struct {
+	DeadLetters []*domain.DeadLetter `json:"dead_letters"`
+	Page        int                  `json:"page"`
+	Limit       int                  `json:"limit"`
+	Total       int64                `json:"total"`
+	TotalPages  int                  `json:"total_pages"`
+}
+
+// NewDeadLetterListResponse creates a DeadLetterListResponse from domain dead letters
+func NewDeadLetterListResponse(letters []*domain.DeadLetter, page, limit int, total int64) DeadLetterListResponse {
+	totalPages := int(total) / limit
+	if int(total)%limit != 0 {
+		totalPages++
+	}
+
+	return DeadLetterListResponse{
+		DeadLetters: letters,
+		Page:        page,
+		Limit:       limit,
+		Total:       total,
+		TotalPages:  totalPages,
+	}
+}
+
+// SchedulerStatusResponse represents the notification scheduler's state
+type SchedulerStatusResponse struct {
+	Running   bool       `json:"running"`
+	Paused    bool       `json:"paused"`
+	PausedAt  *time.Time `json:"paused_at,omitempty"`
+	Interval  string     `json:"interval"`
+	LookAhead string     `json:"look_ahead"`
+	BatchSize int        `json:"batch_size"`
+}
+
+// NewSchedulerStatusResponse creates a SchedulerStatusResponse from a scheduler status
+func NewSchedulerStatusResponse(status services.SchedulerStatus) SchedulerStatusResponse {
+	return SchedulerStatusResponse{
+		Running:   status.Running,
+		Paused:    status.Paused,
+		PausedAt:  status.PausedAt,
+		Interval:  status.Interval.String(),
+		LookAhead: status.LookAhead.String(),
+		BatchSize: status.BatchSize,
+	}
+}
+
+// MetricsResponse represents runtime metrics
+type MetricsResponse struct {
+	Database *DatabaseStatsResponse `json:"database,omitempty"`
+}
+
+// DatabaseStatsResponse represents database connection pool statistics
+type DatabaseStatsResponse struct {
+	MaxOpenConnections int    `json:"max_open_connections"`
+	OpenConnections    int    `json:"open_connections"`
+	InUse              int    `json:"in_use"`
+	Idle               int    `json:"idle"`
+	WaitCount          int64  `json:"wait_count"`
+	WaitDuration       string `json:"wait_duration"`
+	MaxIdleClosed      int64  `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64  `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64  `json:"max_lifetime_closed"`
+}
+
+// NewDatabaseStatsResponse creates a DatabaseStatsResponse from pool statistics
+func NewDatabaseStatsResponse(stats sql.DBStats) DatabaseStatsResponse {
+	return DatabaseStatsResponse{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDuration:       stats.WaitDuration.String(),
+		MaxIdleClosed:      stats.MaxIdleClosed,
+		MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  stats.MaxLifetimeClosed,
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/yourusername/notinoteapp/internal/core/domain"
+	"github.com/yourusername/notinoteapp/internal/core/ports"
 )
 
 // CreateNoteRequest represents the request to create a new note
@@ -21,6 +22,88 @@ type UpdateNoteRequest struct {
 	CoverImage *string `json:"cover_image,omitempty"`
 }
 
+// PatchNoteRequest represents a partial update to a note. Omitted fields are
+// left unchanged; properties are merged, and a null property value removes it.
+type PatchNoteRequest struct {
+	Title      *string                `json:"title,omitempty" binding:"omitempty,min=1,max=500"`
+	Icon       *string                `json:"icon,omitempty"`
+	CoverImage *string                `json:"cover_image,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+// BatchGetNotesRequest represents the request to fetch several notes by ID
+type BatchGetNotesRequest struct {
+	IDs []int64 `json:"ids" binding:"required,min=1"`
+}
+
+// BulkTagRequest represents the request to tag or untag several notes
+type BulkTagRequest struct {
+	NoteIDs []int64 `json:"note_ids" binding:"required,min=1"`
+}
+
+// TagResponse represents a tag. NoteCount is only set when listing tags.
+type TagResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	NoteCount *int64    `json:"note_count,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ToTagResponse converts a domain tag to a response
+func ToTagResponse(tag *domain.Tag) TagResponse {
+	return TagResponse{
+		ID:        tag.ID,
+		Name:      tag.Name,
+		Color:     tag.Color,
+		CreatedAt: tag.CreatedAt,
+		UpdatedAt: tag.UpdatedAt,
+	}
+}
+
+// ToTagResponses converts tag usage to responses
+func ToTagResponses(tags []ports.TagUsage) []TagResponse {
+	responses := make([]TagResponse, len(tags))
+	for i := range tags {
+		responses[i] = ToTagResponse(&tags[i].Tag)
+		responses[i].NoteCount = &tags[i].NoteCount
+	}
+	return responses
+}
+
+// TagListResponse represents a page of tags
+type TagListResponse struct {
+	Tags       []TagResponse      `json:"tags"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+// ToTagListResponse converts a page of tag usage to a list response
+func ToTagListResponse(tags []ports.TagUsage, page, limit int, total int64) TagListResponse {
+	return TagListResponse{
+		Tags:       ToTagResponses(tags),
+		Pagination: NewPaginationResponse(page, limit, total),
+	}
+}
+
+// UpdateTagRequest represents the request to rename or recolor a tag
+type UpdateTagRequest struct {
+	Name  *string `json:"name,omitempty"`
+	Color *string `json:"color,omitempty"`
+}
+
+// MergeTagRequest represents the request to merge a tag into another
+type MergeTagRequest struct {
+	TargetTagID string `json:"target_tag_id" binding:"required"`
+}
+
+// BulkTagResult reports whether a bulk tag operation applied to one note
+type BulkTagResult struct {
+	NoteID  int64  `json:"note_id"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
 // MoveNoteRequest represents the request to move a note
 type MoveNoteRequest struct {
 	NewParentID *int64 `json:"new_parent_id,omitempty"`
@@ -38,6 +121,18 @@ type UpdateBlockRequest struct {
 	Content *domain.BlockContent `json:"content" binding:"required"`
 }
 
+// UpdateBlockTextRequest represents an auto-save of a block's rich text
+type UpdateBlockTextRequest struct {
+	RichText []domain.RichTextSegment `json:"rich_text" binding:"required"`
+}
+
+// BlockTextResponse is the slim reply to a block text update
+type BlockTextResponse struct {
+	NoteID    int64         `json:"note_id"`
+	Block     *domain.Block `json:"block"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
 // ReplaceBlocksRequest represents the request to replace all blocks
 type ReplaceBlocksRequest struct {
 	Blocks []domain.Block `json:"blocks" binding:"required"`
@@ -54,6 +149,8 @@ type UpdateViewMetadataRequest struct {
 	Properties []domain.ViewProperty        `json:"properties,omitempty"`
 	Filters    []domain.ViewFilter          `json:"filters,omitempty"`
 	Sorts      []domain.ViewSort            `json:"sorts,omitempty"`
+	GroupBy       string                    `json:"group_by,omitempty"`
+	CoverProperty string                    `json:"cover_property,omitempty"`
 }
 
 // UpdatePropertiesRequest represents the request to update custom properties
@@ -61,6 +158,18 @@ type UpdatePropertiesRequest struct {
 	Properties map[string]interface{} `json:"properties" binding:"required"`
 }
 
+// CreateSharedLinkRequest represents the request to share a note via link
+type CreateSharedLinkRequest struct {
+	Permission domain.SharePermission `json:"permission,omitempty"`
+	ExpiresAt  *time.Time             `json:"expires_at,omitempty"`
+}
+
+// AddCollaboratorRequest represents the request to grant a user access to a note
+type AddCollaboratorRequest struct {
+	Email string                  `json:"email" binding:"required,email"`
+	Role  domain.CollaboratorRole `json:"role" binding:"required"`
+}
+
 // NoteResponse represents the response for a single note
 type NoteResponse struct {
 	ID           int64                  `json:"id"`
@@ -87,12 +196,10 @@ type NoteListResponse struct {
 	Pagination PaginationResponse `json:"pagination"`
 }
 
-// PaginationResponse represents pagination metadata
-type PaginationResponse struct {
-	Page       int   `json:"page"`
-	Limit      int   `json:"limit"`
-	Total      int64 `json:"total"`
-	TotalPages int   `json:"total_pages"`
+// NoteSummaryListResponse represents a list of note summaries, for ?summary=true
+type NoteSummaryListResponse struct {
+	Notes      []NoteSummaryResponse `json:"notes"`
+	Pagination PaginationResponse    `json:"pagination"`
 }
 
 // NoteSummaryResponse represents a minimal note summary for lists
@@ -120,6 +227,42 @@ type BreadcrumbResponse struct {
 	Icon  string `json:"icon,omitempty"`
 }
 
+// NoteWithBreadcrumbResponse represents a note with its ancestors embedded,
+// for ?include=breadcrumb
+type NoteWithBreadcrumbResponse struct {
+	NoteResponse
+	Breadcrumb []BreadcrumbResponse `json:"breadcrumb"`
+}
+
+// SharedNoteResponse represents a note viewed through a shared link.
+// It deliberately omits owner details and hierarchy information.
+type SharedNoteResponse struct {
+	Title        string                 `json:"title"`
+	Icon         string                 `json:"icon,omitempty"`
+	CoverImage   string                 `json:"cover_image,omitempty"`
+	Blocks       []domain.Block         `json:"blocks"`
+	ViewMetadata *domain.ViewMetadata   `json:"view_metadata,omitempty"`
+	Properties   map[string]interface{} `json:"properties,omitempty"`
+	Permission   domain.SharePermission `json:"permission"`
+	ExpiresAt    *time.Time             `json:"expires_at,omitempty"`
+	UpdatedAt    time.Time              `json:"updated_at"`
+}
+
+// ToSharedNoteResponse converts a note viewed through a shared link to a response DTO
+func ToSharedNoteResponse(note *domain.Note, link *domain.SharedLink) SharedNoteResponse {
+	return SharedNoteResponse{
+		Title:        note.Title,
+		Icon:         note.Icon,
+		CoverImage:   note.CoverImage,
+		Blocks:       note.Blocks,
+		ViewMetadata: note.ViewMetadata,
+		Properties:   note.Properties,
+		Permission:   link.Permission,
+		ExpiresAt:    link.ExpiresAt,
+		UpdatedAt:    note.UpdatedAt,
+	}
+}
+
 // ToNoteResponse converts a domain note to a response DTO
 func ToNoteResponse(note *domain.Note) NoteResponse {
 	return NoteResponse{
@@ -149,19 +292,22 @@ func ToNoteListResponse(notes []*domain.Note, page, limit int, total int64) Note
 		noteResponses[i] = ToNoteResponse(note)
 	}
 
-	totalPages := int(total) / limit
-	if int(total)%limit != 0 {
-		totalPages++
+	return NoteListResponse{
+		Notes:      noteResponses,
+		Pagination: NewPaginationResponse(page, limit, total),
 	}
+}
 
-	return NoteListResponse{
-		Notes: noteResponses,
-		Pagination: PaginationResponse{
-			Page:       page,
-			Limit:      limit,
-			Total:      total,
-			TotalPages: totalPages,
-		},
+// ToNoteSummaryListResponse converts a list of domain notes to a summary list response
+func ToNoteSummaryListResponse(notes []*domain.Note, page, limit int, total int64) NoteSummaryListResponse {
+	summaries := make([]NoteSummaryResponse, len(notes))
+	for i, note := range notes {
+		summaries[i] = ToNoteSummaryResponse(note)
+	}
+
+	return NoteSummaryListResponse{
+		Notes:      summaries,
+		Pagination: NewPaginationResponse(page, limit, total),
 	}
 }
 
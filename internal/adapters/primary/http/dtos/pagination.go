@@ -0,0 +1,27 @@
+package dtos
+
+// PaginationResponse is the pagination metadata every paginated list returns
+// next to its items
+type PaginationResponse struct {
+	Page       int   `json:"page"`
+	Limit      int   `json:"limit"`
+	Total      int64 `json:"total"`
+	TotalPages int   `json:"total_pages"`
+	HasNext    bool  `json:"has_next"`
+}
+
+// NewPaginationResponse derives total_pages and has_next from total and limit
+func NewPaginationResponse(page, limit int, total int64) PaginationResponse {
+	totalPages := 0
+	if limit > 0 {
+		totalPages = int((total + int64(limit) - 1) / int64(limit))
+	}
+
+	return PaginationResponse{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+		HasNext:    page < totalPages,
+	}
+}
@@ -0,0 +1,36 @@
+package dtos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPaginationResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		page       int
+		limit      int
+		total      int64
+		totalPages int
+		hasNext    bool
+	}{
+		{name: "empty", page: 1, limit: 20, total: 0, totalPages: 0, hasNext: false},
+		{name: "partial last page", page: 1, limit: 20, total: 45, totalPages: 3, hasNext: true},
+		{name: "on last page", page: 3, limit: 20, total: 45, totalPages: 3, hasNext: false},
+		{name: "exact multiple", page: 1, limit: 20, total: 40, totalPages: 2, hasNext: true},
+		{name: "past the end", page: 5, limit: 20, total: 40, totalPages: 2, hasNext: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pagination := NewPaginationResponse(tt.page, tt.limit, tt.total)
+
+			assert.Equal(t, tt.page, pagination.Page)
+			assert.Equal(t, tt.limit, pagination.Limit)
+			assert.Equal(t, tt.total, pagination.Total)
+			assert.Equal(t, tt.totalPages, pagination.TotalPages)
+			assert.Equal(t, tt.hasNext, pagination.HasNext)
+		})
+	}
+}
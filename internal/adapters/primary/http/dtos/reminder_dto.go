@@ -0,0 +1,86 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+)
+
+// ReminderResponse represents a reminder together with its note's title
+type ReminderResponse struct {
+	ID              int64                    `json:"id"`
+	NoteID          int64                    `json:"note_id"`
+	NoteTitle       string                   `json:"note_title,omitempty"`
+	NoteDeleted     bool                     `json:"note_deleted"`
+	Title           string                   `json:"title"`
+	Message         string                   `json:"message,omitempty"`
+	ScheduledAt     time.Time                `json:"scheduled_at"`
+	RepeatType      domain.RepeatType        `json:"repeat_type"`
+	RepeatConfig    *domain.RepeatConfig     `json:"repeat_config,omitempty"`
+	RepeatEndAt     *time.Time               `json:"repeat_end_at,omitempty"`
+	Relative        *domain.RelativeSchedule `json:"relative,omitempty"`
+	IsEnabled       bool                     `json:"is_enabled"`
+	NextTriggerAt   time.Time                `json:"next_trigger_at"`
+	LastTriggeredAt *time.Time               `json:"last_triggered_at,omitempty"`
+	TriggerCount    int                      `json:"trigger_count"`
+	SnoozeCount     int                      `json:"snooze_count"`
+	CreatedAt       time.Time                `json:"created_at"`
+	UpdatedAt       time.Time                `json:"updated_at"`
+}
+
+// ToReminderResponse converts a reminder loaded with its note to a response.
+// A missing or trashed note is reported as deleted, without a title.
+func ToReminderResponse(reminder *domain.Reminder) ReminderResponse {
+	resp := ReminderResponse{
+		ID:              reminder.ID,
+		NoteID:          reminder.NoteID,
+		NoteDeleted:     reminder.Note == nil || reminder.Note.IsDeleted,
+		Title:           reminder.Title,
+		Message:         reminder.Message,
+		ScheduledAt:     reminder.ScheduledAt,
+		RepeatType:      reminder.RepeatType,
+		RepeatConfig:    reminder.RepeatConfig,
+		RepeatEndAt:     reminder.RepeatEndAt,
+		Relative:        reminder.Relative,
+		IsEnabled:       reminder.IsEnabled,
+		NextTriggerAt:   reminder.NextTriggerAt,
+		LastTriggeredAt: reminder.LastTriggeredAt,
+		TriggerCount:    reminder.TriggerCount,
+		SnoozeCount:     reminder.SnoozeCount,
+		CreatedAt:       reminder.CreatedAt,
+		UpdatedAt:       reminder.UpdatedAt,
+	}
+	if !resp.NoteDeleted {
+		resp.NoteTitle = reminder.Note.Title
+	}
+	return resp
+}
+
+// ToReminderResponses converts reminders loaded with their notes to responses
+func ToReminderResponses(reminders []*domain.Reminder) []ReminderResponse {
+	responses := make([]ReminderResponse, len(reminders))
+	for i, reminder := range reminders {
+		responses[i] = ToReminderResponse(reminder)
+	}
+	return responses
+}
+
+// ReminderListResponse represents a page of reminders
+type ReminderListResponse struct {
+	Reminders  []ReminderResponse `json:"reminders"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+// ToReminderListResponse converts a page of reminders to a list response
+func ToReminderListResponse(reminders []*domain.Reminder, page, limit int, total int64) ReminderListResponse {
+	return ReminderListResponse{
+		Reminders:  ToReminderResponses(reminders),
+		Pagination: NewPaginationResponse(page, limit, total),
+	}
+}
+
+// UpcomingReminderResponse represents one upcoming time a reminder fires
+type UpcomingReminderResponse struct {
+	TriggerAt time.Time        `json:"trigger_at"`
+	Reminder  ReminderResponse `json:"reminder"`
+}
@@ -0,0 +1,333 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/yourusername/notinoteapp/internal/adapters/primary/http/dto"
+	"github.com/yourusername/notinoteapp/internal/application/services"
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+	"github.com/yourusername/notinoteapp/internal/core/ports"
+	coreServices "github.com/yourusername/notinoteapp/internal/core/services"
+)
+
+// AdminHandler handles admin-only HTTP requests
+type AdminHandler struct {
+	authService         *services.AuthService
+	noteService         *coreServices.NoteService
+	notificationService *services.NotificationService
+	scheduler           *services.NotificationScheduler
+	database            DatabaseStats
+	logger              *logrus.Logger
+}
+
+// DatabaseStats reports connection pool statistics; *sql.DB implements it
+type DatabaseStats interface {
+	Stats() sql.DBStats
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(
+	authService *services.AuthService,
+	noteService *coreServices.NoteService,
+	notificationService *services.NotificationService,
+	logger *logrus.Logger,
+) *AdminHandler {
+	return &AdminHandler{
+		authService:         authService,
+		noteService:         noteService,
+		notificationService: notificationService,
+		logger:              logger,
+	}
+}
+
+// SetScheduler enables the scheduler endpoints. Without it they answer 503.
+func (h *AdminHandler) SetScheduler(scheduler *services.NotificationScheduler) {
+	h.scheduler = scheduler
+}
+
+// SetDatabase adds the database connection pool to the metrics endpoint
+func (h *AdminHandler) SetDatabase(database DatabaseStats) {
+	h.database = database
+}
+
+// ListUsers returns a page of users with the total count
+// GET /api/v1/admin/users?page=1&limit=20
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	users, total, err := h.authService.ListUsers(c.Request.Context(), limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Success: false,
+			Error:   "Failed to list users",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Data:    dto.NewUserListResponse(users, page, limit, total),
+	})
+}
+
+// RebuildNotePaths recomputes the materialized paths of a user's notes from
+// their parent links
+// POST /api/v1/admin/users/:id/rebuild-paths
+func (h *AdminHandler) RebuildNotePaths(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Error:   "Invalid user ID",
+		})
+		return
+	}
+
+	result, err := h.noteService.RebuildPaths(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.WithError(err).WithField("user_id", userID).Error("Failed to rebuild note paths")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Success: false,
+			Error:   "Failed to rebuild note paths",
+		})
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"user_id":     userID,
+		"checked":     result.Checked,
+		"corrected":   len(result.Corrected),
+		"unreachable": len(result.Unreachable),
+	}).Info("Rebuilt note paths")
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Data:    dto.NewPathRebuildResponse(userID, result),
+	})
+}
+
+// RepairOrphans moves notes whose parent no longer exists to the top level and
+// reports notes left under a trashed parent. ?dry_run=true only reports.
+// POST /api/v1/admin/notes/repair-orphans
+func (h *AdminHandler) RepairOrphans(c *gin.Context) {
+	dryRun, _ := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+
+	repairs, err := h.noteService.RepairOrphans(c.Request.Context(), dryRun)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to repair orphaned notes")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Success: false,
+			Error:   "Failed to repair orphaned notes",
+		})
+		return
+	}
+
+	moved := 0
+	for _, repair := range repairs {
+		if repair.Action == coreServices.OrphanMovedToRoot {
+			moved++
+		}
+	}
+	h.logger.WithFields(logrus.Fields{
+		"dry_run": dryRun,
+		"found":   len(repairs),
+		"moved":   moved,
+	}).Info("Repaired orphaned notes")
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Data:    dto.NewOrphanRepairResponse(repairs, dryRun),
+	})
+}
+
+// ListDeadLetters returns a page of notifications that could not be delivered.
+// Only undelivered ones are listed unless ?unresolved=false.
+// GET /api/v1/admin/notifications/dead-letter?page=1&limit=20&channel=push&user_id=1
+func (h *AdminHandler) ListDeadLetters(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > services.MaxDeadLetterLimit {
+		limit = services.DefaultDeadLetterLimit
+	}
+
+	params := ports.DeadLetterQueryParams{
+		Unresolved: c.DefaultQuery("unresolved", "true") != "false",
+		Limit:      limit,
+		Offset:     (page - 1) * limit,
+	}
+
+	if channel := c.Query("channel"); channel != "" {
+		ch := domain.NotificationChannel(channel)
+		switch ch {
+		case domain.NotificationChannelPush, domain.NotificationChannelEmail, domain.NotificationChannelWebhook:
+			params.Channel = &ch
+		default:
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Success: false,
+				Error:   "Invalid channel",
+			})
+			return
+		}
+	}
+
+	if raw := c.Query("user_id"); raw != "" {
+		userID, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Success: false,
+				Error:   "Invalid user ID",
+			})
+			return
+		}
+		params.UserID = &userID
+	}
+
+	letters, total, err := h.notificationService.ListDeadLetters(c.Request.Context(), params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Success: false,
+			Error:   "Failed to list dead letters",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Data:    dto.NewDeadLetterListResponse(letters, page, limit, total),
+	})
+}
+
+// ReplayDeadLetter re-attempts delivery of a dead letter on its original channel
+// POST /api/v1/admin/notifications/dead-letter/replay/:id
+func (h *AdminHandler) ReplayDeadLetter(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Error:   "Invalid dead letter ID",
+		})
+		return
+	}
+
+	letter, err := h.notificationService.ReplayDeadLetter(c.Request.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrDeadLetterNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Success: false,
+				Error:   "Dead letter not found",
+			})
+		case errors.Is(err, domain.ErrDeadLetterResolved):
+			c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+		case errors.Is(err, domain.ErrNotificationFailed):
+			c.JSON(http.StatusBadGateway, dto.ErrorResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Success: false,
+				Error:   "Failed to replay dead letter",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Message: "Notification delivered",
+		Data:    letter,
+	})
+}
+
+// GetScheduler returns the notification scheduler's state
+// GET /api/v1/admin/scheduler
+func (h *AdminHandler) GetScheduler(c *gin.Context) {
+	if !h.requireScheduler(c) {
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Data:    dto.NewSchedulerStatusResponse(h.scheduler.Status()),
+	})
+}
+
+// PauseScheduler stops the notification scheduler from claiming due reminders
+// POST /api/v1/admin/scheduler/pause
+func (h *AdminHandler) PauseScheduler(c *gin.Context) {
+	if !h.requireScheduler(c) {
+		return
+	}
+
+	h.scheduler.Pause()
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Message: "Scheduler paused",
+		Data:    dto.NewSchedulerStatusResponse(h.scheduler.Status()),
+	})
+}
+
+// ResumeScheduler lets a paused notification scheduler claim reminders again
+// POST /api/v1/admin/scheduler/resume
+func (h *AdminHandler) ResumeScheduler(c *gin.Context) {
+	if !h.requireScheduler(c) {
+		return
+	}
+
+	h.scheduler.Resume()
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Message: "Scheduler resumed",
+		Data:    dto.NewSchedulerStatusResponse(h.scheduler.Status()),
+	})
+}
+
+// requireScheduler answers 503 when no scheduler has been set
+func (h *AdminHandler) requireScheduler(c *gin.Context) bool {
+	if h.scheduler == nil {
+		c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{
+			Success: false,
+			Error:   "Notification scheduler is not available",
+		})
+		return false
+	}
+	return true
+}
+
+// GetMetrics returns runtime metrics, currently the database connection pool
+// GET /api/v1/admin/metrics
+func (h *AdminHandler) GetMetrics(c *gin.Context) {
+	var metrics dto.MetricsResponse
+	if h.database != nil {
+		stats := dto.NewDatabaseStatsResponse(h.database.Stats())
+		metrics.Database = &stats
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Data:    metrics,
+	})
+}
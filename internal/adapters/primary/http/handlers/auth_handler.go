@@ -1,8 +1,13 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/yourusername/notinoteapp/internal/adapters/primary/http/dto"
@@ -13,7 +18,19 @@ import (
 
 // AuthHandler handles authentication HTTP requests
 type AuthHandler struct {
-	authService *services.AuthService
+	authService      *services.AuthService
+	oauthRedirectURL string
+	refreshCookie    *RefreshCookie
+}
+
+// RefreshCookie describes the HttpOnly cookie that carries refresh tokens
+type RefreshCookie struct {
+	Name     string
+	Path     string
+	Domain   string
+	Secure   bool
+	SameSite http.SameSite
+	MaxAge   time.Duration
 }
 
 // NewAuthHandler creates a new auth handler
@@ -23,6 +40,23 @@ func NewAuthHandler(authService *services.AuthService) *AuthHandler {
 	}
 }
 
+// SetOAuthRedirectURL makes the OAuth callbacks redirect browsers to the
+// frontend with the result in the URL fragment instead of answering with JSON.
+// Clients that ask for JSON still get it.
+func (h *AuthHandler) SetOAuthRedirectURL(frontendURL string) {
+	if i := strings.IndexByte(frontendURL, '#'); i >= 0 {
+		frontendURL = frontendURL[:i]
+	}
+	h.oauthRedirectURL = frontendURL
+}
+
+// SetRefreshCookie makes the auth endpoints hand out refresh tokens in an
+// HttpOnly cookie, out of reach of scripts, instead of the JSON body.
+// RefreshToken reads the cookie and falls back to the request body.
+func (h *AuthHandler) SetRefreshCookie(cookie *RefreshCookie) {
+	h.refreshCookie = cookie
+}
+
 // Register handles user registration with email/password
 // POST /api/v1/auth/register
 func (h *AuthHandler) Register(c *gin.Context) {
@@ -57,9 +91,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	// Build response
-	resp := h.buildAuthResponse(authResp)
-	c.JSON(http.StatusCreated, resp)
+	h.respondWithAuth(c, http.StatusCreated, authResp)
 }
 
 // Login handles user login with email/password
@@ -96,25 +128,28 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// Build response
-	resp := h.buildAuthResponse(authResp)
-	c.JSON(http.StatusOK, resp)
+	h.respondWithAuth(c, http.StatusOK, authResp)
 }
 
 // RefreshToken handles token refresh
 // POST /api/v1/auth/refresh
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
-	var req dto.RefreshTokenRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Success: false,
-			Error:   "Invalid request: " + err.Error(),
-		})
-		return
+	// Browsers send the cookie; other clients send the token in the body
+	refreshToken := h.refreshTokenFromCookie(c)
+	if refreshToken == "" {
+		var req dto.RefreshTokenRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Success: false,
+				Error:   "Invalid request: " + err.Error(),
+			})
+			return
+		}
+		refreshToken = req.RefreshToken
 	}
 
 	// Refresh token
-	authResp, err := h.authService.RefreshToken(c.Request.Context(), req.RefreshToken)
+	authResp, err := h.authService.RefreshToken(c.Request.Context(), refreshToken)
 	if err != nil {
 		status := http.StatusUnauthorized
 		message := "Invalid or expired refresh token"
@@ -126,9 +161,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	// Build response
-	resp := h.buildAuthResponse(authResp)
-	c.JSON(http.StatusOK, resp)
+	h.respondWithAuth(c, http.StatusOK, authResp)
 }
 
 // Logout handles user logout
@@ -137,18 +170,90 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 	// In a stateless JWT system, logout is handled client-side by removing the token
 	// For additional security, you could implement token blacklisting using Redis
 
+	// Scripts can't remove an HttpOnly cookie, so expire it here
+	h.clearRefreshCookie(c)
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Logged out successfully",
 	})
 }
 
+// Deactivate disables the current user's account without deleting it
+// POST /api/v1/auth/deactivate
+func (h *AuthHandler) Deactivate(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	if err := h.authService.Deactivate(c.Request.Context(), userID.(int64)); err != nil {
+		status := http.StatusInternalServerError
+		message := "Failed to deactivate account"
+
+		if errors.Is(err, domain.ErrUserNotFound) {
+			status = http.StatusNotFound
+			message = "User not found"
+		}
+
+		c.JSON(status, dto.ErrorResponse{
+			Success: false,
+			Error:   message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Message: "Account deactivated",
+	})
+}
+
+// Reactivate re-enables a deactivated account after re-authentication
+// POST /api/v1/auth/reactivate
+func (h *AuthHandler) Reactivate(c *gin.Context) {
+	var req dto.ReactivateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	var authResp *appdto.AuthResponse
+	var err error
+	if req.Provider != "" {
+		authResp, err = h.authService.ReactivateOAuth(ctx, domain.AuthProvider(req.Provider), req.Token)
+	} else {
+		authResp, err = h.authService.Reactivate(ctx, req.Email, req.Password)
+	}
+	if err != nil {
+		message := "Failed to reactivate account"
+		if errors.Is(err, domain.ErrInvalidCredentials) {
+			message = "Invalid credentials"
+		}
+
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Success: false,
+			Error:   message,
+		})
+		return
+	}
+
+	h.respondWithAuth(c, http.StatusOK, authResp)
+}
+
 // GetCurrentUser returns the current authenticated user's profile
-// GET /api/v1/me
+// GET /api/v1/auth/me (also served at GET /api/v1/me)
 func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
 	// Get user ID from context (set by auth middleware)
 	userID, exists := c.Get("user_id")
-	fmt.Println("userID", userID)
 	if !exists {
 		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
 			Success: false,
@@ -163,7 +268,7 @@ func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
 		status := http.StatusInternalServerError
 		message := "Failed to get user profile"
 
-		if err == domain.ErrUserNotFound {
+		if errors.Is(err, domain.ErrUserNotFound) {
 			status = http.StatusNotFound
 			message = "User not found"
 		}
@@ -182,6 +287,63 @@ func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
 	})
 }
 
+// UpdateProfile updates the current user's name, avatar and notification language.
+// For OAuth users the provider's name replaces the local one on the next
+// sign-in unless pin_name is set.
+// PUT /api/v1/auth/profile
+func (h *AuthHandler) UpdateProfile(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	var req dto.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	user, err := h.authService.UpdateProfile(ctx, userID.(int64), req.Name, req.AvatarURL)
+	if err == nil && req.PinName != nil {
+		user, err = h.authService.SetNamePinned(ctx, userID.(int64), *req.PinName)
+	}
+	if err == nil && req.Language != nil {
+		user, err = h.authService.SetLanguage(ctx, userID.(int64), *req.Language)
+	}
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := "Failed to update profile"
+
+		switch {
+		case errors.Is(err, domain.ErrUserNotFound):
+			status = http.StatusNotFound
+			message = "User not found"
+		case errors.Is(err, domain.ErrInvalidName), errors.Is(err, domain.ErrInvalidAvatarURL), errors.Is(err, domain.ErrUnsupportedLanguage):
+			status = http.StatusBadRequest
+			message = err.Error()
+		}
+
+		c.JSON(status, dto.ErrorResponse{
+			Success: false,
+			Error:   message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Data:    dto.NewUserResponse(user),
+	})
+}
+
 // VerifyGoogleToken verifies Google ID token from frontend
 // POST /api/v1/auth/google/verify
 func (h *AuthHandler) VerifyGoogleToken(c *gin.Context) {
@@ -216,9 +378,7 @@ func (h *AuthHandler) VerifyGoogleToken(c *gin.Context) {
 		return
 	}
 
-	// Build response
-	resp := h.buildAuthResponse(authResp)
-	c.JSON(http.StatusOK, resp)
+	h.respondWithAuth(c, http.StatusOK, authResp)
 }
 
 // VerifyFacebookToken verifies Facebook access token from frontend
@@ -254,14 +414,199 @@ func (h *AuthHandler) VerifyFacebookToken(c *gin.Context) {
 		return
 	}
 
-	// Build response
+	h.respondWithAuth(c, http.StatusOK, authResp)
+}
+
+// GoogleLogin returns the Google authorization URL for the redirect flow
+// GET /api/v1/auth/google
+func (h *AuthHandler) GoogleLogin(c *gin.Context) {
+	h.oauthLogin(c, domain.AuthProviderGoogle, "Google")
+}
+
+// GoogleCallback completes the Google redirect flow
+// GET /api/v1/auth/google/callback
+func (h *AuthHandler) GoogleCallback(c *gin.Context) {
+	h.oauthCallback(c, domain.AuthProviderGoogle, "Google")
+}
+
+// FacebookLogin returns the Facebook authorization URL for the redirect flow
+// GET /api/v1/auth/facebook
+func (h *AuthHandler) FacebookLogin(c *gin.Context) {
+	h.oauthLogin(c, domain.AuthProviderFacebook, "Facebook")
+}
+
+// FacebookCallback completes the Facebook redirect flow
+// GET /api/v1/auth/facebook/callback
+func (h *AuthHandler) FacebookCallback(c *gin.Context) {
+	h.oauthCallback(c, domain.AuthProviderFacebook, "Facebook")
+}
+
+func (h *AuthHandler) oauthLogin(c *gin.Context, provider domain.AuthProvider, providerName string) {
+	authURL, err := h.authService.GetOAuthURL(c.Request.Context(), provider)
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := "Failed to start " + providerName + " sign-in"
+
+		if errors.Is(err, domain.ErrOAuthUnavailable) {
+			status = http.StatusServiceUnavailable
+			message = oauthUnavailableMessage(providerName)
+		}
+
+		c.JSON(status, dto.ErrorResponse{
+			Success: false,
+			Error:   message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Data:    gin.H{"auth_url": authURL},
+	})
+}
+
+func (h *AuthHandler) oauthCallback(c *gin.Context, provider domain.AuthProvider, providerName string) {
+	code := c.Query("code")
+	state := c.Query("state")
+	if code == "" || state == "" {
+		// The provider sends the user back with an error when consent is denied
+		errorCode := c.DefaultQuery("error", "invalid_request")
+		h.oauthCallbackError(c, http.StatusBadRequest, errorCode, "Missing code or state parameter")
+		return
+	}
+
+	authResp, err := h.authService.HandleOAuthCallback(c.Request.Context(), provider, code, state)
+	if err != nil {
+		status := http.StatusUnauthorized
+		errorCode := "oauth_failed"
+		message := "Failed to sign in with " + providerName
+
+		switch {
+		case errors.Is(err, domain.ErrOAuthUnavailable):
+			status = http.StatusServiceUnavailable
+			errorCode = "temporarily_unavailable"
+			message = oauthUnavailableMessage(providerName)
+		case errors.Is(err, domain.ErrOAuthStateMismatch):
+			status = http.StatusBadRequest
+			errorCode = "invalid_state"
+			message = "Invalid OAuth state - possible CSRF attack"
+		case errors.Is(err, domain.ErrUserInactive):
+			status = http.StatusForbidden
+			errorCode = "account_inactive"
+			message = "Account is inactive"
+		}
+
+		h.oauthCallbackError(c, status, errorCode, message)
+		return
+	}
+
+	if h.redirectsOAuth(c) {
+		// The fragment never reaches servers or logs on the way to the frontend
+		fragment := url.Values{
+			"access_token": {authResp.AccessToken},
+			"token_type":   {"Bearer"},
+			"expires_in":   {strconv.FormatInt(authResp.ExpiresIn, 10)},
+		}
+		if h.refreshCookie != nil {
+			h.setRefreshCookie(c, authResp.RefreshToken)
+		} else {
+			fragment.Set("refresh_token", authResp.RefreshToken)
+		}
+		h.redirectToFrontend(c, fragment)
+		return
+	}
+
+	h.respondWithAuth(c, http.StatusOK, authResp)
+}
+
+// oauthCallbackError reports a failed callback as JSON or as a redirect to the frontend
+func (h *AuthHandler) oauthCallbackError(c *gin.Context, status int, errorCode, message string) {
+	if h.redirectsOAuth(c) {
+		h.redirectToFrontend(c, url.Values{
+			"error":             {errorCode},
+			"error_description": {message},
+		})
+		return
+	}
+
+	c.JSON(status, dto.ErrorResponse{
+		Success: false,
+		Error:   message,
+	})
+}
+
+// redirectsOAuth returns true if the callback should redirect to the frontend:
+// a redirect URL is configured and the client didn't ask for JSON via
+// ?response=json or an Accept header
+func (h *AuthHandler) redirectsOAuth(c *gin.Context) bool {
+	if h.oauthRedirectURL == "" {
+		return false
+	}
+	if c.Query("response") == "json" {
+		return false
+	}
+	return !strings.Contains(c.GetHeader("Accept"), "application/json")
+}
+
+func (h *AuthHandler) redirectToFrontend(c *gin.Context, fragment url.Values) {
+	c.Redirect(http.StatusFound, h.oauthRedirectURL+"#"+fragment.Encode())
+}
+
+func oauthUnavailableMessage(providerName string) string {
+	return "Sign-in with " + providerName + " is temporarily unavailable. Please try again later or sign in with email and password"
+}
+
+// respondWithAuth sends the authentication response, moving the refresh
+// token into its cookie when one is configured
+func (h *AuthHandler) respondWithAuth(c *gin.Context, status int, authResp *appdto.AuthResponse) {
 	resp := h.buildAuthResponse(authResp)
-	c.JSON(http.StatusOK, resp)
+	if h.refreshCookie != nil {
+		h.setRefreshCookie(c, authResp.RefreshToken)
+		resp.Data.RefreshToken = ""
+	}
+	c.JSON(status, resp)
+}
+
+func (h *AuthHandler) setRefreshCookie(c *gin.Context, refreshToken string) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     h.refreshCookie.Name,
+		Value:    refreshToken,
+		Path:     h.refreshCookie.Path,
+		Domain:   h.refreshCookie.Domain,
+		MaxAge:   int(h.refreshCookie.MaxAge.Seconds()),
+		Secure:   h.refreshCookie.Secure,
+		HttpOnly: true,
+		SameSite: h.refreshCookie.SameSite,
+	})
+}
+
+func (h *AuthHandler) clearRefreshCookie(c *gin.Context) {
+	if h.refreshCookie == nil {
+		return
+	}
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     h.refreshCookie.Name,
+		Path:     h.refreshCookie.Path,
+		Domain:   h.refreshCookie.Domain,
+		MaxAge:   -1,
+		Secure:   h.refreshCookie.Secure,
+		HttpOnly: true,
+		SameSite: h.refreshCookie.SameSite,
+	})
+}
+
+func (h *AuthHandler) refreshTokenFromCookie(c *gin.Context) string {
+	if h.refreshCookie == nil {
+		return ""
+	}
+	token, err := c.Cookie(h.refreshCookie.Name)
+	if err != nil {
+		return ""
+	}
+	return token
 }
 
 // buildAuthResponse builds the authentication response
 func (h *AuthHandler) buildAuthResponse(authResp *appdto.AuthResponse) dto.AuthResponse {
-	// 24 hours in seconds
-	expiresIn := 86400
-	return dto.NewAuthResponse(authResp, expiresIn)
+	return dto.NewAuthResponse(authResp, int(authResp.ExpiresIn))
 }
@@ -28,8 +28,9 @@ func NewDeviceHandler(deviceService *services.DeviceService, logger *logrus.Logg
 type RegisterDeviceRequest struct {
 	DeviceToken string            `json:"device_token" binding:"required"`
 	DeviceType  domain.DeviceType `json:"device_type" binding:"required,oneof=web android ios"`
-	DeviceName  string            `json:"device_name"`
-	BrowserInfo string            `json:"browser_info"`
+	DeviceName  string            `json:"device_name" binding:"max=255"`
+	AppVersion  string            `json:"app_version" binding:"max=50"`
+	BrowserInfo string            `json:"browser_info" binding:"max=255"`
 }
 
 // UnregisterByTokenRequest represents a request to unregister by token
@@ -55,6 +56,7 @@ func (h *DeviceHandler) Register(c *gin.Context) {
 		DeviceToken: req.DeviceToken,
 		DeviceType:  req.DeviceType,
 		DeviceName:  req.DeviceName,
+		AppVersion:  req.AppVersion,
 		BrowserInfo: req.BrowserInfo,
 	}
 
@@ -74,12 +76,20 @@ func (h *DeviceHandler) Register(c *gin.Context) {
 	})
 }
 
-// List returns all devices for the current user
+// List returns the current user's active devices, or all of them with
+// ?include_inactive=true
 // GET /api/v1/devices
 func (h *DeviceHandler) List(c *gin.Context) {
 	userID := c.GetInt64("user_id")
-
-	devices, err := h.deviceService.ListUserDevices(c.Request.Context(), userID)
+	includeInactive, _ := strconv.ParseBool(c.DefaultQuery("include_inactive", "false"))
+
+	var devices []*domain.Device
+	var err error
+	if includeInactive {
+		devices, err = h.deviceService.ListUserDevices(c.Request.Context(), userID)
+	} else {
+		devices, err = h.deviceService.GetActiveDevices(c.Request.Context(), userID)
+	}
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to list devices")
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -134,6 +144,43 @@ func (h *DeviceHandler) Unregister(c *gin.Context) {
 	})
 }
 
+// Heartbeat marks a device as still in use
+// POST /api/v1/devices/:id/heartbeat
+func (h *DeviceHandler) Heartbeat(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+
+	deviceID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid device ID",
+		})
+		return
+	}
+
+	device, err := h.deviceService.Heartbeat(c.Request.Context(), userID, deviceID)
+	if err != nil {
+		if err == domain.ErrDeviceNotFound {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "Device not found",
+			})
+			return
+		}
+		h.logger.WithError(err).Error("Failed to record device heartbeat")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to record device heartbeat",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    device,
+	})
+}
+
 // UnregisterByToken removes a device by token
 // DELETE /api/v1/devices/token
 func (h *DeviceHandler) UnregisterByToken(c *gin.Context) {
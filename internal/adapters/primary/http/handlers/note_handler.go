@@ -1,14 +1,22 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/yourusername/notinoteapp/internal/adapters/primary/http/dtos"
+	"github.com/yourusername/notinoteapp/internal/adapters/primary/http/middleware"
 	"github.com/yourusername/notinoteapp/internal/core/domain"
 	"github.com/yourusername/notinoteapp/internal/core/ports"
 	"github.com/yourusername/notinoteapp/internal/core/services"
+	"golang.org/x/net/websocket"
 )
 
 // NoteHandler handles HTTP requests for note operations
@@ -40,14 +48,26 @@ func (h *NoteHandler) CreateNote(c *gin.Context) {
 
 	note, err := h.noteService.CreateNote(c.Request.Context(), userID.(int64), req.Title, req.ParentID)
 	if err != nil {
-		if err == domain.ErrUnauthorizedAccess {
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
 			return
 		}
-		if err == domain.ErrMaxDepthExceeded {
+		if errors.Is(err, domain.ErrNoteNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "parent note not found"})
+			return
+		}
+		if errors.Is(err, domain.ErrMaxDepthExceeded) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "maximum nesting depth exceeded"})
 			return
 		}
+		if errors.Is(err, domain.ErrInvalidNoteTitle) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid title"})
+			return
+		}
+		if errors.Is(err, domain.ErrQuotaExceeded) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create note"})
 		return
 	}
@@ -67,6 +87,7 @@ func (h *NoteHandler) CreateNote(c *gin.Context) {
 }
 
 // GetNote handles GET /api/v1/notes/:id
+// include=breadcrumb embeds the note's ancestors, as GET /:id/ancestors returns them.
 func (h *NoteHandler) GetNote(c *gin.Context) {
 	noteID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
@@ -76,13 +97,23 @@ func (h *NoteHandler) GetNote(c *gin.Context) {
 
 	userID, _ := c.Get("user_id")
 
-	note, err := h.noteService.GetNote(c.Request.Context(), noteID, userID.(int64))
+	// The breadcrumb costs an extra query, so it's only loaded on request
+	var (
+		note      *domain.Note
+		ancestors []*domain.Note
+	)
+	withBreadcrumb := slices.Contains(strings.Split(c.Query("include"), ","), "breadcrumb")
+	if withBreadcrumb {
+		note, ancestors, err = h.noteService.GetNoteWithAncestors(c.Request.Context(), noteID, userID.(int64))
+	} else {
+		note, err = h.noteService.GetNote(c.Request.Context(), noteID, userID.(int64))
+	}
 	if err != nil {
-		if err == domain.ErrNoteNotFound {
+		if errors.Is(err, domain.ErrNoteNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
 			return
 		}
-		if err == domain.ErrUnauthorizedAccess {
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
 			return
 		}
@@ -90,12 +121,103 @@ func (h *NoteHandler) GetNote(c *gin.Context) {
 		return
 	}
 
+	if withBreadcrumb {
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"data": dtos.NoteWithBreadcrumbResponse{
+				NoteResponse: dtos.ToNoteResponse(note),
+				Breadcrumb:   dtos.ToBreadcrumbResponses(ancestors),
+			},
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data":    dtos.ToNoteResponse(note),
 	})
 }
 
+// GetUsage handles GET /api/v1/usage
+func (h *NoteHandler) GetUsage(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	usage, err := h.noteService.Usage(c.Request.Context(), userID.(int64))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get usage"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"usage": usage,
+			"quota": h.noteService.Quota(),
+		},
+	})
+}
+
+// GetLimits handles GET /api/v1/limits
+func (h *NoteHandler) GetLimits(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    h.noteService.Limits(),
+	})
+}
+
+// CountNotes handles GET /api/v1/notes/count
+func (h *NoteHandler) CountNotes(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	counts, err := h.noteService.CountNotes(c.Request.Context(), userID.(int64))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to count notes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    counts,
+	})
+}
+
+// BatchGetNotes handles POST /api/v1/notes/batch-get
+func (h *NoteHandler) BatchGetNotes(c *gin.Context) {
+	var req dtos.BatchGetNotesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	notes, err := h.noteService.GetNotesByIDs(c.Request.Context(), userID.(int64), req.IDs)
+	if err != nil {
+		if errors.Is(err, domain.ErrTooManyNoteIDs) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("at most %d note IDs can be requested at once", services.MaxBatchGetNotes),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get notes"})
+		return
+	}
+
+	responses := make([]dtos.NoteResponse, len(notes))
+	for i, note := range notes {
+		responses[i] = dtos.ToNoteResponse(note)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    responses,
+	})
+}
+
 // ListNotes handles GET /api/v1/notes
 func (h *NoteHandler) ListNotes(c *gin.Context) {
 	userID, _ := c.Get("user_id")
@@ -138,14 +260,59 @@ func (h *NoteHandler) ListNotes(c *gin.Context) {
 
 	// Sorting
 	filters.SortBy = c.DefaultQuery("sort_by", "updated_at")
+	if !ports.IsValidNoteSortField(filters.SortBy) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":          "invalid sort_by",
+			"allowed_values": ports.NoteSortFields,
+		})
+		return
+	}
 	filters.SortOrder = c.DefaultQuery("sort_order", "desc")
 
+	// Summary mode skips loading blocks, for sidebars and pickers
+	filters.SummaryOnly = c.Query("summary") == "true"
+
+	// Date ranges, as RFC3339 timestamps
+	dateFilters := []struct {
+		param string
+		value **time.Time
+	}{
+		{"created_after", &filters.CreatedAfter},
+		{"created_before", &filters.CreatedBefore},
+		{"updated_after", &filters.UpdatedAfter},
+		{"updated_before", &filters.UpdatedBefore},
+	}
+	for _, dateFilter := range dateFilters {
+		raw := c.Query(dateFilter.param)
+		if raw == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + dateFilter.param + ", expected RFC3339"})
+			return
+		}
+		*dateFilter.value = &parsed
+	}
+
 	notes, total, err := h.noteService.ListNotes(c.Request.Context(), userID.(int64), filters)
 	if err != nil {
+		if errors.Is(err, domain.ErrValidation) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list notes"})
 		return
 	}
 
+	if filters.SummaryOnly {
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"data":    dtos.ToNoteSummaryListResponse(notes, page, limit, total),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data":    dtos.ToNoteListResponse(notes, page, limit, total),
@@ -170,15 +337,60 @@ func (h *NoteHandler) UpdateNote(c *gin.Context) {
 
 	note, err := h.noteService.UpdateNote(c.Request.Context(), noteID, userID.(int64), req.Title, req.Icon, req.CoverImage)
 	if err != nil {
-		if err == domain.ErrNoteNotFound {
+		if errors.Is(err, domain.ErrNoteNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
+			return
+		}
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidNoteTitle) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid title"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update note"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    dtos.ToNoteResponse(note),
+	})
+}
+
+// PatchNote handles PATCH /api/v1/notes/:id
+func (h *NoteHandler) PatchNote(c *gin.Context) {
+	noteID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid note ID"})
+		return
+	}
+
+	var req dtos.PatchNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	note, err := h.noteService.PatchNote(c.Request.Context(), noteID, userID.(int64), services.NotePatch{
+		Title:      req.Title,
+		Icon:       req.Icon,
+		CoverImage: req.CoverImage,
+		Properties: req.Properties,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrNoteNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
 			return
 		}
-		if err == domain.ErrUnauthorizedAccess {
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
 			return
 		}
-		if err == domain.ErrInvalidNoteTitle {
+		if errors.Is(err, domain.ErrInvalidNoteTitle) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid title"})
 			return
 		}
@@ -202,12 +414,36 @@ func (h *NoteHandler) DeleteNote(c *gin.Context) {
 
 	userID, _ := c.Get("user_id")
 
+	// Deleting a note takes its descendants with it, so that has to be confirmed
+	if c.Query("confirm") != "true" {
+		count, err := h.noteService.GetSubtreeSize(c.Request.Context(), noteID, userID.(int64))
+		if err != nil {
+			if errors.Is(err, domain.ErrNoteNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
+				return
+			}
+			if errors.Is(err, domain.ErrUnauthorizedAccess) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete note"})
+			return
+		}
+		if count > 0 {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":             "note has descendants; pass confirm=true to delete them too",
+				"descendants_count": count,
+			})
+			return
+		}
+	}
+
 	if err := h.noteService.DeleteNote(c.Request.Context(), noteID, userID.(int64)); err != nil {
-		if err == domain.ErrNoteNotFound {
+		if errors.Is(err, domain.ErrNoteNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
 			return
 		}
-		if err == domain.ErrUnauthorizedAccess {
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
 			return
 		}
@@ -233,11 +469,11 @@ func (h *NoteHandler) RestoreNote(c *gin.Context) {
 
 	note, err := h.noteService.RestoreNote(c.Request.Context(), noteID, userID.(int64))
 	if err != nil {
-		if err == domain.ErrNoteNotFound {
+		if errors.Is(err, domain.ErrNoteNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
 			return
 		}
-		if err == domain.ErrUnauthorizedAccess {
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
 			return
 		}
@@ -263,11 +499,11 @@ func (h *NoteHandler) ArchiveNote(c *gin.Context) {
 
 	note, err := h.noteService.ArchiveNote(c.Request.Context(), noteID, userID.(int64))
 	if err != nil {
-		if err == domain.ErrNoteNotFound {
+		if errors.Is(err, domain.ErrNoteNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
 			return
 		}
-		if err == domain.ErrUnauthorizedAccess {
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
 			return
 		}
@@ -293,11 +529,11 @@ func (h *NoteHandler) UnarchiveNote(c *gin.Context) {
 
 	note, err := h.noteService.UnarchiveNote(c.Request.Context(), noteID, userID.(int64))
 	if err != nil {
-		if err == domain.ErrNoteNotFound {
+		if errors.Is(err, domain.ErrNoteNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
 			return
 		}
-		if err == domain.ErrUnauthorizedAccess {
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
 			return
 		}
@@ -328,19 +564,19 @@ func (h *NoteHandler) MoveNote(c *gin.Context) {
 	userID, _ := c.Get("user_id")
 
 	if err := h.noteService.MoveNote(c.Request.Context(), noteID, userID.(int64), req.NewParentID, req.Position); err != nil {
-		if err == domain.ErrNoteNotFound {
+		if errors.Is(err, domain.ErrNoteNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
 			return
 		}
-		if err == domain.ErrUnauthorizedAccess {
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
 			return
 		}
-		if err == domain.ErrMaxDepthExceeded {
+		if errors.Is(err, domain.ErrMaxDepthExceeded) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "maximum nesting depth exceeded"})
 			return
 		}
-		if err == domain.ErrCircularReference {
+		if errors.Is(err, domain.ErrCircularReference) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "circular reference detected"})
 			return
 		}
@@ -354,6 +590,86 @@ func (h *NoteHandler) MoveNote(c *gin.Context) {
 	})
 }
 
+// MoveNoteToRoot handles POST /api/v1/notes/:id/move-to-root?position=0
+func (h *NoteHandler) MoveNoteToRoot(c *gin.Context) {
+	noteID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid note ID"})
+		return
+	}
+
+	position, err := strconv.Atoi(c.DefaultQuery("position", "0"))
+	if err != nil || position < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid position"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	note, err := h.noteService.MoveNoteToRoot(c.Request.Context(), noteID, userID.(int64), position)
+	if err != nil {
+		if errors.Is(err, domain.ErrNoteNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
+			return
+		}
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to move note"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    dtos.ToNoteResponse(note),
+	})
+}
+
+// QueryView handles GET /api/v1/notes/:id/rows
+func (h *NoteHandler) QueryView(c *gin.Context) {
+	noteID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid note ID"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	rows, total, err := h.noteService.QueryView(c.Request.Context(), noteID, userID.(int64), limit, (page-1)*limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrNoteNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
+			return
+		}
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+		if errors.Is(err, domain.ErrNoteNotView) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "note has no database view"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to query view"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    dtos.ToNoteListResponse(rows, page, limit, total),
+	})
+}
+
 // GetChildren handles GET /api/v1/notes/:id/children
 func (h *NoteHandler) GetChildren(c *gin.Context) {
 	noteID, err := strconv.ParseInt(c.Param("id"), 10, 64)
@@ -366,11 +682,11 @@ func (h *NoteHandler) GetChildren(c *gin.Context) {
 
 	children, err := h.noteService.GetChildren(c.Request.Context(), noteID, userID.(int64))
 	if err != nil {
-		if err == domain.ErrNoteNotFound {
+		if errors.Is(err, domain.ErrNoteNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
 			return
 		}
-		if err == domain.ErrUnauthorizedAccess {
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
 			return
 		}
@@ -389,6 +705,76 @@ func (h *NoteHandler) GetChildren(c *gin.Context) {
 	})
 }
 
+// GetDescendants handles GET /api/v1/notes/:id/descendants?page=1&limit=20
+func (h *NoteHandler) GetDescendants(c *gin.Context) {
+	noteID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid note ID"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	userID, _ := c.Get("user_id")
+
+	descendants, total, err := h.noteService.ListDescendants(c.Request.Context(), noteID, userID.(int64), limit, (page-1)*limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrNoteNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
+			return
+		}
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get descendants"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    dtos.ToNoteSummaryListResponse(descendants, page, limit, total),
+	})
+}
+
+// GetDescendantCount handles GET /api/v1/notes/:id/descendants/count
+func (h *NoteHandler) GetDescendantCount(c *gin.Context) {
+	noteID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid note ID"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	count, err := h.noteService.GetSubtreeSize(c.Request.Context(), noteID, userID.(int64))
+	if err != nil {
+		if errors.Is(err, domain.ErrNoteNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
+			return
+		}
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to count descendants"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"count": count},
+	})
+}
+
 // GetAncestors handles GET /api/v1/notes/:id/ancestors
 func (h *NoteHandler) GetAncestors(c *gin.Context) {
 	noteID, err := strconv.ParseInt(c.Param("id"), 10, 64)
@@ -401,11 +787,11 @@ func (h *NoteHandler) GetAncestors(c *gin.Context) {
 
 	ancestors, err := h.noteService.GetAncestors(c.Request.Context(), noteID, userID.(int64))
 	if err != nil {
-		if err == domain.ErrNoteNotFound {
+		if errors.Is(err, domain.ErrNoteNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
 			return
 		}
-		if err == domain.ErrUnauthorizedAccess {
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
 			return
 		}
@@ -420,6 +806,8 @@ func (h *NoteHandler) GetAncestors(c *gin.Context) {
 }
 
 // SearchNotes handles GET /api/v1/notes/search
+// With fuzzy=true, a query with no full-text matches falls back to similar titles.
+// within=<note id> limits results to that note and its descendants.
 func (h *NoteHandler) SearchNotes(c *gin.Context) {
 	userID, _ := c.Get("user_id")
 
@@ -439,13 +827,29 @@ func (h *NoteHandler) SearchNotes(c *gin.Context) {
 		limit = 20
 	}
 
+	fuzzy, _ := strconv.ParseBool(c.DefaultQuery("fuzzy", "false"))
+
 	filters := ports.NoteFilters{
+		Fuzzy:  fuzzy,
 		Limit:  limit,
 		Offset: (page - 1) * limit,
 	}
 
+	if withinStr := c.Query("within"); withinStr != "" {
+		withinID, err := strconv.ParseInt(withinStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid within note ID"})
+			return
+		}
+		filters.WithinNoteID = &withinID
+	}
+
 	notes, total, err := h.noteService.SearchNotes(c.Request.Context(), userID.(int64), query, filters)
 	if err != nil {
+		if errors.Is(err, domain.ErrNoteNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to search notes"})
 		return
 	}
@@ -473,26 +877,32 @@ func (h *NoteHandler) UpdateViewMetadata(c *gin.Context) {
 	userID, _ := c.Get("user_id")
 
 	viewMetadata := &domain.ViewMetadata{
-		ViewType:   req.ViewType,
-		Properties: req.Properties,
-		Filters:    req.Filters,
-		Sorts:      req.Sorts,
+		ViewType:      req.ViewType,
+		Properties:    req.Properties,
+		Filters:       req.Filters,
+		Sorts:         req.Sorts,
+		GroupBy:       req.GroupBy,
+		CoverProperty: req.CoverProperty,
 	}
 
 	note, err := h.noteService.UpdateViewMetadata(c.Request.Context(), noteID, userID.(int64), viewMetadata)
 	if err != nil {
-		if err == domain.ErrNoteNotFound {
+		if errors.Is(err, domain.ErrNoteNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
 			return
 		}
-		if err == domain.ErrUnauthorizedAccess {
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
 			return
 		}
-		if err == domain.ErrInvalidViewType {
+		if errors.Is(err, domain.ErrInvalidViewType) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid view type"})
 			return
 		}
+		if errors.Is(err, domain.ErrInvalidViewConfig) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update view metadata"})
 		return
 	}
@@ -519,13 +929,13 @@ func (h *NoteHandler) UpdateProperties(c *gin.Context) {
 
 	userID, _ := c.Get("user_id")
 
-	note, err := h.noteService.UpdateProperties(c.Request.Context(), noteID, userID.(int64), req.Properties)
+	note, err := h.noteService.UpdateProperties(c.Request.Context(), noteID, userID.(int64), req.Properties, false)
 	if err != nil {
-		if err == domain.ErrNoteNotFound {
+		if errors.Is(err, domain.ErrNoteNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
 			return
 		}
-		if err == domain.ErrUnauthorizedAccess {
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
 			return
 		}
@@ -539,15 +949,16 @@ func (h *NoteHandler) UpdateProperties(c *gin.Context) {
 	})
 }
 
-// AddBlock handles POST /api/v1/notes/:id/blocks
-func (h *NoteHandler) AddBlock(c *gin.Context) {
+// MergeProperties handles PATCH /api/v1/notes/:id/properties, setting the
+// given properties and keeping the rest; a null value removes a property
+func (h *NoteHandler) MergeProperties(c *gin.Context) {
 	noteID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid note ID"})
 		return
 	}
 
-	var req dtos.AddBlockRequest
+	var req dtos.UpdatePropertiesRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -555,21 +966,61 @@ func (h *NoteHandler) AddBlock(c *gin.Context) {
 
 	userID, _ := c.Get("user_id")
 
-	note, err := h.noteService.AddBlock(c.Request.Context(), noteID, userID.(int64), req.Type, req.Content)
+	note, err := h.noteService.UpdateProperties(c.Request.Context(), noteID, userID.(int64), req.Properties, true)
 	if err != nil {
-		if err == domain.ErrNoteNotFound {
+		if errors.Is(err, domain.ErrNoteNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
 			return
 		}
-		if err == domain.ErrUnauthorizedAccess {
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
 			return
 		}
-		if err == domain.ErrInvalidBlockType || err == domain.ErrInvalidBlockContent {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to add block"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update properties"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    dtos.ToNoteResponse(note),
+	})
+}
+
+// AddBlock handles POST /api/v1/notes/:id/blocks
+func (h *NoteHandler) AddBlock(c *gin.Context) {
+	noteID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid note ID"})
+		return
+	}
+
+	var req dtos.AddBlockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	note, err := h.noteService.AddBlock(c.Request.Context(), noteID, userID.(int64), req.Type, req.Content)
+	if err != nil {
+		if errors.Is(err, domain.ErrNoteNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
+			return
+		}
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+		if errors.Is(err, domain.ErrQuotaExceeded) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidBlockType) || errors.Is(err, domain.ErrInvalidBlockContent) || errors.Is(err, domain.ErrTooManyBlocks) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to add block"})
 		return
 	}
 
@@ -603,15 +1054,19 @@ func (h *NoteHandler) UpdateBlock(c *gin.Context) {
 
 	note, err := h.noteService.UpdateBlock(c.Request.Context(), noteID, userID.(int64), blockID, req.Content)
 	if err != nil {
-		if err == domain.ErrNoteNotFound {
+		if errors.Is(err, domain.ErrNoteNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
 			return
 		}
-		if err == domain.ErrUnauthorizedAccess {
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
 			return
 		}
-		if err == domain.ErrBlockNotFound || err == domain.ErrInvalidBlockContent {
+		if errors.Is(err, domain.ErrQuotaExceeded) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, domain.ErrBlockNotFound) || errors.Is(err, domain.ErrInvalidBlockContent) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
@@ -625,6 +1080,69 @@ func (h *NoteHandler) UpdateBlock(c *gin.Context) {
 	})
 }
 
+// UpdateBlockText handles PATCH /api/v1/notes/:id/blocks/:block_id/text
+func (h *NoteHandler) UpdateBlockText(c *gin.Context) {
+	noteID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid note ID"})
+		return
+	}
+
+	blockID := c.Param("block_id")
+	if blockID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "block ID is required"})
+		return
+	}
+
+	var req dtos.UpdateBlockTextRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	note, err := h.noteService.UpdateBlockText(c.Request.Context(), noteID, userID.(int64), blockID, req.RichText)
+	if err != nil {
+		if errors.Is(err, domain.ErrNoteNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
+			return
+		}
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+		if errors.Is(err, domain.ErrQuotaExceeded) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, domain.ErrBlockNotFound) || errors.Is(err, domain.ErrBlockNotText) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update block"})
+		return
+	}
+
+	// Auto-save fires often, so reply with the block rather than the whole note
+	var block *domain.Block
+	for i := range note.Blocks {
+		if note.Blocks[i].ID == blockID {
+			block = &note.Blocks[i]
+			break
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": dtos.BlockTextResponse{
+			NoteID:    note.ID,
+			Block:     block,
+			UpdatedAt: note.UpdatedAt,
+		},
+	})
+}
+
 // DeleteBlock handles DELETE /api/v1/notes/:id/blocks/:block_id
 func (h *NoteHandler) DeleteBlock(c *gin.Context) {
 	noteID, err := strconv.ParseInt(c.Param("id"), 10, 64)
@@ -643,15 +1161,15 @@ func (h *NoteHandler) DeleteBlock(c *gin.Context) {
 
 	note, err := h.noteService.DeleteBlock(c.Request.Context(), noteID, userID.(int64), blockID)
 	if err != nil {
-		if err == domain.ErrNoteNotFound {
+		if errors.Is(err, domain.ErrNoteNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
 			return
 		}
-		if err == domain.ErrUnauthorizedAccess {
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
 			return
 		}
-		if err == domain.ErrBlockNotFound {
+		if errors.Is(err, domain.ErrBlockNotFound) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "block not found"})
 			return
 		}
@@ -683,15 +1201,19 @@ func (h *NoteHandler) ReplaceBlocks(c *gin.Context) {
 
 	note, err := h.noteService.ReplaceBlocks(c.Request.Context(), noteID, userID.(int64), req.Blocks)
 	if err != nil {
-		if err == domain.ErrNoteNotFound {
+		if errors.Is(err, domain.ErrNoteNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
 			return
 		}
-		if err == domain.ErrUnauthorizedAccess {
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
 			return
 		}
-		if err == domain.ErrInvalidBlockType || err == domain.ErrInvalidBlockContent {
+		if errors.Is(err, domain.ErrQuotaExceeded) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidBlockType) || errors.Is(err, domain.ErrInvalidBlockContent) || errors.Is(err, domain.ErrTooManyBlocks) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
@@ -723,15 +1245,15 @@ func (h *NoteHandler) ReorderBlocks(c *gin.Context) {
 
 	note, err := h.noteService.ReorderBlocks(c.Request.Context(), noteID, userID.(int64), req.BlockIDs)
 	if err != nil {
-		if err == domain.ErrNoteNotFound {
+		if errors.Is(err, domain.ErrNoteNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
 			return
 		}
-		if err == domain.ErrUnauthorizedAccess {
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
 			return
 		}
-		if err == domain.ErrInvalidBlockOrder {
+		if errors.Is(err, domain.ErrInvalidBlockOrder) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid block order"})
 			return
 		}
@@ -757,11 +1279,11 @@ func (h *NoteHandler) ToggleFavorite(c *gin.Context) {
 
 	note, err := h.noteService.ToggleFavorite(c.Request.Context(), noteID, userID.(int64))
 	if err != nil {
-		if err == domain.ErrNoteNotFound {
+		if errors.Is(err, domain.ErrNoteNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
 			return
 		}
-		if err == domain.ErrUnauthorizedAccess {
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
 			return
 		}
@@ -793,11 +1315,11 @@ func (h *NoteHandler) AddTagToNote(c *gin.Context) {
 
 	note, err := h.noteService.AddTag(c.Request.Context(), noteID, userID.(int64), tagID)
 	if err != nil {
-		if err == domain.ErrNoteNotFound {
+		if errors.Is(err, domain.ErrNoteNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
 			return
 		}
-		if err == domain.ErrUnauthorizedAccess {
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
 			return
 		}
@@ -829,11 +1351,11 @@ func (h *NoteHandler) RemoveTagFromNote(c *gin.Context) {
 
 	note, err := h.noteService.RemoveTag(c.Request.Context(), noteID, userID.(int64), tagID)
 	if err != nil {
-		if err == domain.ErrNoteNotFound {
+		if errors.Is(err, domain.ErrNoteNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
 			return
 		}
-		if err == domain.ErrUnauthorizedAccess {
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
 			return
 		}
@@ -846,3 +1368,476 @@ func (h *NoteHandler) RemoveTagFromNote(c *gin.Context) {
 		"data":    dtos.ToNoteResponse(note),
 	})
 }
+
+// ListTags handles GET /api/v1/tags?page=1&limit=50
+func (h *NoteHandler) ListTags(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+
+	tags, total, err := h.noteService.ListTags(c.Request.Context(), userID.(int64), limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list tags"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    dtos.ToTagListResponse(tags, page, limit, total),
+	})
+}
+
+// UpdateTag handles PATCH /api/v1/tags/:id
+func (h *NoteHandler) UpdateTag(c *gin.Context) {
+	var req dtos.UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	tag, err := h.noteService.UpdateTag(c.Request.Context(), userID.(int64), c.Param("id"), req.Name, req.Color)
+	if err != nil {
+		if errors.Is(err, domain.ErrTagNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "tag not found"})
+			return
+		}
+		if errors.Is(err, domain.ErrTagNameTaken) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidTagName) || errors.Is(err, domain.ErrInvalidTagColor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update tag"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    dtos.ToTagResponse(tag),
+	})
+}
+
+// MergeTag handles POST /api/v1/tags/:id/merge, moving the tag's notes to the
+// target tag and deleting it
+func (h *NoteHandler) MergeTag(c *gin.Context) {
+	var req dtos.MergeTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	tag, err := h.noteService.MergeTags(c.Request.Context(), userID.(int64), c.Param("id"), req.TargetTagID)
+	if err != nil {
+		if errors.Is(err, domain.ErrTagNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "tag not found"})
+			return
+		}
+		if errors.Is(err, domain.ErrMergeTagIntoItself) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to merge tags"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    dtos.ToTagResponse(tag),
+	})
+}
+
+// TagNotes handles POST /api/v1/tags/:id/notes
+func (h *NoteHandler) TagNotes(c *gin.Context) {
+	h.bulkTag(c, h.noteService.BulkAddTag)
+}
+
+// UntagNotes handles DELETE /api/v1/tags/:id/notes
+func (h *NoteHandler) UntagNotes(c *gin.Context) {
+	h.bulkTag(c, h.noteService.BulkRemoveTag)
+}
+
+// bulkTag applies a bulk tag operation and reports the result for each note
+func (h *NoteHandler) bulkTag(c *gin.Context, apply func(ctx context.Context, userID int64, noteIDs []int64, tagID string) ([]services.TagResult, error)) {
+	tagID := c.Param("id")
+
+	var req dtos.BulkTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	results, err := apply(c.Request.Context(), userID.(int64), req.NoteIDs, tagID)
+	if err != nil {
+		if errors.Is(err, domain.ErrTagNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "tag not found"})
+			return
+		}
+		if errors.Is(err, domain.ErrTooManyNoteIDs) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("at most %d notes can be tagged at once", services.MaxBulkTagNotes),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update tags"})
+		return
+	}
+
+	responses := make([]dtos.BulkTagResult, len(results))
+	for i, result := range results {
+		responses[i] = dtos.BulkTagResult{NoteID: result.NoteID, Success: result.Err == nil}
+		if result.Err != nil {
+			responses[i].Error = result.Err.Error()
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"tag_id":  tagID,
+			"results": responses,
+		},
+	})
+}
+
+// NoteSocket handles GET /api/v1/notes/:id/ws
+// Upgrades to a WebSocket that streams block change events for the note.
+// The connection is receive-only: mutations still go through the REST API.
+func (h *NoteHandler) NoteSocket(c *gin.Context) {
+	noteID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid note ID"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	events, unsubscribe, err := h.noteService.SubscribeToNote(c.Request.Context(), noteID, userID.(int64))
+	if err != nil {
+		if errors.Is(err, domain.ErrNoteNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
+			return
+		}
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+		if errors.Is(err, domain.ErrStreamUnavailable) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "live updates are not available"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get note"})
+		return
+	}
+	defer unsubscribe()
+
+	server := websocket.Server{
+		// Origin is not checked: the connection is authenticated by an explicit
+		// token rather than cookies, so cross-site pages can't hijack it
+		Handshake: func(config *websocket.Config, r *http.Request) error {
+			selected := []string{}
+			for _, protocol := range config.Protocol {
+				if protocol == middleware.WebSocketProtocol {
+					selected = append(selected, protocol)
+					break
+				}
+			}
+			config.Protocol = selected
+			return nil
+		},
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+
+			// The server write timeout doesn't apply to long-lived connections
+			ws.SetDeadline(time.Time{})
+
+			// Drain and discard client frames; a read error means the client went away
+			closed := make(chan struct{})
+			go func() {
+				defer close(closed)
+				var msg string
+				for {
+					if err := websocket.Message.Receive(ws, &msg); err != nil {
+						return
+					}
+				}
+			}()
+
+			for {
+				select {
+				case <-closed:
+					return
+				case event, ok := <-events:
+					if !ok {
+						return
+					}
+					if err := websocket.JSON.Send(ws, event); err != nil {
+						return
+					}
+				}
+			}
+		},
+	}
+
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// ShareNote handles POST /api/v1/notes/:id/share
+func (h *NoteHandler) ShareNote(c *gin.Context) {
+	noteID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid note ID"})
+		return
+	}
+
+	// Body is optional: an empty request creates a read-only link that never expires
+	var req dtos.CreateSharedLinkRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	userID, _ := c.Get("user_id")
+
+	link, err := h.noteService.CreateSharedLink(c.Request.Context(), noteID, userID.(int64), req.Permission, req.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, domain.ErrNoteNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
+			return
+		}
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidSharePermission) || errors.Is(err, domain.ErrInvalidShareExpiry) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to share note"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data": gin.H{
+			"link": link,
+			"path": "/api/v1/shared/" + link.Token,
+		},
+	})
+}
+
+// ListSharedLinks handles GET /api/v1/notes/:id/share
+func (h *NoteHandler) ListSharedLinks(c *gin.Context) {
+	noteID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid note ID"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	links, err := h.noteService.ListSharedLinks(c.Request.Context(), noteID, userID.(int64))
+	if err != nil {
+		if errors.Is(err, domain.ErrNoteNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
+			return
+		}
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list shared links"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    links,
+	})
+}
+
+// RevokeSharedLink handles DELETE /api/v1/notes/:id/share/:link_id
+func (h *NoteHandler) RevokeSharedLink(c *gin.Context) {
+	noteID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid note ID"})
+		return
+	}
+
+	linkID, err := strconv.ParseInt(c.Param("link_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid link ID"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	if err := h.noteService.RevokeSharedLink(c.Request.Context(), noteID, linkID, userID.(int64)); err != nil {
+		if errors.Is(err, domain.ErrNoteNotFound) || errors.Is(err, domain.ErrSharedLinkNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "shared link not found"})
+			return
+		}
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to revoke shared link"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "shared link revoked",
+	})
+}
+
+// GetSharedNote handles GET /api/v1/shared/:token
+// Public endpoint: returns a read-only view of the note without owner details.
+func (h *NoteHandler) GetSharedNote(c *gin.Context) {
+	note, link, err := h.noteService.GetSharedNote(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		if errors.Is(err, domain.ErrSharedLinkNotFound) || errors.Is(err, domain.ErrNoteNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "shared note not found"})
+			return
+		}
+		if errors.Is(err, domain.ErrSharedLinkExpired) {
+			c.JSON(http.StatusGone, gin.H{"error": "shared link has expired"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get shared note"})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Robots-Tag", "noindex")
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    dtos.ToSharedNoteResponse(note, link),
+	})
+}
+
+// AddCollaborator handles POST /api/v1/notes/:id/collaborators
+func (h *NoteHandler) AddCollaborator(c *gin.Context) {
+	noteID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid note ID"})
+		return
+	}
+
+	var req dtos.AddCollaboratorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	collaborator, err := h.noteService.AddCollaborator(c.Request.Context(), noteID, userID.(int64), req.Email, req.Role)
+	if err != nil {
+		if errors.Is(err, domain.ErrNoteNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
+			return
+		}
+		if errors.Is(err, domain.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidCollaboratorRole) || errors.Is(err, domain.ErrCannotCollaborateOwn) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to add collaborator"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    collaborator,
+	})
+}
+
+// ListCollaborators handles GET /api/v1/notes/:id/collaborators
+func (h *NoteHandler) ListCollaborators(c *gin.Context) {
+	noteID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid note ID"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	collaborators, err := h.noteService.ListCollaborators(c.Request.Context(), noteID, userID.(int64))
+	if err != nil {
+		if errors.Is(err, domain.ErrNoteNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "note not found"})
+			return
+		}
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list collaborators"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    collaborators,
+	})
+}
+
+// RemoveCollaborator handles DELETE /api/v1/notes/:id/collaborators/:user_id
+func (h *NoteHandler) RemoveCollaborator(c *gin.Context) {
+	noteID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid note ID"})
+		return
+	}
+
+	collaboratorUserID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	if err := h.noteService.RemoveCollaborator(c.Request.Context(), noteID, userID.(int64), collaboratorUserID); err != nil {
+		if errors.Is(err, domain.ErrNoteNotFound) || errors.Is(err, domain.ErrCollaboratorNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "collaborator not found"})
+			return
+		}
+		if errors.Is(err, domain.ErrUnauthorizedAccess) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove collaborator"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "collaborator removed",
+	})
+}
@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/yourusername/notinoteapp/internal/adapters/primary/http/dtos"
+	"github.com/yourusername/notinoteapp/internal/application/services"
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+	"github.com/yourusername/notinoteapp/internal/core/ports"
+)
+
+// NotificationHandler handles notification-related HTTP requests
+type NotificationHandler struct {
+	notificationService *services.NotificationService
+	logger              *logrus.Logger
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(notificationService *services.NotificationService, logger *logrus.Logger) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+		logger:              logger,
+	}
+}
+
+// ListLogs returns the current user's notification history
+// GET /api/v1/notifications/logs?status=failed&from=...&to=...&page=1&limit=20
+func (h *NotificationHandler) ListLogs(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(services.DefaultNotificationLogLimit)))
+	if limit < 1 || limit > services.MaxNotificationLogLimit {
+		limit = services.DefaultNotificationLogLimit
+	}
+
+	params := ports.NotificationLogQueryParams{
+		Limit:  limit,
+		Offset: (page - 1) * limit,
+	}
+
+	if statusStr := c.Query("status"); statusStr != "" {
+		status := domain.NotificationStatus(statusStr)
+		params.Status = &status
+	}
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		fromDate, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Invalid 'from' date, expected RFC3339",
+			})
+			return
+		}
+		params.FromDate = &fromDate
+	}
+
+	if toStr := c.Query("to"); toStr != "" {
+		toDate, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Invalid 'to' date, expected RFC3339",
+			})
+			return
+		}
+		params.ToDate = &toDate
+	}
+
+	logs, total, err := h.notificationService.ListLogs(c.Request.Context(), userID, params)
+	if err != nil {
+		if err == domain.ErrInvalidNotificationStatus {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Invalid status filter",
+			})
+			return
+		}
+		if err == domain.ErrValidation {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "'from' must be before 'to'",
+			})
+			return
+		}
+		h.logger.WithError(err).Error("Failed to list notification logs")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to list notification logs",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"logs":       logs,
+			"pagination": dtos.NewPaginationResponse(page, limit, total),
+		},
+	})
+}
+
+// GetPreferences returns the current user's notification preferences
+// GET /api/v1/notifications/preferences
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+
+	prefs, err := h.notificationService.GetPreferences(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get notification preferences")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to get notification preferences",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    prefs,
+	})
+}
+
+// UpdatePreferences updates the current user's notification preferences
+// PUT /api/v1/notifications/preferences
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+
+	var req services.UpdateNotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	prefs, err := h.notificationService.UpdatePreferences(c.Request.Context(), userID, req)
+	if err != nil {
+		if err == domain.ErrInvalidRepeatType {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Invalid reminder type in muted_repeat_types",
+			})
+			return
+		}
+		h.logger.WithError(err).Error("Failed to update notification preferences")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to update notification preferences",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    prefs,
+	})
+}
+
+// RegisterWebhookRequest represents a request to register a webhook
+type RegisterWebhookRequest struct {
+	URL string `json:"url" binding:"required"`
+}
+
+// RegisterWebhook registers a webhook URL that receives signed reminder events.
+// The signing secret is only returned in this response.
+// POST /api/v1/notifications/webhooks
+func (h *NotificationHandler) RegisterWebhook(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+
+	var req RegisterWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	webhook, err := h.notificationService.RegisterWebhook(c.Request.Context(), userID, req.URL)
+	if err != nil {
+		if err == domain.ErrInvalidWebhookURL || err == domain.ErrWebhookURLNotAllowed {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		h.logger.WithError(err).Error("Failed to register webhook")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to register webhook",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data": gin.H{
+			"webhook": webhook,
+			"secret":  webhook.Secret,
+		},
+	})
+}
+
+// streamKeepAliveInterval is how often a comment is sent on idle streams so
+// proxies don't close the connection
+const streamKeepAliveInterval = 25 * time.Second
+
+// Stream holds the connection open and emits Server-Sent Events as the current
+// user's reminders fire
+// GET /api/v1/notifications/stream
+func (h *NotificationHandler) Stream(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+
+	events, unsubscribe, err := h.notificationService.Subscribe(userID)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"success": false,
+			"error":   "Notification stream is not available",
+		})
+		return
+	}
+	defer unsubscribe()
+
+	// The stream outlives the server write timeout
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.WithError(err).Warn("Failed to clear write deadline; notification stream will be cut at the server write timeout")
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	io.WriteString(c.Writer, ": connected\n\n")
+	c.Writer.Flush()
+
+	h.logger.WithField("user_id", userID).Debug("Notification stream opened")
+
+	keepAlive := time.NewTicker(streamKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent(event.Type, event)
+			return true
+		case <-keepAlive.C:
+			io.WriteString(w, ": keep-alive\n\n")
+			return true
+		}
+	})
+
+	h.logger.WithField("user_id", userID).Debug("Notification stream closed")
+}
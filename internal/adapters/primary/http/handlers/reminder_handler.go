@@ -1,15 +1,20 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+	"github.com/yourusername/notinoteapp/internal/adapters/primary/http/dtos"
 	"github.com/yourusername/notinoteapp/internal/application/services"
 	"github.com/yourusername/notinoteapp/internal/core/domain"
 	"github.com/yourusername/notinoteapp/internal/core/ports"
+	"github.com/yourusername/notinoteapp/pkg/utils/ical"
+	"github.com/yourusername/notinoteapp/pkg/utils/timeparse"
 )
 
 // ReminderHandler handles reminder-related HTTP requests
@@ -28,28 +33,43 @@ func NewReminderHandler(reminderService *services.ReminderService, logger *logru
 
 // CreateReminderRequest represents a reminder creation request
 type CreateReminderRequest struct {
-	Title        string               `json:"title" binding:"required,min=1,max=255"`
-	Message      string               `json:"message"`
-	ScheduledAt  time.Time            `json:"scheduled_at" binding:"required"`
-	RepeatType   domain.RepeatType    `json:"repeat_type"`
-	RepeatConfig *domain.RepeatConfig `json:"repeat_config"`
-	RepeatEndAt  *time.Time           `json:"repeat_end_at"`
+	Title        string                   `json:"title" binding:"required,min=1,max=255"`
+	Message      string                   `json:"message"`
+	ScheduledAt  time.Time                `json:"scheduled_at"`
+	When         string                   `json:"when"`     // e.g., "tomorrow 9am", "in 2 hours"; alternative to scheduled_at
+	Timezone     string                   `json:"timezone"` // IANA name used to resolve "when", defaults to UTC
+	RepeatType   domain.RepeatType        `json:"repeat_type"`
+	RepeatConfig *domain.RepeatConfig     `json:"repeat_config"`
+	RepeatEndAt  *time.Time               `json:"repeat_end_at"`
+	Payload      *domain.ReminderPayload  `json:"payload"`  // click URL, sound and badge; defaults to opening the note
+	Relative     *domain.RelativeSchedule `json:"relative"` // fire relative to a date property; the schedule is the fallback
 }
 
 // UpdateReminderRequest represents a reminder update request
 type UpdateReminderRequest struct {
-	Title        *string              `json:"title"`
-	Message      *string              `json:"message"`
-	ScheduledAt  *time.Time           `json:"scheduled_at"`
-	RepeatType   *domain.RepeatType   `json:"repeat_type"`
-	RepeatConfig *domain.RepeatConfig `json:"repeat_config"`
-	RepeatEndAt  *time.Time           `json:"repeat_end_at"`
-	IsEnabled    *bool                `json:"is_enabled"`
+	Title        *string                  `json:"title"`
+	Message      *string                  `json:"message"`
+	ScheduledAt  *time.Time               `json:"scheduled_at"`
+	RepeatType   *domain.RepeatType       `json:"repeat_type"`
+	RepeatConfig *domain.RepeatConfig     `json:"repeat_config"`
+	RepeatEndAt  *time.Time               `json:"repeat_end_at"`
+	IsEnabled    *bool                    `json:"is_enabled"`
+	Payload      *domain.ReminderPayload  `json:"payload"`  // {} restores the defaults
+	Relative     *domain.RelativeSchedule `json:"relative"` // {} makes the reminder absolute again
 }
 
 // SnoozeRequest represents a snooze request
 type SnoozeRequest struct {
-	Duration string `json:"duration" binding:"required"` // e.g., "10m", "1h", "1d"
+	Duration string `json:"duration" binding:"required"` // e.g., "10m", "1d", "in 2 hours", "tomorrow 9am"
+	Timezone string `json:"timezone"`                    // IANA name used to resolve times of day, defaults to UTC
+}
+
+// parseTimezone resolves an optional IANA timezone name, defaulting to UTC
+func parseTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(name)
 }
 
 // Create creates a new reminder for a note
@@ -75,21 +95,17 @@ func (h *ReminderHandler) Create(c *gin.Context) {
 		return
 	}
 
-	serviceReq := services.CreateReminderRequest{
-		Title:        req.Title,
-		Message:      req.Message,
-		ScheduledAt:  req.ScheduledAt,
-		RepeatType:   req.RepeatType,
-		RepeatConfig: req.RepeatConfig,
-		RepeatEndAt:  req.RepeatEndAt,
+	serviceReq, ok := toServiceCreateRequest(c, req)
+	if !ok {
+		return
 	}
 
 	reminder, err := h.reminderService.CreateReminder(c.Request.Context(), userID, noteID, serviceReq)
 	if err != nil {
-		if err == domain.ErrUnauthorizedAccess {
-			c.JSON(http.StatusForbidden, gin.H{
+		if err == domain.ErrNoteNotFound {
+			c.JSON(http.StatusNotFound, gin.H{
 				"success": false,
-				"error":   "Access denied to this note",
+				"error":   "Note not found",
 			})
 			return
 		}
@@ -100,6 +116,20 @@ func (h *ReminderHandler) Create(c *gin.Context) {
 			})
 			return
 		}
+		if err == domain.ErrInvalidReminderPayload {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Invalid notification payload",
+			})
+			return
+		}
+		if err == domain.ErrInvalidRelativeSchedule {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Invalid relative schedule: only one-time reminders can follow a date property",
+			})
+			return
+		}
 		h.logger.WithError(err).Error("Failed to create reminder")
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -114,6 +144,107 @@ func (h *ReminderHandler) Create(c *gin.Context) {
 	})
 }
 
+// toServiceCreateRequest resolves the request's schedule, writing a 400 and
+// returning false if it is missing or can't be parsed
+func toServiceCreateRequest(c *gin.Context, req CreateReminderRequest) (services.CreateReminderRequest, bool) {
+	if req.When != "" {
+		loc, err := parseTimezone(req.Timezone)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Invalid timezone",
+			})
+			return services.CreateReminderRequest{}, false
+		}
+		scheduledAt, err := timeparse.ParseWhen(req.When, time.Now(), loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Invalid schedule: " + err.Error(),
+			})
+			return services.CreateReminderRequest{}, false
+		}
+		req.ScheduledAt = scheduledAt
+	}
+
+	if req.ScheduledAt.IsZero() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Either scheduled_at or when is required",
+		})
+		return services.CreateReminderRequest{}, false
+	}
+
+	return services.CreateReminderRequest{
+		Title:        req.Title,
+		Message:      req.Message,
+		ScheduledAt:  req.ScheduledAt,
+		RepeatType:   req.RepeatType,
+		RepeatConfig: req.RepeatConfig,
+		RepeatEndAt:  req.RepeatEndAt,
+		Payload:      req.Payload,
+		Relative:     req.Relative,
+	}, true
+}
+
+// Preview returns when a reminder would fire, without creating it
+// POST /api/v1/reminders/preview?count=5
+func (h *ReminderHandler) Preview(c *gin.Context) {
+	count, err := strconv.Atoi(c.DefaultQuery("count", "5"))
+	if err != nil || count < 1 || count > services.MaxPreviewOccurrences {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "count must be between 1 and " + strconv.Itoa(services.MaxPreviewOccurrences),
+		})
+		return
+	}
+
+	var req CreateReminderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	serviceReq, ok := toServiceCreateRequest(c, req)
+	if !ok {
+		return
+	}
+
+	occurrences, err := h.reminderService.PreviewOccurrences(c.Request.Context(), serviceReq, count)
+	if err != nil {
+		if err == domain.ErrInvalidScheduleTime {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Schedule time must be in the future",
+			})
+			return
+		}
+		if err == domain.ErrInvalidRepeatType || err == domain.ErrInvalidRepeatConfig || err == domain.ErrInvalidReminderTitle {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		h.logger.WithError(err).Error("Failed to preview reminder")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to preview reminder",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"occurrences": occurrences,
+		},
+	})
+}
+
 // ListByNote returns all reminders for a specific note
 // GET /api/v1/notes/:id/reminders
 func (h *ReminderHandler) ListByNote(c *gin.Context) {
@@ -130,10 +261,10 @@ func (h *ReminderHandler) ListByNote(c *gin.Context) {
 
 	reminders, err := h.reminderService.ListNoteReminders(c.Request.Context(), userID, noteID)
 	if err != nil {
-		if err == domain.ErrUnauthorizedAccess {
-			c.JSON(http.StatusForbidden, gin.H{
+		if err == domain.ErrNoteNotFound {
+			c.JSON(http.StatusNotFound, gin.H{
 				"success": false,
-				"error":   "Access denied to this note",
+				"error":   "Note not found",
 			})
 			return
 		}
@@ -153,47 +284,68 @@ func (h *ReminderHandler) ListByNote(c *gin.Context) {
 	})
 }
 
-// List returns all reminders for the current user
-// GET /api/v1/reminders
+// List returns a page of the current user's reminders
+// GET /api/v1/reminders?page=1&limit=20
 func (h *ReminderHandler) List(c *gin.Context) {
 	userID := c.GetInt64("user_id")
 
-	// Parse query parameters
-	var params *ports.ReminderQueryParams
-	if c.Query("enabled") != "" || c.Query("from") != "" || c.Query("to") != "" {
-		params = &ports.ReminderQueryParams{}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(services.DefaultReminderListLimit)))
+	if limit < 1 || limit > services.MaxReminderListLimit {
+		limit = services.DefaultReminderListLimit
+	}
 
-		if enabledStr := c.Query("enabled"); enabledStr != "" {
-			enabled := enabledStr == "true"
-			params.IsEnabled = &enabled
-		}
+	// Parse query parameters; notes are loaded so each reminder shows its note's title
+	params := &ports.ReminderQueryParams{
+		Limit:       limit,
+		Offset:      (page - 1) * limit,
+		IncludeNote: true,
+	}
+	if enabledStr := c.Query("enabled"); enabledStr != "" {
+		enabled := enabledStr == "true"
+		params.IsEnabled = &enabled
+	}
 
-		if fromStr := c.Query("from"); fromStr != "" {
-			if fromDate, err := time.Parse(time.RFC3339, fromStr); err == nil {
-				params.FromDate = &fromDate
-			}
+	if noteIDStr := c.Query("note_id"); noteIDStr != "" {
+		noteID, err := strconv.ParseInt(noteIDStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Invalid note ID",
+			})
+			return
 		}
+		params.NoteID = &noteID
+	}
 
-		if toStr := c.Query("to"); toStr != "" {
-			if toDate, err := time.Parse(time.RFC3339, toStr); err == nil {
-				params.ToDate = &toDate
-			}
+	if repeatTypeStr := c.Query("repeat_type"); repeatTypeStr != "" {
+		repeatType := domain.RepeatType(repeatTypeStr)
+		if !domain.IsValidRepeatType(repeatType) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Invalid repeat type",
+			})
+			return
 		}
+		params.RepeatType = &repeatType
+	}
 
-		if limitStr := c.Query("limit"); limitStr != "" {
-			if limit, err := strconv.Atoi(limitStr); err == nil {
-				params.Limit = limit
-			}
+	if fromStr := c.Query("from"); fromStr != "" {
+		if fromDate, err := time.Parse(time.RFC3339, fromStr); err == nil {
+			params.FromDate = &fromDate
 		}
+	}
 
-		if offsetStr := c.Query("offset"); offsetStr != "" {
-			if offset, err := strconv.Atoi(offsetStr); err == nil {
-				params.Offset = offset
-			}
+	if toStr := c.Query("to"); toStr != "" {
+		if toDate, err := time.Parse(time.RFC3339, toStr); err == nil {
+			params.ToDate = &toDate
 		}
 	}
 
-	reminders, err := h.reminderService.ListUserReminders(c.Request.Context(), userID, params)
+	reminders, total, err := h.reminderService.ListUserReminders(c.Request.Context(), userID, params)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to list user reminders")
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -203,14 +355,218 @@ func (h *ReminderHandler) List(c *gin.Context) {
 		return
 	}
 
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    dtos.ToReminderListResponse(reminders, page, limit, total),
+	})
+}
+
+// Upcoming returns when the user's reminders fire over the next days
+// GET /api/v1/reminders/upcoming?days=7
+func (h *ReminderHandler) Upcoming(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "7"))
+	if err != nil || days < 1 || days > services.MaxUpcomingDays {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "days must be between 1 and " + strconv.Itoa(services.MaxUpcomingDays),
+		})
+		return
+	}
+
+	occurrences, err := h.reminderService.UpcomingReminders(c.Request.Context(), userID, days)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list upcoming reminders")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to list upcoming reminders",
+		})
+		return
+	}
+
+	upcoming := make([]dtos.UpcomingReminderResponse, len(occurrences))
+	for i, occurrence := range occurrences {
+		upcoming[i] = dtos.UpcomingReminderResponse{
+			TriggerAt: occurrence.TriggerAt,
+			Reminder:  dtos.ToReminderResponse(occurrence.Reminder),
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data": gin.H{
-			"reminders": reminders,
+			"upcoming": upcoming,
+		},
+	})
+}
+
+// CreateCalendarFeed issues a feed-only token for subscribing to the reminder
+// calendar, replacing any previous one. The token is only shown once.
+// POST /api/v1/reminders/calendar/token
+func (h *ReminderHandler) CreateCalendarFeed(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+
+	token, err := h.reminderService.CreateCalendarFeedToken(c.Request.Context(), userID)
+	if err != nil {
+		if err == domain.ErrCalendarFeedUnavailable {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to create calendar feed",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data": gin.H{
+			"token": token,
+			"url":   "/api/v1/reminders/calendar.ics?token=" + token,
 		},
 	})
 }
 
+// RevokeCalendarFeed invalidates the user's calendar feed token, so existing
+// subscriptions stop updating
+// DELETE /api/v1/reminders/calendar/token
+func (h *ReminderHandler) RevokeCalendarFeed(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+
+	if err := h.reminderService.RevokeCalendarFeedToken(c.Request.Context(), userID); err != nil {
+		if err == domain.ErrCalendarFeedUnavailable {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to revoke calendar feed",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Calendar feed revoked",
+	})
+}
+
+// Calendar renders the user's enabled reminders as an iCalendar feed that
+// calendar apps can subscribe to
+// GET /api/v1/reminders/calendar.ics?token=...
+func (h *ReminderHandler) Calendar(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+
+	reminders, err := h.reminderService.CalendarReminders(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list calendar reminders")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to list reminders",
+		})
+		return
+	}
+
+	calendar := ical.Calendar{
+		ProductID: "-//NotiNote//Reminders//EN",
+		Name:      "NotiNote Reminders",
+		Events:    make([]ical.Event, len(reminders)),
+	}
+	for i, reminder := range reminders {
+		calendar.Events[i] = reminderEvent(reminder)
+	}
+
+	var body strings.Builder
+	if err := calendar.Write(&body); err != nil {
+		h.logger.WithError(err).Error("Failed to render reminder calendar")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to render calendar",
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="reminders.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(body.String()))
+}
+
+// reminderEvent maps a reminder to a calendar event, titled after its note
+func reminderEvent(reminder *domain.Reminder) ical.Event {
+	summary := reminder.Title
+	if reminder.Note != nil && reminder.Note.Title != "" {
+		summary = reminder.Note.Title
+	}
+
+	return ical.Event{
+		UID:         fmt.Sprintf("reminder-%d@notinote", reminder.ID),
+		Start:       reminder.ScheduledAt,
+		Stamp:       reminder.UpdatedAt,
+		Summary:     summary,
+		Description: reminder.Message,
+		RRule:       reminderRRule(reminder),
+		Alarm:       true,
+	}
+}
+
+// reminderRRule maps a reminder's repeat settings to an RRULE value, or
+// returns "" for one-off reminders
+func reminderRRule(reminder *domain.Reminder) string {
+	var parts []string
+	config := reminder.RepeatConfig
+	if config == nil {
+		config = &domain.RepeatConfig{}
+	}
+
+	switch reminder.RepeatType {
+	case domain.RepeatTypeDaily:
+		parts = append(parts, "FREQ=DAILY")
+
+	case domain.RepeatTypeWeekly:
+		parts = append(parts, "FREQ=WEEKLY")
+		if len(config.Days) > 0 {
+			days := make([]string, len(config.Days))
+			for i, day := range config.Days {
+				days[i] = ical.Weekday(time.Weekday(day))
+			}
+			parts = append(parts, "BYDAY="+strings.Join(days, ","))
+		}
+
+	case domain.RepeatTypeMonthly:
+		parts = append(parts, "FREQ=MONTHLY")
+		day := config.Day
+		if day == 0 {
+			day = reminder.ScheduledAt.Day()
+		}
+		if day > 28 && !config.SkipMissingDay {
+			// Fire on the last of days 28..day the month has, so short
+			// months fall back to their last day like the scheduler does
+			days := make([]string, 0, day-27)
+			for d := 28; d <= day; d++ {
+				days = append(days, strconv.Itoa(d))
+			}
+			parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","), "BYSETPOS=-1")
+		} else {
+			parts = append(parts, "BYMONTHDAY="+strconv.Itoa(day))
+		}
+
+	default:
+		return ""
+	}
+
+	if reminder.RepeatEndAt != nil {
+		parts = append(parts, ical.Until(*reminder.RepeatEndAt))
+	}
+	return strings.Join(parts, ";")
+}
+
 // Get returns a specific reminder
 // GET /api/v1/reminders/:id
 func (h *ReminderHandler) Get(c *gin.Context) {
@@ -286,6 +642,7 @@ func (h *ReminderHandler) Update(c *gin.Context) {
 		RepeatConfig: req.RepeatConfig,
 		RepeatEndAt:  req.RepeatEndAt,
 		IsEnabled:    req.IsEnabled,
+		Payload:      req.Payload,
 	}
 
 	reminder, err := h.reminderService.UpdateReminder(c.Request.Context(), userID, reminderID, serviceReq)
@@ -311,6 +668,20 @@ func (h *ReminderHandler) Update(c *gin.Context) {
 			})
 			return
 		}
+		if err == domain.ErrInvalidReminderPayload {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Invalid notification payload",
+			})
+			return
+		}
+		if err == domain.ErrInvalidRelativeSchedule {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Invalid relative schedule: only one-time reminders can follow a date property",
+			})
+			return
+		}
 		h.logger.WithError(err).Error("Failed to update reminder")
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -436,28 +807,33 @@ func (h *ReminderHandler) Snooze(c *gin.Context) {
 		return
 	}
 
-	// Parse duration string (e.g., "10m", "1h", "1d")
-	duration, err := time.ParseDuration(req.Duration)
+	loc, err := parseTimezone(req.Timezone)
 	if err != nil {
-		// Try parsing as days
-		if len(req.Duration) > 1 && req.Duration[len(req.Duration)-1] == 'd' {
-			days, parseErr := strconv.Atoi(req.Duration[:len(req.Duration)-1])
-			if parseErr == nil {
-				duration = time.Duration(days) * 24 * time.Hour
-			} else {
-				c.JSON(http.StatusBadRequest, gin.H{
-					"success": false,
-					"error":   "Invalid duration format. Use formats like '10m', '1h', '1d'",
-				})
-				return
-			}
-		} else {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"success": false,
-				"error":   "Invalid duration format. Use formats like '10m', '1h', '1d'",
-			})
-			return
-		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid timezone",
+		})
+		return
+	}
+
+	// Parse snooze expression (e.g., "10m", "1d", "in 2 hours", "tomorrow 9am")
+	now := time.Now()
+	snoozeUntil, err := timeparse.ParseWhen(req.Duration, now, loc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid duration: " + err.Error(),
+		})
+		return
+	}
+	duration := snoozeUntil.Sub(now)
+
+	if duration <= 0 || duration > domain.MaxSnoozeDuration {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Snooze duration must be positive and at most 30 days",
+		})
+		return
 	}
 
 	reminder, err := h.reminderService.SnoozeReminder(c.Request.Context(), userID, reminderID, duration)
@@ -476,6 +852,20 @@ func (h *ReminderHandler) Snooze(c *gin.Context) {
 			})
 			return
 		}
+		if err == domain.ErrSnoozeLimitReached {
+			c.JSON(http.StatusConflict, gin.H{
+				"success": false,
+				"error":   "Snooze limit reached for this reminder",
+			})
+			return
+		}
+		if err == domain.ErrInvalidSnoozeDuration {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Invalid snooze duration",
+			})
+			return
+		}
 		h.logger.WithError(err).Error("Failed to snooze reminder")
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
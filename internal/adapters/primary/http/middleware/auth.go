@@ -1,16 +1,30 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/yourusername/notinoteapp/internal/core/domain"
 	"github.com/yourusername/notinoteapp/pkg/utils"
 )
 
-// AuthMiddleware validates JWT tokens
-func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
+// TokenValidator validates access tokens and returns their claims
+type TokenValidator interface {
+	ParseToken(token string) (*utils.JWTClaims, error)
+}
+
+// UserStatusChecker reports whether a token's user still exists and is active,
+// so tokens issued before an account was deactivated stop working
+type UserStatusChecker interface {
+	IsUserActive(ctx context.Context, userID int64) (bool, error)
+}
+
+// AuthMiddleware validates JWT tokens. When users is non-nil, tokens belonging
+// to deactivated or deleted accounts are rejected.
+func AuthMiddleware(tokens TokenValidator, users UserStatusChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get token from Authorization header
 		authHeader := c.GetHeader("Authorization")
@@ -34,13 +48,7 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 			return
 		}
 
-		tokenString := parts[1]
-
-		// Parse and validate token
-		token, err := jwt.ParseWithClaims(tokenString, &utils.JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-			return []byte(jwtSecret), nil
-		})
-
+		claims, err := tokens.ParseToken(parts[1])
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"success": false,
@@ -50,30 +58,136 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 			return
 		}
 
-		if !token.Valid {
+		if !checkUserActive(c, users, claims.UserID) {
+			return
+		}
+
+		// Set user ID in context
+		c.Set("user_id", claims.UserID)
+		c.Set("email", claims.Email)
+		c.Set("role", claims.Role)
+
+		c.Next()
+	}
+}
+
+// CalendarFeedResolver maps calendar feed tokens to the user they belong to
+type CalendarFeedResolver interface {
+	ResolveCalendarFeedToken(ctx context.Context, token string) (int64, error)
+}
+
+// CalendarFeedAuthMiddleware authenticates calendar apps subscribing to a feed
+// URL, which can't set headers. The feed token is read from the token query
+// parameter and only identifies the user; it carries no other claims.
+func CalendarFeedAuthMiddleware(feeds CalendarFeedResolver, users UserStatusChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.Query("token")
+		if token == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"error":   "Calendar token is required",
+			})
+			c.Abort()
+			return
+		}
+
+		userID, err := feeds.ResolveCalendarFeedToken(c.Request.Context(), token)
+		if err != nil {
+			if errors.Is(err, domain.ErrCalendarFeedNotFound) {
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"success": false,
+					"error":   "Invalid or revoked calendar token",
+				})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"success": false,
+					"error":   "Failed to verify calendar token",
+				})
+			}
+			c.Abort()
+			return
+		}
+
+		if !checkUserActive(c, users, userID) {
+			return
+		}
+
+		c.Set("user_id", userID)
+
+		c.Next()
+	}
+}
+
+// queryToken extracts the access token from the Authorization header or the
+// access_token query parameter
+func queryToken(c *gin.Context) string {
+	if parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2); len(parts) == 2 && parts[0] == "Bearer" {
+		return parts[1]
+	}
+	return c.Query("access_token")
+}
+
+// tokenAuthMiddleware validates the token returned by extract
+func tokenAuthMiddleware(tokens TokenValidator, users UserStatusChecker, extract func(c *gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString := extract(c)
+		if tokenString == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"success": false,
-				"error":   "Invalid token",
+				"error":   "Access token is required",
 			})
 			c.Abort()
 			return
 		}
 
-		// Extract claims
-		claims, ok := token.Claims.(*utils.JWTClaims)
-		if !ok {
+		claims, err := tokens.ParseToken(tokenString)
+		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"success": false,
-				"error":   "Invalid token claims",
+				"error":   "Invalid or expired token",
 			})
 			c.Abort()
 			return
 		}
 
+		if !checkUserActive(c, users, claims.UserID) {
+			return
+		}
+
 		// Set user ID in context
 		c.Set("user_id", claims.UserID)
 		c.Set("email", claims.Email)
+		c.Set("role", claims.Role)
 
 		c.Next()
 	}
 }
+
+// checkUserActive aborts the request unless the user is still active. It
+// returns true when the request may continue.
+func checkUserActive(c *gin.Context, users UserStatusChecker, userID int64) bool {
+	if users == nil {
+		return true
+	}
+
+	active, err := users.IsUserActive(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to verify account",
+		})
+		c.Abort()
+		return false
+	}
+
+	if !active {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   "Account is deactivated",
+		})
+		c.Abort()
+		return false
+	}
+
+	return true
+}
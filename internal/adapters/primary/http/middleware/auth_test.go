@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/notinoteapp/pkg/utils"
+)
+
+// stubUserStatus reports users as active unless they are listed as deactivated
+type stubUserStatus struct {
+	deactivated map[int64]bool
+}
+
+func (s *stubUserStatus) IsUserActive(ctx context.Context, userID int64) (bool, error) {
+	return !s.deactivated[userID], nil
+}
+
+func TestAuthMiddleware_RejectsDeactivatedUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tokens := utils.NewJWTService("test-secret", "test", time.Hour, time.Hour)
+	users := &stubUserStatus{deactivated: map[int64]bool{}}
+
+	router := gin.New()
+	router.GET("/me", AuthMiddleware(tokens, users), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	router.GET("/ws", WebSocketAuthMiddleware(tokens, users), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	token, err := tokens.GenerateToken(1, "user@example.com")
+	require.NoError(t, err)
+
+	request := func(path string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, request("/me"))
+	assert.Equal(t, http.StatusOK, request("/ws"))
+
+	// The token was issued before deactivation and is still unexpired
+	users.deactivated[1] = true
+
+	assert.Equal(t, http.StatusUnauthorized, request("/me"))
+	assert.Equal(t, http.StatusUnauthorized, request("/ws"))
+}
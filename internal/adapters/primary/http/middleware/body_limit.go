@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BodyLimit caps request bodies at maxBytes and responds 413 Request Entity Too
+// Large when a body exceeds it. overrides sets a different limit for specific
+// routes, keyed by method and route pattern, e.g. "PUT /api/v1/notes/:id/blocks".
+//
+// The body is read up front so oversized requests are rejected before any
+// handler starts decoding them.
+func BodyLimit(maxBytes int64, overrides map[string]int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		limit := maxBytes
+		if override, ok := overrides[c.Request.Method+" "+c.FullPath()]; ok {
+			limit = override
+		}
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > limit {
+			abortTooLarge(c)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, limit))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				abortTooLarge(c)
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Failed to read request body",
+			})
+			return
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+	}
+}
+
+func abortTooLarge(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+		"success": false,
+		"error":   "Request body too large",
+	})
+}
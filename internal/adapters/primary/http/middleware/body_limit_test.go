@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newBodyLimitRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(BodyLimit(8, map[string]int64{"PUT /big": 64}))
+
+	echo := func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	}
+	router.POST("/small", echo)
+	router.PUT("/big", echo)
+	return router
+}
+
+func TestBodyLimit(t *testing.T) {
+	router := newBodyLimitRouter()
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		body    string
+		chunked bool
+		status  int
+	}{
+		{"within limit", http.MethodPost, "/small", "12345678", false, http.StatusOK},
+		{"over limit", http.MethodPost, "/small", "123456789", false, http.StatusRequestEntityTooLarge},
+		{"over limit without content length", http.MethodPost, "/small", "123456789", true, http.StatusRequestEntityTooLarge},
+		{"route override", http.MethodPut, "/big", strings.Repeat("x", 64), false, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusOK {
+				assert.Equal(t, tt.body, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/notinoteapp/pkg/config"
+	"github.com/yourusername/notinoteapp/pkg/logger"
+)
+
+// CORS returns a middleware that allows cross-origin requests from the configured
+// origins only. Requests from other origins are rejected with 403 instead of having
+// their origin reflected back, and preflight OPTIONS requests are answered directly.
+//
+// Credentialed requests are allowed for an explicit origin list. A "*" origin allows
+// every origin but disables credentials, since browsers refuse the combination and
+// it would otherwise hand any site the user's session.
+func CORS(cfg config.CORSConfig) gin.HandlerFunc {
+	corsConfig := cors.Config{
+		AllowMethods:  cfg.AllowedMethods,
+		AllowHeaders:  cfg.AllowedHeaders,
+		ExposeHeaders: []string{"Content-Length", RequestIDHeader},
+		MaxAge:        12 * time.Hour,
+	}
+
+	switch {
+	case containsWildcard(cfg.AllowedOrigins):
+		logger.Warn("CORS allows all origins; credentialed cross-origin requests are disabled")
+		corsConfig.AllowAllOrigins = true
+		corsConfig.AllowCredentials = false
+	case len(cfg.AllowedOrigins) == 0:
+		// No cross-origin access at all; same-origin requests are unaffected
+		corsConfig.AllowOriginFunc = func(string) bool { return false }
+	default:
+		corsConfig.AllowOrigins = cfg.AllowedOrigins
+		corsConfig.AllowCredentials = true
+	}
+
+	return cors.New(corsConfig)
+}
+
+func containsWildcard(origins []string) bool {
+	for _, origin := range origins {
+		if origin == "*" {
+			return true
+		}
+	}
+	return false
+}
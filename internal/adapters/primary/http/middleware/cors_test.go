@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/notinoteapp/pkg/config"
+)
+
+func newCORSRouter(origins ...string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CORS(config.CORSConfig{
+		AllowedOrigins: origins,
+		AllowedMethods: []string{"GET", "POST", "PATCH"},
+		AllowedHeaders: []string{"Authorization", "Content-Type"},
+	}))
+	router.GET("/notes", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func corsRequest(router *gin.Engine, method, origin string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/notes", nil)
+	req.Header.Set("Origin", origin)
+	if method == http.MethodOptions {
+		req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORS_AllowedOrigin(t *testing.T) {
+	router := newCORSRouter("https://app.example.com")
+
+	w := corsRequest(router, http.MethodGet, "https://app.example.com")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+
+	w = corsRequest(router, http.MethodOptions, "https://app.example.com")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Methods"), "PATCH")
+}
+
+func TestCORS_RejectsOtherOrigins(t *testing.T) {
+	router := newCORSRouter("https://app.example.com")
+
+	w := corsRequest(router, http.MethodGet, "https://evil.example.com")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+
+	w = corsRequest(newCORSRouter(), http.MethodGet, "https://app.example.com")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestCORS_WildcardDisablesCredentials(t *testing.T) {
+	router := newCORSRouter("*")
+
+	w := corsRequest(router, http.MethodGet, "https://anywhere.example.com")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+}
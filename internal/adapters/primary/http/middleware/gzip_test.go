@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newGzipRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Gzip(64))
+
+	router.GET("/large", func(c *gin.Context) {
+		c.String(http.StatusOK, strings.Repeat("note ", 100))
+	})
+	router.GET("/small", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	router.GET("/image", func(c *gin.Context) {
+		c.Data(http.StatusOK, "image/png", []byte(strings.Repeat("x", 100)))
+	})
+	router.GET("/empty", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	return router
+}
+
+func doGzipRequest(router *gin.Engine, path, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGzip_CompressesLargeResponses(t *testing.T) {
+	w := doGzipRequest(newGzipRouter(), "/large", "deflate, gzip")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Contains(t, w.Header().Values("Vary"), "Accept-Encoding")
+
+	reader, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("note ", 100), string(body))
+}
+
+func TestGzip_PassesThrough(t *testing.T) {
+	router := newGzipRouter()
+
+	tests := []struct {
+		name           string
+		path           string
+		acceptEncoding string
+		status         int
+		body           string
+	}{
+		{"not accepted", "/large", "", http.StatusOK, strings.Repeat("note ", 100)},
+		{"refused with q=0", "/large", "gzip;q=0", http.StatusOK, strings.Repeat("note ", 100)},
+		{"below threshold", "/small", "gzip", http.StatusOK, "ok"},
+		{"already compressed type", "/image", "gzip", http.StatusOK, strings.Repeat("x", 100)},
+		{"no body", "/empty", "gzip", http.StatusNoContent, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doGzipRequest(router, tt.path, tt.acceptEncoding)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Empty(t, w.Header().Get("Content-Encoding"))
+			assert.Equal(t, tt.body, w.Body.String())
+		})
+	}
+}
+
+func TestGzip_StreamOutlivesWriteTimeout(t *testing.T) {
+	const writeTimeout = 100 * time.Millisecond
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Gzip(64))
+
+	var deadlineErr error
+	router.GET("/stream", func(c *gin.Context) {
+		// As the notification stream does, clear the server's write deadline
+		deadlineErr = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Status(http.StatusOK)
+		io.WriteString(c.Writer, "data: first\n\n")
+		c.Writer.Flush()
+
+		time.Sleep(3 * writeTimeout)
+		io.WriteString(c.Writer, "data: second\n\n")
+		c.Writer.Flush()
+	})
+
+	server := httptest.NewUnstartedServer(router)
+	server.Config.WriteTimeout = writeTimeout
+	server.Start()
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/stream", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	resp, err := server.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, deadlineErr)
+	assert.Empty(t, resp.Header.Get("Content-Encoding"))
+	assert.Equal(t, "data: first\n\ndata: second\n\n", string(body))
+}
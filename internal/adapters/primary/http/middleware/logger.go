@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -9,8 +10,14 @@ import (
 	"github.com/yourusername/notinoteapp/pkg/logger"
 )
 
-// Logger returns a gin middleware for logging HTTP requests
-func Logger() gin.HandlerFunc {
+// Logger returns a gin middleware for logging HTTP requests. Requests to
+// skipPaths (e.g. health checks) aren't logged unless they fail with a 5xx.
+func Logger(skipPaths []string) gin.HandlerFunc {
+	skip := make(map[string]struct{}, len(skipPaths))
+	for _, path := range skipPaths {
+		skip[path] = struct{}{}
+	}
+
 	return func(c *gin.Context) {
 		// Start timer
 		start := time.Now()
@@ -25,11 +32,14 @@ func Logger() gin.HandlerFunc {
 
 		// Get status code
 		statusCode := c.Writer.Status()
+		if _, ok := skip[path]; ok && statusCode < 500 {
+			return
+		}
 
-		// Build full path with query string
+		// Build full path with query string, without any credentials in it
 		fullPath := path
 		if raw != "" {
-			fullPath = path + "?" + raw
+			fullPath = path + "?" + redactQuery(raw)
 		}
 
 		// Get response size
@@ -45,6 +55,10 @@ func Logger() gin.HandlerFunc {
 			"size":     formatBytes(responseSize),
 		}
 
+		if requestID := GetRequestID(c); requestID != "" {
+			fields["request_id"] = requestID
+		}
+
 		// Add user ID if authenticated
 		if userID, exists := c.Get("user_id"); exists {
 			fields["user_id"] = userID
@@ -72,6 +86,29 @@ func Logger() gin.HandlerFunc {
 	}
 }
 
+// redactedQueryParams are query parameters that carry credentials, such as
+// the access tokens accepted by WebSocket and calendar feed requests
+var redactedQueryParams = map[string]struct{}{
+	"access_token": {},
+	"token":        {},
+}
+
+// redactQuery masks the values of credential-bearing query parameters,
+// keeping the rest of the query string as sent
+func redactQuery(raw string) string {
+	params := strings.Split(raw, "&")
+	for i, param := range params {
+		key, _, found := strings.Cut(param, "=")
+		if !found {
+			continue
+		}
+		if _, ok := redactedQueryParams[key]; ok {
+			params[i] = key + "=REDACTED"
+		}
+	}
+	return strings.Join(params, "&")
+}
+
 // formatLatency formats the latency duration for better readability
 func formatLatency(d time.Duration) string {
 	switch {
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/notinoteapp/pkg/logger"
+)
+
+func TestLogger_LogsRequestsWithRequestID(t *testing.T) {
+	var out bytes.Buffer
+	logger.Init("info", "json")
+	logger.SetOutput(&out)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID(), Logger([]string{"/health"}))
+	router.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/notes", func(c *gin.Context) { c.String(http.StatusNotFound, "missing") })
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Empty(t, out.String(), "skipped paths aren't logged")
+
+	req = httptest.NewRequest(http.MethodGet, "/notes?page=2", nil)
+	req.Header.Set(RequestIDHeader, "req-1")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 1)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, "warning", entry["level"])
+	assert.Equal(t, "GET", entry["method"])
+	assert.Equal(t, "/notes?page=2", entry["path"])
+	assert.Equal(t, float64(http.StatusNotFound), entry["status"])
+	assert.Equal(t, "7B", entry["size"])
+	assert.Equal(t, "req-1", entry["request_id"])
+	assert.Contains(t, entry, "ip")
+	assert.Contains(t, entry, "latency")
+}
+
+func TestLogger_RedactsTokensInQuery(t *testing.T) {
+	var out bytes.Buffer
+	logger.Init("info", "json")
+	logger.SetOutput(&out)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Logger(nil))
+	router.GET("/calendar.ics", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/calendar.ics?access_token=eyJhbGciOi.secret&lang=en&token=feed-secret", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &entry))
+	assert.Equal(t, "/calendar.ics?access_token=REDACTED&lang=en&token=REDACTED", entry["path"])
+	assert.NotContains(t, out.String(), "secret")
+}
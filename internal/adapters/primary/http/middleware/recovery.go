@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"strings"
+	"syscall"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/yourusername/notinoteapp/pkg/logger"
+)
+
+// Recovery turns a panicking handler into a 500 response instead of letting
+// it take the connection down. The panic value and stack trace are logged with
+// the request ID so the failure can be matched to the client's report.
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+
+			// net/http handles this one itself by quietly dropping the connection
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			fields := logrus.Fields{
+				"method": c.Request.Method,
+				"path":   c.Request.URL.Path,
+				"panic":  fmt.Sprint(recovered),
+			}
+			if requestID := GetRequestID(c); requestID != "" {
+				fields["request_id"] = requestID
+			}
+
+			// The client went away mid-response; there's nobody to answer
+			if isBrokenPipe(recovered) {
+				logger.WithFields(fields).Warn("Client connection closed during response")
+				c.Abort()
+				return
+			}
+
+			fields["stack"] = string(debug.Stack())
+			logger.WithFields(fields).Error("Recovered from panic")
+
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   "Internal server error",
+			})
+		}()
+
+		c.Next()
+	}
+}
+
+func isBrokenPipe(recovered interface{}) bool {
+	err, ok := recovered.(error)
+	if !ok {
+		return false
+	}
+
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		return false
+	}
+	var sysErr *os.SyscallError
+	if errors.As(opErr, &sysErr) {
+		return errors.Is(sysErr, syscall.EPIPE) || errors.Is(sysErr, syscall.ECONNRESET)
+	}
+	msg := strings.ToLower(opErr.Error())
+	return strings.Contains(msg, "broken pipe") || strings.Contains(msg, "connection reset by peer")
+}
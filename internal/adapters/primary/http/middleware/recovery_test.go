@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/notinoteapp/pkg/logger"
+)
+
+func TestRecovery_PanicReturns500AndLogsStack(t *testing.T) {
+	var out bytes.Buffer
+	logger.Init("info", "json")
+	logger.SetOutput(&out)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID(), Recovery())
+	router.GET("/boom", func(c *gin.Context) { panic("export exploded") })
+	router.GET("/ok", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/boom", nil)
+	req.Header.Set(RequestIDHeader, "req-42")
+	w := httptest.NewRecorder()
+	require.NotPanics(t, func() { router.ServeHTTP(w, req) })
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"success":false,"error":"Internal server error"}`, w.Body.String())
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &entry))
+	assert.Equal(t, "error", entry["level"])
+	assert.Equal(t, "export exploded", entry["panic"])
+	assert.Equal(t, "req-42", entry["request_id"])
+	assert.Contains(t, entry["stack"], "recovery_test.go")
+
+	// The server keeps serving
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ok", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRequestIDRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, GetRequestID(c)) })
+	return router
+}
+
+func TestRequestID(t *testing.T) {
+	router := newRequestIDRouter()
+
+	tests := []struct {
+		name     string
+		incoming string
+		kept     bool
+	}{
+		{"generated when missing", "", false},
+		{"kept when well-formed", "req-abc_123.4:5", true},
+		{"replaced when unsafe", "abc\r\nX-Injected: 1", false},
+		{"replaced when too long", strings.Repeat("a", 129), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+			if tt.incoming != "" {
+				req.Header.Set(RequestIDHeader, tt.incoming)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			id := w.Header().Get(RequestIDHeader)
+			assert.NotEmpty(t, id)
+			assert.Equal(t, id, w.Body.String(), "handlers see the same ID")
+			if tt.kept {
+				assert.Equal(t, tt.incoming, id)
+			} else {
+				assert.NotEqual(t, tt.incoming, id)
+				assert.Len(t, id, 32)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+)
+
+// RequireRole only lets through users whose access token carries the given
+// role. It must run after AuthMiddleware. The role is fixed when the token is
+// issued, so a role change applies once the user's access token is renewed.
+func RequireRole(role domain.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, exists := c.Get("user_id"); !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"error":   "User not authenticated",
+			})
+			c.Abort()
+			return
+		}
+
+		if domain.Role(c.GetString("role")) != role {
+			c.JSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "Insufficient permissions",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+	"github.com/yourusername/notinoteapp/pkg/utils"
+)
+
+func newRoleRouter(tokens *utils.JWTService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin", AuthMiddleware(tokens, nil), RequireRole(domain.RoleAdmin), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestRequireRole(t *testing.T) {
+	tokens := utils.NewJWTService("test-secret", "test", time.Hour, time.Hour)
+	router := newRoleRouter(tokens)
+
+	adminToken, err := tokens.GenerateTokenWithRole(1, "admin@example.com", "admin")
+	require.NoError(t, err)
+	userToken, err := tokens.GenerateTokenWithRole(2, "user@example.com", "user")
+	require.NoError(t, err)
+	roleless, err := tokens.GenerateToken(3, "old@example.com")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		token  string
+		status int
+	}{
+		{"admin role", adminToken, http.StatusOK},
+		{"wrong role", userToken, http.StatusForbidden},
+		{"no role claim", roleless, http.StatusForbidden},
+		{"no token", "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestRequireRole_WithoutAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin", RequireRole(domain.RoleAdmin), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
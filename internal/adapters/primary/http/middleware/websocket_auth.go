@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebSocket subprotocol conventions. Browsers can't set an Authorization header
+// on WebSocket requests, so clients may instead offer
+// "Sec-WebSocket-Protocol: notinote, bearer.<token>"; the server selects
+// WebSocketProtocol and never echoes the token.
+const (
+	WebSocketProtocol             = "notinote"
+	WebSocketBearerProtocolPrefix = "bearer."
+)
+
+// WebSocketAuthMiddleware validates JWT tokens on WebSocket upgrade requests.
+// The token is read from the Authorization header, the access_token query
+// parameter, or a bearer subprotocol, in that order.
+func WebSocketAuthMiddleware(tokens TokenValidator, users UserStatusChecker) gin.HandlerFunc {
+	return tokenAuthMiddleware(tokens, users, webSocketToken)
+}
+
+// webSocketToken extracts the access token from a WebSocket upgrade request
+func webSocketToken(c *gin.Context) string {
+	if token := queryToken(c); token != "" {
+		return token
+	}
+
+	for _, header := range c.Request.Header.Values("Sec-WebSocket-Protocol") {
+		for _, protocol := range strings.Split(header, ",") {
+			protocol = strings.TrimSpace(protocol)
+			if strings.HasPrefix(protocol, WebSocketBearerProtocolPrefix) {
+				return strings.TrimPrefix(protocol, WebSocketBearerProtocolPrefix)
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,389 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/notinoteapp/internal/adapters/primary/http/dtos"
+	"github.com/yourusername/notinoteapp/internal/adapters/primary/http/handlers"
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+	"github.com/yourusername/notinoteapp/internal/core/ports"
+	"github.com/yourusername/notinoteapp/internal/core/services"
+	"github.com/yourusername/notinoteapp/pkg/config"
+	"github.com/yourusername/notinoteapp/pkg/utils"
+)
+
+var testTokens = utils.NewJWTService("test-secret", "test", time.Hour, time.Hour)
+
+// stubNoteRepository serves notes from memory; unimplemented methods panic
+type stubNoteRepository struct {
+	ports.NoteRepository
+	notes map[int64]*domain.Note
+}
+
+func (r *stubNoteRepository) FindByID(ctx context.Context, id int64) (*domain.Note, error) {
+	note, ok := r.notes[id]
+	if !ok {
+		return nil, domain.ErrNoteNotFound
+	}
+	copied := *note
+	return &copied, nil
+}
+
+func (r *stubNoteRepository) FindChildren(ctx context.Context, parentID int64) ([]*domain.Note, error) {
+	var children []*domain.Note
+	for id := int64(1); id <= int64(len(r.notes)); id++ {
+		if note := r.notes[id]; note.ParentID != nil && *note.ParentID == parentID {
+			copied := *note
+			children = append(children, &copied)
+		}
+	}
+	return children, nil
+}
+
+func (r *stubNoteRepository) FindByUserID(ctx context.Context, userID int64, filters ports.NoteFilters) ([]*domain.Note, int64, error) {
+	var found []*domain.Note
+	for id := int64(1); id <= int64(len(r.notes)); id++ {
+		if note := r.notes[id]; note.UserID == userID {
+			copied := *note
+			found = append(found, &copied)
+		}
+	}
+	return found, int64(len(found)), nil
+}
+
+func (r *stubNoteRepository) Search(ctx context.Context, userID int64, query string, filters ports.NoteFilters) ([]*domain.Note, int64, error) {
+	var found []*domain.Note
+	for id := int64(1); id <= int64(len(r.notes)); id++ {
+		note := r.notes[id]
+		if note.UserID != userID {
+			continue
+		}
+		// Stands in for full-text and trigram matching
+		if strings.Contains(note.Title, query) || (filters.Fuzzy && strings.EqualFold(note.Title, query)) {
+			copied := *note
+			found = append(found, &copied)
+		}
+	}
+	return found, int64(len(found)), nil
+}
+
+func (r *stubNoteRepository) UpdateBlockText(ctx context.Context, noteID int64, blockID string, richText []domain.RichTextSegment) error {
+	note, ok := r.notes[noteID]
+	if !ok {
+		return domain.ErrNoteNotFound
+	}
+	return note.UpdateBlockText(blockID, richText)
+}
+
+func (r *stubNoteRepository) Update(ctx context.Context, note *domain.Note) (*domain.Note, error) {
+	stored := *note
+	r.notes[note.ID] = &stored
+	copied := stored
+	return &copied, nil
+}
+
+// MoveNote stands in for the hierarchy trigger, which sets path and depth
+func (r *stubNoteRepository) MoveNote(ctx context.Context, noteID int64, newParentID *int64, newPosition int) error {
+	note, ok := r.notes[noteID]
+	if !ok {
+		return domain.ErrNoteNotFound
+	}
+	note.ParentID = newParentID
+	note.Position = newPosition
+	if newParentID == nil {
+		note.Path = fmt.Sprintf("/%d/", noteID)
+		note.Depth = 0
+	}
+	return nil
+}
+
+// newNoteTestRouter serves note 1, owned by user 7, through the real service and handler
+func newNoteTestRouter() http.Handler {
+	noteRepo := &stubNoteRepository{notes: map[int64]*domain.Note{
+		1: {ID: 1, UserID: 7, Title: "Mine", Path: "/1/"},
+	}}
+	noteService := services.NewNoteService(noteRepo, nil, nil, nil)
+
+	return SetupRouter(RouterConfig{
+		NoteHandler:    handlers.NewNoteHandler(noteService),
+		TokenValidator: testTokens,
+		Config: &config.Config{
+			Server: config.ServerConfig{Mode: "test"},
+			CORS:   config.CORSConfig{AllowedOrigins: []string{"http://localhost:3000"}},
+		},
+	})
+}
+
+func doNoteRequest(t *testing.T, router http.Handler, method, path, body string, userID int64) *httptest.ResponseRecorder {
+	token, err := testTokens.GenerateToken(userID, "user@example.com")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestNoteRoutes_MissingNoteReturns404(t *testing.T) {
+	router := newNoteTestRouter()
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{http.MethodGet, "/api/v1/notes/99", ""},
+		{http.MethodPut, "/api/v1/notes/99", `{"title":"Renamed"}`},
+		{http.MethodDelete, "/api/v1/notes/99", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			w := doNoteRequest(t, router, tt.method, tt.path, tt.body, 7)
+			assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+		})
+	}
+}
+
+func TestNoteRoutes_WrongOwnerLooksMissing(t *testing.T) {
+	router := newNoteTestRouter()
+
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		w := doNoteRequest(t, router, method, "/api/v1/notes/1", "", 8)
+		assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+	}
+
+	w := doNoteRequest(t, router, http.MethodGet, "/api/v1/notes/1", "", 7)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestNoteRoutes_QueryViewRows(t *testing.T) {
+	parentID := int64(2)
+	noteRepo := &stubNoteRepository{notes: map[int64]*domain.Note{
+		1: {ID: 1, UserID: 7, Title: "Mine", Path: "/1/"},
+		2: {ID: 2, UserID: 7, Title: "Tasks", Path: "/2/", ViewMetadata: &domain.ViewMetadata{
+			ViewType:   domain.ViewTypeTable,
+			Properties: []domain.ViewProperty{{ID: "estimate", Type: domain.PropertyTypeNumber}},
+			Filters:    []domain.ViewFilter{{PropertyID: "estimate", Operator: domain.FilterGreaterThan, Value: float64(1)}},
+			Sorts:      []domain.ViewSort{{PropertyID: "estimate", Direction: "desc"}},
+		}},
+		3: {ID: 3, UserID: 7, ParentID: &parentID, Title: "Small", Properties: map[string]interface{}{"estimate": float64(1)}},
+		4: {ID: 4, UserID: 7, ParentID: &parentID, Title: "Medium", Properties: map[string]interface{}{"estimate": float64(3)}},
+		5: {ID: 5, UserID: 7, ParentID: &parentID, Title: "Large", Properties: map[string]interface{}{"estimate": float64(8)}},
+	}}
+	router := SetupRouter(RouterConfig{
+		NoteHandler:    handlers.NewNoteHandler(services.NewNoteService(noteRepo, nil, nil, nil)),
+		TokenValidator: testTokens,
+		Config:         &config.Config{Server: config.ServerConfig{Mode: "test"}},
+	})
+
+	w := doNoteRequest(t, router, http.MethodGet, "/api/v1/notes/2/rows?limit=1&page=2", "", 7)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Data dtos.NoteListResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data.Notes, 1)
+	assert.Equal(t, int64(4), resp.Data.Notes[0].ID)
+	assert.Equal(t, int64(2), resp.Data.Pagination.Total)
+
+	w = doNoteRequest(t, router, http.MethodGet, "/api/v1/notes/1/rows", "", 7)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+	w = doNoteRequest(t, router, http.MethodGet, "/api/v1/notes/2/rows", "", 8)
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+}
+
+func TestNoteRoutes_SearchPassesFuzzyFlag(t *testing.T) {
+	router := newNoteTestRouter()
+
+	search := func(path string) int64 {
+		w := doNoteRequest(t, router, http.MethodGet, path, "", 7)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var resp struct {
+			Data dtos.NoteListResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data.Pagination.Total
+	}
+
+	assert.Equal(t, int64(0), search("/api/v1/notes/search?q=mine"))
+	assert.Equal(t, int64(1), search("/api/v1/notes/search?q=mine&fuzzy=true"))
+}
+
+func TestNoteRoutes_SearchWithinChecksOwnership(t *testing.T) {
+	router := newNoteTestRouter()
+
+	w := doNoteRequest(t, router, http.MethodGet, "/api/v1/notes/search?q=Mine&within=1", "", 7)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = doNoteRequest(t, router, http.MethodGet, "/api/v1/notes/search?q=Mine&within=1", "", 8)
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+
+	w = doNoteRequest(t, router, http.MethodGet, "/api/v1/notes/search?q=Mine&within=99", "", 7)
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+
+	w = doNoteRequest(t, router, http.MethodGet, "/api/v1/notes/search?q=Mine&within=abc", "", 7)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+}
+
+func TestNoteRoutes_UpdateBlockText(t *testing.T) {
+	checked := true
+	noteRepo := &stubNoteRepository{notes: map[int64]*domain.Note{
+		1: {ID: 1, UserID: 7, Title: "Mine", Path: "/1/", Blocks: []domain.Block{
+			{ID: "a", Type: domain.BlockTypeCheckbox, Content: &domain.BlockContent{Checked: &checked}},
+			{ID: "b", Type: domain.BlockTypeDivider},
+		}},
+	}}
+	router := SetupRouter(RouterConfig{
+		NoteHandler:    handlers.NewNoteHandler(services.NewNoteService(noteRepo, nil, nil, nil)),
+		TokenValidator: testTokens,
+		Config:         &config.Config{Server: config.ServerConfig{Mode: "test"}},
+	})
+
+	body := `{"rich_text":[{"text":"Buy milk"}]}`
+	w := doNoteRequest(t, router, http.MethodPatch, "/api/v1/notes/1/blocks/a/text", body, 7)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Data dtos.BlockTextResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(1), resp.Data.NoteID)
+	require.NotNil(t, resp.Data.Block)
+	assert.Equal(t, "Buy milk", resp.Data.Block.Content.RichText[0].Text)
+	assert.True(t, *resp.Data.Block.Content.Checked)
+
+	w = doNoteRequest(t, router, http.MethodPatch, "/api/v1/notes/1/blocks/b/text", body, 7)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+	w = doNoteRequest(t, router, http.MethodPatch, "/api/v1/notes/1/blocks/a/text", `{}`, 7)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+	w = doNoteRequest(t, router, http.MethodPatch, "/api/v1/notes/1/blocks/a/text", body, 8)
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+}
+
+func TestNoteRoutes_ListSummary(t *testing.T) {
+	noteRepo := &stubNoteRepository{notes: map[int64]*domain.Note{
+		1: {ID: 1, UserID: 7, Title: "Mine", Icon: "📁", Path: "/1/", Blocks: []domain.Block{{ID: "a", Type: domain.BlockTypeDivider}}},
+	}}
+	router := SetupRouter(RouterConfig{
+		NoteHandler:    handlers.NewNoteHandler(services.NewNoteService(noteRepo, nil, nil, nil)),
+		TokenValidator: testTokens,
+		Config:         &config.Config{Server: config.ServerConfig{Mode: "test"}},
+	})
+
+	list := func(path string) map[string]interface{} {
+		w := doNoteRequest(t, router, http.MethodGet, path, "", 7)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var resp struct {
+			Data struct {
+				Notes []map[string]interface{} `json:"notes"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Data.Notes, 1)
+		return resp.Data.Notes[0]
+	}
+
+	assert.Contains(t, list("/api/v1/notes"), "blocks")
+
+	summary := list("/api/v1/notes?summary=true")
+	assert.NotContains(t, summary, "blocks")
+	assert.Equal(t, "Mine", summary["title"])
+	assert.Equal(t, "📁", summary["icon"])
+}
+
+func TestNoteRoutes_PatchNote(t *testing.T) {
+	noteRepo := &stubNoteRepository{notes: map[int64]*domain.Note{
+		1: {ID: 1, UserID: 7, Title: "Mine", Icon: "📁", CoverImage: "cover.png", Path: "/1/",
+			Properties: map[string]interface{}{"status": "todo", "priority": "high", "owner": "sam"}},
+	}}
+	router := SetupRouter(RouterConfig{
+		NoteHandler:    handlers.NewNoteHandler(services.NewNoteService(noteRepo, nil, nil, nil)),
+		TokenValidator: testTokens,
+		Config:         &config.Config{Server: config.ServerConfig{Mode: "test"}},
+	})
+
+	body := `{"icon":"","properties":{"status":"done","owner":null}}`
+	w := doNoteRequest(t, router, http.MethodPatch, "/api/v1/notes/1", body, 7)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	note := noteRepo.notes[1]
+	assert.Equal(t, "Mine", note.Title, "omitted fields are left alone")
+	assert.Equal(t, "", note.Icon, "empty values are set")
+	assert.Equal(t, "cover.png", note.CoverImage)
+	assert.Equal(t, map[string]interface{}{"status": "done", "priority": "high"}, note.Properties)
+
+	w = doNoteRequest(t, router, http.MethodPatch, "/api/v1/notes/1", `{"title":""}`, 7)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+	w = doNoteRequest(t, router, http.MethodPatch, "/api/v1/notes/1", `{"title":"Theirs"}`, 8)
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+}
+
+func TestNoteRoutes_Properties(t *testing.T) {
+	noteRepo := &stubNoteRepository{notes: map[int64]*domain.Note{
+		1: {ID: 1, UserID: 7, Title: "Mine", Path: "/1/", Properties: map[string]interface{}{"status": "todo", "owner": "sam"}},
+	}}
+	router := SetupRouter(RouterConfig{
+		NoteHandler:    handlers.NewNoteHandler(services.NewNoteService(noteRepo, nil, nil, nil)),
+		TokenValidator: testTokens,
+		Config:         &config.Config{Server: config.ServerConfig{Mode: "test"}},
+	})
+
+	w := doNoteRequest(t, router, http.MethodPatch, "/api/v1/notes/1/properties", `{"properties":{"status":"done","owner":null,"due":"2025-01-31"}}`, 7)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, map[string]interface{}{"status": "done", "due": "2025-01-31"}, noteRepo.notes[1].Properties)
+
+	w = doNoteRequest(t, router, http.MethodPut, "/api/v1/notes/1/properties", `{"properties":{"priority":"high"}}`, 7)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, map[string]interface{}{"priority": "high"}, noteRepo.notes[1].Properties)
+}
+
+func TestNoteRoutes_MoveToRoot(t *testing.T) {
+	parentID := int64(1)
+	noteRepo := &stubNoteRepository{notes: map[int64]*domain.Note{
+		1: {ID: 1, UserID: 7, Title: "Parent", Path: "/1/"},
+		2: {ID: 2, UserID: 7, Title: "Child", ParentID: &parentID, Path: "/1/2/", Depth: 1},
+	}}
+	router := SetupRouter(RouterConfig{
+		NoteHandler:    handlers.NewNoteHandler(services.NewNoteService(noteRepo, nil, nil, nil)),
+		TokenValidator: testTokens,
+		Config:         &config.Config{Server: config.ServerConfig{Mode: "test"}},
+	})
+
+	w := doNoteRequest(t, router, http.MethodPost, "/api/v1/notes/2/move-to-root?position=3", "", 7)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Data dtos.NoteResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Nil(t, resp.Data.ParentID)
+	assert.Equal(t, 0, resp.Data.Depth)
+	assert.Equal(t, "/2/", resp.Data.Path)
+	assert.Equal(t, 3, resp.Data.Position)
+
+	w = doNoteRequest(t, router, http.MethodPost, "/api/v1/notes/2/move-to-root?position=-1", "", 7)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+	w = doNoteRequest(t, router, http.MethodPost, "/api/v1/notes/2/move-to-root", "", 8)
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+}
@@ -0,0 +1,179 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/notinoteapp/internal/adapters/primary/http/handlers"
+	appservices "github.com/yourusername/notinoteapp/internal/application/services"
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+	"github.com/yourusername/notinoteapp/internal/core/ports"
+	"github.com/yourusername/notinoteapp/pkg/config"
+)
+
+// stubReminderRepository serves reminders from memory; unimplemented methods panic
+type stubReminderRepository struct {
+	ports.ReminderRepository
+	reminders []*domain.Reminder
+}
+
+func (r *stubReminderRepository) FindByUserID(ctx context.Context, userID int64, params *ports.ReminderQueryParams) ([]*domain.Reminder, error) {
+	var found []*domain.Reminder
+	for _, reminder := range r.reminders {
+		if reminder.UserID == userID {
+			found = append(found, reminder)
+		}
+	}
+	return found, nil
+}
+
+// stubCalendarFeedRepository keeps calendar feeds in memory, keyed by user
+type stubCalendarFeedRepository struct {
+	feeds map[int64]*domain.CalendarFeed
+}
+
+func (r *stubCalendarFeedRepository) Save(ctx context.Context, feed *domain.CalendarFeed) error {
+	r.feeds[feed.UserID] = feed
+	return nil
+}
+
+func (r *stubCalendarFeedRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*domain.CalendarFeed, error) {
+	for _, feed := range r.feeds {
+		if feed.TokenHash == tokenHash {
+			return feed, nil
+		}
+	}
+	return nil, domain.ErrCalendarFeedNotFound
+}
+
+func (r *stubCalendarFeedRepository) DeleteByUserID(ctx context.Context, userID int64) error {
+	delete(r.feeds, userID)
+	return nil
+}
+
+func newReminderTestRouter(reminders ...*domain.Reminder) http.Handler {
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+	reminderService := appservices.NewReminderService(&stubReminderRepository{reminders: reminders}, nil, 0, logger)
+	reminderService.SetCalendarFeedRepository(&stubCalendarFeedRepository{feeds: map[int64]*domain.CalendarFeed{}})
+
+	return SetupRouter(RouterConfig{
+		ReminderHandler: handlers.NewReminderHandler(reminderService, logger),
+		TokenValidator:  testTokens,
+		CalendarFeeds:   reminderService,
+		Config: &config.Config{
+			Server: config.ServerConfig{Mode: "test"},
+			CORS:   config.CORSConfig{AllowedOrigins: []string{"http://localhost:3000"}},
+		},
+	})
+}
+
+func TestReminderRoutes_Calendar(t *testing.T) {
+	at := time.Date(2025, time.January, 31, 9, 0, 0, 0, time.UTC)
+	endAt := time.Date(2025, time.June, 30, 0, 0, 0, 0, time.UTC)
+	note := &domain.Note{ID: 1, Title: "Groceries"}
+	router := newReminderTestRouter(
+		&domain.Reminder{ID: 1, UserID: 7, Title: "Shop", Message: "Milk, eggs", ScheduledAt: at, UpdatedAt: at,
+			RepeatType: domain.RepeatTypeWeekly, RepeatConfig: &domain.RepeatConfig{Days: []int{1, 3}}, RepeatEndAt: &endAt, Note: note},
+		&domain.Reminder{ID: 2, UserID: 7, Title: "Rent", ScheduledAt: at, UpdatedAt: at,
+			RepeatType: domain.RepeatTypeMonthly, RepeatConfig: &domain.RepeatConfig{Day: 30}, Note: note},
+		&domain.Reminder{ID: 3, UserID: 7, Title: "Trashed", ScheduledAt: at, UpdatedAt: at,
+			RepeatType: domain.RepeatTypeOnce, Note: &domain.Note{ID: 2, IsDeleted: true}},
+	)
+
+	feedURL := createCalendarFeed(t, router, 7)
+
+	req := httptest.NewRequest(http.MethodGet, feedURL, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+	body := w.Body.String()
+	assert.Contains(t, body, "UID:reminder-1@notinote\r\n")
+	assert.Contains(t, body, "SUMMARY:Groceries\r\n")
+	assert.Contains(t, body, `DESCRIPTION:Milk\, eggs`+"\r\n")
+	assert.Contains(t, body, "DTSTART:20250131T090000Z\r\n")
+	assert.Contains(t, body, "RRULE:FREQ=WEEKLY;BYDAY=MO,WE;UNTIL=20250630T000000Z\r\n")
+	assert.Contains(t, body, "RRULE:FREQ=MONTHLY;BYMONTHDAY=28,29,30;BYSETPOS=-1\r\n")
+	assert.NotContains(t, body, "reminder-3@notinote")
+}
+
+// createCalendarFeed issues a calendar feed token for the user and returns the feed URL
+func createCalendarFeed(t *testing.T, router http.Handler, userID int64) string {
+	t.Helper()
+
+	token, err := testTokens.GenerateToken(userID, "user@example.com")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/reminders/calendar/token", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var resp struct {
+		Data struct {
+			Token string `json:"token"`
+			URL   string `json:"url"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotEmpty(t, resp.Data.Token)
+	assert.Equal(t, "/api/v1/reminders/calendar.ics?token="+resp.Data.Token, resp.Data.URL)
+	return resp.Data.URL
+}
+
+func TestReminderRoutes_CalendarFeedTokenRevocation(t *testing.T) {
+	router := newReminderTestRouter()
+
+	get := func(path string) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w.Code
+	}
+
+	first := createCalendarFeed(t, router, 7)
+	require.Equal(t, http.StatusOK, get(first))
+
+	// Rotating the token invalidates the old URL
+	second := createCalendarFeed(t, router, 7)
+	assert.Equal(t, http.StatusUnauthorized, get(first))
+	assert.Equal(t, http.StatusOK, get(second))
+
+	token, err := testTokens.GenerateToken(7, "user@example.com")
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/reminders/calendar/token", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	assert.Equal(t, http.StatusUnauthorized, get(second))
+}
+
+func TestReminderRoutes_CalendarRequiresToken(t *testing.T) {
+	router := newReminderTestRouter()
+
+	token, err := testTokens.GenerateToken(7, "user@example.com")
+	require.NoError(t, err)
+
+	for _, path := range []string{
+		"/api/v1/reminders/calendar.ics",
+		"/api/v1/reminders/calendar.ics?token=garbage",
+		// Session JWTs aren't accepted in place of a feed token
+		"/api/v1/reminders/calendar.ics?token=" + token,
+		"/api/v1/reminders/calendar.ics?access_token=" + token,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code, path)
+	}
+}
@@ -3,20 +3,25 @@ package http
 import (
 	"time"
 
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/yourusername/notinoteapp/internal/adapters/primary/http/handlers"
 	"github.com/yourusername/notinoteapp/internal/adapters/primary/http/middleware"
+	"github.com/yourusername/notinoteapp/internal/core/domain"
 	"github.com/yourusername/notinoteapp/pkg/config"
 )
 
 // RouterConfig holds router configuration
 type RouterConfig struct {
-	AuthHandler     *handlers.AuthHandler
-	NoteHandler     *handlers.NoteHandler
-	DeviceHandler   *handlers.DeviceHandler
-	ReminderHandler *handlers.ReminderHandler
-	Config          *config.Config
+	AuthHandler         *handlers.AuthHandler
+	AdminHandler        *handlers.AdminHandler
+	NoteHandler         *handlers.NoteHandler
+	DeviceHandler       *handlers.DeviceHandler
+	ReminderHandler     *handlers.ReminderHandler
+	NotificationHandler *handlers.NotificationHandler
+	TokenValidator      middleware.TokenValidator
+	UserStatus          middleware.UserStatusChecker
+	CalendarFeeds       middleware.CalendarFeedResolver
+	Config              *config.Config
 }
 
 // SetupRouter sets up the HTTP router with all routes
@@ -28,17 +33,16 @@ func SetupRouter(cfg RouterConfig) *gin.Engine {
 	router := gin.New()
 
 	// Global middleware
-	router.Use(gin.Recovery())
-	router.Use(middleware.Logger())
-
-	// CORS middleware
-	router.Use(cors.New(cors.Config{
-		AllowOrigins:     cfg.Config.CORS.AllowedOrigins,
-		AllowMethods:     cfg.Config.CORS.AllowedMethods,
-		AllowHeaders:     cfg.Config.CORS.AllowedHeaders,
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: true,
-		MaxAge:           12 * time.Hour,
+	router.Use(middleware.RequestID())
+	router.Use(middleware.Logger(cfg.Config.Log.AccessLogSkipPaths))
+	if cfg.Config.Server.GzipEnabled {
+		// Outside Recovery so a recovered 500 still goes through the gzip writer
+		router.Use(middleware.Gzip(cfg.Config.Server.GzipMinBytes))
+	}
+	router.Use(middleware.Recovery()) // inside Logger so recovered requests are logged as 500s
+	router.Use(middleware.CORS(cfg.Config.CORS))
+	router.Use(middleware.BodyLimit(cfg.Config.Server.MaxBodyBytes, map[string]int64{
+		"PUT /api/v1/notes/:id/blocks": cfg.Config.Server.MaxBlocksBodyBytes,
 	}))
 
 	// Health check endpoint
@@ -58,29 +62,67 @@ func SetupRouter(cfg RouterConfig) *gin.Engine {
 			auth.POST("/register", cfg.AuthHandler.Register)
 			auth.POST("/login", cfg.AuthHandler.Login)
 			auth.POST("/refresh", cfg.AuthHandler.RefreshToken)
+			auth.POST("/logout", cfg.AuthHandler.Logout)
+			auth.POST("/reactivate", cfg.AuthHandler.Reactivate)
+
+			// OAuth redirect flow
+			auth.GET("/google", cfg.AuthHandler.GoogleLogin)
+			auth.GET("/google/callback", cfg.AuthHandler.GoogleCallback)
+			auth.GET("/facebook", cfg.AuthHandler.FacebookLogin)
+			auth.GET("/facebook/callback", cfg.AuthHandler.FacebookCallback)
 
 			// OAuth verification routes (frontend-initiated)
 			auth.POST("/google/verify", cfg.AuthHandler.VerifyGoogleToken)
 			auth.POST("/facebook/verify", cfg.AuthHandler.VerifyFacebookToken)
 		}
 
+		// Shared notes are public and read-only
+		if cfg.NoteHandler != nil {
+			v1.GET("/shared/:token", cfg.NoteHandler.GetSharedNote)
+		}
+
+		// Limits are public so clients can validate before signing in
+		if cfg.NoteHandler != nil {
+			v1.GET("/limits", cfg.NoteHandler.GetLimits)
+		}
+
+		// Live note updates authenticate via query token or subprotocol,
+		// since browsers can't set Authorization on WebSocket requests
+		if cfg.NoteHandler != nil {
+			v1.GET("/notes/:id/ws", middleware.WebSocketAuthMiddleware(cfg.TokenValidator, cfg.UserStatus), cfg.NoteHandler.NoteSocket)
+		}
+
+		// Calendar apps subscribe by URL and can't set Authorization, so the
+		// feed authenticates with a feed-only token in the query string
+		if cfg.ReminderHandler != nil && cfg.CalendarFeeds != nil {
+			v1.GET("/reminders/calendar.ics", middleware.CalendarFeedAuthMiddleware(cfg.CalendarFeeds, cfg.UserStatus), cfg.ReminderHandler.Calendar)
+		}
+
 		// Protected routes
 		protected := v1.Group("")
-		protected.Use(middleware.AuthMiddleware(cfg.Config.JWT.Secret))
+		protected.Use(middleware.AuthMiddleware(cfg.TokenValidator, cfg.UserStatus))
 		{
 			// User routes
+			protected.GET("/auth/me", cfg.AuthHandler.GetCurrentUser)
+			protected.PUT("/auth/profile", cfg.AuthHandler.UpdateProfile)
+			protected.POST("/auth/deactivate", cfg.AuthHandler.Deactivate)
 			protected.GET("/me", cfg.AuthHandler.GetCurrentUser)
 
 			// Notes routes
 			if cfg.NoteHandler != nil {
+				protected.GET("/usage", cfg.NoteHandler.GetUsage)
+
 				notes := protected.Group("/notes")
 				{
 					// Basic CRUD operations
 					notes.GET("", cfg.NoteHandler.ListNotes)
 					notes.POST("", cfg.NoteHandler.CreateNote)
 					notes.GET("/search", cfg.NoteHandler.SearchNotes)
+					notes.GET("/count", cfg.NoteHandler.CountNotes)
+					notes.POST("/batch-get", cfg.NoteHandler.BatchGetNotes)
 					notes.GET("/:id", cfg.NoteHandler.GetNote)
 					notes.PUT("/:id", cfg.NoteHandler.UpdateNote)
+					notes.PATCH("/:id", cfg.NoteHandler.PatchNote)
 					notes.DELETE("/:id", cfg.NoteHandler.DeleteNote)
 
 					// Note lifecycle operations
@@ -88,27 +130,41 @@ func SetupRouter(cfg RouterConfig) *gin.Engine {
 					notes.POST("/:id/unarchive", cfg.NoteHandler.UnarchiveNote)
 					notes.POST("/:id/restore", cfg.NoteHandler.RestoreNote)
 					notes.POST("/:id/move", cfg.NoteHandler.MoveNote)
+					notes.POST("/:id/move-to-root", cfg.NoteHandler.MoveNoteToRoot)
 
 					// Hierarchy operations
 					notes.GET("/:id/children", cfg.NoteHandler.GetChildren)
+					notes.GET("/:id/descendants", cfg.NoteHandler.GetDescendants)
+					notes.GET("/:id/descendants/count", cfg.NoteHandler.GetDescendantCount)
+					notes.GET("/:id/rows", cfg.NoteHandler.QueryView)
 					notes.GET("/:id/ancestors", cfg.NoteHandler.GetAncestors)
 
 					// Block operations
 					notes.PUT("/:id/blocks", cfg.NoteHandler.ReplaceBlocks)
 					notes.POST("/:id/blocks", cfg.NoteHandler.AddBlock)
 					notes.PATCH("/:id/blocks/:block_id", cfg.NoteHandler.UpdateBlock)
+					notes.PATCH("/:id/blocks/:block_id/text", cfg.NoteHandler.UpdateBlockText)
 					notes.DELETE("/:id/blocks/:block_id", cfg.NoteHandler.DeleteBlock)
 					notes.POST("/:id/blocks/reorder", cfg.NoteHandler.ReorderBlocks)
 
 					// View and properties
 					notes.PUT("/:id/view", cfg.NoteHandler.UpdateViewMetadata)
 					notes.PUT("/:id/properties", cfg.NoteHandler.UpdateProperties)
+					notes.PATCH("/:id/properties", cfg.NoteHandler.MergeProperties)
 
 					// Favorite and tags
 					notes.PATCH("/:id/favorite", cfg.NoteHandler.ToggleFavorite)
 					notes.POST("/:id/tags/:tag_id", cfg.NoteHandler.AddTagToNote)
 					notes.DELETE("/:id/tags/:tag_id", cfg.NoteHandler.RemoveTagFromNote)
 
+					// Sharing
+					notes.POST("/:id/share", cfg.NoteHandler.ShareNote)
+					notes.GET("/:id/share", cfg.NoteHandler.ListSharedLinks)
+					notes.DELETE("/:id/share/:link_id", cfg.NoteHandler.RevokeSharedLink)
+					notes.POST("/:id/collaborators", cfg.NoteHandler.AddCollaborator)
+					notes.GET("/:id/collaborators", cfg.NoteHandler.ListCollaborators)
+					notes.DELETE("/:id/collaborators/:user_id", cfg.NoteHandler.RemoveCollaborator)
+
 					// Reminder routes (nested under notes)
 					if cfg.ReminderHandler != nil {
 						notes.POST("/:id/reminders", cfg.ReminderHandler.Create)
@@ -117,6 +173,18 @@ func SetupRouter(cfg RouterConfig) *gin.Engine {
 				}
 			}
 
+			// Tag routes
+			if cfg.NoteHandler != nil {
+				tags := protected.Group("/tags")
+				{
+					tags.GET("", cfg.NoteHandler.ListTags)
+					tags.PATCH("/:id", cfg.NoteHandler.UpdateTag)
+					tags.POST("/:id/merge", cfg.NoteHandler.MergeTag)
+					tags.POST("/:id/notes", cfg.NoteHandler.TagNotes)
+					tags.DELETE("/:id/notes", cfg.NoteHandler.UntagNotes)
+				}
+			}
+
 			// Device routes
 			if cfg.DeviceHandler != nil {
 				devices := protected.Group("/devices")
@@ -124,6 +192,7 @@ func SetupRouter(cfg RouterConfig) *gin.Engine {
 					devices.POST("", cfg.DeviceHandler.Register)
 					devices.GET("", cfg.DeviceHandler.List)
 					devices.DELETE("/:id", cfg.DeviceHandler.Unregister)
+					devices.POST("/:id/heartbeat", cfg.DeviceHandler.Heartbeat)
 					devices.DELETE("/token", cfg.DeviceHandler.UnregisterByToken)
 				}
 			}
@@ -133,6 +202,10 @@ func SetupRouter(cfg RouterConfig) *gin.Engine {
 				reminders := protected.Group("/reminders")
 				{
 					reminders.GET("", cfg.ReminderHandler.List)
+					reminders.GET("/upcoming", cfg.ReminderHandler.Upcoming)
+					reminders.POST("/preview", cfg.ReminderHandler.Preview)
+					reminders.POST("/calendar/token", cfg.ReminderHandler.CreateCalendarFeed)
+					reminders.DELETE("/calendar/token", cfg.ReminderHandler.RevokeCalendarFeed)
 					reminders.GET("/:id", cfg.ReminderHandler.Get)
 					reminders.PUT("/:id", cfg.ReminderHandler.Update)
 					reminders.DELETE("/:id", cfg.ReminderHandler.Delete)
@@ -140,6 +213,35 @@ func SetupRouter(cfg RouterConfig) *gin.Engine {
 					reminders.POST("/:id/snooze", cfg.ReminderHandler.Snooze)
 				}
 			}
+
+			// Notification routes
+			if cfg.NotificationHandler != nil {
+				notifications := protected.Group("/notifications")
+				{
+					notifications.GET("/logs", cfg.NotificationHandler.ListLogs)
+					notifications.GET("/preferences", cfg.NotificationHandler.GetPreferences)
+					notifications.PUT("/preferences", cfg.NotificationHandler.UpdatePreferences)
+					notifications.POST("/webhooks", cfg.NotificationHandler.RegisterWebhook)
+					notifications.GET("/stream", cfg.NotificationHandler.Stream)
+				}
+			}
+
+			// Admin routes
+			if cfg.AdminHandler != nil {
+				admin := protected.Group("/admin")
+				admin.Use(middleware.RequireRole(domain.RoleAdmin))
+				{
+					admin.GET("/users", cfg.AdminHandler.ListUsers)
+					admin.POST("/users/:id/rebuild-paths", cfg.AdminHandler.RebuildNotePaths)
+					admin.POST("/notes/repair-orphans", cfg.AdminHandler.RepairOrphans)
+					admin.GET("/notifications/dead-letter", cfg.AdminHandler.ListDeadLetters)
+					admin.POST("/notifications/dead-letter/replay/:id", cfg.AdminHandler.ReplayDeadLetter)
+					admin.GET("/scheduler", cfg.AdminHandler.GetScheduler)
+					admin.POST("/scheduler/pause", cfg.AdminHandler.PauseScheduler)
+					admin.POST("/scheduler/resume", cfg.AdminHandler.ResumeScheduler)
+					admin.GET("/metrics", cfg.AdminHandler.GetMetrics)
+				}
+			}
 		}
 	}
 
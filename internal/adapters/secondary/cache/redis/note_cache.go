@@ -0,0 +1,98 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+)
+
+// NoteCache implements ports.NoteCache on top of Redis.
+//
+// Each note has a generation counter that is bumped on invalidation, and cached
+// copies are keyed by note ID and generation. A read that loaded a note before a
+// concurrent write therefore stores it under a generation nobody reads anymore,
+// instead of overwriting the invalidation with stale data.
+type NoteCache struct {
+	client *redis.Client
+	ttl    time.Duration
+	logger *logrus.Logger
+}
+
+// NewNoteCache creates a new Redis-backed note cache
+func NewNoteCache(client *redis.Client, ttl time.Duration, logger *logrus.Logger) *NoteCache {
+	if ttl <= 0 {
+		ttl = 5 * time.Minute
+	}
+
+	return &NoteCache{
+		client: client,
+		ttl:    ttl,
+		logger: logger,
+	}
+}
+
+func generationKey(noteID int64) string {
+	return fmt.Sprintf("note:gen:%d", noteID)
+}
+
+func noteKey(noteID int64, generation int64) string {
+	return fmt.Sprintf("note:%d:%d", noteID, generation)
+}
+
+// GetOrLoad returns the cached note, or loads it and caches the result.
+// Redis failures are logged and fall back to load.
+func (c *NoteCache) GetOrLoad(ctx context.Context, noteID int64, load func(ctx context.Context) (*domain.Note, error)) (*domain.Note, error) {
+	generation, err := c.client.Get(ctx, generationKey(noteID)).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		c.logger.WithError(err).WithField("note_id", noteID).Warn("Note cache unavailable")
+		return load(ctx)
+	}
+
+	key := noteKey(noteID, generation)
+	data, err := c.client.Get(ctx, key).Bytes()
+	if err == nil {
+		var note domain.Note
+		if err := json.Unmarshal(data, &note); err == nil {
+			return &note, nil
+		}
+		c.logger.WithField("note_id", noteID).Warn("Discarding undecodable cached note")
+	} else if !errors.Is(err, redis.Nil) {
+		c.logger.WithError(err).WithField("note_id", noteID).Warn("Note cache unavailable")
+		return load(ctx)
+	}
+
+	note, err := load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = json.Marshal(note)
+	if err != nil {
+		return note, nil
+	}
+	if err := c.client.Set(ctx, key, data, c.ttl).Err(); err != nil {
+		c.logger.WithError(err).WithField("note_id", noteID).Warn("Failed to cache note")
+	}
+
+	return note, nil
+}
+
+// Invalidate bumps the note's generation so previously cached copies are no longer read
+func (c *NoteCache) Invalidate(ctx context.Context, noteID int64) {
+	key := generationKey(noteID)
+
+	// The counter outlives every entry written under it, so letting it expire
+	// can never resurrect a stale copy.
+	pipe := c.client.TxPipeline()
+	pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, 2*c.ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		c.logger.WithError(err).WithField("note_id", noteID).Error("Failed to invalidate cached note")
+	}
+}
@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/utils"
+)
+
+// gormLogger routes GORM's logging through the application's structured
+// logger. Queries slower than slowThreshold are logged at warn level. SQL is
+// always logged with placeholders, never with its parameters, so passwords and
+// tokens bound to a query stay out of the logs.
+type gormLogger struct {
+	log           *logrus.Logger
+	level         logger.LogLevel
+	slowThreshold time.Duration
+}
+
+// newGormLogger creates a GORM logger. A zero slowThreshold disables slow
+// query logging.
+func newGormLogger(log *logrus.Logger, level logger.LogLevel, slowThreshold time.Duration) *gormLogger {
+	if log == nil {
+		log = logrus.StandardLogger()
+	}
+	l := &gormLogger{log: log, level: level, slowThreshold: slowThreshold}
+
+	// Scan records its SQL through GORM's trace recorder, which takes its
+	// filter from a package variable rather than from the logger
+	logger.RecorderParamsFilter = l.ParamsFilter
+
+	return l
+}
+
+// parseLogLevel maps a DB_LOG_LEVEL value to a GORM log level, defaulting to warn
+func parseLogLevel(level string) logger.LogLevel {
+	switch level {
+	case "silent":
+		return logger.Silent
+	case "error":
+		return logger.Error
+	case "info":
+		return logger.Info
+	default:
+		return logger.Warn
+	}
+}
+
+// LogMode returns a copy of the logger at the given level
+func (l *gormLogger) LogMode(level logger.LogLevel) logger.Interface {
+	copied := *l
+	copied.level = level
+	return &copied
+}
+
+func (l *gormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Info {
+		l.log.WithField("source", utils.FileWithLineNum()).Infof(msg, args...)
+	}
+}
+
+func (l *gormLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Warn {
+		l.log.WithField("source", utils.FileWithLineNum()).Warnf(msg, args...)
+	}
+}
+
+func (l *gormLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Error {
+		l.log.WithField("source", utils.FileWithLineNum()).Errorf(msg, args...)
+	}
+}
+
+// Trace logs a finished query: failures at error level, slow queries at warn
+// level, and every query at info level
+func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= logger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	entry := func() *logrus.Entry {
+		sql, rows := fc()
+		return l.log.WithFields(logrus.Fields{
+			"sql":      sql,
+			"rows":     rows,
+			"duration": elapsed,
+			"source":   utils.FileWithLineNum(),
+		})
+	}
+
+	switch {
+	case err != nil && l.level >= logger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		entry().WithError(err).Error("SQL query failed")
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= logger.Warn:
+		entry().WithField("threshold", l.slowThreshold).Warn("Slow SQL query")
+	case l.level >= logger.Info:
+		entry().Info("SQL query")
+	}
+}
+
+// ParamsFilter drops query parameters so logged SQL keeps its placeholders
+func (l *gormLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	return sql, nil
+}
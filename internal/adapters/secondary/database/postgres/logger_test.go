@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupLoggerTestDB opens an in-memory SQLite database that logs through a
+// test logger
+func setupLoggerTestDB(t *testing.T, level logger.LogLevel, slowThreshold time.Duration) (*gorm.DB, *test.Hook) {
+	log, hook := test.NewNullLogger()
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{
+		Logger: newGormLogger(log, level, slowThreshold),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec("CREATE TABLE secrets (id INTEGER PRIMARY KEY, token TEXT)").Error)
+	hook.Reset()
+	return db, hook
+}
+
+func TestGormLogger_SlowQuery(t *testing.T) {
+	db, hook := setupLoggerTestDB(t, logger.Warn, time.Nanosecond)
+
+	var id int64
+	require.NoError(t, db.Raw("SELECT id FROM secrets WHERE token = ?", "hunter2").Scan(&id).Error)
+
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, logrus.WarnLevel, entry.Level)
+	assert.Equal(t, "Slow SQL query", entry.Message)
+	assert.Equal(t, "SELECT id FROM secrets WHERE token = ?", entry.Data["sql"])
+	assert.NotContains(t, entry.Data["sql"], "hunter2")
+	assert.Contains(t, entry.Data, "duration")
+}
+
+func TestGormLogger_FastQueryNotLogged(t *testing.T) {
+	db, hook := setupLoggerTestDB(t, logger.Warn, time.Hour)
+
+	var id int64
+	require.NoError(t, db.Raw("SELECT id FROM secrets").Scan(&id).Error)
+
+	assert.Empty(t, hook.AllEntries())
+}
+
+func TestGormLogger_Errors(t *testing.T) {
+	db, hook := setupLoggerTestDB(t, logger.Warn, 0)
+
+	// Not finding a record is expected, not an error
+	var row struct{ ID int64 }
+	err := db.Table("secrets").Where("token = ?", "hunter2").First(&row).Error
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Empty(t, hook.AllEntries())
+
+	require.Error(t, db.Exec("SELECT * FROM missing WHERE token = ?", "hunter2").Error)
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, logrus.ErrorLevel, entry.Level)
+	assert.NotContains(t, entry.Data["sql"], "hunter2")
+}
+
+func TestGormLogger_Silent(t *testing.T) {
+	db, hook := setupLoggerTestDB(t, logger.Silent, time.Nanosecond)
+
+	require.Error(t, db.Exec("SELECT * FROM missing").Error)
+
+	assert.Empty(t, hook.AllEntries())
+}
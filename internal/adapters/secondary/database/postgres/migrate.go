@@ -0,0 +1,326 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// schemaMigrationsTable records the applied version. It matches golang-migrate's
+// layout, so databases migrated with the CLI are picked up where they left off.
+const schemaMigrationsTable = "schema_migrations"
+
+// migrationLockID keys the Postgres advisory lock held while migrating, so
+// instances starting together apply each migration once. Any constant works
+// as long as every instance uses the same one.
+const migrationLockID int64 = 7_262_001
+
+// ErrDirtyDatabase is returned when a migration run by another tool (such as
+// the golang-migrate CLI) failed part-way. The schema has to be repaired by
+// hand and the version set with Force.
+var ErrDirtyDatabase = errors.New("database is dirty")
+
+// Migration is one numbered schema change
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Migrator applies migrations to a database, one transaction per migration.
+// Up, Down and Force hold an advisory lock on a dedicated connection for the
+// whole run, so concurrent migrators wait for each other.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+	lock       func(ctx context.Context, conn *sql.Conn) (unlock func(), err error)
+}
+
+// sqlConn is satisfied by both *sql.DB and *sql.Conn
+type sqlConn interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// NewMigrator loads the migrations in fsys for db
+func NewMigrator(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations, lock: advisoryLock}, nil
+}
+
+// advisoryLock blocks until conn holds the migration lock. If the unlock
+// fails, the connection is discarded so the session and its lock end.
+func advisoryLock(ctx context.Context, conn *sql.Conn) (func(), error) {
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		return nil, fmt.Errorf("failed to take migration lock: %w", err)
+	}
+
+	return func() {
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockID); err != nil {
+			conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+	}, nil
+}
+
+// locked runs fn on a dedicated connection while holding the migration lock
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open migration connection: %w", err)
+	}
+	defer conn.Close()
+
+	unlock, err := m.lock(ctx, conn)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return fn(conn)
+}
+
+// LoadMigrations reads NNN_name.up.sql / NNN_name.down.sql pairs from the root
+// of fsys, ordered by version
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[uint]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+
+		version, name, direction, err := parseMigrationName(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		} else if m.Name != name {
+			return nil, fmt.Errorf("migration %d has two names: %q and %q", version, m.Name, name)
+		}
+
+		if direction == "up" {
+			m.Up = string(data)
+		} else {
+			m.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// parseMigrationName splits "000002_create_notes_table.up.sql"
+func parseMigrationName(filename string) (uint, string, string, error) {
+	base := strings.TrimSuffix(filename, ".sql")
+	direction := path.Ext(base)
+	if direction != ".up" && direction != ".down" {
+		return 0, "", "", fmt.Errorf("migration %s must end in .up.sql or .down.sql", filename)
+	}
+	base = strings.TrimSuffix(base, direction)
+
+	number, name, ok := strings.Cut(base, "_")
+	if !ok {
+		return 0, "", "", fmt.Errorf("migration %s must be named NNN_description", filename)
+	}
+	version, err := strconv.ParseUint(number, 10, 64)
+	if err != nil || version == 0 {
+		return 0, "", "", fmt.Errorf("migration %s has an invalid version", filename)
+	}
+	return uint(version), name, strings.TrimPrefix(direction, "."), nil
+}
+
+// Migrations returns the known migrations, oldest first
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Version returns the applied version (0 when none) and whether the last
+// migration failed part-way
+func (m *Migrator) Version(ctx context.Context) (uint, bool, error) {
+	return readVersion(ctx, m.db)
+}
+
+// readVersion reads the applied version through conn
+func readVersion(ctx context.Context, conn sqlConn) (uint, bool, error) {
+	if err := ensureTable(ctx, conn); err != nil {
+		return 0, false, err
+	}
+
+	var version int64
+	var dirty bool
+	err := conn.QueryRowContext(ctx, "SELECT version, dirty FROM "+schemaMigrationsTable+" LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return uint(version), dirty, nil
+}
+
+// Up applies every pending migration and returns how many ran
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied := 0
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		// Read under the lock, as another instance may have migrated while we waited
+		current, err := cleanVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if migration.Version <= current {
+				continue
+			}
+			if err := apply(ctx, conn, migration.Up, migration.Version); err != nil {
+				return fmt.Errorf("migration %d_%s up: %w", migration.Version, migration.Name, err)
+			}
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts up to steps applied migrations, newest first, and returns how
+// many ran. steps <= 0 reverts all of them.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	reverted := 0
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		current, err := cleanVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			if steps > 0 && reverted == steps {
+				break
+			}
+			migration := m.migrations[i]
+			if migration.Version > current {
+				continue
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("migration %d_%s has no down file", migration.Version, migration.Name)
+			}
+
+			var previous uint
+			if i > 0 {
+				previous = m.migrations[i-1].Version
+			}
+			if err := apply(ctx, conn, migration.Down, previous); err != nil {
+				return fmt.Errorf("migration %d_%s down: %w", migration.Version, migration.Name, err)
+			}
+			reverted++
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Force records version as applied and clean without running anything. It is
+// for recovering from a dirty database once the schema has been fixed by hand.
+func (m *Migrator) Force(ctx context.Context, version uint) error {
+	return m.locked(ctx, func(conn *sql.Conn) error {
+		if err := ensureTable(ctx, conn); err != nil {
+			return err
+		}
+
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if err := setVersion(ctx, tx, version); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
+// cleanVersion returns the applied version, refusing to continue from a dirty one
+func cleanVersion(ctx context.Context, conn sqlConn) (uint, error) {
+	version, dirty, err := readVersion(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+	if dirty {
+		return 0, fmt.Errorf("%w at version %d", ErrDirtyDatabase, version)
+	}
+	return version, nil
+}
+
+// apply runs one migration's SQL and records the resulting version in the same
+// transaction. Postgres DDL is transactional, so a failing migration leaves
+// neither schema changes nor a version bump behind.
+func apply(ctx context.Context, conn sqlConn, statements string, resulting uint) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, statements); err != nil {
+		return err
+	}
+	if err := setVersion(ctx, tx, resulting); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func ensureTable(ctx context.Context, conn sqlConn) error {
+	_, err := conn.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+schemaMigrationsTable+" (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)")
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", schemaMigrationsTable, err)
+	}
+	return nil
+}
+
+// setVersion replaces the single version row; version 0 means nothing applied
+func setVersion(ctx context.Context, tx *sql.Tx, version uint) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM "+schemaMigrationsTable); err != nil {
+		return fmt.Errorf("failed to clear schema version: %w", err)
+	}
+	if version == 0 {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO "+schemaMigrationsTable+" (version, dirty) VALUES ($1, false)", int64(version)); err != nil {
+		return fmt.Errorf("failed to record schema version: %w", err)
+	}
+	return nil
+}
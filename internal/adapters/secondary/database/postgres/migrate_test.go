@@ -0,0 +1,190 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/notinoteapp/internal/adapters/secondary/database/postgres/migrations"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupMigrateTestDB creates an in-memory SQLite database private to the test
+func setupMigrateTestDB(t *testing.T) *sql.DB {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	return sqlDB
+}
+
+// newTestMigrator returns a migrator for db without the Postgres advisory lock,
+// which SQLite doesn't have
+func newTestMigrator(t *testing.T, db *sql.DB, fsys fstest.MapFS) *Migrator {
+	migrator, err := NewMigrator(db, fsys)
+	require.NoError(t, err)
+	migrator.lock = func(ctx context.Context, conn *sql.Conn) (func(), error) {
+		return func() {}, nil
+	}
+	return migrator
+}
+
+var testMigrations = fstest.MapFS{
+	"000001_create_widgets.up.sql":    {Data: []byte("CREATE TABLE widgets (id INTEGER PRIMARY KEY);")},
+	"000001_create_widgets.down.sql":  {Data: []byte("DROP TABLE widgets;")},
+	"000002_add_widget_name.up.sql":   {Data: []byte("ALTER TABLE widgets ADD COLUMN name TEXT; CREATE INDEX idx_widgets_name ON widgets(name);")},
+	"000002_add_widget_name.down.sql": {Data: []byte("DROP INDEX idx_widgets_name; ALTER TABLE widgets DROP COLUMN name;")},
+	"README.md":                       {Data: []byte("not a migration")},
+}
+
+func tableExists(t *testing.T, db *sql.DB, name string) bool {
+	var count int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", name).Scan(&count))
+	return count > 0
+}
+
+func TestMigrator_UpDown(t *testing.T) {
+	ctx := context.Background()
+	db := setupMigrateTestDB(t)
+	migrator := newTestMigrator(t, db, testMigrations)
+
+	version, dirty, err := migrator.Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint(0), version)
+	assert.False(t, dirty)
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, applied)
+	_, err = db.Exec("INSERT INTO widgets (id, name) VALUES (1, 'gear')")
+	require.NoError(t, err)
+
+	// Nothing left to do
+	applied, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, applied)
+
+	version, _, err = migrator.Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), version)
+
+	reverted, err := migrator.Down(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, reverted)
+	version, _, err = migrator.Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), version)
+	assert.True(t, tableExists(t, db, "widgets"))
+
+	reverted, err = migrator.Down(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, reverted)
+	version, _, err = migrator.Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint(0), version)
+	assert.False(t, tableExists(t, db, "widgets"))
+}
+
+func TestMigrator_FailedMigrationRollsBack(t *testing.T) {
+	ctx := context.Background()
+	db := setupMigrateTestDB(t)
+
+	fsys := fstest.MapFS{
+		"000001_create_widgets.up.sql": testMigrations["000001_create_widgets.up.sql"],
+		"000002_broken.up.sql":         {Data: []byte("CREATE TABLE gadgets (id INTEGER); SELECT * FROM missing_table;")},
+	}
+	migrator := newTestMigrator(t, db, fsys)
+
+	applied, err := migrator.Up(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, 1, applied)
+
+	version, dirty, err := migrator.Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), version)
+	assert.False(t, dirty)
+	assert.False(t, tableExists(t, db, "gadgets"))
+}
+
+func TestMigrator_RefusesDirtyDatabase(t *testing.T) {
+	ctx := context.Background()
+	db := setupMigrateTestDB(t)
+	migrator := newTestMigrator(t, db, testMigrations)
+
+	// As left behind by a failed golang-migrate CLI run
+	_, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	_, err = db.Exec("UPDATE schema_migrations SET dirty = true")
+	require.NoError(t, err)
+
+	_, err = migrator.Down(ctx, 1)
+	assert.True(t, errors.Is(err, ErrDirtyDatabase))
+
+	require.NoError(t, migrator.Force(ctx, 2))
+	reverted, err := migrator.Down(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, reverted)
+}
+
+func TestMigrator_RereadsVersionUnderLock(t *testing.T) {
+	ctx := context.Background()
+	db := setupMigrateTestDB(t)
+	migrator := newTestMigrator(t, db, testMigrations)
+
+	// Another instance finishes migrating while this one waits for the lock
+	other := newTestMigrator(t, db, testMigrations)
+	var locks, unlocks int
+	migrator.lock = func(ctx context.Context, conn *sql.Conn) (func(), error) {
+		locks++
+		applied, err := other.Up(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 2, applied)
+		return func() { unlocks++ }, nil
+	}
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, applied)
+	assert.Equal(t, 1, locks)
+	assert.Equal(t, 1, unlocks)
+
+	// Released on failure too
+	_, err = db.Exec("UPDATE schema_migrations SET dirty = true")
+	require.NoError(t, err)
+	migrator.lock = func(ctx context.Context, conn *sql.Conn) (func(), error) {
+		locks++
+		return func() { unlocks++ }, nil
+	}
+	_, err = migrator.Down(ctx, 1)
+	assert.ErrorIs(t, err, ErrDirtyDatabase)
+	assert.Equal(t, 2, locks)
+	assert.Equal(t, 2, unlocks)
+}
+
+func TestLoadMigrations_Embedded(t *testing.T) {
+	loaded, err := LoadMigrations(migrations.FS)
+	require.NoError(t, err)
+	require.NotEmpty(t, loaded)
+
+	for i, m := range loaded {
+		assert.Equal(t, uint(i+1), m.Version, "versions should be contiguous")
+		assert.NotEmpty(t, m.Up, m.Name)
+		assert.NotEmpty(t, m.Down, m.Name)
+	}
+}
+
+func TestLoadMigrations_InvalidNames(t *testing.T) {
+	for _, name := range []string{"create_widgets.up.sql", "000001_create_widgets.sql", "abc_create_widgets.up.sql"} {
+		_, err := LoadMigrations(fstest.MapFS{name: {Data: []byte("SELECT 1;")}})
+		assert.Error(t, err, name)
+	}
+
+	_, err := LoadMigrations(fstest.MapFS{"000001_create_widgets.down.sql": {Data: []byte("DROP TABLE widgets;")}})
+	assert.Error(t, err, "a down file needs its up file")
+}
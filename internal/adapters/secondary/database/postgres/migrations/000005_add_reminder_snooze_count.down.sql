@@ -0,0 +1,2 @@
+-- Remove snooze_count column from note_reminders
+ALTER TABLE note_reminders DROP COLUMN IF EXISTS snooze_count;
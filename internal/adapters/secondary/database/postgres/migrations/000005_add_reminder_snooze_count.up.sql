@@ -0,0 +1,4 @@
+-- Track how many times a reminder has been snoozed since it last fired
+ALTER TABLE note_reminders ADD COLUMN snooze_count INTEGER NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN note_reminders.snooze_count IS 'Number of snoozes since the reminder last triggered';
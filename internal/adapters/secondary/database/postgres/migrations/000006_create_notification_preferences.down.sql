@@ -0,0 +1,9 @@
+-- Drop trigger
+DROP TRIGGER IF EXISTS update_notification_preferences_updated_at ON notification_preferences;
+
+-- Drop notification_preferences table
+DROP TABLE IF EXISTS notification_preferences;
+
+-- Note: PostgreSQL cannot drop enum values, so 'skipped_by_preference' stays on notification_status.
+-- Move any skipped logs to cancelled so nothing depends on it.
+UPDATE notification_logs SET status = 'cancelled' WHERE status = 'skipped_by_preference';
@@ -0,0 +1,24 @@
+-- Allow logging notifications that were skipped because of user preferences
+ALTER TYPE notification_status ADD VALUE IF NOT EXISTS 'skipped_by_preference';
+
+-- Per-user notification channel preferences
+-- Users without a row fall back to the defaults (push enabled, email disabled)
+CREATE TABLE notification_preferences (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    push_enabled BOOLEAN NOT NULL DEFAULT true,
+    email_enabled BOOLEAN NOT NULL DEFAULT false,
+    muted_repeat_types JSONB NOT NULL DEFAULT '[]', -- e.g. ["daily", "weekly"]
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create trigger for notification_preferences updated_at
+CREATE TRIGGER update_notification_preferences_updated_at
+    BEFORE UPDATE ON notification_preferences
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE notification_preferences IS 'Per-user notification channel preferences';
+COMMENT ON COLUMN notification_preferences.push_enabled IS 'Whether push notifications are delivered';
+COMMENT ON COLUMN notification_preferences.email_enabled IS 'Whether email notifications are delivered';
+COMMENT ON COLUMN notification_preferences.muted_repeat_types IS 'Reminder repeat types the user does not want notifications for';
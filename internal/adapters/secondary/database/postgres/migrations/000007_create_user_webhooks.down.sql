@@ -0,0 +1,8 @@
+-- Drop trigger
+DROP TRIGGER IF EXISTS update_user_webhooks_updated_at ON user_webhooks;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_user_webhooks_user_id;
+
+-- Drop user_webhooks table
+DROP TABLE IF EXISTS user_webhooks;
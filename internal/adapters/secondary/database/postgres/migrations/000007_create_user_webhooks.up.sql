@@ -0,0 +1,22 @@
+-- Outgoing webhooks that receive reminder events
+CREATE TABLE user_webhooks (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    secret VARCHAR(128) NOT NULL, -- HMAC-SHA256 signing secret
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Find active webhooks for a user when a reminder fires
+CREATE INDEX idx_user_webhooks_user_id ON user_webhooks(user_id) WHERE is_active = true;
+
+-- Create trigger for user_webhooks updated_at
+CREATE TRIGGER update_user_webhooks_updated_at
+    BEFORE UPDATE ON user_webhooks
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE user_webhooks IS 'User-registered webhook URLs that receive signed reminder events';
+COMMENT ON COLUMN user_webhooks.secret IS 'Secret used to sign request bodies (X-NotiNote-Signature header)';
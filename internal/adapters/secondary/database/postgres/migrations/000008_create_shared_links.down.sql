@@ -0,0 +1,6 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_shared_links_note;
+DROP INDEX IF EXISTS idx_shared_links_token;
+
+-- Drop shared_links table
+DROP TABLE IF EXISTS shared_links;
@@ -0,0 +1,23 @@
+-- Read-only links that expose a note without login
+CREATE TABLE shared_links (
+    id BIGSERIAL PRIMARY KEY,
+    token VARCHAR(64) NOT NULL,
+    note_id BIGINT NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    permission VARCHAR(20) NOT NULL DEFAULT 'read',
+    expires_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_shared_links_permission CHECK (permission IN ('read'))
+);
+
+-- Tokens are looked up on every public request
+CREATE UNIQUE INDEX idx_shared_links_token ON shared_links(token);
+
+-- List links for a note
+CREATE INDEX idx_shared_links_note ON shared_links(note_id);
+
+COMMENT ON TABLE shared_links IS 'Unguessable links that grant read-only access to a note';
+COMMENT ON COLUMN shared_links.token IS 'Random URL-safe token used in /shared/:token';
+COMMENT ON COLUMN shared_links.expires_at IS 'Optional expiry; NULL means the link never expires';
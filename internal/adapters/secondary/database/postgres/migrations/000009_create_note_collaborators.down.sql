@@ -0,0 +1,9 @@
+-- Drop trigger
+DROP TRIGGER IF EXISTS update_note_collaborators_updated_at ON note_collaborators;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_note_collaborators_user;
+DROP INDEX IF EXISTS idx_note_collaborators_note_user;
+
+-- Drop note_collaborators table
+DROP TABLE IF EXISTS note_collaborators;
@@ -0,0 +1,26 @@
+-- Users granted access to a note and its descendants by the owner
+CREATE TABLE note_collaborators (
+    id BIGSERIAL PRIMARY KEY,
+    note_id BIGINT NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_note_collaborators_role CHECK (role IN ('viewer', 'editor'))
+);
+
+-- One grant per user per note; also serves lookups by note
+CREATE UNIQUE INDEX idx_note_collaborators_note_user ON note_collaborators(note_id, user_id);
+
+-- Access checks look up a user's grants on a note's ancestors
+CREATE INDEX idx_note_collaborators_user ON note_collaborators(user_id);
+
+-- Create trigger for note_collaborators updated_at
+CREATE TRIGGER update_note_collaborators_updated_at
+    BEFORE UPDATE ON note_collaborators
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE note_collaborators IS 'Users granted viewer or editor access to a note subtree';
+COMMENT ON COLUMN note_collaborators.role IS 'viewer: read-only; editor: may modify content but not delete or re-share';
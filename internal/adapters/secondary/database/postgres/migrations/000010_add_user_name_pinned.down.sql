@@ -0,0 +1,2 @@
+-- Remove name_pinned column from users
+ALTER TABLE users DROP COLUMN IF EXISTS name_pinned;
@@ -0,0 +1,4 @@
+-- Let users keep a locally-set name instead of the one from their OAuth provider
+ALTER TABLE users ADD COLUMN name_pinned BOOLEAN NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN users.name_pinned IS 'Whether OAuth sign-ins keep the locally-set name';
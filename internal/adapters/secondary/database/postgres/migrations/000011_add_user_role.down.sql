@@ -0,0 +1,2 @@
+-- Remove role column from users
+ALTER TABLE users DROP COLUMN IF EXISTS role;
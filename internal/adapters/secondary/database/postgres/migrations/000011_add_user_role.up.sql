@@ -0,0 +1,5 @@
+-- Add a role to users for admin-only endpoints. Admins are promoted manually:
+--   UPDATE users SET role = 'admin' WHERE email = '...';
+ALTER TABLE users ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'user';
+
+COMMENT ON COLUMN users.role IS 'User role (user, admin)';
@@ -0,0 +1,3 @@
+-- Drop blocks size tracking
+DROP INDEX IF EXISTS idx_notes_user_blocks_size;
+ALTER TABLE notes DROP COLUMN IF EXISTS blocks_size;
@@ -0,0 +1,7 @@
+-- Track each note's block storage so per-user quotas can be summed without
+-- reading the blocks themselves
+ALTER TABLE notes ADD COLUMN blocks_size BIGINT GENERATED ALWAYS AS (octet_length(blocks::text)) STORED;
+
+CREATE INDEX idx_notes_user_blocks_size ON notes(user_id) INCLUDE (blocks_size);
+
+COMMENT ON COLUMN notes.blocks_size IS 'Size of the blocks column in bytes, counted against the storage quota';
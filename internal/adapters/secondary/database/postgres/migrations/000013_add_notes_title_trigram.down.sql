@@ -0,0 +1,3 @@
+-- Drop fuzzy title search support. The pg_trgm extension is left installed,
+-- since other schemas in the database may rely on it.
+DROP INDEX IF EXISTS idx_notes_title_trgm;
@@ -0,0 +1,8 @@
+-- Fuzzy title search (GET /notes/search?fuzzy=true) falls back to trigram
+-- similarity when full-text search finds nothing. pg_trgm ships with
+-- PostgreSQL's contrib modules; creating it needs a role allowed to create
+-- extensions (superuser, or database owner on PostgreSQL 13+ since it is trusted).
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Serves both the similarity operator (title % query) and ILIKE substring matches
+CREATE INDEX idx_notes_title_trgm ON notes USING GIN (title gin_trgm_ops) WHERE is_deleted = false;
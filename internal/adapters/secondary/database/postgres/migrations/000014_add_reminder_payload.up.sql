@@ -0,0 +1,4 @@
+-- Per-reminder push notification options (click URL, sound, badge)
+ALTER TABLE note_reminders ADD COLUMN payload JSONB;
+
+COMMENT ON COLUMN note_reminders.payload IS 'Notification customization; NULL uses the defaults';
@@ -0,0 +1,2 @@
+-- Remove language column from users
+ALTER TABLE users DROP COLUMN IF EXISTS language;
@@ -0,0 +1,4 @@
+-- Language the user's notifications are sent in (ISO 639-1 code)
+ALTER TABLE users ADD COLUMN language VARCHAR(10) NOT NULL DEFAULT 'en';
+
+COMMENT ON COLUMN users.language IS 'Language of notifications, e.g. en or th';
@@ -0,0 +1,7 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_notification_dead_letters_user_id;
+DROP INDEX IF EXISTS idx_notification_dead_letters_unresolved;
+DROP INDEX IF EXISTS idx_notification_dead_letters_created_at;
+
+-- Drop notification_dead_letters table
+DROP TABLE IF EXISTS notification_dead_letters;
@@ -0,0 +1,34 @@
+-- Notifications that could not be delivered, kept for inspection and replay
+CREATE TABLE notification_dead_letters (
+    id BIGSERIAL PRIMARY KEY,
+    notification_log_id BIGINT REFERENCES notification_logs(id) ON DELETE SET NULL,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reminder_id BIGINT REFERENCES note_reminders(id) ON DELETE SET NULL,
+    device_id BIGINT REFERENCES user_devices(id) ON DELETE SET NULL,
+
+    channel VARCHAR(20) NOT NULL,
+    device_type device_type,
+    device_name VARCHAR(255),
+
+    title VARCHAR(255) NOT NULL,
+    body TEXT,
+    data JSONB,
+
+    error_message TEXT NOT NULL,
+    replay_count INTEGER NOT NULL DEFAULT 0,
+    last_replayed_at TIMESTAMPTZ,
+    resolved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Admin listing, newest first, usually only what is still undelivered
+CREATE INDEX idx_notification_dead_letters_created_at ON notification_dead_letters(created_at DESC);
+CREATE INDEX idx_notification_dead_letters_unresolved ON notification_dead_letters(created_at DESC)
+    WHERE resolved_at IS NULL;
+CREATE INDEX idx_notification_dead_letters_user_id ON notification_dead_letters(user_id);
+
+COMMENT ON TABLE notification_dead_letters IS 'Permanently failed notifications, replayable by admins';
+COMMENT ON COLUMN notification_dead_letters.notification_log_id IS 'Log of the failed attempt (null once cleaned up)';
+COMMENT ON COLUMN notification_dead_letters.channel IS 'Delivery channel: push, email or webhook';
+COMMENT ON COLUMN notification_dead_letters.error_message IS 'Error from the last failed attempt';
+COMMENT ON COLUMN notification_dead_letters.resolved_at IS 'When a replay delivered the notification';
@@ -0,0 +1,2 @@
+-- Remove app_version column from user_devices
+ALTER TABLE user_devices DROP COLUMN IF EXISTS app_version;
@@ -0,0 +1,4 @@
+-- Version of the app a device runs, shown when listing devices
+ALTER TABLE user_devices ADD COLUMN app_version VARCHAR(50);
+
+COMMENT ON COLUMN user_devices.app_version IS 'App version reported by the client, e.g. 2.4.1';
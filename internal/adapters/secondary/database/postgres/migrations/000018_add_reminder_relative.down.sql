@@ -0,0 +1,2 @@
+-- Remove relative column from note_reminders
+ALTER TABLE note_reminders DROP COLUMN IF EXISTS relative;
@@ -0,0 +1,4 @@
+-- Reminders that fire relative to a date property of their note
+ALTER TABLE note_reminders ADD COLUMN relative JSONB;
+
+COMMENT ON COLUMN note_reminders.relative IS 'Property ID and offset in minutes; NULL fires at scheduled_at';
@@ -0,0 +1,10 @@
+-- Feed-only tokens for subscribing to the reminder calendar
+CREATE TABLE calendar_feeds (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL, -- SHA-256 of the token in the feed URL
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_calendar_feeds_token_hash ON calendar_feeds(token_hash);
+
+COMMENT ON TABLE calendar_feeds IS 'Revocable tokens that only grant read access to a user''s reminder calendar';
@@ -0,0 +1,13 @@
+// Package migrations embeds the SQL schema migrations so the binaries can
+// apply them without the files on disk.
+//
+// Files follow golang-migrate's naming, NNNNNN_description.up.sql and
+// NNNNNN_description.down.sql, so `make migrate-create` keeps working.
+package migrations
+
+import "embed"
+
+// FS holds every migration file
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+)
+
+// CalendarFeed represents the database model for calendar feed tokens
+type CalendarFeed struct {
+	UserID    int64     `gorm:"primaryKey"`
+	TokenHash string    `gorm:"type:varchar(64);not null;uniqueIndex:idx_calendar_feeds_token_hash"`
+	CreatedAt time.Time `gorm:"type:timestamptz;autoCreateTime"`
+}
+
+// TableName specifies the table name for GORM
+func (CalendarFeed) TableName() string {
+	return "calendar_feeds"
+}
+
+// ToDomain converts database model to domain entity
+func (f *CalendarFeed) ToDomain() *domain.CalendarFeed {
+	return &domain.CalendarFeed{
+		UserID:    f.UserID,
+		TokenHash: f.TokenHash,
+		CreatedAt: f.CreatedAt,
+	}
+}
+
+// FromDomain converts domain entity to database model
+func (f *CalendarFeed) FromDomain(feed *domain.CalendarFeed) {
+	f.UserID = feed.UserID
+	f.TokenHash = feed.TokenHash
+	f.CreatedAt = feed.CreatedAt
+}
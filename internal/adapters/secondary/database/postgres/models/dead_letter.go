@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+)
+
+// DeadLetter represents the database model for undeliverable notifications
+type DeadLetter struct {
+	ID                int64                      `gorm:"primaryKey;autoIncrement"`
+	NotificationLogID *int64                     `gorm:"type:bigint"`
+	UserID            int64                      `gorm:"not null;index:idx_dead_letter_user"`
+	ReminderID        *int64                     `gorm:"type:bigint"`
+	DeviceID          *int64                     `gorm:"type:bigint"`
+	Channel           domain.NotificationChannel `gorm:"type:varchar(20);not null"`
+	DeviceType        *domain.DeviceType         `gorm:"type:device_type"`
+	DeviceName        string                     `gorm:"type:varchar(255)"`
+	Title             string                     `gorm:"type:varchar(255);not null"`
+	Body              string                     `gorm:"type:text"`
+	Data              StringMapJSON              `gorm:"type:jsonb"`
+	ErrorMessage      string                     `gorm:"type:text;not null"`
+	ReplayCount       int                        `gorm:"not null;default:0"`
+	LastReplayedAt    *time.Time                 `gorm:"type:timestamptz"`
+	ResolvedAt        *time.Time                 `gorm:"type:timestamptz"`
+	CreatedAt         time.Time                  `gorm:"type:timestamptz;autoCreateTime;index:idx_dead_letter_created,sort:desc"`
+}
+
+// TableName specifies the table name for GORM
+func (DeadLetter) TableName() string {
+	return "notification_dead_letters"
+}
+
+// ToDomain converts database model to domain entity
+func (d *DeadLetter) ToDomain() *domain.DeadLetter {
+	letter := &domain.DeadLetter{
+		ID:                d.ID,
+		NotificationLogID: d.NotificationLogID,
+		UserID:            d.UserID,
+		ReminderID:        d.ReminderID,
+		DeviceID:          d.DeviceID,
+		Channel:           d.Channel,
+		DeviceName:        d.DeviceName,
+		Title:             d.Title,
+		Body:              d.Body,
+		Data:              d.Data,
+		ErrorMessage:      d.ErrorMessage,
+		ReplayCount:       d.ReplayCount,
+		LastReplayedAt:    d.LastReplayedAt,
+		ResolvedAt:        d.ResolvedAt,
+		CreatedAt:         d.CreatedAt,
+	}
+	if d.DeviceType != nil {
+		letter.DeviceType = *d.DeviceType
+	}
+	return letter
+}
+
+// FromDomain converts domain entity to database model
+func (d *DeadLetter) FromDomain(letter *domain.DeadLetter) {
+	d.ID = letter.ID
+	d.NotificationLogID = letter.NotificationLogID
+	d.UserID = letter.UserID
+	d.ReminderID = letter.ReminderID
+	d.DeviceID = letter.DeviceID
+	d.Channel = letter.Channel
+	d.DeviceType = nil
+	if letter.DeviceType != "" {
+		deviceType := letter.DeviceType
+		d.DeviceType = &deviceType // the column is an enum, so store null rather than ""
+	}
+	d.DeviceName = letter.DeviceName
+	d.Title = letter.Title
+	d.Body = letter.Body
+	d.Data = letter.Data
+	d.ErrorMessage = letter.ErrorMessage
+	d.ReplayCount = letter.ReplayCount
+	d.LastReplayedAt = letter.LastReplayedAt
+	d.ResolvedAt = letter.ResolvedAt
+	d.CreatedAt = letter.CreatedAt
+}
@@ -13,6 +13,7 @@ type Device struct {
 	DeviceToken string            `gorm:"type:text;not null;index:idx_device_token"`
 	DeviceType  domain.DeviceType `gorm:"type:device_type;not null"`
 	DeviceName  string            `gorm:"size:255"`
+	AppVersion  string            `gorm:"size:50"`
 	BrowserInfo string            `gorm:"size:255"`
 	IsActive    bool              `gorm:"not null;default:true"`
 	LastUsedAt  *time.Time        `gorm:"type:timestamptz"`
@@ -33,6 +34,7 @@ func (d *Device) ToDomain() *domain.Device {
 		DeviceToken: d.DeviceToken,
 		DeviceType:  d.DeviceType,
 		DeviceName:  d.DeviceName,
+		AppVersion:  d.AppVersion,
 		BrowserInfo: d.BrowserInfo,
 		IsActive:    d.IsActive,
 		LastUsedAt:  d.LastUsedAt,
@@ -48,6 +50,7 @@ func (d *Device) FromDomain(domainDevice *domain.Device) {
 	d.DeviceToken = domainDevice.DeviceToken
 	d.DeviceType = domainDevice.DeviceType
 	d.DeviceName = domainDevice.DeviceName
+	d.AppVersion = domainDevice.AppVersion
 	d.BrowserInfo = domainDevice.BrowserInfo
 	d.IsActive = domainDevice.IsActive
 	d.LastUsedAt = domainDevice.LastUsedAt
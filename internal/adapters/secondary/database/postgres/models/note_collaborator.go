@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+)
+
+// NoteCollaborator represents the database model for note collaborators
+type NoteCollaborator struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement"`
+	NoteID    int64     `gorm:"not null;uniqueIndex:idx_note_collaborators_note_user"`
+	UserID    int64     `gorm:"not null;uniqueIndex:idx_note_collaborators_note_user;index:idx_note_collaborators_user"`
+	Role      string    `gorm:"type:varchar(20);not null"`
+	CreatedAt time.Time `gorm:"type:timestamptz;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"type:timestamptz;autoUpdateTime"`
+}
+
+// TableName specifies the table name for GORM
+func (NoteCollaborator) TableName() string {
+	return "note_collaborators"
+}
+
+// ToDomain converts database model to domain entity
+func (c *NoteCollaborator) ToDomain() *domain.NoteCollaborator {
+	return &domain.NoteCollaborator{
+		ID:        c.ID,
+		NoteID:    c.NoteID,
+		UserID:    c.UserID,
+		Role:      domain.CollaboratorRole(c.Role),
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
+
+// FromDomain converts domain entity to database model
+func (c *NoteCollaborator) FromDomain(collaborator *domain.NoteCollaborator) {
+	c.ID = collaborator.ID
+	c.NoteID = collaborator.NoteID
+	c.UserID = collaborator.UserID
+	c.Role = string(collaborator.Role)
+	c.CreatedAt = collaborator.CreatedAt
+	c.UpdatedAt = collaborator.UpdatedAt
+}
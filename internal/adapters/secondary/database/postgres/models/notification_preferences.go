@@ -0,0 +1,82 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+)
+
+// RepeatTypesJSON is a wrapper for a list of repeat types stored as JSONB
+type RepeatTypesJSON []domain.RepeatType
+
+// Scan implements the sql.Scanner interface for RepeatTypesJSON
+func (r *RepeatTypesJSON) Scan(value interface{}) error {
+	if value == nil {
+		*r = nil
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+
+	var types []domain.RepeatType
+	if err := json.Unmarshal(bytes, &types); err != nil {
+		return err
+	}
+	*r = types
+	return nil
+}
+
+// Value implements the driver.Valuer interface for RepeatTypesJSON
+func (r RepeatTypesJSON) Value() (driver.Value, error) {
+	if r == nil {
+		return json.Marshal([]domain.RepeatType{})
+	}
+	return json.Marshal([]domain.RepeatType(r))
+}
+
+// NotificationPreferences represents the database model for notification preferences
+type NotificationPreferences struct {
+	UserID           int64           `gorm:"primaryKey"`
+	PushEnabled      bool            `gorm:"not null;default:true"`
+	EmailEnabled     bool            `gorm:"not null;default:false"`
+	MutedRepeatTypes RepeatTypesJSON `gorm:"type:jsonb;not null;default:'[]'"`
+	CreatedAt        time.Time       `gorm:"type:timestamptz;autoCreateTime"`
+	UpdatedAt        time.Time       `gorm:"type:timestamptz;autoUpdateTime"`
+}
+
+// TableName specifies the table name for GORM
+func (NotificationPreferences) TableName() string {
+	return "notification_preferences"
+}
+
+// ToDomain converts database model to domain entity
+func (p *NotificationPreferences) ToDomain() *domain.NotificationPreferences {
+	muted := []domain.RepeatType(p.MutedRepeatTypes)
+	if muted == nil {
+		muted = []domain.RepeatType{}
+	}
+
+	return &domain.NotificationPreferences{
+		UserID:           p.UserID,
+		PushEnabled:      p.PushEnabled,
+		EmailEnabled:     p.EmailEnabled,
+		MutedRepeatTypes: muted,
+		CreatedAt:        p.CreatedAt,
+		UpdatedAt:        p.UpdatedAt,
+	}
+}
+
+// FromDomain converts domain entity to database model
+func (p *NotificationPreferences) FromDomain(prefs *domain.NotificationPreferences) {
+	p.UserID = prefs.UserID
+	p.PushEnabled = prefs.PushEnabled
+	p.EmailEnabled = prefs.EmailEnabled
+	p.MutedRepeatTypes = RepeatTypesJSON(prefs.MutedRepeatTypes)
+	p.CreatedAt = prefs.CreatedAt
+	p.UpdatedAt = prefs.UpdatedAt
+}
@@ -41,23 +41,104 @@ func (r RepeatConfigJSON) Value() (driver.Value, error) {
 	return json.Marshal(r.RepeatConfig)
 }
 
+// ReminderPayloadJSON is a wrapper for ReminderPayload to handle JSON serialization with GORM
+type ReminderPayloadJSON struct {
+	*domain.ReminderPayload
+}
+
+// Scan implements the sql.Scanner interface for ReminderPayloadJSON
+func (p *ReminderPayloadJSON) Scan(value interface{}) error {
+	if value == nil {
+		p.ReminderPayload = nil
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return nil
+	}
+
+	var payload domain.ReminderPayload
+	if err := json.Unmarshal(bytes, &payload); err != nil {
+		return err
+	}
+	p.ReminderPayload = &payload
+	return nil
+}
+
+// Value implements the driver.Valuer interface for ReminderPayloadJSON
+func (p ReminderPayloadJSON) Value() (driver.Value, error) {
+	if p.ReminderPayload == nil {
+		return nil, nil
+	}
+	return json.Marshal(p.ReminderPayload)
+}
+
+// RelativeScheduleJSON is a wrapper for RelativeSchedule to handle JSON serialization with GORM
+type RelativeScheduleJSON struct {
+	*domain.RelativeSchedule
+}
+
+// Scan implements the sql.Scanner interface for RelativeScheduleJSON
+func (s *RelativeScheduleJSON) Scan(value interface{}) error {
+	if value == nil {
+		s.RelativeSchedule = nil
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return nil
+	}
+
+	var schedule domain.RelativeSchedule
+	if err := json.Unmarshal(bytes, &schedule); err != nil {
+		return err
+	}
+	s.RelativeSchedule = &schedule
+	return nil
+}
+
+// Value implements the driver.Valuer interface for RelativeScheduleJSON
+func (s RelativeScheduleJSON) Value() (driver.Value, error) {
+	if s.RelativeSchedule == nil {
+		return nil, nil
+	}
+	return json.Marshal(s.RelativeSchedule)
+}
+
 // Reminder represents the database model for note reminders
 type Reminder struct {
-	ID              int64              `gorm:"primaryKey;autoIncrement"`
-	NoteID          int64              `gorm:"not null;index:idx_reminder_note"`
-	UserID          int64              `gorm:"not null;index:idx_reminder_user"`
-	Title           string             `gorm:"type:varchar(255);not null"`
-	Message         string             `gorm:"type:text"`
-	ScheduledAt     time.Time          `gorm:"type:timestamptz;not null"`
-	RepeatType      domain.RepeatType  `gorm:"type:repeat_type;not null;default:'once'"`
-	RepeatConfig    RepeatConfigJSON   `gorm:"type:jsonb"`
-	RepeatEndAt     *time.Time         `gorm:"type:timestamptz"`
-	IsEnabled       bool               `gorm:"not null;default:true"`
-	NextTriggerAt   time.Time          `gorm:"type:timestamptz;not null;index:idx_reminder_trigger,where:is_enabled = true"`
-	LastTriggeredAt *time.Time         `gorm:"type:timestamptz"`
-	TriggerCount    int                `gorm:"not null;default:0"`
-	CreatedAt       time.Time          `gorm:"type:timestamptz;autoCreateTime"`
-	UpdatedAt       time.Time          `gorm:"type:timestamptz;autoUpdateTime"`
+	ID              int64                `gorm:"primaryKey;autoIncrement"`
+	NoteID          int64                `gorm:"not null;index:idx_reminder_note"`
+	UserID          int64                `gorm:"not null;index:idx_reminder_user"`
+	Title           string               `gorm:"type:varchar(255);not null"`
+	Message         string               `gorm:"type:text"`
+	ScheduledAt     time.Time            `gorm:"type:timestamptz;not null"`
+	RepeatType      domain.RepeatType    `gorm:"type:repeat_type;not null;default:'once'"`
+	RepeatConfig    RepeatConfigJSON     `gorm:"type:jsonb"`
+	RepeatEndAt     *time.Time           `gorm:"type:timestamptz"`
+	Payload         ReminderPayloadJSON  `gorm:"type:jsonb"`
+	Relative        RelativeScheduleJSON `gorm:"type:jsonb"`
+	IsEnabled       bool                 `gorm:"not null;default:true"`
+	NextTriggerAt   time.Time            `gorm:"type:timestamptz;not null;index:idx_reminder_trigger,where:is_enabled = true"`
+	LastTriggeredAt *time.Time           `gorm:"type:timestamptz"`
+	TriggerCount    int                  `gorm:"not null;default:0"`
+	SnoozeCount     int                  `gorm:"not null;default:0"`
+	CreatedAt       time.Time            `gorm:"type:timestamptz;autoCreateTime"`
+	UpdatedAt       time.Time            `gorm:"type:timestamptz;autoUpdateTime"`
+	Note            *Note                `gorm:"foreignKey:NoteID"`
+	User            *User                `gorm:"foreignKey:UserID"`
 }
 
 // TableName specifies the table name for GORM
@@ -67,7 +148,7 @@ func (Reminder) TableName() string {
 
 // ToDomain converts database model to domain entity
 func (r *Reminder) ToDomain() *domain.Reminder {
-	return &domain.Reminder{
+	reminder := &domain.Reminder{
 		ID:              r.ID,
 		NoteID:          r.NoteID,
 		UserID:          r.UserID,
@@ -77,13 +158,26 @@ func (r *Reminder) ToDomain() *domain.Reminder {
 		RepeatType:      r.RepeatType,
 		RepeatConfig:    r.RepeatConfig.RepeatConfig,
 		RepeatEndAt:     r.RepeatEndAt,
+		Payload:         r.Payload.ReminderPayload,
+		Relative:        r.Relative.RelativeSchedule,
 		IsEnabled:       r.IsEnabled,
 		NextTriggerAt:   r.NextTriggerAt,
 		LastTriggeredAt: r.LastTriggeredAt,
 		TriggerCount:    r.TriggerCount,
+		SnoozeCount:     r.SnoozeCount,
 		CreatedAt:       r.CreatedAt,
 		UpdatedAt:       r.UpdatedAt,
 	}
+
+	if r.Note != nil {
+		reminder.Note = r.Note.ToDomain()
+	}
+
+	if r.User != nil {
+		reminder.User = r.User.ToDomain()
+	}
+
+	return reminder
 }
 
 // FromDomain converts domain entity to database model
@@ -97,10 +191,13 @@ func (r *Reminder) FromDomain(domainReminder *domain.Reminder) {
 	r.RepeatType = domainReminder.RepeatType
 	r.RepeatConfig = RepeatConfigJSON{RepeatConfig: domainReminder.RepeatConfig}
 	r.RepeatEndAt = domainReminder.RepeatEndAt
+	r.Payload = ReminderPayloadJSON{ReminderPayload: domainReminder.Payload}
+	r.Relative = RelativeScheduleJSON{RelativeSchedule: domainReminder.Relative}
 	r.IsEnabled = domainReminder.IsEnabled
 	r.NextTriggerAt = domainReminder.NextTriggerAt
 	r.LastTriggeredAt = domainReminder.LastTriggeredAt
 	r.TriggerCount = domainReminder.TriggerCount
+	r.SnoozeCount = domainReminder.SnoozeCount
 	r.CreatedAt = domainReminder.CreatedAt
 	r.UpdatedAt = domainReminder.UpdatedAt
 }
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+)
+
+// SharedLink represents the database model for shared note links
+type SharedLink struct {
+	ID         int64      `gorm:"primaryKey;autoIncrement"`
+	Token      string     `gorm:"type:varchar(64);not null;uniqueIndex:idx_shared_links_token"`
+	NoteID     int64      `gorm:"not null;index:idx_shared_links_note"`
+	UserID     int64      `gorm:"not null"`
+	Permission string     `gorm:"type:varchar(20);not null;default:'read'"`
+	ExpiresAt  *time.Time `gorm:"type:timestamptz"`
+	RevokedAt  *time.Time `gorm:"type:timestamptz"`
+	CreatedAt  time.Time  `gorm:"type:timestamptz;autoCreateTime"`
+}
+
+// TableName specifies the table name for GORM
+func (SharedLink) TableName() string {
+	return "shared_links"
+}
+
+// ToDomain converts database model to domain entity
+func (l *SharedLink) ToDomain() *domain.SharedLink {
+	return &domain.SharedLink{
+		ID:         l.ID,
+		Token:      l.Token,
+		NoteID:     l.NoteID,
+		UserID:     l.UserID,
+		Permission: domain.SharePermission(l.Permission),
+		ExpiresAt:  l.ExpiresAt,
+		RevokedAt:  l.RevokedAt,
+		CreatedAt:  l.CreatedAt,
+	}
+}
+
+// FromDomain converts domain entity to database model
+func (l *SharedLink) FromDomain(link *domain.SharedLink) {
+	l.ID = link.ID
+	l.Token = link.Token
+	l.NoteID = link.NoteID
+	l.UserID = link.UserID
+	l.Permission = string(link.Permission)
+	l.ExpiresAt = link.ExpiresAt
+	l.RevokedAt = link.RevokedAt
+	l.CreatedAt = link.CreatedAt
+}
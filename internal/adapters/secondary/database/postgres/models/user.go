@@ -16,6 +16,9 @@ type User struct {
 	Provider     domain.AuthProvider `gorm:"type:varchar(20);not null;default:'email'"`
 	ProviderID   string            `gorm:"size:255;index:idx_provider_id"`
 	AvatarURL    string            `gorm:"size:500"`
+	NamePinned   bool              `gorm:"not null;default:false"`
+	Language     string            `gorm:"size:10;not null;default:'en'"`
+	Role         domain.Role       `gorm:"type:varchar(20);not null;default:'user'"`
 	IsActive     bool              `gorm:"not null;default:true"`
 	CreatedAt    time.Time         `gorm:"autoCreateTime"`
 	UpdatedAt    time.Time         `gorm:"autoUpdateTime"`
@@ -37,6 +40,9 @@ func (u *User) ToDomain() *domain.User {
 		Provider:     u.Provider,
 		ProviderID:   u.ProviderID,
 		AvatarURL:    u.AvatarURL,
+		NamePinned:   u.NamePinned,
+		Language:     u.Language,
+		Role:         u.Role,
 		IsActive:     u.IsActive,
 		CreatedAt:    u.CreatedAt,
 		UpdatedAt:    u.UpdatedAt,
@@ -52,6 +58,12 @@ func (u *User) FromDomain(domainUser *domain.User) {
 	u.Provider = domainUser.Provider
 	u.ProviderID = domainUser.ProviderID
 	u.AvatarURL = domainUser.AvatarURL
+	u.NamePinned = domainUser.NamePinned
+	u.Language = domainUser.Language
+	if u.Language == "" {
+		u.Language = domain.DefaultLanguage
+	}
+	u.Role = domainUser.Role
 	u.IsActive = domainUser.IsActive
 	u.CreatedAt = domainUser.CreatedAt
 	u.UpdatedAt = domainUser.UpdatedAt
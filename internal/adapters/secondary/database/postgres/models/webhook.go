@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+)
+
+// Webhook represents the database model for user webhooks
+type Webhook struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement"`
+	UserID    int64     `gorm:"not null;index:idx_webhook_user"`
+	URL       string    `gorm:"type:text;not null"`
+	Secret    string    `gorm:"type:varchar(128);not null"`
+	IsActive  bool      `gorm:"not null;default:true"`
+	CreatedAt time.Time `gorm:"type:timestamptz;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"type:timestamptz;autoUpdateTime"`
+}
+
+// TableName specifies the table name for GORM
+func (Webhook) TableName() string {
+	return "user_webhooks"
+}
+
+// ToDomain converts database model to domain entity
+func (w *Webhook) ToDomain() *domain.Webhook {
+	return &domain.Webhook{
+		ID:        w.ID,
+		UserID:    w.UserID,
+		URL:       w.URL,
+		Secret:    w.Secret,
+		IsActive:  w.IsActive,
+		CreatedAt: w.CreatedAt,
+		UpdatedAt: w.UpdatedAt,
+	}
+}
+
+// FromDomain converts domain entity to database model
+func (w *Webhook) FromDomain(webhook *domain.Webhook) {
+	w.ID = webhook.ID
+	w.UserID = webhook.UserID
+	w.URL = webhook.URL
+	w.Secret = webhook.Secret
+	w.IsActive = webhook.IsActive
+	w.CreatedAt = webhook.CreatedAt
+	w.UpdatedAt = webhook.UpdatedAt
+}
@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// poolWarnPercent is how much of MaxOpenConns may be in use before the pool
+// monitor warns
+const poolWarnPercent = 80
+
+// PoolMonitor periodically checks the connection pool and warns when in-use
+// connections approach the configured maximum
+type PoolMonitor struct {
+	db       *sql.DB
+	interval time.Duration
+	logger   *logrus.Logger
+	stopCh   chan struct{}
+	wg       sync.WaitGroup
+	running  bool
+	mu       sync.Mutex
+
+	lastWaitCount int64
+}
+
+// NewPoolMonitor creates a new pool monitor
+func NewPoolMonitor(db *sql.DB, interval time.Duration, logger *logrus.Logger) *PoolMonitor {
+	return &PoolMonitor{
+		db:       db,
+		interval: interval,
+		logger:   logger,
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Start begins checking the pool
+func (m *PoolMonitor) Start() {
+	m.mu.Lock()
+	if m.running {
+		m.mu.Unlock()
+		return
+	}
+	m.running = true
+	m.stopCh = make(chan struct{})
+	m.mu.Unlock()
+
+	m.wg.Add(1)
+	go m.run()
+}
+
+// Stop stops the monitor
+func (m *PoolMonitor) Stop() {
+	m.mu.Lock()
+	if !m.running {
+		m.mu.Unlock()
+		return
+	}
+	m.running = false
+	m.mu.Unlock()
+
+	close(m.stopCh)
+	m.wg.Wait()
+}
+
+func (m *PoolMonitor) run() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stopCh:
+			return
+		case <-ticker.C:
+			m.check()
+		}
+	}
+}
+
+// check warns if the pool is nearly exhausted or requests had to wait for a
+// connection since the last check
+func (m *PoolMonitor) check() {
+	stats := m.db.Stats()
+	waited := stats.WaitCount - m.lastWaitCount
+	m.lastWaitCount = stats.WaitCount
+
+	nearlyFull := stats.MaxOpenConnections > 0 && stats.InUse*100 >= stats.MaxOpenConnections*poolWarnPercent
+	if !nearlyFull && waited == 0 {
+		return
+	}
+
+	m.logger.WithFields(logrus.Fields{
+		"in_use":   stats.InUse,
+		"idle":     stats.Idle,
+		"max_open": stats.MaxOpenConnections,
+		"waited":   waited,
+	}).Warn("Database connection pool is running low")
+}
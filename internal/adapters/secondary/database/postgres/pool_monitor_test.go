@@ -0,0 +1,39 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolMonitor_WarnsWhenNearlyFull(t *testing.T) {
+	db := setupMigrateTestDB(t)
+	db.SetMaxOpenConns(2)
+	log, hook := test.NewNullLogger()
+	monitor := NewPoolMonitor(db, time.Minute, log)
+	ctx := context.Background()
+
+	conn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// One of two connections in use is below the threshold
+	monitor.check()
+	assert.Empty(t, hook.AllEntries())
+
+	conn2, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn2.Close()
+
+	monitor.check()
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, logrus.WarnLevel, entry.Level)
+	assert.Equal(t, 2, entry.Data["in_use"])
+	assert.Equal(t, 2, entry.Data["max_open"])
+}
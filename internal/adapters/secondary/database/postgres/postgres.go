@@ -5,9 +5,9 @@ import (
 	"log"
 	"time"
 
+	"github.com/sirupsen/logrus"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
 // Config holds database configuration
@@ -22,6 +22,16 @@ type Config struct {
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
 	LogLevel        string
+
+	// SlowQueryThreshold is how long a query may take before it is logged at
+	// warn level; zero disables slow query logging
+	SlowQueryThreshold time.Duration
+
+	// Logger receives GORM's logs; the standard logrus logger if nil
+	Logger *logrus.Logger
+
+	// ReplicaDSN is the connection string of an optional read replica
+	ReplicaDSN string
 }
 
 // NewConnection creates a new PostgreSQL database connection
@@ -36,23 +46,37 @@ func NewConnection(config Config) (*gorm.DB, error) {
 		config.SSLMode,
 	)
 
-	// Configure GORM logger
-	var logLevel logger.LogLevel
-	switch config.LogLevel {
-	case "silent":
-		logLevel = logger.Silent
-	case "error":
-		logLevel = logger.Error
-	case "warn":
-		logLevel = logger.Warn
-	case "info":
-		logLevel = logger.Info
-	default:
-		logLevel = logger.Info
+	db, err := open(dsn, config)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Println("Successfully connected to PostgreSQL database")
+
+	return db, nil
+}
+
+// NewReplicaConnection connects to the read replica. It returns nil without an
+// error when no replica is configured, so callers fall back to the primary.
+func NewReplicaConnection(config Config) (*gorm.DB, error) {
+	if config.ReplicaDSN == "" {
+		return nil, nil
 	}
 
+	db, err := open(config.ReplicaDSN, config)
+	if err != nil {
+		return nil, fmt.Errorf("replica: %w", err)
+	}
+
+	log.Println("Successfully connected to PostgreSQL read replica")
+
+	return db, nil
+}
+
+// open connects to dsn with the logging and pool settings from config
+func open(dsn string, config Config) (*gorm.DB, error) {
 	gormConfig := &gorm.Config{
-		Logger: logger.Default.LogMode(logLevel),
+		Logger: newGormLogger(config.Logger, parseLogLevel(config.LogLevel), config.SlowQueryThreshold),
 		NowFunc: func() time.Time {
 			return time.Now().UTC()
 		},
@@ -78,8 +102,6 @@ func NewConnection(config Config) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	log.Println("Successfully connected to PostgreSQL database")
-
 	return db, nil
 }
 
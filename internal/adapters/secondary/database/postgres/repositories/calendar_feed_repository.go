@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/yourusername/notinoteapp/internal/adapters/secondary/database/postgres/models"
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CalendarFeedRepository implements the calendar feed repository interface using PostgreSQL
+type CalendarFeedRepository struct {
+	db *gorm.DB
+}
+
+// NewCalendarFeedRepository creates a new calendar feed repository
+func NewCalendarFeedRepository(db *gorm.DB) *CalendarFeedRepository {
+	return &CalendarFeedRepository{db: db}
+}
+
+// Save stores the user's calendar feed, replacing any previous token
+func (r *CalendarFeedRepository) Save(ctx context.Context, feed *domain.CalendarFeed) error {
+	dbFeed := &models.CalendarFeed{}
+	dbFeed.FromDomain(feed)
+
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"token_hash", "created_at"}),
+		}).
+		Create(dbFeed).Error
+}
+
+// FindByTokenHash finds the calendar feed with the given token hash
+func (r *CalendarFeedRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*domain.CalendarFeed, error) {
+	var dbFeed models.CalendarFeed
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&dbFeed).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrCalendarFeedNotFound
+		}
+		return nil, err
+	}
+
+	return dbFeed.ToDomain(), nil
+}
+
+// DeleteByUserID removes the user's calendar feed, if any
+func (r *CalendarFeedRepository) DeleteByUserID(ctx context.Context, userID int64) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&models.CalendarFeed{}).Error
+}
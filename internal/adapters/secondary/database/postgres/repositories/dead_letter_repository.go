@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/yourusername/notinoteapp/internal/adapters/secondary/database/postgres/models"
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+	"github.com/yourusername/notinoteapp/internal/core/ports"
+	"gorm.io/gorm"
+)
+
+// DeadLetterRepository implements the dead letter repository interface using PostgreSQL
+type DeadLetterRepository struct {
+	db *gorm.DB
+}
+
+// NewDeadLetterRepository creates a new dead letter repository
+func NewDeadLetterRepository(db *gorm.DB) *DeadLetterRepository {
+	return &DeadLetterRepository{db: db}
+}
+
+// Create stores a new dead letter
+func (r *DeadLetterRepository) Create(ctx context.Context, letter *domain.DeadLetter) error {
+	dbLetter := &models.DeadLetter{}
+	dbLetter.FromDomain(letter)
+
+	if err := r.db.WithContext(ctx).Create(dbLetter).Error; err != nil {
+		return err
+	}
+
+	letter.ID = dbLetter.ID
+	letter.CreatedAt = dbLetter.CreatedAt
+
+	return nil
+}
+
+// FindByID finds a dead letter by ID
+func (r *DeadLetterRepository) FindByID(ctx context.Context, id int64) (*domain.DeadLetter, error) {
+	var dbLetter models.DeadLetter
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&dbLetter).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrDeadLetterNotFound
+		}
+		return nil, err
+	}
+
+	return dbLetter.ToDomain(), nil
+}
+
+// List finds dead letters matching the filters, newest first
+func (r *DeadLetterRepository) List(ctx context.Context, params *ports.DeadLetterQueryParams) ([]*domain.DeadLetter, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.DeadLetter{})
+
+	if params != nil {
+		if params.UserID != nil {
+			query = query.Where("user_id = ?", *params.UserID)
+		}
+		if params.Channel != nil {
+			query = query.Where("channel = ?", *params.Channel)
+		}
+		if params.Unresolved {
+			query = query.Where("resolved_at IS NULL")
+		}
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query = query.Order("created_at DESC").Order("id DESC")
+	if params != nil {
+		if params.Limit > 0 {
+			query = query.Limit(params.Limit)
+		}
+		if params.Offset > 0 {
+			query = query.Offset(params.Offset)
+		}
+	}
+
+	var dbLetters []models.DeadLetter
+	if err := query.Find(&dbLetters).Error; err != nil {
+		return nil, 0, err
+	}
+
+	letters := make([]*domain.DeadLetter, len(dbLetters))
+	for i, dbLetter := range dbLetters {
+		letters[i] = dbLetter.ToDomain()
+	}
+
+	return letters, total, nil
+}
+
+// UpdateReplay saves the outcome of a replay attempt
+func (r *DeadLetterRepository) UpdateReplay(ctx context.Context, letter *domain.DeadLetter) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.DeadLetter{}).
+		Where("id = ?", letter.ID).
+		Updates(map[string]interface{}{
+			"error_message":    letter.ErrorMessage,
+			"replay_count":     letter.ReplayCount,
+			"last_replayed_at": letter.LastReplayedAt,
+			"resolved_at":      letter.ResolvedAt,
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrDeadLetterNotFound
+	}
+
+	return nil
+}
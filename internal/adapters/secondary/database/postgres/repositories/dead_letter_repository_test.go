@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+	"github.com/yourusername/notinoteapp/internal/core/ports"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupDeadLetterTestDB creates an in-memory SQLite database private to the test
+func setupDeadLetterTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+
+	// Declared by hand: SQLite only scans times from columns typed as datetime
+	err = db.Exec(`CREATE TABLE notification_dead_letters (
+		id integer PRIMARY KEY AUTOINCREMENT,
+		notification_log_id integer,
+		user_id integer NOT NULL,
+		reminder_id integer,
+		device_id integer,
+		channel text NOT NULL,
+		device_type text,
+		device_name text,
+		title text NOT NULL,
+		body text,
+		data text,
+		error_message text NOT NULL,
+		replay_count integer NOT NULL DEFAULT 0,
+		last_replayed_at datetime,
+		resolved_at datetime,
+		created_at datetime
+	)`).Error
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestDeadLetterRepository_CreateListReplay(t *testing.T) {
+	db := setupDeadLetterTestDB(t)
+	repo := NewDeadLetterRepository(db)
+	ctx := context.Background()
+
+	log := &domain.NotificationLog{ID: 11, UserID: 7, Title: "Stand-up", Data: map[string]string{"type": "reminder"}}
+	device := &domain.Device{ID: 2, DeviceType: domain.DeviceTypeWeb, DeviceName: "Firefox"}
+	push := domain.NewDeadLetter(log, domain.NotificationChannelPush, device, "token expired")
+	require.NoError(t, repo.Create(ctx, push))
+	require.NotZero(t, push.ID)
+
+	email := domain.NewDeadLetter(&domain.NotificationLog{UserID: 8, Title: "Other"}, domain.NotificationChannelEmail, nil, "smtp down")
+	require.NoError(t, repo.Create(ctx, email))
+
+	found, err := repo.FindByID(ctx, push.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), *found.NotificationLogID)
+	assert.Equal(t, int64(2), *found.DeviceID)
+	assert.Equal(t, domain.DeviceTypeWeb, found.DeviceType)
+	assert.Equal(t, "Firefox", found.DeviceName)
+	assert.Equal(t, map[string]string{"type": "reminder"}, found.Data)
+	assert.Equal(t, "token expired", found.ErrorMessage)
+
+	channel := domain.NotificationChannelPush
+	letters, total, err := repo.List(ctx, &ports.DeadLetterQueryParams{Channel: &channel, Unresolved: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, letters, 1)
+	assert.Equal(t, push.ID, letters[0].ID)
+
+	found.RecordReplay(nil)
+	require.NoError(t, repo.UpdateReplay(ctx, found))
+
+	letters, total, err = repo.List(ctx, &ports.DeadLetterQueryParams{Unresolved: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, email.ID, letters[0].ID)
+
+	replayed, err := repo.FindByID(ctx, push.ID)
+	require.NoError(t, err)
+	assert.True(t, replayed.IsResolved())
+	assert.Equal(t, 1, replayed.ReplayCount)
+
+	_, err = repo.FindByID(ctx, 999)
+	assert.ErrorIs(t, err, domain.ErrDeadLetterNotFound)
+}
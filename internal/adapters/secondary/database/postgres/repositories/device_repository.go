@@ -125,9 +125,12 @@ func (r *DeviceRepository) Update(ctx context.Context, device *domain.Device) er
 	dbDevice := &models.Device{}
 	dbDevice.FromDomain(device)
 
+	// Select every column so cleared fields and deactivation are saved too
 	result := r.db.WithContext(ctx).
 		Model(&models.Device{}).
 		Where("id = ?", device.ID).
+		Select("*").
+		Omit("id", "created_at").
 		Updates(dbDevice)
 
 	if result.Error != nil {
@@ -211,3 +214,17 @@ func (r *DeviceRepository) DeactivateStaleDevices(ctx context.Context, before ti
 
 	return result.RowsAffected, nil
 }
+
+// DeleteInactiveDevices deletes inactive devices not used since the given time.
+// Devices that were never used count from when they were registered.
+func (r *DeviceRepository) DeleteInactiveDevices(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("is_active = ? AND COALESCE(last_used_at, created_at) < ?", false, before).
+		Delete(&models.Device{})
+
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/yourusername/notinoteapp/internal/adapters/secondary/database/postgres/models"
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NoteCollaboratorRepository implements the note collaborator repository interface using PostgreSQL
+type NoteCollaboratorRepository struct {
+	db *gorm.DB
+}
+
+// NewNoteCollaboratorRepository creates a new note collaborator repository
+func NewNoteCollaboratorRepository(db *gorm.DB) *NoteCollaboratorRepository {
+	return &NoteCollaboratorRepository{db: db}
+}
+
+// Upsert creates a collaborator or updates the role of an existing one
+func (r *NoteCollaboratorRepository) Upsert(ctx context.Context, collaborator *domain.NoteCollaborator) error {
+	dbCollaborator := &models.NoteCollaborator{}
+	dbCollaborator.FromDomain(collaborator)
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "note_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"role", "updated_at"}),
+		}).
+		Create(dbCollaborator).Error
+	if err != nil {
+		return err
+	}
+
+	collaborator.ID = dbCollaborator.ID
+	collaborator.CreatedAt = dbCollaborator.CreatedAt
+	collaborator.UpdatedAt = dbCollaborator.UpdatedAt
+	return nil
+}
+
+// Delete removes a collaborator from a note
+func (r *NoteCollaboratorRepository) Delete(ctx context.Context, noteID, userID int64) error {
+	result := r.db.WithContext(ctx).
+		Where("note_id = ? AND user_id = ?", noteID, userID).
+		Delete(&models.NoteCollaborator{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrCollaboratorNotFound
+	}
+
+	return nil
+}
+
+// FindByNoteID finds all collaborators on a note
+func (r *NoteCollaboratorRepository) FindByNoteID(ctx context.Context, noteID int64) ([]*domain.NoteCollaborator, error) {
+	var dbCollaborators []models.NoteCollaborator
+	if err := r.db.WithContext(ctx).
+		Where("note_id = ?", noteID).
+		Order("created_at ASC").
+		Find(&dbCollaborators).Error; err != nil {
+		return nil, err
+	}
+
+	return toDomainCollaborators(dbCollaborators), nil
+}
+
+// FindByUserAndNoteIDs finds the user's collaborator grants on any of the given notes
+func (r *NoteCollaboratorRepository) FindByUserAndNoteIDs(ctx context.Context, userID int64, noteIDs []int64) ([]*domain.NoteCollaborator, error) {
+	if len(noteIDs) == 0 {
+		return []*domain.NoteCollaborator{}, nil
+	}
+
+	var dbCollaborators []models.NoteCollaborator
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND note_id IN ?", userID, noteIDs).
+		Find(&dbCollaborators).Error; err != nil {
+		return nil, err
+	}
+
+	return toDomainCollaborators(dbCollaborators), nil
+}
+
+func toDomainCollaborators(dbCollaborators []models.NoteCollaborator) []*domain.NoteCollaborator {
+	collaborators := make([]*domain.NoteCollaborator, len(dbCollaborators))
+	for i, dbCollaborator := range dbCollaborators {
+		collaborators[i] = dbCollaborator.ToDomain()
+	}
+	return collaborators
+}
@@ -6,16 +6,27 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/yourusername/notinoteapp/internal/adapters/secondary/database/postgres/models"
 	"github.com/yourusername/notinoteapp/internal/core/domain"
 	"github.com/yourusername/notinoteapp/internal/core/ports"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // NoteRepository implements the note repository interface using PostgreSQL
 type NoteRepository struct {
 	db *gorm.DB
+
+	// replica serves FindByID, FindByUserID and Search when set, except for
+	// notes and users written within the replica's lag window
+	replica *gorm.DB
+	writes  *recentWrites
+
+	// titleCollation is the database collation title sorts use, e.g.
+	// "en-US-x-icu"; when empty they compare LOWER(title) instead
+	titleCollation string
 }
 
 // NewNoteRepository creates a new note repository
@@ -23,6 +34,50 @@ func NewNoteRepository(db *gorm.DB) *NoteRepository {
 	return &NoteRepository{db: db}
 }
 
+// SetReplica sends lookups, listings and searches to a read replica. Notes
+// and users written through this repository within maxLag keep being read
+// from the primary, so a note can be read back right after it is saved.
+func (r *NoteRepository) SetReplica(replica *gorm.DB, maxLag time.Duration) {
+	r.replica = replica
+	r.writes = newRecentWrites(maxLag)
+}
+
+// SetTitleCollation makes sorting by title use a locale-aware database
+// collation instead of lowercasing titles. The name must not contain a double
+// quote; config validation guarantees that.
+func (r *NoteRepository) SetTitleCollation(collation string) {
+	r.titleCollation = collation
+}
+
+// reader returns the replica unless there is none or any of keys was written
+// recently enough that the replica may not have it yet
+func (r *NoteRepository) reader(keys ...string) *gorm.DB {
+	if r.replica == nil || r.writes.any(keys...) {
+		return r.db
+	}
+	return r.replica
+}
+
+// written records writes for reader. Call it after the write has succeeded.
+func (r *NoteRepository) written(keys ...string) {
+	if r.writes != nil {
+		r.writes.mark(keys...)
+	}
+}
+
+// noteKey and userKey identify what a write touched for reader
+func noteKey(id int64) string { return fmt.Sprintf("note:%d", id) }
+func userKey(id int64) string { return fmt.Sprintf("user:%d", id) }
+
+// noteKeys returns the keys for several notes
+func noteKeys(ids []int64) []string {
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = noteKey(id)
+	}
+	return keys
+}
+
 // Create creates a new note
 func (r *NoteRepository) Create(ctx context.Context, note *domain.Note) error {
 	dbNote := &models.Note{}
@@ -37,6 +92,7 @@ func (r *NoteRepository) Create(ctx context.Context, note *domain.Note) error {
 	note.CreatedAt = dbNote.CreatedAt
 	note.UpdatedAt = dbNote.UpdatedAt
 	note.Path = dbNote.Path // Set by database trigger
+	r.written(noteKey(note.ID), userKey(note.UserID))
 
 	return nil
 }
@@ -45,7 +101,7 @@ func (r *NoteRepository) Create(ctx context.Context, note *domain.Note) error {
 func (r *NoteRepository) FindByID(ctx context.Context, id int64) (*domain.Note, error) {
 	var dbNote models.Note
 
-	err := r.db.WithContext(ctx).
+	err := r.reader(noteKey(id)).WithContext(ctx).
 		Where("id = ? AND is_deleted = ?", id, false).
 		First(&dbNote).Error
 
@@ -70,6 +126,76 @@ func (r *NoteRepository) FindByID(ctx context.Context, id int64) (*domain.Note,
 	return note, nil
 }
 
+// FindByIDs finds the notes with the given IDs that belong to the user.
+// Missing, deleted, and other users' notes are omitted; order is not guaranteed.
+func (r *NoteRepository) FindByIDs(ctx context.Context, userID int64, ids []int64) ([]*domain.Note, error) {
+	if len(ids) == 0 {
+		return []*domain.Note{}, nil
+	}
+
+	db := r.reader(noteKeys(ids)...).WithContext(ctx)
+
+	var dbNotes []models.Note
+	err := db.
+		Where("user_id = ? AND id IN ? AND is_deleted = ?", userID, ids, false).
+		Find(&dbNotes).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find notes: %w", err)
+	}
+
+	// Load tags for all the notes in one query. Tags are optional, as in
+	// FindByID, so a failure leaves them empty rather than failing the batch.
+	tagsByNote, err := noteTagsByID(db, ids)
+	if err != nil {
+		tagsByNote = map[int64][]domain.Tag{}
+	}
+
+	notes := make([]*domain.Note, len(dbNotes))
+	for i, dbNote := range dbNotes {
+		notes[i] = dbNote.ToDomain()
+		notes[i].Tags = tagsByNote[dbNote.ID]
+		if notes[i].Tags == nil {
+			notes[i].Tags = []domain.Tag{}
+		}
+	}
+
+	return notes, nil
+}
+
+// noteTagsByID returns the tags of each of the given notes, ordered by name
+func noteTagsByID(db *gorm.DB, noteIDs []int64) (map[int64][]domain.Tag, error) {
+	var rows []struct {
+		NoteID int64
+		domain.Tag
+	}
+
+	query := `
+		SELECT nt.note_id, t.id, t.user_id, t.name, t.color, t.created_at, t.updated_at
+		FROM tags t
+		INNER JOIN note_tags nt ON t.id = nt.tag_id
+		WHERE nt.note_id IN ?
+		ORDER BY t.name ASC
+	`
+
+	if err := db.Raw(query, noteIDs).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get note tags: %w", err)
+	}
+
+	tags := make(map[int64][]domain.Tag, len(noteIDs))
+	for _, row := range rows {
+		tags[row.NoteID] = append(tags[row.NoteID], row.Tag)
+	}
+	return tags, nil
+}
+
+// noteUpdateColumns are the columns Update writes. Listing them makes GORM
+// persist false, nil, and empty values too; path and depth are left to the
+// hierarchy trigger.
+var noteUpdateColumns = []string{
+	"parent_id", "title", "icon", "cover_image", "blocks", "view_metadata", "properties",
+	"position", "is_archived", "is_deleted", "is_favorite", "updated_at",
+}
+
 // Update updates a note
 func (r *NoteRepository) Update(ctx context.Context, note *domain.Note) (*domain.Note, error) {
 	dbNote := &models.Note{}
@@ -78,6 +204,7 @@ func (r *NoteRepository) Update(ctx context.Context, note *domain.Note) (*domain
 	result := r.db.WithContext(ctx).
 		Model(&models.Note{}).
 		Where("id = ? AND is_deleted = ?", note.ID, false).
+		Select(noteUpdateColumns).
 		Updates(dbNote)
 
 	if result.Error != nil {
@@ -87,14 +214,18 @@ func (r *NoteRepository) Update(ctx context.Context, note *domain.Note) (*domain
 	if result.RowsAffected == 0 {
 		return nil, domain.ErrNoteNotFound
 	}
-	fmt.Println("dbNote",note.Blocks)
+	r.written(noteKey(note.ID), userKey(note.UserID))
+
 	return note, nil
 }
 
 // Delete soft deletes a note
 func (r *NoteRepository) Delete(ctx context.Context, id int64) error {
+	// The owner is returned so their note lists can be marked as written
+	var note models.Note
 	result := r.db.WithContext(ctx).
-		Model(&models.Note{}).
+		Model(&note).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "user_id"}}}).
 		Where("id = ?", id).
 		Updates(map[string]interface{}{
 			"is_deleted": true,
@@ -108,13 +239,39 @@ func (r *NoteRepository) Delete(ctx context.Context, id int64) error {
 	if result.RowsAffected == 0 {
 		return domain.ErrNoteNotFound
 	}
+	r.written(noteKey(id), userKey(note.UserID))
+
+	return nil
+}
+
+// Restore un-deletes a trashed note. The query is unscoped so it matches rows
+// that Delete also stamped with deleted_at.
+func (r *NoteRepository) Restore(ctx context.Context, id, userID int64) error {
+	result := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&models.Note{}).
+		Where("id = ? AND user_id = ? AND is_deleted = ?", id, userID, true).
+		Updates(map[string]interface{}{
+			"is_deleted": false,
+			"deleted_at": nil,
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to restore note: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrNoteNotFound
+	}
+	r.written(noteKey(id), userKey(userID))
 
 	return nil
 }
 
 // FindByUserID finds all notes for a user with filtering and pagination
 func (r *NoteRepository) FindByUserID(ctx context.Context, userID int64, filters ports.NoteFilters) ([]*domain.Note, int64, error) {
-	query := r.db.WithContext(ctx).Model(&models.Note{}).
+	query := r.reader(userKey(userID)).WithContext(ctx).Model(&models.Note{}).
 		Where("user_id = ? AND is_deleted = ?", userID, false)
 
 	// Apply filters
@@ -137,6 +294,11 @@ func (r *NoteRepository) FindByUserID(ctx context.Context, userID int64, filters
 		query = query.Offset(filters.Offset)
 	}
 
+	// Selected after counting so the count stays a plain COUNT(*)
+	if filters.SummaryOnly {
+		query = query.Select(noteSummaryColumns)
+	}
+
 	var dbNotes []models.Note
 	if err := query.Find(&dbNotes).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to find notes: %w", err)
@@ -150,6 +312,49 @@ func (r *NoteRepository) FindByUserID(ctx context.Context, userID int64, filters
 	return notes, total, nil
 }
 
+// CountByUser counts the user's notes by state in a single query
+func (r *NoteRepository) CountByUser(ctx context.Context, userID int64) (*ports.NoteCounts, error) {
+	var counts ports.NoteCounts
+
+	// Unscoped so trashed notes (soft-deleted via deleted_at) are counted too;
+	// the is_deleted CASEs split them out
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&models.Note{}).
+		Select(`
+			COALESCE(SUM(CASE WHEN NOT is_deleted THEN 1 ELSE 0 END), 0) AS total,
+			COALESCE(SUM(CASE WHEN NOT is_deleted AND is_archived THEN 1 ELSE 0 END), 0) AS archived,
+			COALESCE(SUM(CASE WHEN NOT is_deleted AND is_favorite THEN 1 ELSE 0 END), 0) AS favorites,
+			COALESCE(SUM(CASE WHEN is_deleted THEN 1 ELSE 0 END), 0) AS trashed`).
+		Where("user_id = ?", userID).
+		Scan(&counts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count notes: %w", err)
+	}
+
+	return &counts, nil
+}
+
+// UsageByUser sums a user's note count and block storage. It reads the
+// blocks_size column rather than the blocks themselves.
+func (r *NoteRepository) UsageByUser(ctx context.Context, userID int64) (*ports.NoteUsage, error) {
+	var usage ports.NoteUsage
+
+	// Unscoped so trashed notes count until they are purged; otherwise a user
+	// could trash notes, create more, then restore past their quota
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&models.Note{}).
+		Select("COUNT(*) AS notes, COALESCE(SUM(blocks_size), 0) AS block_bytes").
+		Where("user_id = ?", userID).
+		Scan(&usage).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum note usage: %w", err)
+	}
+
+	return &usage, nil
+}
+
 // FindChildren finds direct children of a parent note
 func (r *NoteRepository) FindChildren(ctx context.Context, parentID int64) ([]*domain.Note, error) {
 	var dbNotes []models.Note
@@ -171,6 +376,9 @@ func (r *NoteRepository) FindChildren(ctx context.Context, parentID int64) ([]*d
 	return notes, nil
 }
 
+// descendantsBatchSize is how many notes FindDescendants reads at a time
+const descendantsBatchSize = 500
+
 // FindDescendants finds all descendants of a parent note using materialized path
 func (r *NoteRepository) FindDescendants(ctx context.Context, parentID int64) ([]*domain.Note, error) {
 	// First get the parent to get its path
@@ -183,13 +391,29 @@ func (r *NoteRepository) FindDescendants(ctx context.Context, parentID int64) ([
 
 	// Use path pattern matching for efficient descendant query
 	// If parent path is "/1/23/", this matches all notes with path like "/1/23/.../"
-	err = r.db.WithContext(ctx).
-		Where("path LIKE ? AND id != ? AND is_deleted = ?", parent.Path+"%", parentID, false).
-		Order("path ASC, position ASC").
-		Find(&dbNotes).Error
+	// Paths are unique, so large subtrees are read in batches keyed on the
+	// path, stopping between batches if ctx is canceled.
+	lastPath := parent.Path
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to find descendants: %w", err)
+		var batch []models.Note
+		err = r.db.WithContext(ctx).
+			Where("path LIKE ? AND path > ? AND id != ? AND is_deleted = ?", parent.Path+"%", lastPath, parentID, false).
+			Order("path ASC").
+			Limit(descendantsBatchSize).
+			Find(&batch).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to find descendants: %w", err)
+		}
+
+		dbNotes = append(dbNotes, batch...)
+		if len(batch) < descendantsBatchSize {
+			break
+		}
+		lastPath = batch[len(batch)-1].Path
 	}
 
 	notes := make([]*domain.Note, len(dbNotes))
@@ -200,6 +424,77 @@ func (r *NoteRepository) FindDescendants(ctx context.Context, parentID int64) ([
 	return notes, nil
 }
 
+// FindDescendantsPage finds one page of a note's live descendants in path
+// order, so each note follows its parent, loading summary fields only
+func (r *NoteRepository) FindDescendantsPage(ctx context.Context, parentID int64, limit, offset int) ([]*domain.Note, int64, error) {
+	var parent models.Note
+	err := r.db.WithContext(ctx).
+		Select("id", "user_id", "path").
+		Where("id = ? AND is_deleted = ?", parentID, false).
+		First(&parent).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0, domain.ErrNoteNotFound
+		}
+		return nil, 0, fmt.Errorf("failed to find note: %w", err)
+	}
+
+	query := r.db.WithContext(ctx).
+		Model(&models.Note{}).
+		Where("user_id = ? AND path LIKE ? AND id != ? AND is_deleted = ?", parent.UserID, parent.Path+"%", parentID, false)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count descendants: %w", err)
+	}
+
+	query = query.Select(noteSummaryColumns).Order("path ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	var dbNotes []models.Note
+	if err := query.Find(&dbNotes).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to find descendants: %w", err)
+	}
+
+	notes := make([]*domain.Note, len(dbNotes))
+	for i, dbNote := range dbNotes {
+		notes[i] = dbNote.ToDomain()
+	}
+
+	return notes, total, nil
+}
+
+// CountDescendants counts a note's live descendants using the materialized path
+func (r *NoteRepository) CountDescendants(ctx context.Context, parentID int64) (int64, error) {
+	var parent models.Note
+	err := r.db.WithContext(ctx).
+		Select("id", "user_id", "path").
+		Where("id = ? AND is_deleted = ?", parentID, false).
+		First(&parent).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, domain.ErrNoteNotFound
+		}
+		return 0, fmt.Errorf("failed to find note: %w", err)
+	}
+
+	var count int64
+	err = r.db.WithContext(ctx).
+		Model(&models.Note{}).
+		Where("user_id = ? AND path LIKE ? AND id != ? AND is_deleted = ?", parent.UserID, parent.Path+"%", parentID, false).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count descendants: %w", err)
+	}
+
+	return count, nil
+}
+
 // FindAncestors finds all ancestors of a note using materialized path
 func (r *NoteRepository) FindAncestors(ctx context.Context, noteID int64) ([]*domain.Note, error) {
 	// Get the note to parse its path
@@ -234,9 +529,13 @@ func (r *NoteRepository) FindAncestors(ctx context.Context, noteID int64) ([]*do
 	return notes, nil
 }
 
-// MoveNote moves a note to a new parent and position
+// MoveNote moves a note to a new parent and position. The hierarchy trigger
+// only recomputes the moved row, so the paths and depths of its descendants
+// are rewritten here too.
 func (r *NoteRepository) MoveNote(ctx context.Context, noteID int64, newParentID *int64, newPosition int) error {
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	var userID int64
+	var descendantIDs []int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// Get current note
 		var note models.Note
 		if err := tx.Where("id = ?", noteID).First(&note).Error; err != nil {
@@ -246,6 +545,9 @@ func (r *NoteRepository) MoveNote(ctx context.Context, noteID int64, newParentID
 			return err
 		}
 
+		newPath := fmt.Sprintf("/%d/", noteID)
+		newDepth := 0
+
 		// Check for circular reference
 		if newParentID != nil {
 			if *newParentID == noteID {
@@ -266,16 +568,25 @@ func (r *NoteRepository) MoveNote(ctx context.Context, noteID int64, newParentID
 				return domain.ErrCircularReference
 			}
 
-			// Check max depth
-			if newParent.Depth+1 > domain.MaxNestingDepth {
-				return domain.ErrMaxDepthExceeded
-			}
+			newPath = fmt.Sprintf("%s%d/", newParent.Path, noteID)
+			newDepth = newParent.Depth + 1
+		}
+
+		// Check max depth, including the deepest descendant
+		height, err := r.subtreeHeight(tx, &note)
+		if err != nil {
+			return err
+		}
+		if newDepth+height > domain.MaxNestingDepth {
+			return domain.ErrMaxDepthExceeded
 		}
 
-		// Update parent and position
-		// The trigger will automatically update path and depth
+		// Update parent and position. Path and depth are written as the
+		// trigger would set them, so trees without it stay consistent.
 		updates := map[string]interface{}{
 			"position": newPosition,
+			"path":     newPath,
+			"depth":    newDepth,
 		}
 
 		if newParentID == nil {
@@ -284,12 +595,79 @@ func (r *NoteRepository) MoveNote(ctx context.Context, noteID int64, newParentID
 			updates["parent_id"] = *newParentID
 		}
 
+		// Updates copies the new values into note, so keep its old path and depth
+		moved := note
 		if err := tx.Model(&note).Updates(updates).Error; err != nil {
 			return err
 		}
 
-		return nil
+		userID = note.UserID
+		descendantIDs, err = r.rewriteSubtreePaths(tx, &moved, newPath, newDepth)
+		return err
 	})
+	if err != nil {
+		return err
+	}
+	r.written(append(noteKeys(descendantIDs), noteKey(noteID), userKey(userID))...)
+
+	return nil
+}
+
+// subtreeHeight returns how many levels sit below a note, trashed notes
+// included, measured from the paths
+func (r *NoteRepository) subtreeHeight(tx *gorm.DB, note *models.Note) (int, error) {
+	if note.Path == "" {
+		return 0, nil
+	}
+
+	var paths []string
+	err := tx.Unscoped().
+		Model(&models.Note{}).
+		Where("user_id = ? AND path LIKE ? AND id != ?", note.UserID, note.Path+"%", note.ID).
+		Pluck("path", &paths).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to load descendants: %w", err)
+	}
+
+	base := strings.Count(note.Path, "/")
+	height := 0
+	for _, path := range paths {
+		height = max(height, strings.Count(path, "/")-base)
+	}
+	return height, nil
+}
+
+// rewriteSubtreePaths moves the paths and depths of a moved note's
+// descendants, trashed ones included, from its old path to the new one, and
+// returns the IDs of the descendants it rewrote
+func (r *NoteRepository) rewriteSubtreePaths(tx *gorm.DB, note *models.Note, newPath string, newDepth int) ([]int64, error) {
+	if note.Path == "" || (note.Path == newPath && note.Depth == newDepth) {
+		return nil, nil
+	}
+
+	var ids []int64
+	err := tx.Unscoped().
+		Model(&models.Note{}).
+		Where("user_id = ? AND path LIKE ? AND id != ?", note.UserID, note.Path+"%", note.ID).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load descendants: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	err = tx.Unscoped().
+		Model(&models.Note{}).
+		Where("id IN ?", ids).
+		UpdateColumns(map[string]interface{}{
+			"path":  gorm.Expr("? || SUBSTR(path, ?)", newPath, len(note.Path)+1),
+			"depth": gorm.Expr("depth + ?", newDepth-note.Depth),
+		}).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to update descendant paths: %w", err)
+	}
+	return ids, nil
 }
 
 // UpdateBlocks updates the blocks of a note
@@ -311,13 +689,89 @@ func (r *NoteRepository) UpdateBlocks(ctx context.Context, noteID int64, blocks
 	if result.RowsAffected == 0 {
 		return domain.ErrNoteNotFound
 	}
+	r.written(noteKey(noteID))
+
+	return nil
+}
+
+// UpdateBlockContent rewrites one block's content with jsonb_set instead of replacing
+// the whole blocks array. The block's array index is looked up in the same statement
+// and re-checked against the row being updated, so a concurrent reorder can't make
+// the update land on a different block.
+func (r *NoteRepository) UpdateBlockContent(ctx context.Context, noteID int64, blockID string, content *domain.BlockContent) error {
+	contentJSON, err := json.Marshal(content)
+	if err != nil {
+		return fmt.Errorf("failed to marshal block content: %w", err)
+	}
+
+	return r.patchBlockContent(ctx, noteID, blockID, "?::jsonb", string(contentJSON))
+}
+
+// UpdateBlockText sets only the rich_text key of one block's content, so a
+// concurrent change to e.g. a checkbox's checked state isn't overwritten
+func (r *NoteRepository) UpdateBlockText(ctx context.Context, noteID int64, blockID string, richText []domain.RichTextSegment) error {
+	if richText == nil {
+		richText = []domain.RichTextSegment{}
+	}
+	textJSON, err := json.Marshal(richText)
+	if err != nil {
+		return fmt.Errorf("failed to marshal rich text: %w", err)
+	}
+
+	contentExpr := `
+		CASE WHEN jsonb_typeof(notes.blocks->(target.idx::int)->'content') = 'object'
+			THEN notes.blocks->(target.idx::int)->'content'
+			ELSE '{}'::jsonb
+		END || jsonb_build_object('rich_text', ?::jsonb)`
+
+	return r.patchBlockContent(ctx, noteID, blockID, contentExpr, string(textJSON))
+}
+
+// patchBlockContent sets one top-level block's content to contentExpr, whose single
+// placeholder is bound to arg. contentExpr may refer to the block as
+// notes.blocks->(target.idx::int).
+func (r *NoteRepository) patchBlockContent(ctx context.Context, noteID int64, blockID string, contentExpr string, arg interface{}) error {
+	query := `
+		UPDATE notes
+		SET blocks = jsonb_set(notes.blocks, ARRAY[target.idx::text, 'content'], ` + contentExpr + `),
+			updated_at = CURRENT_TIMESTAMP
+		FROM (
+			SELECT e.ordinality - 1 AS idx
+			FROM notes n, jsonb_array_elements(n.blocks) WITH ORDINALITY AS e(block, ordinality)
+			WHERE n.id = ? AND e.block->>'id' = ?
+			LIMIT 1
+		) AS target
+		WHERE notes.id = ? AND notes.is_deleted = false
+			AND notes.blocks->(target.idx::int)->>'id' = ?
+	`
+
+	result := r.db.WithContext(ctx).Exec(query, arg, noteID, blockID, noteID, blockID)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update block: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		var count int64
+		if err := r.db.WithContext(ctx).Model(&models.Note{}).
+			Where("id = ? AND is_deleted = ?", noteID, false).
+			Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check note: %w", err)
+		}
+		if count == 0 {
+			return domain.ErrNoteNotFound
+		}
+		return domain.ErrBlockNotFound
+	}
+	r.written(noteKey(noteID))
 
 	return nil
 }
 
-// Search searches notes by title with filters
+// Search searches notes by title with filters. With filters.Fuzzy, a query the
+// full-text search finds nothing for is retried as a trigram similarity search
+// (requires pg_trgm), ranking substring matches above merely similar titles.
 func (r *NoteRepository) Search(ctx context.Context, userID int64, query string, filters ports.NoteFilters) ([]*domain.Note, int64, error) {
-	dbQuery := r.db.WithContext(ctx).Model(&models.Note{}).
+	dbQuery := r.reader(userKey(userID)).WithContext(ctx).Model(&models.Note{}).
 		Where("user_id = ? AND is_deleted = ?", userID, false)
 
 	// Full-text search on title
@@ -334,9 +788,41 @@ func (r *NoteRepository) Search(ctx context.Context, userID int64, query string,
 		return nil, 0, fmt.Errorf("failed to count notes: %w", err)
 	}
 
+	if total == 0 && filters.Fuzzy && query != "" {
+		return r.fuzzySearch(ctx, userID, query, filters)
+	}
+
 	// Apply sorting
 	dbQuery = r.applySorting(dbQuery, filters)
 
+	return r.findPage(dbQuery, filters, total)
+}
+
+// fuzzySearch matches titles similar to query by trigrams, or containing it
+func (r *NoteRepository) fuzzySearch(ctx context.Context, userID int64, query string, filters ports.NoteFilters) ([]*domain.Note, int64, error) {
+	contains := "%" + escapeLike(query) + "%"
+
+	dbQuery := r.reader(userKey(userID)).WithContext(ctx).Model(&models.Note{}).
+		Where("user_id = ? AND is_deleted = ?", userID, false).
+		Where("(title % ? OR title ILIKE ?)", query, contains)
+	dbQuery = r.applyFilters(dbQuery, filters)
+
+	var total int64
+	if err := dbQuery.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count notes: %w", err)
+	}
+
+	dbQuery = dbQuery.
+		Order(clause.Expr{SQL: "lower(title) = lower(?) DESC", Vars: []interface{}{query}}).
+		Order(clause.Expr{SQL: "title ILIKE ? DESC", Vars: []interface{}{contains}}).
+		Order(clause.Expr{SQL: "similarity(title, ?) DESC", Vars: []interface{}{query}}).
+		Order("id DESC")
+
+	return r.findPage(dbQuery, filters, total)
+}
+
+// findPage loads one page of a search
+func (r *NoteRepository) findPage(dbQuery *gorm.DB, filters ports.NoteFilters, total int64) ([]*domain.Note, int64, error) {
 	// Apply pagination
 	if filters.Limit > 0 {
 		dbQuery = dbQuery.Limit(filters.Limit)
@@ -358,6 +844,11 @@ func (r *NoteRepository) Search(ctx context.Context, userID int64, query string,
 	return notes, total, nil
 }
 
+// escapeLike escapes LIKE wildcards so s matches literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
 // BulkArchive archives multiple notes
 func (r *NoteRepository) BulkArchive(ctx context.Context, noteIDs []int64) error {
 	if len(noteIDs) == 0 {
@@ -372,6 +863,7 @@ func (r *NoteRepository) BulkArchive(ctx context.Context, noteIDs []int64) error
 	if result.Error != nil {
 		return fmt.Errorf("failed to bulk archive notes: %w", result.Error)
 	}
+	r.written(noteKeys(noteIDs)...)
 
 	return nil
 }
@@ -393,10 +885,58 @@ func (r *NoteRepository) BulkDelete(ctx context.Context, noteIDs []int64) error
 	if result.Error != nil {
 		return fmt.Errorf("failed to bulk delete notes: %w", result.Error)
 	}
+	r.written(noteKeys(noteIDs)...)
 
 	return nil
 }
 
+// SoftDeleteSubtree soft deletes a note and its live descendants. Either the
+// whole subtree is trashed or, on error, none of it is.
+func (r *NoteRepository) SoftDeleteSubtree(ctx context.Context, noteID int64) ([]int64, error) {
+	var deleted []int64
+	var userID int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var note models.Note
+		if err := tx.Where("id = ? AND is_deleted = ?", noteID, false).First(&note).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrNoteNotFound
+			}
+			return err
+		}
+
+		var descendantIDs []int64
+		err := tx.Model(&models.Note{}).
+			Where("user_id = ? AND path LIKE ? AND id != ? AND is_deleted = ?", note.UserID, note.Path+"%", noteID, false).
+			Pluck("id", &descendantIDs).Error
+		if err != nil {
+			return fmt.Errorf("failed to find descendants: %w", err)
+		}
+
+		trash := map[string]interface{}{
+			"is_deleted": true,
+			"deleted_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		}
+		if len(descendantIDs) > 0 {
+			if err := tx.Model(&models.Note{}).Where("id IN ?", descendantIDs).Updates(trash).Error; err != nil {
+				return fmt.Errorf("failed to delete descendants: %w", err)
+			}
+		}
+		if err := tx.Model(&models.Note{}).Where("id = ?", noteID).Updates(trash).Error; err != nil {
+			return fmt.Errorf("failed to delete note: %w", err)
+		}
+
+		deleted = append(descendantIDs, noteID)
+		userID = note.UserID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.written(append(noteKeys(deleted), userKey(userID))...)
+
+	return deleted, nil
+}
+
 // CheckOwnership checks if a user owns a note
 func (r *NoteRepository) CheckOwnership(ctx context.Context, noteID, userID int64) (bool, error) {
 	var count int64
@@ -415,6 +955,13 @@ func (r *NoteRepository) CheckOwnership(ctx context.Context, noteID, userID int6
 
 // Helper methods
 
+// noteSummaryColumns are the columns loaded for summary listings; the JSONB
+// columns (blocks, view_metadata, properties) are left out
+var noteSummaryColumns = []string{
+	"id", "user_id", "parent_id", "title", "icon", "path", "depth", "position",
+	"is_archived", "is_deleted", "is_favorite", "created_at", "updated_at",
+}
+
 // applyFilters applies filters to a query
 func (r *NoteRepository) applyFilters(query *gorm.DB, filters ports.NoteFilters) *gorm.DB {
 	if filters.ParentID != nil {
@@ -429,6 +976,24 @@ func (r *NoteRepository) applyFilters(query *gorm.DB, filters ports.NoteFilters)
 		query = query.Where("to_tsvector('english', title) @@ plainto_tsquery('english', ?)", filters.SearchQuery)
 	}
 
+	// Paths are ancestor IDs and slashes, so the root's path is a safe LIKE prefix
+	if filters.WithinNoteID != nil {
+		query = query.Where("path LIKE (SELECT root.path FROM notes root WHERE root.id = ?) || '%'", *filters.WithinNoteID)
+	}
+
+	if filters.CreatedAfter != nil {
+		query = query.Where("created_at >= ?", *filters.CreatedAfter)
+	}
+	if filters.CreatedBefore != nil {
+		query = query.Where("created_at <= ?", *filters.CreatedBefore)
+	}
+	if filters.UpdatedAfter != nil {
+		query = query.Where("updated_at >= ?", *filters.UpdatedAfter)
+	}
+	if filters.UpdatedBefore != nil {
+		query = query.Where("updated_at <= ?", *filters.UpdatedBefore)
+	}
+
 	// TODO: Add property filtering when needed
 	// This would require JSONB queries like:
 	// query.Where("properties->>'status' = ?", value)
@@ -449,14 +1014,7 @@ func (r *NoteRepository) applySorting(query *gorm.DB, filters ports.NoteFilters)
 	}
 
 	// Validate sortBy to prevent SQL injection
-	validSortFields := map[string]bool{
-		"created_at": true,
-		"updated_at": true,
-		"title":      true,
-		"position":   true,
-	}
-
-	if !validSortFields[sortBy] {
+	if !ports.IsValidNoteSortField(sortBy) {
 		sortBy = "created_at"
 	}
 
@@ -465,7 +1023,17 @@ func (r *NoteRepository) applySorting(query *gorm.DB, filters ports.NoteFilters)
 		sortOrder = "desc"
 	}
 
-	return query.Order(fmt.Sprintf("%s %s", sortBy, sortOrder))
+	// Titles sort case-insensitively, so "apple" doesn't follow "Zebra"
+	orderBy := sortBy
+	if sortBy == "title" {
+		orderBy = "LOWER(title)"
+		if r.titleCollation != "" {
+			orderBy = `title COLLATE "` + r.titleCollation + `"`
+		}
+	}
+
+	// id breaks ties, so rows with equal sort values keep one order across pages
+	return query.Order(fmt.Sprintf("%s %s", orderBy, sortOrder)).Order("id " + sortOrder)
 }
 
 // parseAncestorIDs parses ancestor IDs from a materialized path
@@ -493,6 +1061,170 @@ func (r *NoteRepository) parseAncestorIDs(path string, excludeID int64) []int64
 	return ancestorIDs
 }
 
+// rebuildPathsBatchSize is how many notes RebuildPaths reads or writes at a time
+const rebuildPathsBatchSize = 1000
+
+// noteLink is the part of a note RebuildPaths needs
+type noteLink struct {
+	ID       int64
+	ParentID *int64
+	Path     string
+	Depth    int
+}
+
+// RebuildPaths recomputes path and depth for a user's notes by walking parent
+// links. Notes are read and corrected in batches so large trees don't have to
+// be updated in one statement; each batch of corrections is its own transaction.
+func (r *NoteRepository) RebuildPaths(ctx context.Context, userID int64) (*ports.PathRebuildResult, error) {
+	links := make(map[int64]*noteLink)
+	var order []int64
+
+	lastID := int64(0)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var batch []noteLink
+		err := r.db.WithContext(ctx).
+			Unscoped(). // trashed notes keep their place in the tree
+			Model(&models.Note{}).
+			Select("id, parent_id, path, depth").
+			Where("user_id = ? AND id > ?", userID, lastID).
+			Order("id ASC").
+			Limit(rebuildPathsBatchSize).
+			Scan(&batch).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to load notes: %w", err)
+		}
+
+		for i := range batch {
+			links[batch[i].ID] = &batch[i]
+			order = append(order, batch[i].ID)
+		}
+		if len(batch) < rebuildPathsBatchSize {
+			break
+		}
+		lastID = batch[len(batch)-1].ID
+	}
+
+	result := &ports.PathRebuildResult{Checked: len(order)}
+
+	// Resolve each note's path from its parent's, remembering results so
+	// every chain is walked once
+	type position struct {
+		path  string
+		depth int
+		ok    bool
+	}
+	resolved := make(map[int64]position, len(links))
+	var resolve func(id int64, visiting map[int64]bool) position
+	resolve = func(id int64, visiting map[int64]bool) position {
+		if pos, done := resolved[id]; done {
+			return pos
+		}
+		link := links[id]
+
+		var pos position
+		switch {
+		case link.ParentID == nil:
+			pos = position{path: fmt.Sprintf("/%d/", id), ok: true}
+		case links[*link.ParentID] == nil || visiting[id]:
+			// Missing parent (or another user's), or a cycle
+		default:
+			visiting[id] = true
+			if parent := resolve(*link.ParentID, visiting); parent.ok {
+				pos = position{path: fmt.Sprintf("%s%d/", parent.path, id), depth: parent.depth + 1, ok: true}
+			}
+			delete(visiting, id)
+		}
+
+		resolved[id] = pos
+		return pos
+	}
+
+	var stale []noteLink
+	for _, id := range order {
+		pos := resolve(id, map[int64]bool{})
+		if !pos.ok {
+			result.Unreachable = append(result.Unreachable, id)
+			continue
+		}
+		if link := links[id]; link.Path != pos.path || link.Depth != pos.depth {
+			stale = append(stale, noteLink{ID: id, Path: pos.path, Depth: pos.depth})
+		}
+	}
+
+	for start := 0; start < len(stale); start += rebuildPathsBatchSize {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		batch := stale[start:min(start+rebuildPathsBatchSize, len(stale))]
+		err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			for _, link := range batch {
+				err := tx.Unscoped().
+					Model(&models.Note{}).
+					Where("id = ?", link.ID).
+					UpdateColumns(map[string]interface{}{"path": link.Path, "depth": link.Depth}).Error
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return result, fmt.Errorf("failed to update note paths: %w", err)
+		}
+		for _, link := range batch {
+			result.Corrected = append(result.Corrected, link.ID)
+		}
+	}
+
+	return result, nil
+}
+
+// FindOrphans finds notes whose parent link is broken across all users
+func (r *NoteRepository) FindOrphans(ctx context.Context) ([]ports.OrphanNote, error) {
+	var orphans []ports.OrphanNote
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT n.id, n.user_id, n.parent_id, p.id IS NOT NULL AS parent_trashed
+		FROM notes n
+		LEFT JOIN notes p ON p.id = n.parent_id AND p.user_id = n.user_id
+		WHERE n.parent_id IS NOT NULL
+			AND (p.id IS NULL OR (p.is_deleted = ? AND n.is_deleted = ?))
+		ORDER BY n.id`, true, false).
+		Scan(&orphans).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find orphaned notes: %w", err)
+	}
+	return orphans, nil
+}
+
+// DetachNotes moves notes to the top level. Their descendants' paths are left
+// for RebuildPaths to correct.
+func (r *NoteRepository) DetachNotes(ctx context.Context, noteIDs []int64) error {
+	if len(noteIDs) == 0 {
+		return nil
+	}
+
+	// The trigger sets path and depth on Postgres; they're set here too for
+	// databases without it
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&models.Note{}).
+		Where("id IN ?", noteIDs).
+		UpdateColumns(map[string]interface{}{
+			"parent_id": gorm.Expr("NULL"),
+			"path":      gorm.Expr("'/' || id || '/'"),
+			"depth":     0,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to detach notes: %w", err)
+	}
+	return nil
+}
+
 // AddTag adds a tag to a note (creates note_tags association)
 func (r *NoteRepository) AddTag(ctx context.Context, noteID int64, tagID string) error {
 	// Use raw SQL to insert into note_tags junction table
@@ -522,6 +1254,170 @@ func (r *NoteRepository) RemoveTag(ctx context.Context, noteID int64, tagID stri
 	return nil
 }
 
+// ListTags retrieves a page of a user's tags with how many notes use each,
+// in one query, along with the user's total tag count
+func (r *NoteRepository) ListTags(ctx context.Context, userID int64, limit, offset int) ([]ports.TagUsage, int64, error) {
+	var total int64
+	if err := r.db.WithContext(ctx).Table("tags").Where("user_id = ?", userID).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count tags: %w", err)
+	}
+
+	var tags []ports.TagUsage
+
+	// Left joins keep unused tags, which count zero notes
+	query := `
+		SELECT t.id, t.user_id, t.name, t.color, t.created_at, t.updated_at, COUNT(n.id) AS note_count
+		FROM tags t
+		LEFT JOIN note_tags nt ON nt.tag_id = t.id
+		LEFT JOIN notes n ON n.id = nt.note_id AND n.is_deleted = ?
+		WHERE t.user_id = ?
+		GROUP BY t.id, t.user_id, t.name, t.color, t.created_at, t.updated_at
+		ORDER BY t.name ASC
+	`
+	args := []interface{}{false, userID}
+	if limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, limit, offset)
+	}
+
+	if err := r.db.WithContext(ctx).Raw(query, args...).Scan(&tags).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	return tags, total, nil
+}
+
+// FindTag retrieves a tag by ID
+func (r *NoteRepository) FindTag(ctx context.Context, tagID string) (*domain.Tag, error) {
+	return r.findTag(ctx, "id = ?", tagID)
+}
+
+// FindTagByName retrieves one of a user's tags by its exact name
+func (r *NoteRepository) FindTagByName(ctx context.Context, userID int64, name string) (*domain.Tag, error) {
+	return r.findTag(ctx, "user_id = ? AND name = ?", userID, name)
+}
+
+func (r *NoteRepository) findTag(ctx context.Context, where string, args ...interface{}) (*domain.Tag, error) {
+	var tags []domain.Tag
+
+	query := `SELECT id, user_id, name, color, created_at, updated_at FROM tags WHERE ` + where + ` LIMIT 1`
+
+	if err := r.db.WithContext(ctx).Raw(query, args...).Scan(&tags).Error; err != nil {
+		return nil, fmt.Errorf("failed to find tag: %w", err)
+	}
+	if len(tags) == 0 {
+		return nil, domain.ErrTagNotFound
+	}
+
+	return &tags[0], nil
+}
+
+// UpdateTag saves a tag's name and color
+func (r *NoteRepository) UpdateTag(ctx context.Context, tag *domain.Tag) error {
+	query := `UPDATE tags SET name = ?, color = ?, updated_at = ? WHERE id = ?`
+
+	result := r.db.WithContext(ctx).Exec(query, tag.Name, tag.Color, tag.UpdatedAt, tag.ID)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update tag: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrTagNotFound
+	}
+
+	return nil
+}
+
+// FindNoteIDsByTag retrieves the IDs of all notes with a tag
+func (r *NoteRepository) FindNoteIDsByTag(ctx context.Context, tagID string) ([]int64, error) {
+	var noteIDs []int64
+
+	query := `SELECT note_id FROM note_tags WHERE tag_id = ? ORDER BY note_id`
+
+	if err := r.db.WithContext(ctx).Raw(query, tagID).Scan(&noteIDs).Error; err != nil {
+		return nil, fmt.Errorf("failed to find tagged notes: %w", err)
+	}
+
+	return noteIDs, nil
+}
+
+// MergeTags retags the source tag's notes with the target tag and deletes the source
+func (r *NoteRepository) MergeTags(ctx context.Context, sourceTagID, targetTagID string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Notes that already have both tags keep their existing target association
+		retag := `
+			INSERT INTO note_tags (note_id, tag_id, created_at)
+			SELECT note_id, ?, created_at FROM note_tags WHERE tag_id = ?
+			ON CONFLICT (note_id, tag_id) DO NOTHING
+		`
+		if err := tx.Exec(retag, targetTagID, sourceTagID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`DELETE FROM note_tags WHERE tag_id = ?`, sourceTagID).Error; err != nil {
+			return err
+		}
+
+		result := tx.Exec(`DELETE FROM tags WHERE id = ?`, sourceTagID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrTagNotFound
+		}
+		return nil
+	})
+	if errors.Is(err, domain.ErrTagNotFound) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to merge tags: %w", err)
+	}
+
+	return nil
+}
+
+// BulkAddTag adds a tag to several notes in one transaction
+func (r *NoteRepository) BulkAddTag(ctx context.Context, noteIDs []int64, tagID string) error {
+	if len(noteIDs) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO note_tags (note_id, tag_id, created_at)
+		VALUES (?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT (note_id, tag_id) DO NOTHING
+	`
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, noteID := range noteIDs {
+			if err := tx.Exec(query, noteID, tagID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to bulk add tag: %w", err)
+	}
+
+	return nil
+}
+
+// BulkRemoveTag removes a tag from several notes
+func (r *NoteRepository) BulkRemoveTag(ctx context.Context, noteIDs []int64, tagID string) error {
+	if len(noteIDs) == 0 {
+		return nil
+	}
+
+	query := `DELETE FROM note_tags WHERE tag_id = ? AND note_id IN ?`
+
+	if err := r.db.WithContext(ctx).Exec(query, tagID, noteIDs).Error; err != nil {
+		return fmt.Errorf("failed to bulk remove tag: %w", err)
+	}
+
+	return nil
+}
+
 // GetNoteTags retrieves all tags associated with a note
 func (r *NoteRepository) GetNoteTags(ctx context.Context, noteID int64) ([]domain.Tag, error) {
 	var tags []domain.Tag
@@ -0,0 +1,813 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/notinoteapp/internal/adapters/secondary/database/postgres/models"
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+	"github.com/yourusername/notinoteapp/internal/core/ports"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupNoteTestDB creates an in-memory SQLite database private to the test
+func setupNoteTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&models.Note{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestNoteRepository_CountByUser(t *testing.T) {
+	db := setupNoteTestDB(t)
+	repo := NewNoteRepository(db)
+	ctx := context.Background()
+
+	notes := []models.Note{
+		{UserID: 1, Title: "Active"},
+		{UserID: 1, Title: "Favorite", IsFavorite: true},
+		{UserID: 1, Title: "Archived", IsArchived: true},
+		{UserID: 1, Title: "Trashed favorite", IsFavorite: true},
+		{UserID: 2, Title: "Someone else's"},
+	}
+	require.NoError(t, db.Create(&notes).Error)
+	require.NoError(t, repo.Delete(ctx, notes[3].ID))
+
+	counts, err := repo.CountByUser(ctx, 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), counts.Total)
+	assert.Equal(t, int64(1), counts.Archived)
+	assert.Equal(t, int64(1), counts.Favorites)
+	assert.Equal(t, int64(1), counts.Trashed)
+}
+
+func TestNoteRepository_CountByUser_NoNotes(t *testing.T) {
+	db := setupNoteTestDB(t)
+	repo := NewNoteRepository(db)
+
+	counts, err := repo.CountByUser(context.Background(), 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), counts.Total)
+}
+
+func TestNoteRepository_UsageByUser_CountsTrash(t *testing.T) {
+	db := setupNoteTestDB(t)
+	// Stands in for the generated column added by migration 000012
+	require.NoError(t, db.Exec("ALTER TABLE notes ADD COLUMN blocks_size INTEGER GENERATED ALWAYS AS (length(blocks)) VIRTUAL").Error)
+	repo := NewNoteRepository(db)
+	ctx := context.Background()
+	quota := domain.StorageQuota{MaxNotes: 2}
+
+	first := &domain.Note{UserID: 1, Title: "First"}
+	second := &domain.Note{UserID: 1, Title: "Second", Blocks: []domain.Block{
+		{ID: "b1", Type: domain.BlockTypeParagraph, Content: &domain.BlockContent{Code: "some text"}},
+	}}
+	require.NoError(t, repo.Create(ctx, first))
+	require.NoError(t, repo.Create(ctx, second))
+
+	before, err := repo.UsageByUser(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), before.Notes)
+	assert.Positive(t, before.BlockBytes)
+
+	// Trashing a note doesn't free room for another
+	require.NoError(t, repo.Delete(ctx, second.ID))
+	usage, err := repo.UsageByUser(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, before, usage)
+	assert.ErrorIs(t, quota.CheckNewNote(usage.Notes), domain.ErrQuotaExceeded)
+
+	// So restoring it can't take the user over quota
+	require.NoError(t, repo.Restore(ctx, second.ID, 1))
+	usage, err = repo.UsageByUser(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, before, usage)
+}
+
+func TestNoteRepository_Restore(t *testing.T) {
+	db := setupNoteTestDB(t)
+	repo := NewNoteRepository(db)
+	ctx := context.Background()
+
+	note := &domain.Note{UserID: 1, Title: "Trash me"}
+	require.NoError(t, repo.Create(ctx, note))
+	require.NoError(t, repo.Delete(ctx, note.ID))
+
+	_, err := repo.FindByID(ctx, note.ID)
+	require.ErrorIs(t, err, domain.ErrNoteNotFound)
+
+	// Another user can't restore it
+	assert.ErrorIs(t, repo.Restore(ctx, note.ID, 2), domain.ErrNoteNotFound)
+
+	require.NoError(t, repo.Restore(ctx, note.ID, 1))
+
+	restored, err := repo.FindByID(ctx, note.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Trash me", restored.Title)
+	assert.False(t, restored.IsDeleted)
+
+	// A live note has nothing to restore
+	assert.ErrorIs(t, repo.Restore(ctx, note.ID, 1), domain.ErrNoteNotFound)
+}
+
+func TestNoteRepository_Update_PersistsZeroValues(t *testing.T) {
+	db := setupNoteTestDB(t)
+	repo := NewNoteRepository(db)
+	ctx := context.Background()
+
+	parent := &domain.Note{UserID: 1, Title: "Parent"}
+	require.NoError(t, repo.Create(ctx, parent))
+	note := &domain.Note{
+		UserID:     1,
+		ParentID:   &parent.ID,
+		Title:      "Child",
+		IsFavorite: true,
+		IsArchived: true,
+		Properties: map[string]interface{}{"status": "done"},
+	}
+	require.NoError(t, repo.Create(ctx, note))
+
+	note.IsFavorite = false
+	note.IsArchived = false
+	note.Properties = map[string]interface{}{}
+	note.ParentID = nil
+	_, err := repo.Update(ctx, note)
+	require.NoError(t, err)
+
+	var stored models.Note
+	require.NoError(t, db.First(&stored, note.ID).Error)
+	assert.False(t, stored.IsFavorite)
+	assert.False(t, stored.IsArchived)
+	assert.Nil(t, stored.ParentID)
+
+	var properties string
+	require.NoError(t, db.Raw("SELECT properties FROM notes WHERE id = ?", note.ID).Row().Scan(&properties))
+	assert.JSONEq(t, `{}`, properties)
+}
+
+func TestNoteRepository_MoveNote_RewritesSubtree(t *testing.T) {
+	db := setupNoteTestDB(t)
+	repo := NewNoteRepository(db)
+	ctx := context.Background()
+
+	// A chain nine levels deep, and a separate three-level subtree. Paths are
+	// maintained by a Postgres trigger, so they're set by hand here.
+	var notes []models.Note
+	path := "/"
+	for id := int64(1); id <= 9; id++ {
+		path += fmt.Sprintf("%d/", id)
+		var parentID *int64
+		if id > 1 {
+			parentID = &notes[id-2].ID
+		}
+		notes = append(notes, models.Note{ID: id, UserID: 1, ParentID: parentID, Title: "Deep", Path: path, Depth: int(id - 1)})
+	}
+	subtreeRoot, subtreeChild := int64(20), int64(21)
+	notes = append(notes,
+		models.Note{ID: 20, UserID: 1, Title: "Subtree", Path: "/20/", Depth: 0},
+		models.Note{ID: 21, UserID: 1, ParentID: &subtreeRoot, Title: "Child", Path: "/20/21/", Depth: 1},
+		models.Note{ID: 22, UserID: 1, ParentID: &subtreeChild, Title: "Grandchild", Path: "/20/21/22/", Depth: 2},
+	)
+	require.NoError(t, db.Create(&notes).Error)
+
+	parentID := int64(8)
+	require.NoError(t, repo.MoveNote(ctx, 20, &parentID, 0))
+
+	var moved []models.Note
+	require.NoError(t, db.Where("id >= ?", 20).Order("id").Find(&moved).Error)
+	require.Len(t, moved, 3)
+	assert.Equal(t, "/1/2/3/4/5/6/7/8/20/", moved[0].Path)
+	assert.Equal(t, 8, moved[0].Depth)
+	assert.Equal(t, "/1/2/3/4/5/6/7/8/20/21/", moved[1].Path)
+	assert.Equal(t, 9, moved[1].Depth)
+	assert.Equal(t, "/1/2/3/4/5/6/7/8/20/21/22/", moved[2].Path)
+	assert.Equal(t, 10, moved[2].Depth)
+
+	// The grandchild is at the limit, so the subtree can't go any deeper
+	parentID = 9
+	assert.ErrorIs(t, repo.MoveNote(ctx, 20, &parentID, 0), domain.ErrMaxDepthExceeded)
+
+	// Moving back to the root rewrites the subtree again
+	require.NoError(t, repo.MoveNote(ctx, 20, nil, 0))
+	var grandchild models.Note
+	require.NoError(t, db.First(&grandchild, 22).Error)
+	assert.Equal(t, "/20/21/22/", grandchild.Path)
+	assert.Equal(t, 2, grandchild.Depth)
+}
+
+func TestNoteRepository_CountDescendants(t *testing.T) {
+	db := setupNoteTestDB(t)
+	repo := NewNoteRepository(db)
+	ctx := context.Background()
+
+	notes := []models.Note{
+		{ID: 1, UserID: 1, Title: "Workspace", Path: "/1/"},
+		{ID: 2, UserID: 1, Title: "Project", Path: "/1/2/"},
+		{ID: 3, UserID: 1, Title: "Task", Path: "/1/2/3/"},
+		{ID: 4, UserID: 1, Title: "Trashed task", Path: "/1/2/4/", IsDeleted: true},
+		{ID: 10, UserID: 1, Title: "Lookalike prefix", Path: "/10/"},
+	}
+	require.NoError(t, db.Create(&notes).Error)
+
+	count, err := repo.CountDescendants(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	count, err = repo.CountDescendants(ctx, 3)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	_, err = repo.CountDescendants(ctx, 99)
+	assert.ErrorIs(t, err, domain.ErrNoteNotFound)
+}
+
+func TestNoteRepository_SoftDeleteSubtree(t *testing.T) {
+	db := setupNoteTestDB(t)
+	repo := NewNoteRepository(db)
+	ctx := context.Background()
+
+	// Paths are maintained by a Postgres trigger, so they're set by hand here
+	notes := []models.Note{
+		{ID: 1, UserID: 1, Title: "Workspace", Path: "/1/"},
+		{ID: 2, UserID: 1, Title: "Project", Path: "/1/2/"},
+		{ID: 3, UserID: 1, Title: "Task", Path: "/1/2/3/"},
+		{ID: 4, UserID: 1, Title: "Sibling", Path: "/4/"},
+	}
+	require.NoError(t, db.Create(&notes).Error)
+
+	// Fail the second update, after the descendants have been trashed
+	updates := 0
+	require.NoError(t, db.Callback().Update().Before("gorm:update").Register("test:fail_second_update", func(tx *gorm.DB) {
+		updates++
+		if updates == 2 {
+			_ = tx.AddError(errors.New("connection lost"))
+		}
+	}))
+
+	_, err := repo.SoftDeleteSubtree(ctx, 1)
+	require.Error(t, err)
+
+	var trashed int64
+	require.NoError(t, db.Model(&models.Note{}).Where("is_deleted = ?", true).Count(&trashed).Error)
+	assert.Zero(t, trashed, "a failed delete leaves no note trashed")
+
+	require.NoError(t, db.Callback().Update().Remove("test:fail_second_update"))
+
+	deleted, err := repo.SoftDeleteSubtree(ctx, 1)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []int64{1, 2, 3}, deleted)
+
+	var live []int64
+	require.NoError(t, db.Model(&models.Note{}).Where("is_deleted = ?", false).Pluck("id", &live).Error)
+	assert.Equal(t, []int64{4}, live)
+
+	_, err = repo.SoftDeleteSubtree(ctx, 1)
+	assert.ErrorIs(t, err, domain.ErrNoteNotFound)
+}
+
+func TestNoteRepository_FindDescendants_Canceled(t *testing.T) {
+	db := setupNoteTestDB(t)
+	repo := NewNoteRepository(db)
+
+	notes := []models.Note{{ID: 1, UserID: 1, Title: "Workspace", Path: "/1/"}}
+	for id := int64(2); id <= 3*descendantsBatchSize; id++ {
+		notes = append(notes, models.Note{ID: id, UserID: 1, Title: "Child", Path: fmt.Sprintf("/1/%d/", id)})
+	}
+	require.NoError(t, db.CreateInBatches(&notes, 200).Error)
+
+	descendants, err := repo.FindDescendants(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Len(t, descendants, 3*descendantsBatchSize-1)
+
+	// Cancel once the first batch of descendants has been read
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	batches := 0
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:cancel_after_batch", func(tx *gorm.DB) {
+		if _, ok := tx.Statement.Dest.(*[]models.Note); ok {
+			batches++
+			cancel()
+		}
+	}))
+
+	descendants, err = repo.FindDescendants(ctx, 1)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, descendants)
+	assert.Equal(t, 1, batches, "no batches are read after cancellation")
+}
+
+func TestNoteRepository_Replica(t *testing.T) {
+	primary := setupNoteTestDB(t)
+	replica, err := gorm.Open(sqlite.Open("file:"+t.Name()+"_replica?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, replica.AutoMigrate(&models.Note{}))
+
+	repo := NewNoteRepository(primary)
+	repo.SetReplica(replica, time.Hour)
+	ctx := context.Background()
+
+	// Only on the replica, standing in for rows it has already caught up on
+	require.NoError(t, replica.Create(&models.Note{ID: 50, UserID: 2, Title: "Replicated", Path: "/50/"}).Error)
+
+	note, err := repo.FindByID(ctx, 50)
+	require.NoError(t, err)
+	assert.Equal(t, "Replicated", note.Title)
+	batch, err := repo.FindByIDs(ctx, 2, []int64{50})
+	require.NoError(t, err)
+	assert.Len(t, batch, 1)
+	_, total, err := repo.FindByUserID(ctx, 2, ports.NoteFilters{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	// A note just written is read back from the primary
+	created := &domain.Note{UserID: 1, Title: "Fresh"}
+	require.NoError(t, repo.Create(ctx, created))
+
+	note, err = repo.FindByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Fresh", note.Title)
+	batch, err = repo.FindByIDs(ctx, 1, []int64{created.ID})
+	require.NoError(t, err)
+	assert.Len(t, batch, 1)
+	_, total, err = repo.FindByUserID(ctx, 1, ports.NoteFilters{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+}
+
+func TestNoteRepository_Replica_MoveAndDeleteMarkWrites(t *testing.T) {
+	primary := setupNoteTestDB(t)
+	replica, err := gorm.Open(sqlite.Open("file:"+t.Name()+"_replica?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+
+	repo := NewNoteRepository(primary)
+	ctx := context.Background()
+
+	parentID, childID := int64(1), int64(2)
+	require.NoError(t, primary.Create(&[]models.Note{
+		{ID: 1, UserID: 1, Title: "Parent", Path: "/1/"},
+		{ID: 2, UserID: 1, ParentID: &parentID, Title: "Child", Path: "/1/2/", Depth: 1},
+		{ID: 3, UserID: 1, ParentID: &childID, Title: "Grandchild", Path: "/1/2/3/", Depth: 2},
+		{ID: 4, UserID: 1, Title: "New parent", Path: "/4/"},
+		{ID: 5, UserID: 2, Title: "Someone else's", Path: "/5/"},
+	}).Error)
+	repo.SetReplica(replica, time.Hour)
+
+	// Descendants get new paths too, so they must be read from the primary
+	newParentID := int64(4)
+	require.NoError(t, repo.MoveNote(ctx, 1, &newParentID, 0))
+	for _, id := range []int64{1, 2, 3} {
+		assert.True(t, repo.writes.any(noteKey(id)), "note %d", id)
+	}
+	assert.False(t, repo.writes.any(noteKey(4)))
+
+	// Trashing a note changes its owner's listings
+	require.NoError(t, repo.Delete(ctx, 5))
+	assert.True(t, repo.writes.any(noteKey(5)))
+	assert.True(t, repo.writes.any(userKey(2)))
+}
+
+func TestNoteRepository_Replica_AfterLagWindow(t *testing.T) {
+	primary := setupNoteTestDB(t)
+	replica, err := gorm.Open(sqlite.Open("file:"+t.Name()+"_replica?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, replica.AutoMigrate(&models.Note{}))
+
+	repo := NewNoteRepository(primary)
+	repo.SetReplica(replica, time.Nanosecond)
+	ctx := context.Background()
+
+	created := &domain.Note{UserID: 1, Title: "Fresh"}
+	require.NoError(t, repo.Create(ctx, created))
+	time.Sleep(time.Millisecond)
+
+	// The replica never received it, and the write is no longer recent
+	_, err = repo.FindByID(ctx, created.ID)
+	assert.ErrorIs(t, err, domain.ErrNoteNotFound)
+}
+
+func TestNoteRepository_FindByUserID_WithinSubtree(t *testing.T) {
+	db := setupNoteTestDB(t)
+	repo := NewNoteRepository(db)
+
+	// Paths are maintained by a Postgres trigger, so they're set by hand here
+	notes := []models.Note{
+		{ID: 1, UserID: 1, Title: "Workspace", Path: "/1/"},
+		{ID: 2, UserID: 1, Title: "Project", Path: "/1/2/"},
+		{ID: 3, UserID: 1, Title: "Task", Path: "/1/2/3/"},
+		{ID: 4, UserID: 1, Title: "Elsewhere", Path: "/4/"},
+		{ID: 10, UserID: 1, Title: "Lookalike prefix", Path: "/10/"},
+	}
+	require.NoError(t, db.Create(&notes).Error)
+
+	within := int64(1)
+	found, total, err := repo.FindByUserID(context.Background(), 1, ports.NoteFilters{WithinNoteID: &within, SortBy: "title", SortOrder: "asc"})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	titles := make([]string, len(found))
+	for i, note := range found {
+		titles[i] = note.Title
+	}
+	assert.Equal(t, []string{"Project", "Task", "Workspace"}, titles)
+}
+
+func TestNoteRepository_FindByUserID_DateRanges(t *testing.T) {
+	db := setupNoteTestDB(t)
+	repo := NewNoteRepository(db)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	lastWeek := now.AddDate(0, 0, -7)
+	lastMonth := now.AddDate(0, -1, 0)
+	notes := []models.Note{
+		{ID: 1, UserID: 1, Title: "Old, untouched", CreatedAt: lastMonth, UpdatedAt: lastMonth},
+		{ID: 2, UserID: 1, Title: "Old, edited", CreatedAt: lastMonth, UpdatedAt: now},
+		{ID: 3, UserID: 1, Title: "Recent", CreatedAt: lastWeek.Add(time.Hour), UpdatedAt: lastWeek.Add(time.Hour)},
+	}
+	require.NoError(t, db.Create(&notes).Error)
+
+	titles := func(filters ports.NoteFilters) []string {
+		filters.SortBy, filters.SortOrder = "title", "asc"
+		found, _, err := repo.FindByUserID(context.Background(), 1, filters)
+		require.NoError(t, err)
+		titles := make([]string, len(found))
+		for i, note := range found {
+			titles[i] = note.Title
+		}
+		return titles
+	}
+
+	assert.Equal(t, []string{"Recent"}, titles(ports.NoteFilters{CreatedAfter: &lastWeek}))
+	assert.Equal(t, []string{"Old, edited", "Old, untouched"}, titles(ports.NoteFilters{CreatedBefore: &lastWeek}))
+	assert.Equal(t, []string{"Old, edited", "Recent"}, titles(ports.NoteFilters{UpdatedAfter: &lastWeek}))
+	assert.Equal(t, []string{"Recent"}, titles(ports.NoteFilters{UpdatedAfter: &lastWeek, UpdatedBefore: &now, CreatedAfter: &lastWeek}))
+}
+
+func TestNoteRepository_FindByUserID_SortsTitlesCaseInsensitively(t *testing.T) {
+	db := setupNoteTestDB(t)
+	repo := NewNoteRepository(db)
+
+	notes := []models.Note{
+		{ID: 1, UserID: 1, Title: "banana"},
+		{ID: 2, UserID: 1, Title: "Cherry"},
+		{ID: 3, UserID: 1, Title: "apple"},
+		{ID: 4, UserID: 1, Title: "Date"},
+	}
+	require.NoError(t, db.Create(&notes).Error)
+
+	titles := func() []string {
+		found, _, err := repo.FindByUserID(context.Background(), 1, ports.NoteFilters{SortBy: "title", SortOrder: "asc"})
+		require.NoError(t, err)
+		titles := make([]string, len(found))
+		for i, note := range found {
+			titles[i] = note.Title
+		}
+		return titles
+	}
+
+	assert.Equal(t, []string{"apple", "banana", "Cherry", "Date"}, titles())
+
+	// SQLite's NOCASE stands in for a locale-aware Postgres collation
+	repo.SetTitleCollation("NOCASE")
+	assert.Equal(t, []string{"apple", "banana", "Cherry", "Date"}, titles())
+}
+
+func TestNoteRepository_FindByUserID_FavoritesFirst(t *testing.T) {
+	db := setupNoteTestDB(t)
+	repo := NewNoteRepository(db)
+
+	notes := []models.Note{
+		{ID: 1, UserID: 1, Title: "Plain"},
+		{ID: 2, UserID: 1, Title: "Favorite", IsFavorite: true},
+		{ID: 3, UserID: 1, Title: "Also plain"},
+	}
+	require.NoError(t, db.Create(&notes).Error)
+
+	found, _, err := repo.FindByUserID(context.Background(), 1, ports.NoteFilters{SortBy: "is_favorite", SortOrder: "desc"})
+
+	require.NoError(t, err)
+	require.Len(t, found, 3)
+	assert.Equal(t, "Favorite", found[0].Title)
+}
+
+func TestNoteRepository_FindByUserID_TiesPaginateConsistently(t *testing.T) {
+	db := setupNoteTestDB(t)
+	repo := NewNoteRepository(db)
+
+	at := time.Now().UTC().Truncate(time.Second)
+	notes := []models.Note{
+		{ID: 1, UserID: 1, Title: "First", CreatedAt: at, UpdatedAt: at},
+		{ID: 2, UserID: 1, Title: "Second", CreatedAt: at, UpdatedAt: at},
+		{ID: 3, UserID: 1, Title: "Third", CreatedAt: at, UpdatedAt: at},
+	}
+	require.NoError(t, db.Create(&notes).Error)
+
+	var ids []int64
+	for offset := 0; offset < len(notes); offset++ {
+		found, total, err := repo.FindByUserID(context.Background(), 1, ports.NoteFilters{SortBy: "updated_at", SortOrder: "desc", Limit: 1, Offset: offset})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		require.Len(t, found, 1)
+		ids = append(ids, found[0].ID)
+	}
+
+	assert.Equal(t, []int64{3, 2, 1}, ids)
+}
+
+func TestNoteRepository_FindDescendantsPage(t *testing.T) {
+	db := setupNoteTestDB(t)
+	repo := NewNoteRepository(db)
+	ctx := context.Background()
+
+	// Paths are maintained by a Postgres trigger, so they're set by hand here
+	notes := []models.Note{
+		{ID: 1, UserID: 1, Title: "Workspace", Path: "/1/"},
+		{ID: 2, UserID: 1, Title: "Project", Path: "/1/2/", Blocks: models.BlocksJSON{
+			{ID: "a", Type: domain.BlockTypeParagraph, Content: &domain.BlockContent{RichText: []domain.RichTextSegment{{Text: "body"}}}},
+		}},
+		{ID: 3, UserID: 1, Title: "Task", Path: "/1/2/3/"},
+		{ID: 4, UserID: 1, Title: "Notes", Path: "/1/4/"},
+		{ID: 5, UserID: 1, Title: "Trashed", Path: "/1/5/", IsDeleted: true},
+		{ID: 6, UserID: 1, Title: "Elsewhere", Path: "/6/"},
+	}
+	require.NoError(t, db.Create(&notes).Error)
+
+	ids := func(found []*domain.Note) []int64 {
+		ids := make([]int64, len(found))
+		for i, note := range found {
+			ids[i] = note.ID
+		}
+		return ids
+	}
+
+	found, total, err := repo.FindDescendantsPage(ctx, 1, 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Equal(t, []int64{2, 3}, ids(found))
+	assert.Empty(t, found[0].Blocks, "only summary fields are loaded")
+
+	found, total, err = repo.FindDescendantsPage(ctx, 1, 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Equal(t, []int64{4}, ids(found))
+
+	_, _, err = repo.FindDescendantsPage(ctx, 5, 2, 0)
+	assert.ErrorIs(t, err, domain.ErrNoteNotFound)
+}
+
+func TestNoteRepository_FindByUserID_SummaryOnly(t *testing.T) {
+	db := setupNoteTestDB(t)
+	repo := NewNoteRepository(db)
+
+	note := models.Note{ID: 1, UserID: 1, Title: "Workspace", Icon: "📁", Path: "/1/", Blocks: models.BlocksJSON{
+		{ID: "a", Type: domain.BlockTypeParagraph, Content: &domain.BlockContent{RichText: []domain.RichTextSegment{{Text: "body"}}}},
+	}}
+	require.NoError(t, db.Create(&note).Error)
+
+	found, total, err := repo.FindByUserID(context.Background(), 1, ports.NoteFilters{SummaryOnly: true, Limit: 10})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, found, 1)
+	assert.Equal(t, "Workspace", found[0].Title)
+	assert.Equal(t, "📁", found[0].Icon)
+	assert.Empty(t, found[0].Blocks)
+}
+
+func TestNoteRepository_RebuildPaths(t *testing.T) {
+	db := setupNoteTestDB(t)
+	repo := NewNoteRepository(db)
+
+	id := func(v int64) *int64 { return &v }
+	notes := []models.Note{
+		{ID: 1, UserID: 1, Title: "Workspace", Path: "/1/"},
+		{ID: 2, UserID: 1, Title: "Moved project", ParentID: id(1), Path: "/5/2/", Depth: 1},
+		{ID: 3, UserID: 1, Title: "Task", ParentID: id(2), Path: "/5/2/3/", Depth: 2},
+		{ID: 4, UserID: 1, Title: "Trashed", ParentID: id(3), IsDeleted: true, DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}},
+		{ID: 5, UserID: 1, Title: "Lost parent", ParentID: id(99), Path: "/99/5/", Depth: 1},
+		{ID: 6, UserID: 1, Title: "Cycle A", ParentID: id(7)},
+		{ID: 7, UserID: 1, Title: "Cycle B", ParentID: id(6)},
+		{ID: 8, UserID: 2, Title: "Someone else's", ParentID: id(1)},
+	}
+	require.NoError(t, db.Create(&notes).Error)
+
+	result, err := repo.RebuildPaths(context.Background(), 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, 7, result.Checked)
+	assert.Equal(t, []int64{2, 3, 4}, result.Corrected)
+	assert.Equal(t, []int64{5, 6, 7}, result.Unreachable)
+
+	var rebuilt []models.Note
+	require.NoError(t, db.Unscoped().Order("id").Find(&rebuilt, []int64{1, 2, 3, 4, 5, 8}).Error)
+	paths := make(map[int64]string, len(rebuilt))
+	depths := make(map[int64]int, len(rebuilt))
+	for _, note := range rebuilt {
+		paths[note.ID] = note.Path
+		depths[note.ID] = note.Depth
+	}
+	assert.Equal(t, map[int64]string{1: "/1/", 2: "/1/2/", 3: "/1/2/3/", 4: "/1/2/3/4/", 5: "/99/5/", 8: ""}, paths)
+	assert.Equal(t, map[int64]int{1: 0, 2: 1, 3: 2, 4: 3, 5: 1, 8: 0}, depths)
+
+	// Nothing is left to correct on a second run
+	result, err = repo.RebuildPaths(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Empty(t, result.Corrected)
+}
+
+func TestNoteRepository_FindOrphans(t *testing.T) {
+	db := setupNoteTestDB(t)
+	repo := NewNoteRepository(db)
+
+	id := func(v int64) *int64 { return &v }
+	trashed := gorm.DeletedAt{Time: time.Now(), Valid: true}
+	notes := []models.Note{
+		{ID: 1, UserID: 1, Title: "Workspace", Path: "/1/"},
+		{ID: 2, UserID: 1, Title: "Child", ParentID: id(1), Path: "/1/2/", Depth: 1},
+		{ID: 3, UserID: 1, Title: "Lost parent", ParentID: id(99), Path: "/99/3/", Depth: 1},
+		{ID: 4, UserID: 1, Title: "Lost grandchild", ParentID: id(3), Path: "/99/3/4/", Depth: 2},
+		{ID: 5, UserID: 1, Title: "Trashed parent", Path: "/5/", IsDeleted: true, DeletedAt: trashed},
+		{ID: 6, UserID: 1, Title: "Under trash", ParentID: id(5), Path: "/5/6/", Depth: 1},
+		{ID: 7, UserID: 1, Title: "Trashed with parent", ParentID: id(5), Path: "/5/7/", Depth: 1, IsDeleted: true, DeletedAt: trashed},
+		{ID: 8, UserID: 2, Title: "Someone else's parent", ParentID: id(1), Path: "/1/8/", Depth: 1},
+	}
+	require.NoError(t, db.Create(&notes).Error)
+
+	orphans, err := repo.FindOrphans(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []ports.OrphanNote{
+		{ID: 3, UserID: 1, ParentID: 99},
+		{ID: 6, UserID: 1, ParentID: 5, ParentTrashed: true},
+		{ID: 8, UserID: 2, ParentID: 1},
+	}, orphans)
+
+	require.NoError(t, repo.DetachNotes(context.Background(), []int64{3, 8}))
+
+	var detached []models.Note
+	require.NoError(t, db.Order("id").Find(&detached, []int64{3, 8}).Error)
+	require.Len(t, detached, 2)
+	for _, note := range detached {
+		assert.Nil(t, note.ParentID)
+		assert.Equal(t, fmt.Sprintf("/%d/", note.ID), note.Path)
+		assert.Equal(t, 0, note.Depth)
+	}
+
+	orphans, err = repo.FindOrphans(context.Background())
+	require.NoError(t, err)
+	require.Len(t, orphans, 1)
+	assert.Equal(t, int64(6), orphans[0].ID)
+}
+
+// setupTagTables creates the tag tables, which have no GORM models
+func setupTagTables(t *testing.T, db *gorm.DB) {
+	require.NoError(t, db.Exec(`CREATE TABLE tags (
+		id VARCHAR(100) PRIMARY KEY,
+		user_id BIGINT NOT NULL,
+		name VARCHAR(100) NOT NULL,
+		color VARCHAR(50) NOT NULL DEFAULT 'gray',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE note_tags (
+		note_id BIGINT NOT NULL,
+		tag_id VARCHAR(100) NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (note_id, tag_id)
+	)`).Error)
+}
+
+func TestNoteRepository_BulkTags(t *testing.T) {
+	db := setupNoteTestDB(t)
+	setupTagTables(t, db)
+	repo := NewNoteRepository(db)
+	ctx := context.Background()
+
+	require.NoError(t, db.Exec(`INSERT INTO tags (id, user_id, name) VALUES ('tag-work', 1, 'Work')`).Error)
+
+	tag, err := repo.FindTag(ctx, "tag-work")
+	require.NoError(t, err)
+	assert.Equal(t, "Work", tag.Name)
+	assert.Equal(t, int64(1), tag.UserID)
+
+	_, err = repo.FindTag(ctx, "tag-missing")
+	assert.ErrorIs(t, err, domain.ErrTagNotFound)
+
+	countTagged := func() int64 {
+		var count int64
+		require.NoError(t, db.Raw(`SELECT COUNT(*) FROM note_tags WHERE tag_id = 'tag-work'`).Scan(&count).Error)
+		return count
+	}
+
+	require.NoError(t, repo.AddTag(ctx, 1, "tag-work"))
+	require.NoError(t, repo.BulkAddTag(ctx, []int64{1, 2, 3}, "tag-work"))
+	assert.Equal(t, int64(3), countTagged(), "already tagged notes are skipped")
+
+	require.NoError(t, repo.BulkRemoveTag(ctx, []int64{1, 3}, "tag-work"))
+	assert.Equal(t, int64(1), countTagged())
+}
+
+func TestNoteRepository_FindByIDs(t *testing.T) {
+	db := setupNoteTestDB(t)
+	setupTagTables(t, db)
+	repo := NewNoteRepository(db)
+
+	notes := []models.Note{
+		{ID: 1, UserID: 1, Title: "Plan"},
+		{ID: 2, UserID: 1, Title: "Report"},
+		{ID: 3, UserID: 1, Title: "Trashed", IsDeleted: true},
+		{ID: 4, UserID: 2, Title: "Someone else's"},
+	}
+	require.NoError(t, db.Create(&notes).Error)
+	require.NoError(t, db.Exec(`INSERT INTO tags (id, user_id, name) VALUES
+		('tag-work', 1, 'Work'), ('tag-urgent', 1, 'Urgent')`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO note_tags (note_id, tag_id) VALUES
+		(1, 'tag-work'), (1, 'tag-urgent'), (3, 'tag-work')`).Error)
+
+	found, err := repo.FindByIDs(context.Background(), 1, []int64{1, 2, 3, 4, 99})
+
+	require.NoError(t, err)
+	require.Len(t, found, 2)
+	byID := map[int64]*domain.Note{found[0].ID: found[0], found[1].ID: found[1]}
+	require.Contains(t, byID, int64(1))
+	require.Contains(t, byID, int64(2))
+
+	// Tags are loaded as FindByID does, ordered by name
+	require.Len(t, byID[1].Tags, 2)
+	assert.Equal(t, "Urgent", byID[1].Tags[0].Name)
+	assert.Equal(t, "Work", byID[1].Tags[1].Name)
+	assert.NotNil(t, byID[2].Tags)
+	assert.Empty(t, byID[2].Tags)
+}
+
+func TestNoteRepository_ListTags(t *testing.T) {
+	db := setupNoteTestDB(t)
+	setupTagTables(t, db)
+	repo := NewNoteRepository(db)
+
+	notes := []models.Note{
+		{ID: 1, UserID: 1, Title: "Plan"},
+		{ID: 2, UserID: 1, Title: "Report"},
+		{ID: 3, UserID: 1, Title: "Trashed", IsDeleted: true},
+	}
+	require.NoError(t, db.Create(&notes).Error)
+	require.NoError(t, db.Exec(`INSERT INTO tags (id, user_id, name) VALUES
+		('tag-work', 1, 'Work'), ('tag-unused', 1, 'Unused'), ('tag-theirs', 2, 'Theirs')`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO note_tags (note_id, tag_id) VALUES
+		(1, 'tag-work'), (2, 'tag-work'), (3, 'tag-work'), (1, 'tag-theirs')`).Error)
+
+	tags, total, err := repo.ListTags(context.Background(), 1, 0, 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, tags, 2)
+	assert.Equal(t, "Unused", tags[0].Name)
+	assert.Equal(t, int64(0), tags[0].NoteCount)
+	assert.Equal(t, "Work", tags[1].Name)
+	assert.Equal(t, int64(2), tags[1].NoteCount, "trashed notes aren't counted")
+
+	tags, total, err = repo.ListTags(context.Background(), 1, 1, 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, tags, 1)
+	assert.Equal(t, "Work", tags[0].Name)
+}
+
+func TestNoteRepository_MergeTags(t *testing.T) {
+	db := setupNoteTestDB(t)
+	setupTagTables(t, db)
+	repo := NewNoteRepository(db)
+	ctx := context.Background()
+
+	require.NoError(t, db.Exec(`INSERT INTO tags (id, user_id, name) VALUES
+		('tag-work', 1, 'Work'), ('tag-wrk', 1, 'Wrk')`).Error)
+	// Note 2 has both tags
+	require.NoError(t, db.Exec(`INSERT INTO note_tags (note_id, tag_id) VALUES
+		(1, 'tag-wrk'), (2, 'tag-wrk'), (2, 'tag-work'), (3, 'tag-work')`).Error)
+
+	require.NoError(t, repo.MergeTags(ctx, "tag-wrk", "tag-work"))
+
+	noteIDs, err := repo.FindNoteIDsByTag(ctx, "tag-work")
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, noteIDs)
+
+	noteIDs, err = repo.FindNoteIDsByTag(ctx, "tag-wrk")
+	require.NoError(t, err)
+	assert.Empty(t, noteIDs)
+
+	_, err = repo.FindTag(ctx, "tag-wrk")
+	assert.ErrorIs(t, err, domain.ErrTagNotFound)
+
+	assert.ErrorIs(t, repo.MergeTags(ctx, "tag-wrk", "tag-work"), domain.ErrTagNotFound)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/yourusername/notinoteapp/internal/adapters/secondary/database/postgres/models"
 	"github.com/yourusername/notinoteapp/internal/core/domain"
+	"github.com/yourusername/notinoteapp/internal/core/ports"
 	"gorm.io/gorm"
 )
 
@@ -83,6 +84,52 @@ func (r *NotificationLogRepository) FindByUserID(ctx context.Context, userID int
 	return logs, total, nil
 }
 
+// ListByUser finds log entries for a user with filters and pagination
+func (r *NotificationLogRepository) ListByUser(ctx context.Context, userID int64, params *ports.NotificationLogQueryParams) ([]*domain.NotificationLog, int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&models.NotificationLog{}).
+		Where("user_id = ?", userID)
+
+	if params != nil {
+		if params.Status != nil {
+			query = query.Where("status = ?", *params.Status)
+		}
+		if params.FromDate != nil {
+			query = query.Where("created_at >= ?", *params.FromDate)
+		}
+		if params.ToDate != nil {
+			query = query.Where("created_at <= ?", *params.ToDate)
+		}
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query = query.Order("created_at DESC").Order("id DESC")
+	if params != nil {
+		if params.Limit > 0 {
+			query = query.Limit(params.Limit)
+		}
+		if params.Offset > 0 {
+			query = query.Offset(params.Offset)
+		}
+	}
+
+	var dbLogs []models.NotificationLog
+	if err := query.Find(&dbLogs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	logs := make([]*domain.NotificationLog, len(dbLogs))
+	for i, dbLog := range dbLogs {
+		logs[i] = dbLog.ToDomain()
+	}
+
+	return logs, total, nil
+}
+
 // FindByReminderID finds log entries for a reminder
 func (r *NotificationLogRepository) FindByReminderID(ctx context.Context, reminderID int64) ([]*domain.NotificationLog, error) {
 	var dbLogs []models.NotificationLog
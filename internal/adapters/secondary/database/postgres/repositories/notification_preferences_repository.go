@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/yourusername/notinoteapp/internal/adapters/secondary/database/postgres/models"
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotificationPreferencesRepository implements the notification preferences repository interface using PostgreSQL
+type NotificationPreferencesRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationPreferencesRepository creates a new notification preferences repository
+func NewNotificationPreferencesRepository(db *gorm.DB) *NotificationPreferencesRepository {
+	return &NotificationPreferencesRepository{db: db}
+}
+
+// FindByUserID finds the preferences for a user
+func (r *NotificationPreferencesRepository) FindByUserID(ctx context.Context, userID int64) (*domain.NotificationPreferences, error) {
+	var dbPrefs models.NotificationPreferences
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&dbPrefs).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotificationPreferencesNotFound
+		}
+		return nil, err
+	}
+
+	return dbPrefs.ToDomain(), nil
+}
+
+// Upsert creates or replaces the preferences for a user
+func (r *NotificationPreferencesRepository) Upsert(ctx context.Context, prefs *domain.NotificationPreferences) error {
+	dbPrefs := &models.NotificationPreferences{}
+	dbPrefs.FromDomain(prefs)
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"push_enabled", "email_enabled", "muted_repeat_types", "updated_at"}),
+		}).
+		Create(dbPrefs).Error
+	if err != nil {
+		return err
+	}
+
+	prefs.CreatedAt = dbPrefs.CreatedAt
+	prefs.UpdatedAt = dbPrefs.UpdatedAt
+	return nil
+}
@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"sync"
+	"time"
+)
+
+// recentWritesSweepSize is how many entries recentWrites holds before a write
+// sweeps out expired ones
+const recentWritesSweepSize = 1024
+
+// recentWrites remembers what was written within the last window, so reads of
+// it can skip a replica that may not have caught up yet. It only sees writes
+// made through this process.
+type recentWrites struct {
+	mu      sync.Mutex
+	window  time.Duration
+	written map[string]time.Time
+}
+
+func newRecentWrites(window time.Duration) *recentWrites {
+	return &recentWrites{window: window, written: make(map[string]time.Time)}
+}
+
+// mark records a write to each key
+func (w *recentWrites) mark(keys ...string) {
+	now := time.Now()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.written) >= recentWritesSweepSize {
+		for key, at := range w.written {
+			if now.Sub(at) > w.window {
+				delete(w.written, key)
+			}
+		}
+	}
+	for _, key := range keys {
+		w.written[key] = now
+	}
+}
+
+// any reports whether any of the keys was written within the window
+func (w *recentWrites) any(keys ...string) bool {
+	now := time.Now()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, key := range keys {
+		at, ok := w.written[key]
+		if !ok {
+			continue
+		}
+		if now.Sub(at) <= w.window {
+			return true
+		}
+		delete(w.written, key)
+	}
+	return false
+}
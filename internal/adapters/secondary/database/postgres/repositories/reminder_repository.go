@@ -41,14 +41,14 @@ func (r *ReminderRepository) Create(ctx context.Context, reminder *domain.Remind
 // FindByID finds a reminder by ID
 func (r *ReminderRepository) FindByID(ctx context.Context, id int64) (*domain.Reminder, error) {
 	var dbReminder models.Reminder
-	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&dbReminder).Error; err != nil {
+	if err := preloadNoteState(r.db.WithContext(ctx)).Where("id = ?", id).First(&dbReminder).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, domain.ErrReminderNotFound
 		}
 		return nil, err
 	}
 
-	return dbReminder.ToDomain(), nil
+	return withNoteLoaded(dbReminder.ToDomain()), nil
 }
 
 // FindByNoteID finds all reminders for a note
@@ -71,24 +71,19 @@ func (r *ReminderRepository) FindByNoteID(ctx context.Context, noteID int64) ([]
 
 // FindByUserID finds all reminders for a user with filters
 func (r *ReminderRepository) FindByUserID(ctx context.Context, userID int64, params *ports.ReminderQueryParams) ([]*domain.Reminder, error) {
-	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	query := filterReminders(r.db.WithContext(ctx).Where("user_id = ?", userID), params)
 
 	if params != nil {
-		if params.IsEnabled != nil {
-			query = query.Where("is_enabled = ?", *params.IsEnabled)
-		}
-		if params.FromDate != nil {
-			query = query.Where("next_trigger_at >= ?", *params.FromDate)
-		}
-		if params.ToDate != nil {
-			query = query.Where("next_trigger_at <= ?", *params.ToDate)
-		}
 		if params.Limit > 0 {
 			query = query.Limit(params.Limit)
 		}
 		if params.Offset > 0 {
 			query = query.Offset(params.Offset)
 		}
+		if params.IncludeNote {
+			// One batched query for all the notes rather than one per reminder
+			query = preloadNoteState(query)
+		}
 	}
 
 	var dbReminders []models.Reminder
@@ -99,16 +94,54 @@ func (r *ReminderRepository) FindByUserID(ctx context.Context, userID int64, par
 	reminders := make([]*domain.Reminder, len(dbReminders))
 	for i, dbReminder := range dbReminders {
 		reminders[i] = dbReminder.ToDomain()
+		if params != nil && params.IncludeNote {
+			withNoteLoaded(reminders[i])
+		}
 	}
 
 	return reminders, nil
 }
 
+// CountByUserID counts a user's reminders matching the filters in params,
+// ignoring its limit and offset
+func (r *ReminderRepository) CountByUserID(ctx context.Context, userID int64, params *ports.ReminderQueryParams) (int64, error) {
+	var total int64
+	err := filterReminders(r.db.WithContext(ctx).Model(&models.Reminder{}).Where("user_id = ?", userID), params).
+		Count(&total).Error
+	return total, err
+}
+
+// filterReminders applies the filters in params to a reminder query
+func filterReminders(query *gorm.DB, params *ports.ReminderQueryParams) *gorm.DB {
+	if params == nil {
+		return query
+	}
+	if params.IsEnabled != nil {
+		query = query.Where("is_enabled = ?", *params.IsEnabled)
+	}
+	if params.NoteID != nil {
+		query = query.Where("note_id = ?", *params.NoteID)
+	}
+	if params.RepeatType != nil {
+		query = query.Where("repeat_type = ?", *params.RepeatType)
+	}
+	if params.FromDate != nil {
+		query = query.Where("next_trigger_at >= ?", *params.FromDate)
+	}
+	if params.ToDate != nil {
+		query = query.Where("next_trigger_at <= ?", *params.ToDate)
+	}
+	return query
+}
+
 // FindDueReminders finds all enabled reminders that are due (next_trigger_at <= until)
 func (r *ReminderRepository) FindDueReminders(ctx context.Context, until time.Time, limit int) ([]*domain.Reminder, error) {
 	var dbReminders []models.Reminder
-	query := r.db.WithContext(ctx).
+	query := preloadOwnerState(preloadNoteState(r.db.WithContext(ctx))).
 		Where("is_enabled = ? AND next_trigger_at <= ?", true, until).
+		// A zero trigger time is always "due"; skip such rows rather than
+		// firing them on every tick
+		Where("next_trigger_at > ?", time.Time{}).
 		Order("next_trigger_at ASC")
 
 	if limit > 0 {
@@ -121,7 +154,7 @@ func (r *ReminderRepository) FindDueReminders(ctx context.Context, until time.Ti
 
 	reminders := make([]*domain.Reminder, len(dbReminders))
 	for i, dbReminder := range dbReminders {
-		reminders[i] = dbReminder.ToDomain()
+		reminders[i] = withNoteLoaded(dbReminder.ToDomain())
 	}
 
 	return reminders, nil
@@ -132,9 +165,12 @@ func (r *ReminderRepository) Update(ctx context.Context, reminder *domain.Remind
 	dbReminder := &models.Reminder{}
 	dbReminder.FromDomain(reminder)
 
+	// Select all columns so zero values (is_enabled=false, snooze_count=0) are persisted
 	result := r.db.WithContext(ctx).
 		Model(&models.Reminder{}).
 		Where("id = ?", reminder.ID).
+		Select("*").
+		Omit("id", "created_at", "Note").
 		Updates(dbReminder)
 
 	if result.Error != nil {
@@ -212,6 +248,67 @@ func (r *ReminderRepository) IncrementTriggerCount(ctx context.Context, id int64
 	return nil
 }
 
+// MarkTriggered records a trigger in one statement: it sets the next trigger
+// time and enabled state, stamps the last trigger time with triggeredAt,
+// increments the trigger count and clears the snooze count
+func (r *ReminderRepository) MarkTriggered(ctx context.Context, id int64, nextTrigger time.Time, isEnabled bool, triggeredAt time.Time) error {
+	result := markTriggered(r.db.WithContext(ctx), id, nextTrigger, isEnabled, triggeredAt)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrReminderNotFound
+	}
+
+	return nil
+}
+
+// MarkTriggeredBatch marks several reminders triggered at triggeredAt in one
+// transaction, using each reminder's NextTriggerAt and IsEnabled. Reminders
+// deleted in the meantime are skipped and their IDs returned.
+func (r *ReminderRepository) MarkTriggeredBatch(ctx context.Context, reminders []*domain.Reminder, triggeredAt time.Time) ([]int64, error) {
+	if len(reminders) == 0 {
+		return nil, nil
+	}
+
+	var missing []int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		missing = nil
+		for _, reminder := range reminders {
+			result := markTriggered(tx, reminder.ID, reminder.NextTriggerAt, reminder.IsEnabled, triggeredAt)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				missing = append(missing, reminder.ID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return missing, nil
+}
+
+// markTriggered issues the single UPDATE behind MarkTriggered
+func markTriggered(db *gorm.DB, id int64, nextTrigger time.Time, isEnabled bool, triggeredAt time.Time) *gorm.DB {
+	return db.
+		Model(&models.Reminder{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"next_trigger_at":   nextTrigger,
+			"last_triggered_at": triggeredAt,
+			"is_enabled":        isEnabled,
+			"trigger_count":     gorm.Expr("trigger_count + 1"),
+			"snooze_count":      0,
+			"updated_at":        triggeredAt,
+		})
+}
+
 // CheckOwnership checks if a reminder belongs to a user
 func (r *ReminderRepository) CheckOwnership(ctx context.Context, reminderID, userID int64) (bool, error) {
 	var count int64
@@ -226,3 +323,28 @@ func (r *ReminderRepository) CheckOwnership(ctx context.Context, reminderID, use
 
 	return count > 0, nil
 }
+
+// preloadNoteState loads the archived/deleted state of each reminder's note so
+// callers can decide whether the reminder should still be delivered, and the
+// properties relative reminders are resolved against. Trashed notes are
+// loaded too; the default scope would drop them and leave the note nil.
+func preloadNoteState(db *gorm.DB) *gorm.DB {
+	return db.Preload("Note", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped().Select("id", "user_id", "title", "properties", "is_archived", "is_deleted")
+	})
+}
+
+// withNoteLoaded marks a reminder as loaded through preloadNoteState, so a
+// missing note reads as gone rather than unknown
+func withNoteLoaded(reminder *domain.Reminder) *domain.Reminder {
+	reminder.NoteLoaded = true
+	return reminder
+}
+
+// preloadOwnerState loads just enough of the reminder's owner to tell whether
+// the account is active
+func preloadOwnerState(db *gorm.DB) *gorm.DB {
+	return db.Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "is_active")
+	})
+}
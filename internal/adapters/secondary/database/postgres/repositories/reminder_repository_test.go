@@ -0,0 +1,290 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/notinoteapp/internal/adapters/secondary/database/postgres/models"
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+	"github.com/yourusername/notinoteapp/internal/core/ports"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupReminderTestDB creates an in-memory SQLite database private to the test
+func setupReminderTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&models.Note{})
+	require.NoError(t, err)
+
+	// Declared by hand: SQLite only scans times from columns typed as datetime,
+	// not the model's timestamptz
+	err = db.Exec(`CREATE TABLE note_reminders (
+		id integer PRIMARY KEY AUTOINCREMENT,
+		note_id integer NOT NULL,
+		user_id integer NOT NULL,
+		title text NOT NULL,
+		message text,
+		scheduled_at datetime NOT NULL,
+		repeat_type text NOT NULL DEFAULT 'once',
+		repeat_config text,
+		repeat_end_at datetime,
+		payload text,
+		relative text,
+		is_enabled numeric NOT NULL DEFAULT true,
+		next_trigger_at datetime NOT NULL,
+		last_triggered_at datetime,
+		trigger_count integer NOT NULL DEFAULT 0,
+		snooze_count integer NOT NULL DEFAULT 0,
+		created_at datetime,
+		updated_at datetime
+	)`).Error
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestReminderRepository_FindByUserID_IncludeNote(t *testing.T) {
+	db := setupReminderTestDB(t)
+	repo := NewReminderRepository(db)
+
+	notes := []models.Note{
+		{ID: 1, UserID: 1, Title: "Groceries"},
+		{ID: 2, UserID: 1, Title: "Trashed", IsDeleted: true},
+	}
+	require.NoError(t, db.Create(&notes).Error)
+
+	at := time.Now().Add(time.Hour).UTC()
+	reminders := []models.Reminder{
+		{NoteID: 1, UserID: 1, Title: "Buy milk", RepeatType: "once", ScheduledAt: at, NextTriggerAt: at, IsEnabled: true},
+		{NoteID: 2, UserID: 1, Title: "Old", RepeatType: "once", ScheduledAt: at, NextTriggerAt: at.Add(time.Minute), IsEnabled: true},
+		{NoteID: 99, UserID: 1, Title: "Orphan", RepeatType: "once", ScheduledAt: at, NextTriggerAt: at.Add(2 * time.Minute), IsEnabled: true},
+	}
+	require.NoError(t, db.Create(&reminders).Error)
+
+	found, err := repo.FindByUserID(context.Background(), 1, &ports.ReminderQueryParams{IncludeNote: true})
+
+	require.NoError(t, err)
+	require.Len(t, found, 3)
+	require.NotNil(t, found[0].Note)
+	assert.Equal(t, "Groceries", found[0].Note.Title)
+	require.NotNil(t, found[1].Note)
+	assert.True(t, found[1].Note.IsDeleted)
+	assert.Nil(t, found[2].Note)
+
+	found, err = repo.FindByUserID(context.Background(), 1, nil)
+	require.NoError(t, err)
+	assert.Nil(t, found[0].Note)
+}
+
+func TestReminderRepository_FindByUserID_Filters(t *testing.T) {
+	db := setupReminderTestDB(t)
+	repo := NewReminderRepository(db)
+
+	at := time.Now().Add(time.Hour).UTC()
+	reminders := []models.Reminder{
+		{NoteID: 1, UserID: 1, Title: "Once on 1", RepeatType: "once", ScheduledAt: at, NextTriggerAt: at, IsEnabled: true},
+		{NoteID: 1, UserID: 1, Title: "Daily on 1", RepeatType: "daily", ScheduledAt: at, NextTriggerAt: at.Add(time.Minute), IsEnabled: true},
+		{NoteID: 2, UserID: 1, Title: "Daily on 2", RepeatType: "daily", ScheduledAt: at, NextTriggerAt: at.Add(2 * time.Minute), IsEnabled: true},
+		{NoteID: 1, UserID: 2, Title: "Someone else's", RepeatType: "daily", ScheduledAt: at, NextTriggerAt: at, IsEnabled: true},
+	}
+	require.NoError(t, db.Create(&reminders).Error)
+
+	titles := func(params *ports.ReminderQueryParams) []string {
+		found, err := repo.FindByUserID(context.Background(), 1, params)
+		require.NoError(t, err)
+		titles := make([]string, len(found))
+		for i, reminder := range found {
+			titles[i] = reminder.Title
+		}
+		return titles
+	}
+
+	noteID := int64(1)
+	daily := domain.RepeatTypeDaily
+	assert.Equal(t, []string{"Once on 1", "Daily on 1"}, titles(&ports.ReminderQueryParams{NoteID: &noteID}))
+	assert.Equal(t, []string{"Daily on 1", "Daily on 2"}, titles(&ports.ReminderQueryParams{RepeatType: &daily}))
+	assert.Equal(t, []string{"Daily on 1"}, titles(&ports.ReminderQueryParams{NoteID: &noteID, RepeatType: &daily}))
+
+	// Paging limits the rows returned but not the count
+	params := &ports.ReminderQueryParams{RepeatType: &daily, Limit: 1, Offset: 1}
+	assert.Equal(t, []string{"Daily on 2"}, titles(params))
+	total, err := repo.CountByUserID(context.Background(), 1, params)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+}
+
+func TestReminderRepository_Payload(t *testing.T) {
+	db := setupReminderTestDB(t)
+	repo := NewReminderRepository(db)
+	ctx := context.Background()
+
+	badge := 3
+	withPayload := &domain.Reminder{NoteID: 1, UserID: 1, Title: "Custom", ScheduledAt: time.Now(), RepeatType: domain.RepeatTypeOnce, NextTriggerAt: time.Now(),
+		Payload: &domain.ReminderPayload{ClickURL: "/notes?id=1&block=b1", Sound: "chime", Badge: &badge}}
+	withoutPayload := &domain.Reminder{NoteID: 1, UserID: 1, Title: "Default", ScheduledAt: time.Now(), RepeatType: domain.RepeatTypeOnce, NextTriggerAt: time.Now()}
+	require.NoError(t, repo.Create(ctx, withPayload))
+	require.NoError(t, repo.Create(ctx, withoutPayload))
+
+	found, err := repo.FindByID(ctx, withPayload.ID)
+	require.NoError(t, err)
+	assert.Equal(t, withPayload.Payload, found.Payload)
+
+	found, err = repo.FindByID(ctx, withoutPayload.ID)
+	require.NoError(t, err)
+	assert.Nil(t, found.Payload)
+}
+
+func TestReminderRepository_MarkTriggered(t *testing.T) {
+	db := setupReminderTestDB(t)
+	repo := NewReminderRepository(db)
+	ctx := context.Background()
+
+	at := time.Now().UTC()
+	reminder := &domain.Reminder{NoteID: 1, UserID: 1, Title: "Daily", ScheduledAt: at, RepeatType: domain.RepeatTypeDaily, NextTriggerAt: at, IsEnabled: true, TriggerCount: 2, SnoozeCount: 1}
+	require.NoError(t, repo.Create(ctx, reminder))
+
+	next := at.Add(24 * time.Hour)
+	triggeredAt := at.Add(-time.Hour)
+	require.NoError(t, repo.MarkTriggered(ctx, reminder.ID, next, true, triggeredAt))
+
+	found, err := repo.FindByID(ctx, reminder.ID)
+	require.NoError(t, err)
+	assert.WithinDuration(t, next, found.NextTriggerAt, time.Second)
+	require.NotNil(t, found.LastTriggeredAt)
+	assert.WithinDuration(t, triggeredAt, *found.LastTriggeredAt, time.Second)
+	assert.True(t, found.IsEnabled)
+	assert.Equal(t, 3, found.TriggerCount)
+	assert.Equal(t, 0, found.SnoozeCount)
+
+	require.NoError(t, repo.MarkTriggered(ctx, reminder.ID, next, false, at))
+	found, err = repo.FindByID(ctx, reminder.ID)
+	require.NoError(t, err)
+	assert.False(t, found.IsEnabled)
+	assert.Equal(t, 4, found.TriggerCount)
+
+	assert.ErrorIs(t, repo.MarkTriggered(ctx, 999, next, true, at), domain.ErrReminderNotFound)
+}
+
+func TestReminderRepository_MarkTriggeredBatch(t *testing.T) {
+	db := setupReminderTestDB(t)
+	repo := NewReminderRepository(db)
+	ctx := context.Background()
+
+	at := time.Now().UTC()
+	daily := &domain.Reminder{NoteID: 1, UserID: 1, Title: "Daily", ScheduledAt: at, RepeatType: domain.RepeatTypeDaily, NextTriggerAt: at, IsEnabled: true, SnoozeCount: 2}
+	once := &domain.Reminder{NoteID: 1, UserID: 1, Title: "Once", ScheduledAt: at, RepeatType: domain.RepeatTypeOnce, NextTriggerAt: at, IsEnabled: true, TriggerCount: 1}
+	require.NoError(t, repo.Create(ctx, daily))
+	require.NoError(t, repo.Create(ctx, once))
+
+	daily.NextTriggerAt = at.Add(24 * time.Hour)
+	once.IsEnabled = false
+	deleted := &domain.Reminder{ID: 999, NextTriggerAt: at, IsEnabled: true}
+	triggeredAt := at.Add(-time.Minute)
+
+	missing, err := repo.MarkTriggeredBatch(ctx, []*domain.Reminder{daily, deleted, once}, triggeredAt)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{999}, missing)
+
+	found, err := repo.FindByID(ctx, daily.ID)
+	require.NoError(t, err)
+	assert.WithinDuration(t, daily.NextTriggerAt, found.NextTriggerAt, time.Second)
+	require.NotNil(t, found.LastTriggeredAt)
+	assert.WithinDuration(t, triggeredAt, *found.LastTriggeredAt, time.Second)
+	assert.True(t, found.IsEnabled)
+	assert.Equal(t, 1, found.TriggerCount)
+	assert.Equal(t, 0, found.SnoozeCount)
+
+	// Marked despite the missing reminder ahead of it in the batch
+	found, err = repo.FindByID(ctx, once.ID)
+	require.NoError(t, err)
+	require.NotNil(t, found.LastTriggeredAt)
+	assert.WithinDuration(t, triggeredAt, *found.LastTriggeredAt, time.Second)
+	assert.False(t, found.IsEnabled)
+	assert.Equal(t, 2, found.TriggerCount)
+
+	missing, err = repo.MarkTriggeredBatch(ctx, nil, triggeredAt)
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+}
+
+func TestReminderRepository_FindDueReminders_SkipsZeroTrigger(t *testing.T) {
+	db := setupReminderTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.User{}))
+	repo := NewReminderRepository(db)
+
+	at := time.Now().Add(-time.Minute).UTC()
+	reminders := []models.Reminder{
+		{NoteID: 1, UserID: 1, Title: "Due", RepeatType: "once", ScheduledAt: at, NextTriggerAt: at, IsEnabled: true},
+		{NoteID: 1, UserID: 1, Title: "Malformed", RepeatType: "once", ScheduledAt: at, IsEnabled: true},
+	}
+	require.NoError(t, db.Create(&reminders).Error)
+
+	due, err := repo.FindDueReminders(context.Background(), time.Now(), 10)
+
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, "Due", due[0].Title)
+}
+
+func TestReminderRepository_Relative(t *testing.T) {
+	db := setupReminderTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.User{}))
+	repo := NewReminderRepository(db)
+	ctx := context.Background()
+
+	note := models.Note{UserID: 1, Title: "Launch", Properties: models.PropertiesJSON{"due": "2025-03-20"}}
+	require.NoError(t, db.Create(&note).Error)
+
+	at := time.Now().Add(-time.Minute).UTC()
+	reminder := &domain.Reminder{NoteID: note.ID, UserID: 1, Title: "Day before", ScheduledAt: at, RepeatType: domain.RepeatTypeOnce, NextTriggerAt: at, IsEnabled: true,
+		Relative: &domain.RelativeSchedule{PropertyID: "due", OffsetMinutes: -24 * 60}}
+	require.NoError(t, repo.Create(ctx, reminder))
+
+	found, err := repo.FindByID(ctx, reminder.ID)
+	require.NoError(t, err)
+	assert.Equal(t, reminder.Relative, found.Relative)
+
+	// Due reminders come with the properties they resolve against
+	due, err := repo.FindDueReminders(ctx, time.Now(), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	require.NotNil(t, due[0].Note)
+	assert.Equal(t, "2025-03-20", due[0].Note.Properties["due"])
+}
+
+func TestReminderRepository_FindDueReminders_TrashedNote(t *testing.T) {
+	db := setupReminderTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.User{}))
+	repo := NewReminderRepository(db)
+	ctx := context.Background()
+
+	trashed := models.Note{UserID: 1, Title: "Trashed"}
+	gone := models.Note{UserID: 1, Title: "Gone"}
+	require.NoError(t, db.Create(&trashed).Error)
+	require.NoError(t, db.Create(&gone).Error)
+	require.NoError(t, NewNoteRepository(db).Delete(ctx, trashed.ID))
+	require.NoError(t, db.Unscoped().Delete(&models.Note{}, gone.ID).Error)
+
+	at := time.Now().Add(-time.Minute).UTC()
+	reminders := []models.Reminder{
+		{NoteID: trashed.ID, UserID: 1, Title: "On trashed note", RepeatType: "once", ScheduledAt: at, NextTriggerAt: at, IsEnabled: true},
+		{NoteID: gone.ID, UserID: 1, Title: "On missing note", RepeatType: "once", ScheduledAt: at, NextTriggerAt: at.Add(time.Second), IsEnabled: true},
+	}
+	require.NoError(t, db.Create(&reminders).Error)
+
+	due, err := repo.FindDueReminders(ctx, time.Now(), 10)
+
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+	require.NotNil(t, due[0].Note, "trashed notes are still loaded")
+	assert.True(t, due[0].Note.IsDeleted)
+	assert.False(t, due[0].IsNoteActive())
+	assert.Nil(t, due[1].Note)
+	assert.False(t, due[1].IsNoteActive())
+}
@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/yourusername/notinoteapp/internal/adapters/secondary/database/postgres/models"
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+	"gorm.io/gorm"
+)
+
+// SharedLinkRepository implements the shared link repository interface using PostgreSQL
+type SharedLinkRepository struct {
+	db *gorm.DB
+}
+
+// NewSharedLinkRepository creates a new shared link repository
+func NewSharedLinkRepository(db *gorm.DB) *SharedLinkRepository {
+	return &SharedLinkRepository{db: db}
+}
+
+// Create creates a new shared link
+func (r *SharedLinkRepository) Create(ctx context.Context, link *domain.SharedLink) error {
+	dbLink := &models.SharedLink{}
+	dbLink.FromDomain(link)
+
+	if err := r.db.WithContext(ctx).Create(dbLink).Error; err != nil {
+		return err
+	}
+
+	link.ID = dbLink.ID
+	link.CreatedAt = dbLink.CreatedAt
+
+	return nil
+}
+
+// FindByID finds a shared link by ID
+func (r *SharedLinkRepository) FindByID(ctx context.Context, id int64) (*domain.SharedLink, error) {
+	var dbLink models.SharedLink
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&dbLink).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrSharedLinkNotFound
+		}
+		return nil, err
+	}
+
+	return dbLink.ToDomain(), nil
+}
+
+// FindByToken finds a shared link by its token
+func (r *SharedLinkRepository) FindByToken(ctx context.Context, token string) (*domain.SharedLink, error) {
+	var dbLink models.SharedLink
+	if err := r.db.WithContext(ctx).Where("token = ?", token).First(&dbLink).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrSharedLinkNotFound
+		}
+		return nil, err
+	}
+
+	return dbLink.ToDomain(), nil
+}
+
+// FindByNoteID finds all shared links for a note, including revoked ones
+func (r *SharedLinkRepository) FindByNoteID(ctx context.Context, noteID int64) ([]*domain.SharedLink, error) {
+	var dbLinks []models.SharedLink
+	if err := r.db.WithContext(ctx).
+		Where("note_id = ?", noteID).
+		Order("created_at DESC").
+		Find(&dbLinks).Error; err != nil {
+		return nil, err
+	}
+
+	links := make([]*domain.SharedLink, len(dbLinks))
+	for i, dbLink := range dbLinks {
+		links[i] = dbLink.ToDomain()
+	}
+
+	return links, nil
+}
+
+// Revoke marks a shared link as revoked
+func (r *SharedLinkRepository) Revoke(ctx context.Context, id int64) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.SharedLink{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrSharedLinkNotFound
+	}
+
+	return nil
+}
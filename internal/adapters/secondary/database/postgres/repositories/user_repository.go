@@ -82,9 +82,12 @@ func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 	dbUser := &models.User{}
 	dbUser.FromDomain(user)
 
+	// Select every column so cleared fields (empty avatar, unpinned name) are written too
 	result := r.db.WithContext(ctx).
 		Model(&models.User{}).
 		Where("id = ?", user.ID).
+		Select("*").
+		Omit("id", "created_at", "deleted_at").
 		Updates(dbUser)
 
 	if result.Error != nil {
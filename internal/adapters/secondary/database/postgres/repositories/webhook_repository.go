@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/yourusername/notinoteapp/internal/adapters/secondary/database/postgres/models"
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+	"gorm.io/gorm"
+)
+
+// WebhookRepository implements the webhook repository interface using PostgreSQL
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// Create creates a new webhook
+func (r *WebhookRepository) Create(ctx context.Context, webhook *domain.Webhook) error {
+	dbWebhook := &models.Webhook{}
+	dbWebhook.FromDomain(webhook)
+
+	if err := r.db.WithContext(ctx).Create(dbWebhook).Error; err != nil {
+		return err
+	}
+
+	webhook.ID = dbWebhook.ID
+	webhook.CreatedAt = dbWebhook.CreatedAt
+	webhook.UpdatedAt = dbWebhook.UpdatedAt
+
+	return nil
+}
+
+// FindByID finds a webhook by ID
+func (r *WebhookRepository) FindByID(ctx context.Context, id int64) (*domain.Webhook, error) {
+	var dbWebhook models.Webhook
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&dbWebhook).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	return dbWebhook.ToDomain(), nil
+}
+
+// FindActiveByUserID finds all active webhooks for a user
+func (r *WebhookRepository) FindActiveByUserID(ctx context.Context, userID int64) ([]*domain.Webhook, error) {
+	var dbWebhooks []models.Webhook
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND is_active = ?", userID, true).
+		Order("created_at ASC").
+		Find(&dbWebhooks).Error; err != nil {
+		return nil, err
+	}
+
+	webhooks := make([]*domain.Webhook, len(dbWebhooks))
+	for i, dbWebhook := range dbWebhooks {
+		webhooks[i] = dbWebhook.ToDomain()
+	}
+
+	return webhooks, nil
+}
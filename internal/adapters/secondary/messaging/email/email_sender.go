@@ -0,0 +1,256 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/yourusername/notinoteapp/pkg/i18n"
+)
+
+// TLS modes supported by the email sender
+const (
+	TLSModeStartTLS = "starttls"
+	TLSModeTLS      = "tls"
+	TLSModeNone     = "none"
+)
+
+// Config holds SMTP connection settings
+type Config struct {
+	Host        string
+	Port        int
+	Username    string
+	Password    string
+	From        string
+	TLSMode     string
+	DryRun      bool
+	LinkBaseURL string
+}
+
+// maxDryRunMessages is how many dry-run messages the sender keeps; older ones
+// are dropped so a deployment left in dry-run mode doesn't grow without bound
+const maxDryRunMessages = 100
+
+// Message is an email rendered by the sender
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// bodyTemplate's fixed text comes from the i18n catalog in the language given
+// by the "language" data key
+var bodyTemplate = template.Must(template.New("reminder").Parse(`{{.Body}}
+{{if .Link}}
+{{.OpenNote}}: {{.Link}}
+{{end}}
+--
+{{.Footer}}
+`))
+
+// EmailSender implements the NotificationSender interface over SMTP.
+// The device token passed to it is the recipient's email address.
+type EmailSender struct {
+	config Config
+	logger *logrus.Logger
+
+	mu   sync.Mutex
+	sent []Message
+}
+
+// NewEmailSender creates a new SMTP email sender
+func NewEmailSender(cfg Config, logger *logrus.Logger) (*EmailSender, error) {
+	if cfg.TLSMode == "" {
+		cfg.TLSMode = TLSModeStartTLS
+	}
+	switch cfg.TLSMode {
+	case TLSModeStartTLS, TLSModeTLS, TLSModeNone:
+	default:
+		return nil, fmt.Errorf("invalid SMTP TLS mode: %s", cfg.TLSMode)
+	}
+	if !cfg.DryRun && cfg.Host == "" {
+		return nil, fmt.Errorf("SMTP host is required")
+	}
+	if _, err := mail.ParseAddress(cfg.From); err != nil {
+		return nil, fmt.Errorf("invalid SMTP from address: %w", err)
+	}
+
+	return &EmailSender{
+		config: cfg,
+		logger: logger,
+	}, nil
+}
+
+// SendPushNotification sends an email to the given address
+func (s *EmailSender) SendPushNotification(ctx context.Context, to, title, body string, data map[string]string) error {
+	if _, err := mail.ParseAddress(to); err != nil {
+		return fmt.Errorf("invalid recipient address: %w", err)
+	}
+
+	msg, err := s.render(to, title, body, data)
+	if err != nil {
+		return err
+	}
+
+	if s.config.DryRun {
+		s.mu.Lock()
+		if len(s.sent) >= maxDryRunMessages {
+			s.sent = append(s.sent[:0], s.sent[len(s.sent)-maxDryRunMessages+1:]...)
+		}
+		s.sent = append(s.sent, msg)
+		s.mu.Unlock()
+
+		s.logger.WithFields(logrus.Fields{
+			"to":      to,
+			"subject": title,
+		}).Info("Email dry run - message not sent")
+		return nil
+	}
+
+	if err := s.send(ctx, to, s.buildMIME(msg)); err != nil {
+		s.logger.WithError(err).WithField("to", to).Error("Failed to send email")
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	s.logger.WithField("to", to).Debug("Email sent successfully")
+	return nil
+}
+
+// SendToMultipleDevices sends the same email to multiple addresses
+func (s *EmailSender) SendToMultipleDevices(ctx context.Context, addresses []string, title, body string, data map[string]string) error {
+	var lastErr error
+	for _, to := range addresses {
+		if err := s.SendPushNotification(ctx, to, title, body, data); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// SentMessages returns the most recent messages captured in dry-run mode
+func (s *EmailSender) SentMessages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	messages := make([]Message, len(s.sent))
+	copy(messages, s.sent)
+	return messages
+}
+
+func (s *EmailSender) render(to, title, body string, data map[string]string) (Message, error) {
+	link := ""
+	if clickURL := data["click_url"]; clickURL != "" {
+		link = strings.TrimRight(s.config.LinkBaseURL, "/") + clickURL
+	}
+
+	var buf bytes.Buffer
+	language := data["language"]
+	if err := bodyTemplate.Execute(&buf, struct {
+		Body     string
+		Link     string
+		OpenNote string
+		Footer   string
+	}{
+		Body:     body,
+		Link:     link,
+		OpenNote: i18n.T(language, i18n.EmailOpenNote),
+		Footer:   i18n.T(language, i18n.EmailFooter),
+	}); err != nil {
+		return Message{}, fmt.Errorf("failed to render email: %w", err)
+	}
+
+	return Message{To: to, Subject: title, Body: buf.String()}, nil
+}
+
+func (s *EmailSender) buildMIME(msg Message) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", s.config.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return buf.Bytes()
+}
+
+func (s *EmailSender) send(ctx context.Context, to string, msg []byte) error {
+	addr := net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Port))
+	tlsConfig := &tls.Config{ServerName: s.config.Host, MinVersion: tls.VersionTLS12}
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+
+	var conn net.Conn
+	var err error
+	if s.config.TLSMode == TLSModeTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.config.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to create SMTP client: %w", err)
+	}
+	defer client.Close()
+
+	if s.config.TLSMode == TLSModeStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("SMTP server does not support STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+
+	if s.config.Username != "" {
+		auth := smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+
+	from, err := mail.ParseAddress(s.config.From)
+	if err != nil {
+		return fmt.Errorf("invalid from address: %w", err)
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
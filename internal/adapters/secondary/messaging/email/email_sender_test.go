@@ -0,0 +1,93 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDryRunSender(t *testing.T) *EmailSender {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	sender, err := NewEmailSender(Config{
+		From:        "NotiNote <no-reply@example.com>",
+		DryRun:      true,
+		LinkBaseURL: "https://app.example.com/",
+	}, logger)
+	require.NoError(t, err)
+	return sender
+}
+
+func TestEmailSender_DryRunRendersReminder(t *testing.T) {
+	sender := newDryRunSender(t)
+
+	err := sender.SendPushNotification(context.Background(), "user@example.com", "Pay rent", "Rent is due today", map[string]string{
+		"click_url": "/notes?id=42",
+	})
+	require.NoError(t, err)
+
+	messages := sender.SentMessages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "user@example.com", messages[0].To)
+	assert.Equal(t, "Pay rent", messages[0].Subject)
+	assert.Contains(t, messages[0].Body, "Rent is due today")
+	assert.Contains(t, messages[0].Body, "Open note: https://app.example.com/notes?id=42")
+}
+
+func TestEmailSender_DryRunRendersLanguage(t *testing.T) {
+	sender := newDryRunSender(t)
+
+	err := sender.SendPushNotification(context.Background(), "user@example.com", "จ่ายค่าเช่า", "Rent is due today", map[string]string{
+		"click_url": "/notes?id=42",
+		"language":  "th",
+	})
+	require.NoError(t, err)
+
+	messages := sender.SentMessages()
+	require.Len(t, messages, 1)
+	assert.Contains(t, messages[0].Body, "Rent is due today")
+	assert.Contains(t, messages[0].Body, "เปิดโน้ต: https://app.example.com/notes?id=42")
+	assert.NotContains(t, messages[0].Body, "Open note")
+}
+
+func TestEmailSender_DryRunKeepsRecentMessages(t *testing.T) {
+	sender := newDryRunSender(t)
+
+	for i := 0; i < maxDryRunMessages+5; i++ {
+		require.NoError(t, sender.SendPushNotification(context.Background(), "user@example.com", fmt.Sprintf("Reminder %d", i), "", nil))
+	}
+
+	sent := sender.SentMessages()
+	require.Len(t, sent, maxDryRunMessages)
+	assert.Equal(t, "Reminder 5", sent[0].Subject)
+	assert.Equal(t, fmt.Sprintf("Reminder %d", maxDryRunMessages+4), sent[len(sent)-1].Subject)
+}
+
+func TestEmailSender_InvalidRecipient(t *testing.T) {
+	sender := newDryRunSender(t)
+
+	err := sender.SendPushNotification(context.Background(), "not-an-email", "Title", "Body", nil)
+
+	assert.Error(t, err)
+	assert.Empty(t, sender.SentMessages())
+}
+
+func TestNewEmailSender_Validation(t *testing.T) {
+	logger := logrus.New()
+
+	_, err := NewEmailSender(Config{From: "no-reply@example.com", TLSMode: "ssl3", DryRun: true}, logger)
+	assert.Error(t, err)
+
+	_, err = NewEmailSender(Config{From: "no-reply@example.com"}, logger)
+	assert.Error(t, err, "host is required unless dry run")
+
+	_, err = NewEmailSender(Config{Host: "smtp.example.com", Port: 587, From: "no-reply@example.com"}, logger)
+	assert.NoError(t, err)
+}
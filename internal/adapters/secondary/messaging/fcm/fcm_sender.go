@@ -3,10 +3,12 @@ package fcm
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	firebase "firebase.google.com/go/v4"
 	"firebase.google.com/go/v4/messaging"
 	"github.com/sirupsen/logrus"
+	"github.com/yourusername/notinoteapp/internal/core/domain"
 	"google.golang.org/api/option"
 )
 
@@ -16,11 +18,20 @@ type FCMSender struct {
 	logger *logrus.Logger
 }
 
-// NewFCMSender creates a new FCM sender
+// NewFCMSender creates a new FCM sender from a service account credentials file
 func NewFCMSender(credentialsFile string, logger *logrus.Logger) (*FCMSender, error) {
+	return newFCMSender(option.WithCredentialsFile(credentialsFile), logger)
+}
+
+// NewFCMSenderFromJSON creates a new FCM sender from service account JSON,
+// e.g. read from an environment variable or a secret manager
+func NewFCMSenderFromJSON(credentialsJSON []byte, logger *logrus.Logger) (*FCMSender, error) {
+	return newFCMSender(option.WithCredentialsJSON(credentialsJSON), logger)
+}
+
+func newFCMSender(opt option.ClientOption, logger *logrus.Logger) (*FCMSender, error) {
 	ctx := context.Background()
 
-	opt := option.WithCredentialsFile(credentialsFile)
 	app, err := firebase.NewApp(ctx, nil, opt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize firebase app: %w", err)
@@ -65,7 +76,7 @@ func (s *FCMSender) SendPushNotification(ctx context.Context, deviceToken, title
 			Notification: &messaging.AndroidNotification{
 				Title:       title,
 				Body:        body,
-				Sound:       "default",
+				Sound:       notificationSound(data),
 				ChannelID:   "note_reminders",
 				ClickAction: "OPEN_NOTE",
 			},
@@ -78,8 +89,8 @@ func (s *FCMSender) SendPushNotification(ctx context.Context, deviceToken, title
 						Title: title,
 						Body:  body,
 					},
-					Sound: "default",
-					Badge: func() *int { i := 1; return &i }(),
+					Sound: notificationSound(data),
+					Badge: notificationBadge(data),
 				},
 			},
 		},
@@ -122,6 +133,9 @@ func (s *FCMSender) SendToMultipleDevices(ctx context.Context, deviceTokens []st
 				Body:  body,
 				Icon:  "/icons/notification-icon.png",
 			},
+			FCMOptions: &messaging.WebpushFCMOptions{
+				Link: data["click_url"],
+			},
 		},
 		// Android configuration
 		Android: &messaging.AndroidConfig{
@@ -129,7 +143,7 @@ func (s *FCMSender) SendToMultipleDevices(ctx context.Context, deviceTokens []st
 			Notification: &messaging.AndroidNotification{
 				Title:     title,
 				Body:      body,
-				Sound:     "default",
+				Sound:     notificationSound(data),
 				ChannelID: "note_reminders",
 			},
 		},
@@ -141,7 +155,8 @@ func (s *FCMSender) SendToMultipleDevices(ctx context.Context, deviceTokens []st
 						Title: title,
 						Body:  body,
 					},
-					Sound: "default",
+					Sound: notificationSound(data),
+					Badge: notificationBadge(data),
 				},
 			},
 		},
@@ -210,6 +225,28 @@ func (s *FCMSender) SendBatchWithResponse(ctx context.Context, deviceTokens []st
 	}, nil
 }
 
+// notificationSound returns the sound named in the data payload, "default"
+// when none is set, or no sound for "none"
+func notificationSound(data map[string]string) string {
+	switch sound := data["sound"]; sound {
+	case "":
+		return "default"
+	case domain.SoundNone:
+		return ""
+	default:
+		return sound
+	}
+}
+
+// notificationBadge returns the badge count in the data payload, 1 when none is set
+func notificationBadge(data map[string]string) *int {
+	badge, err := strconv.Atoi(data["badge"])
+	if err != nil || badge < 0 {
+		badge = 1
+	}
+	return &badge
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
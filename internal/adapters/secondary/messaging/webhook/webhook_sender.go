@@ -0,0 +1,245 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+	"github.com/yourusername/notinoteapp/internal/core/ports"
+)
+
+// Request headers sent with every webhook delivery
+const (
+	SignatureHeader = "X-NotiNote-Signature"
+	EventHeader     = "X-NotiNote-Event"
+	TimestampHeader = "X-NotiNote-Timestamp"
+)
+
+// Config holds webhook delivery settings
+type Config struct {
+	MaxRetries   int
+	RetryBackoff time.Duration
+	Timeout      time.Duration
+}
+
+// Payload is the JSON body POSTed to webhooks
+type Payload struct {
+	Event  string            `json:"event"`
+	Title  string            `json:"title"`
+	Body   string            `json:"body"`
+	Data   map[string]string `json:"data,omitempty"`
+	SentAt time.Time         `json:"sent_at"`
+}
+
+// WebhookSender implements the NotificationSender interface by POSTing signed JSON.
+// The device token passed to it is the webhook ID; URL and secret are loaded from the repository.
+// Server errors, 429s and transport failures are retried with a linear backoff; other
+// responses fail immediately. Callers on the scheduler path should not wait on it.
+type WebhookSender struct {
+	webhookRepo ports.WebhookRepository
+	client      *http.Client
+	config      Config
+	logger      *logrus.Logger
+}
+
+// NewWebhookSender creates a new webhook sender
+func NewWebhookSender(webhookRepo ports.WebhookRepository, cfg Config, logger *logrus.Logger) *WebhookSender {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+
+	return &WebhookSender{
+		webhookRepo: webhookRepo,
+		client:      newClient(cfg.Timeout, refuseInternalAddress),
+		config:      cfg,
+		logger:      logger,
+	}
+}
+
+// newClient builds the HTTP client used for deliveries. control is run for every
+// outgoing connection after DNS resolution; redirects are never followed.
+func newClient(timeout time.Duration, control func(network, address string, c syscall.RawConn) error) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: control,
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// errAddressNotAllowed is returned when the resolved webhook address is internal
+var errAddressNotAllowed = errors.New("webhook address is not allowed")
+
+// statusError reports a non-2xx response from the receiver
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("webhook responded with status %d", e.code)
+}
+
+// retryable reports whether a failed delivery may succeed if attempted again
+func retryable(err error) bool {
+	if errors.Is(err, errAddressNotAllowed) || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var status *statusError
+	if errors.As(err, &status) {
+		return status.code >= 500 || status.code == http.StatusTooManyRequests
+	}
+
+	// Anything else failed in transport before a response came back
+	return true
+}
+
+// refuseInternalAddress rejects connections to loopback, private and link-local
+// addresses (including cloud metadata endpoints) so webhooks cannot reach internal services
+func refuseInternalAddress(network, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || domain.IsInternalIP(ip) {
+		return fmt.Errorf("%w: %s", errAddressNotAllowed, host)
+	}
+
+	return nil
+}
+
+// Sign returns the hex-encoded HMAC-SHA256 of "timestamp.body" using secret, as sent in SignatureHeader.
+// Receivers should recompute it with the TimestampHeader value and reject stale timestamps.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// SendPushNotification delivers the notification to the webhook with the given ID
+func (s *WebhookSender) SendPushNotification(ctx context.Context, webhookID, title, body string, data map[string]string) error {
+	id, err := strconv.ParseInt(webhookID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid webhook id %q: %w", webhookID, err)
+	}
+
+	webhook, err := s.webhookRepo.FindByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to load webhook: %w", err)
+	}
+
+	event := data["type"]
+	if event == "" {
+		event = "notification"
+	}
+
+	sentAt := time.Now().UTC()
+	payload, err := json.Marshal(Payload{
+		Event:  event,
+		Title:  title,
+		Body:   body,
+		Data:   data,
+		SentAt: sentAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	// Every attempt carries the same timestamp and signature so receivers can deduplicate
+	timestamp := strconv.FormatInt(sentAt.Unix(), 10)
+	signature := Sign(webhook.Secret, timestamp, payload)
+
+	var lastErr error
+	attempts := 0
+	for attempt := 0; attempt <= s.config.MaxRetries; attempt++ {
+		if attempt > 0 {
+			backoff := s.config.RetryBackoff * time.Duration(attempt)
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("webhook delivery failed after %d attempts: %w", attempts, ctx.Err())
+			case <-time.After(backoff):
+			}
+		}
+
+		attempts++
+		lastErr = s.post(ctx, webhook.URL, event, timestamp, signature, payload)
+		if lastErr == nil {
+			return nil
+		}
+
+		s.logger.WithError(lastErr).WithFields(logrus.Fields{
+			"webhook_id": webhook.ID,
+			"attempt":    attempts,
+		}).Warn("Webhook delivery failed")
+
+		if !retryable(lastErr) {
+			break
+		}
+	}
+
+	return fmt.Errorf("webhook delivery failed after %d attempts: %w", attempts, lastErr)
+}
+
+// SendToMultipleDevices delivers the notification to multiple webhooks
+func (s *WebhookSender) SendToMultipleDevices(ctx context.Context, webhookIDs []string, title, body string, data map[string]string) error {
+	var lastErr error
+	for _, id := range webhookIDs {
+		if err := s.SendPushNotification(ctx, id, title, body, data); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+func (s *WebhookSender) post(ctx context.Context, url, event, timestamp, signature string, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "NotiNote-Webhook/1.0")
+	req.Header.Set(SignatureHeader, signature)
+	req.Header.Set(EventHeader, event)
+	req.Header.Set(TimestampHeader, timestamp)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &statusError{code: resp.StatusCode}
+	}
+
+	return nil
+}
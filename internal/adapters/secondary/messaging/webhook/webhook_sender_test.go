@@ -0,0 +1,215 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+)
+
+type stubWebhookRepository struct {
+	webhook *domain.Webhook
+}
+
+func (r *stubWebhookRepository) Create(ctx context.Context, webhook *domain.Webhook) error {
+	return nil
+}
+
+func (r *stubWebhookRepository) FindByID(ctx context.Context, id int64) (*domain.Webhook, error) {
+	if r.webhook == nil || r.webhook.ID != id {
+		return nil, domain.ErrWebhookNotFound
+	}
+	return r.webhook, nil
+}
+
+func (r *stubWebhookRepository) FindActiveByUserID(ctx context.Context, userID int64) ([]*domain.Webhook, error) {
+	return []*domain.Webhook{r.webhook}, nil
+}
+
+// newTestSender returns a sender whose client may reach the loopback test server
+func newTestSender(url string, cfg Config) *WebhookSender {
+	sender := newGuardedTestSender(url, cfg)
+	sender.client = newClient(time.Second, nil)
+	return sender
+}
+
+func newGuardedTestSender(url string, cfg Config) *WebhookSender {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	repo := &stubWebhookRepository{webhook: &domain.Webhook{ID: 1, UserID: 1, URL: url, Secret: "s3cret", IsActive: true}}
+	return NewWebhookSender(repo, cfg, logger)
+}
+
+func TestWebhookSender_SignsBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		timestamp := r.Header.Get(TimestampHeader)
+		sentAt, err := strconv.ParseInt(timestamp, 10, 64)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), time.Unix(sentAt, 0), time.Minute)
+
+		assert.Equal(t, Sign("s3cret", timestamp, body), r.Header.Get(SignatureHeader))
+		assert.NotEqual(t, Sign("s3cret", "0", body), r.Header.Get(SignatureHeader))
+		assert.Equal(t, "reminder", r.Header.Get(EventHeader))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var payload Payload
+		require.NoError(t, json.Unmarshal(body, &payload))
+		assert.Equal(t, "Pay rent", payload.Title)
+		assert.Equal(t, "42", payload.Data["note_id"])
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sender := newTestSender(server.URL, Config{})
+	err := sender.SendPushNotification(context.Background(), "1", "Pay rent", "Rent is due", map[string]string{
+		"type":    "reminder",
+		"note_id": "42",
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestWebhookSender_RetriesServerErrorsWithSameSignature(t *testing.T) {
+	var calls int32
+	var delivered int32
+	var mu sync.Mutex
+	var signatures, timestamps []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		signatures = append(signatures, r.Header.Get(SignatureHeader))
+		timestamps = append(timestamps, r.Header.Get(TimestampHeader))
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		atomic.AddInt32(&delivered, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sender := newTestSender(server.URL, Config{MaxRetries: 3, RetryBackoff: time.Millisecond})
+	err := sender.SendPushNotification(context.Background(), "1", "Title", "Body", nil)
+
+	require.NoError(t, err)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&delivered))
+	require.Len(t, signatures, 2)
+	assert.Equal(t, signatures[0], signatures[1])
+	assert.Equal(t, timestamps[0], timestamps[1])
+}
+
+func TestWebhookSender_RetryPolicy(t *testing.T) {
+	tests := []struct {
+		status    int
+		wantCalls int32
+	}{
+		{http.StatusInternalServerError, 3},
+		{http.StatusServiceUnavailable, 3},
+		{http.StatusTooManyRequests, 3},
+		{http.StatusBadRequest, 1},
+		{http.StatusGone, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			sender := newTestSender(server.URL, Config{MaxRetries: 2, RetryBackoff: time.Millisecond})
+			err := sender.SendPushNotification(context.Background(), "1", "Title", "Body", nil)
+
+			assert.Error(t, err)
+			assert.Equal(t, tt.wantCalls, atomic.LoadInt32(&calls))
+		})
+	}
+}
+
+func TestWebhookSender_StopsRetryingWhenCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	sender := newTestSender(server.URL, Config{MaxRetries: 5, RetryBackoff: time.Hour})
+	start := time.Now()
+	err := sender.SendPushNotification(ctx, "1", "Title", "Body", nil)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestWebhookSender_DoesNotFollowRedirects(t *testing.T) {
+	var redirected int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&redirected, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	sender := newTestSender(server.URL, Config{})
+	err := sender.SendPushNotification(context.Background(), "1", "Title", "Body", nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&redirected))
+}
+
+func TestWebhookSender_RefusesInternalAddresses(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sender := newGuardedTestSender(server.URL, Config{MaxRetries: 2, RetryBackoff: time.Millisecond})
+	err := sender.SendPushNotification(context.Background(), "1", "Title", "Body", nil)
+
+	assert.ErrorContains(t, err, "not allowed")
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+}
+
+func TestRefuseInternalAddress(t *testing.T) {
+	for _, address := range []string{"127.0.0.1:80", "10.1.2.3:443", "192.168.0.10:80", "169.254.169.254:80", "[::1]:443", "[fe80::1]:80", "0.0.0.0:80"} {
+		assert.Error(t, refuseInternalAddress("tcp", address, nil), address)
+	}
+	assert.NoError(t, refuseInternalAddress("tcp", "93.184.216.34:443", nil))
+}
+
+func TestWebhookSender_UnknownWebhook(t *testing.T) {
+	sender := newTestSender("http://127.0.0.1:0", Config{})
+
+	err := sender.SendPushNotification(context.Background(), "99", "Title", "Body", nil)
+
+	assert.ErrorIs(t, err, domain.ErrWebhookNotFound)
+}
@@ -53,22 +53,24 @@ func NewFacebookProvider(appID, appSecret, redirectURL string, scopes []string)
 	}
 }
 
-// GetAuthURL generates the OAuth authorization URL with state
-func (f *FacebookProvider) GetAuthURL(state string) string {
+// GetAuthURL generates the OAuth authorization URL with state and an S256 code challenge
+func (f *FacebookProvider) GetAuthURL(state, codeChallenge string) string {
 	params := url.Values{}
 	params.Set("client_id", f.appID)
 	params.Set("redirect_uri", f.redirectURL)
 	params.Set("scope", strings.Join(f.scopes, ","))
 	params.Set("state", state)
 	params.Set("response_type", "code")
+	params.Set("code_challenge", codeChallenge)
+	params.Set("code_challenge_method", "S256")
 
 	return "https://www.facebook.com/v18.0/dialog/oauth?" + params.Encode()
 }
 
 // ExchangeCode exchanges authorization code for access token and retrieves user info
-func (f *FacebookProvider) ExchangeCode(ctx context.Context, code string) (*domain.OAuthUserInfo, error) {
+func (f *FacebookProvider) ExchangeCode(ctx context.Context, code, codeVerifier string) (*domain.OAuthUserInfo, error) {
 	// Exchange code for access token
-	token, err := f.getAccessToken(ctx, code)
+	token, err := f.getAccessToken(ctx, code, codeVerifier)
 	if err != nil {
 		return nil, err
 	}
@@ -89,12 +91,13 @@ func (f *FacebookProvider) ExchangeCode(ctx context.Context, code string) (*doma
 }
 
 // getAccessToken exchanges code for access token
-func (f *FacebookProvider) getAccessToken(ctx context.Context, code string) (*FacebookTokenResponse, error) {
+func (f *FacebookProvider) getAccessToken(ctx context.Context, code, codeVerifier string) (*FacebookTokenResponse, error) {
 	params := url.Values{}
 	params.Set("client_id", f.appID)
 	params.Set("client_secret", f.appSecret)
 	params.Set("redirect_uri", f.redirectURL)
 	params.Set("code", code)
+	params.Set("code_verifier", codeVerifier)
 
 	tokenURL := "https://graph.facebook.com/v18.0/oauth/access_token?" + params.Encode()
 
@@ -60,7 +60,7 @@ func TestFacebookProvider_GetAuthURL(t *testing.T) {
 	provider := NewFacebookProvider("test-app-id", "test-secret", "http://localhost/callback", nil)
 
 	state := "random-state-string"
-	authURL := provider.GetAuthURL(state)
+	authURL := provider.GetAuthURL(state, "test-challenge")
 
 	assert.NotEmpty(t, authURL)
 	assert.Contains(t, authURL, "facebook.com/v18.0/dialog/oauth")
@@ -69,6 +69,8 @@ func TestFacebookProvider_GetAuthURL(t *testing.T) {
 	assert.Contains(t, authURL, "redirect_uri=")
 	assert.Contains(t, authURL, "scope=")
 	assert.Contains(t, authURL, "response_type=code")
+	assert.Contains(t, authURL, "code_challenge=test-challenge")
+	assert.Contains(t, authURL, "code_challenge_method=S256")
 }
 
 func TestFacebookProvider_GetProviderName(t *testing.T) {
@@ -168,7 +170,7 @@ func TestFacebookProvider_ExchangeCode_InvalidCode(t *testing.T) {
 	provider := NewFacebookProvider("test-app-id", "test-secret", "http://localhost/callback", nil)
 
 	ctx := context.Background()
-	userInfo, err := provider.ExchangeCode(ctx, "invalid-code")
+	userInfo, err := provider.ExchangeCode(ctx, "invalid-code", "test-verifier")
 
 	assert.Error(t, err)
 	assert.Nil(t, userInfo)
@@ -304,7 +306,7 @@ func TestFacebookProvider_ScopeFormat(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			provider := NewFacebookProvider("test-app-id", "test-secret", "http://localhost/callback", tt.scopes)
-			authURL := provider.GetAuthURL("test-state")
+			authURL := provider.GetAuthURL("test-state", "test-challenge")
 
 			// Verify scope parameter is correctly formatted
 			assert.Contains(t, authURL, "scope="+tt.expectedString)
@@ -315,7 +317,7 @@ func TestFacebookProvider_ScopeFormat(t *testing.T) {
 func TestFacebookProvider_URLEncoding(t *testing.T) {
 	provider := NewFacebookProvider("test-app-id", "test-secret", "http://localhost:8080/auth/callback", nil)
 
-	authURL := provider.GetAuthURL("test-state-123")
+	authURL := provider.GetAuthURL("test-state-123", "test-challenge")
 
 	// Verify URL encoding for redirect_uri
 	assert.Contains(t, authURL, "redirect_uri=")
@@ -48,15 +48,19 @@ func NewGoogleProvider(clientID, clientSecret, redirectURL string, scopes []stri
 	}
 }
 
-// GetAuthURL generates the OAuth authorization URL with state
-func (g *GoogleProvider) GetAuthURL(state string) string {
-	return g.config.AuthCodeURL(state, oauth2.AccessTypeOffline)
+// GetAuthURL generates the OAuth authorization URL with state and an S256 code challenge
+func (g *GoogleProvider) GetAuthURL(state, codeChallenge string) string {
+	return g.config.AuthCodeURL(state,
+		oauth2.AccessTypeOffline,
+		oauth2.SetAuthURLParam("code_challenge", codeChallenge),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	)
 }
 
 // ExchangeCode exchanges authorization code for access token and retrieves user info
-func (g *GoogleProvider) ExchangeCode(ctx context.Context, code string) (*domain.OAuthUserInfo, error) {
+func (g *GoogleProvider) ExchangeCode(ctx context.Context, code, codeVerifier string) (*domain.OAuthUserInfo, error) {
 	// Exchange code for token
-	token, err := g.config.Exchange(ctx, code)
+	token, err := g.config.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", domain.ErrOAuthCodeExchange, err)
 	}
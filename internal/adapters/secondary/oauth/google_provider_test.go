@@ -61,7 +61,7 @@ func TestGoogleProvider_GetAuthURL(t *testing.T) {
 	provider := NewGoogleProvider("test-client-id", "test-secret", "http://localhost/callback", nil)
 
 	state := "random-state-string"
-	authURL := provider.GetAuthURL(state)
+	authURL := provider.GetAuthURL(state, "test-challenge")
 
 	assert.NotEmpty(t, authURL)
 	assert.Contains(t, authURL, "accounts.google.com/o/oauth2")
@@ -70,6 +70,8 @@ func TestGoogleProvider_GetAuthURL(t *testing.T) {
 	assert.Contains(t, authURL, "redirect_uri=")
 	assert.Contains(t, authURL, "scope=")
 	assert.Contains(t, authURL, "access_type=offline")
+	assert.Contains(t, authURL, "code_challenge=test-challenge")
+	assert.Contains(t, authURL, "code_challenge_method=S256")
 }
 
 func TestGoogleProvider_GetProviderName(t *testing.T) {
@@ -125,7 +127,7 @@ func TestGoogleProvider_ExchangeCode_InvalidCode(t *testing.T) {
 	provider := NewGoogleProvider("test-client-id", "test-secret", "http://localhost/callback", nil)
 
 	ctx := context.Background()
-	userInfo, err := provider.ExchangeCode(ctx, "invalid-code")
+	userInfo, err := provider.ExchangeCode(ctx, "invalid-code", "test-verifier")
 
 	assert.Error(t, err)
 	assert.Nil(t, userInfo)
@@ -13,6 +13,7 @@ type AuthResponse struct {
 	AccessToken  string   `json:"access_token"`
 	RefreshToken string   `json:"refresh_token"`
 	ExpiresAt    int64    `json:"expires_at"` // Unix timestamp
+	ExpiresIn    int64    `json:"expires_in"` // Access token lifetime in seconds
 }
 
 // UserDTO represents user data returned in responses
@@ -76,12 +77,14 @@ func ToUserDTO(user *domain.User) *UserDTO {
 	}
 }
 
-// NewAuthResponse creates an AuthResponse from domain user and tokens
-func NewAuthResponse(user *domain.User, accessToken, refreshToken string, expiresAt int64) *AuthResponse {
+// NewAuthResponse creates an AuthResponse from domain user and tokens.
+// expiresIn is the access token's lifetime from now.
+func NewAuthResponse(user *domain.User, accessToken, refreshToken string, expiresIn time.Duration) *AuthResponse {
 	return &AuthResponse{
 		User:         ToUserDTO(user),
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
-		ExpiresAt:    expiresAt,
+		ExpiresAt:    time.Now().Add(expiresIn).Unix(),
+		ExpiresIn:    int64(expiresIn / time.Second),
 	}
 }
\ No newline at end of file
@@ -2,12 +2,18 @@ package services
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/sirupsen/logrus"
 	"github.com/yourusername/notinoteapp/internal/application/dto"
 	"github.com/yourusername/notinoteapp/internal/core/domain"
 	"github.com/yourusername/notinoteapp/internal/core/ports"
+	"github.com/yourusername/notinoteapp/pkg/i18n"
 )
 
 // AuthService handles authentication business logic
@@ -16,9 +22,14 @@ type AuthService struct {
 	passwordHasher ports.PasswordHasher
 	tokenService   ports.TokenService
 	stateGenerator ports.StateGenerator
+	stateTTL       time.Duration
 	oauthProviders map[domain.AuthProvider]ports.OAuthProvider
+	logger         *logrus.Logger
 }
 
+// defaultOAuthStateTTL is how long a user has to finish the provider's consent screen
+const defaultOAuthStateTTL = 10 * time.Minute
+
 // NewAuthService creates a new authentication service
 func NewAuthService(
 	userRepo ports.UserRepository,
@@ -31,7 +42,23 @@ func NewAuthService(
 		passwordHasher: passwordHasher,
 		tokenService:   tokenService,
 		stateGenerator: stateGenerator,
+		stateTTL:       defaultOAuthStateTTL,
 		oauthProviders: make(map[domain.AuthProvider]ports.OAuthProvider),
+		logger:         logrus.StandardLogger(),
+	}
+}
+
+// SetLogger sets the logger used for failures that don't fail the request
+func (s *AuthService) SetLogger(logger *logrus.Logger) {
+	if logger != nil {
+		s.logger = logger
+	}
+}
+
+// SetOAuthStateTTL sets how long OAuth state stays valid; non-positive values keep the default
+func (s *AuthService) SetOAuthStateTTL(ttl time.Duration) {
+	if ttl > 0 {
+		s.stateTTL = ttl
 	}
 }
 
@@ -113,10 +140,32 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (*dto.A
 		return nil, domain.ErrInvalidCredentials
 	}
 
+	s.rehashPassword(ctx, user, password)
+
 	// Generate tokens
 	return s.generateAuthResponse(user)
 }
 
+// rehashPassword upgrades the user's stored hash to the hasher's current
+// settings while the plaintext is at hand. Failures only delay the upgrade to
+// a later sign-in, so they don't fail the login.
+func (s *AuthService) rehashPassword(ctx context.Context, user *domain.User, password string) {
+	if !s.passwordHasher.NeedsRehash(user.PasswordHash) {
+		return
+	}
+
+	hash, err := s.passwordHasher.HashPassword(password)
+	if err != nil {
+		s.logger.WithError(err).WithField("user_id", user.ID).Warn("Failed to rehash password")
+		return
+	}
+
+	user.PasswordHash = hash
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		s.logger.WithError(err).WithField("user_id", user.ID).Warn("Failed to store rehashed password")
+	}
+}
+
 // GetOAuthURL generates the OAuth authorization URL
 func (s *AuthService) GetOAuthURL(ctx context.Context, provider domain.AuthProvider) (string, error) {
 	oauthProvider, ok := s.oauthProviders[provider]
@@ -124,30 +173,51 @@ func (s *AuthService) GetOAuthURL(ctx context.Context, provider domain.AuthProvi
 		return "", fmt.Errorf("oauth provider %s not supported", provider)
 	}
 
+	// The redirect flow needs somewhere to keep the state between requests
+	if s.stateGenerator == nil {
+		return "", domain.ErrOAuthUnavailable
+	}
+
 	// Generate state for CSRF protection
 	state, err := s.stateGenerator.GenerateState()
 	if err != nil {
 		return "", fmt.Errorf("failed to generate state: %w", err)
 	}
 
-	// Store state in Redis with 10 minute expiration
-	if err := s.stateGenerator.StoreState(ctx, state, 600); err != nil {
-		return "", fmt.Errorf("failed to store state: %w", err)
+	// Generate a PKCE verifier so an intercepted code can't be redeemed elsewhere
+	codeVerifier, err := s.stateGenerator.GenerateCodeVerifier()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate code verifier: %w", err)
+	}
+
+	// Store state and verifier in Redis until the user is expected back from the provider
+	if err := s.stateGenerator.StoreState(ctx, state, codeVerifier, int(s.stateTTL.Seconds())); err != nil {
+		return "", fmt.Errorf("%w: failed to store state: %v", domain.ErrOAuthUnavailable, err)
 	}
 
 	// Generate authorization URL
-	authURL := oauthProvider.GetAuthURL(state)
+	authURL := oauthProvider.GetAuthURL(state, pkceChallenge(codeVerifier))
 	return authURL, nil
 }
 
+// pkceChallenge derives the S256 code challenge for a PKCE code verifier
+func pkceChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
 // HandleOAuthCallback handles the OAuth callback
 func (s *AuthService) HandleOAuthCallback(ctx context.Context, provider domain.AuthProvider, code, state string) (*dto.AuthResponse, error) {
+	if s.stateGenerator == nil {
+		return nil, domain.ErrOAuthUnavailable
+	}
+
 	// Validate state to prevent CSRF
-	valid, err := s.stateGenerator.GetState(ctx, state)
+	codeVerifier, found, err := s.stateGenerator.GetState(ctx, state)
 	if err != nil {
-		return nil, fmt.Errorf("failed to validate state: %w", err)
+		return nil, fmt.Errorf("%w: failed to validate state: %v", domain.ErrOAuthUnavailable, err)
 	}
-	if !valid {
+	if !found || codeVerifier == "" {
 		return nil, domain.ErrOAuthStateMismatch
 	}
 
@@ -158,7 +228,7 @@ func (s *AuthService) HandleOAuthCallback(ctx context.Context, provider domain.A
 	}
 
 	// Exchange code for user info
-	userInfo, err := oauthProvider.ExchangeCode(ctx, code)
+	userInfo, err := oauthProvider.ExchangeCode(ctx, code, codeVerifier)
 	if err != nil {
 		return nil, err
 	}
@@ -176,13 +246,11 @@ func (s *AuthService) HandleOAuthCallback(ctx context.Context, provider domain.A
 			return nil, domain.ErrUserInactive
 		}
 
-		// Update user info (name, avatar) if changed
-		if user.Name != userInfo.Name || user.AvatarURL != userInfo.AvatarURL {
-			user.Name = userInfo.Name
-			user.AvatarURL = userInfo.AvatarURL
+		// Update user info (name, avatar) if changed, keeping a pinned name
+		if user.ApplyOAuthProfile(userInfo) {
 			if err := s.userRepo.Update(ctx, user); err != nil {
 				// Log error but don't fail login
-				fmt.Printf("failed to update user info: %v\n", err)
+				s.logger.WithError(err).WithField("user_id", user.ID).Warn("Failed to update user info")
 			}
 		}
 
@@ -242,6 +310,98 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*d
 	return s.generateAuthResponse(user)
 }
 
+// Deactivate disables the user's account without deleting any data. Sign-in
+// and token refresh are refused until the account is reactivated, existing
+// access tokens are rejected by the auth middleware (see IsUserActive), and
+// the notification scheduler skips the user's reminders.
+func (s *AuthService) Deactivate(ctx context.Context, userID int64) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.IsActive {
+		return nil
+	}
+
+	user.Deactivate()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return fmt.Errorf("failed to deactivate user: %w", err)
+	}
+
+	return nil
+}
+
+// IsUserActive reports whether the user exists and has not been deactivated.
+// Unknown users are reported as inactive rather than as an error.
+func (s *AuthService) IsUserActive(ctx context.Context, userID int64) (bool, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	return user.IsActive, nil
+}
+
+// Reactivate re-enables a deactivated email/password account after checking
+// the user's credentials, and signs them in
+func (s *AuthService) Reactivate(ctx context.Context, email, password string) (*dto.AuthResponse, error) {
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, domain.ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	if user.IsOAuthUser() {
+		return nil, fmt.Errorf("this account uses %s sign-in. Please reactivate with %s", user.Provider, user.Provider)
+	}
+
+	if !s.passwordHasher.CheckPassword(password, user.PasswordHash) {
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	s.rehashPassword(ctx, user, password)
+
+	return s.reactivate(ctx, user)
+}
+
+// ReactivateOAuth re-enables a deactivated OAuth account after verifying a
+// fresh provider token (Google ID token or Facebook access token), and signs
+// the user in
+func (s *AuthService) ReactivateOAuth(ctx context.Context, provider domain.AuthProvider, token string) (*dto.AuthResponse, error) {
+	userInfo, err := s.verifyProviderToken(ctx, provider, token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByProvider(ctx, userInfo.Provider, userInfo.ProviderID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, domain.ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("failed to find user by provider: %w", err)
+	}
+
+	return s.reactivate(ctx, user)
+}
+
+// reactivate marks an authenticated user active again and issues tokens
+func (s *AuthService) reactivate(ctx context.Context, user *domain.User) (*dto.AuthResponse, error) {
+	if !user.IsActive {
+		user.Activate()
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return nil, fmt.Errorf("failed to reactivate user: %w", err)
+		}
+	}
+
+	return s.generateAuthResponse(user)
+}
+
 // GetUserByID retrieves a user by their ID
 func (s *AuthService) GetUserByID(ctx context.Context, userID int64) (*domain.User, error) {
 	user, err := s.userRepo.FindByID(ctx, userID)
@@ -252,54 +412,97 @@ func (s *AuthService) GetUserByID(ctx context.Context, userID int64) (*domain.Us
 	return user, nil
 }
 
-// VerifyGoogleToken verifies a Google ID token from frontend SDK
-func (s *AuthService) VerifyGoogleToken(ctx context.Context, idToken string) (*dto.AuthResponse, error) {
-	// Get Google provider
-	googleProvider, ok := s.oauthProviders[domain.AuthProviderGoogle]
-	if !ok {
-		return nil, fmt.Errorf("google OAuth provider not registered")
+// UpdateProfile updates the user's display name and avatar. Nil fields are
+// left unchanged; an empty avatar URL removes the avatar.
+//
+// OAuth users get the provider's name on every sign-in unless they pin their
+// name with SetNamePinned. The avatar always follows the provider.
+func (s *AuthService) UpdateProfile(ctx context.Context, userID int64, name *string, avatarURL *string) (*domain.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Type assert to access VerifyIDToken method
-	type GoogleTokenVerifier interface {
-		VerifyIDToken(ctx context.Context, idToken string) (*domain.OAuthUserInfo, error)
+	newName := user.Name
+	if name != nil {
+		newName = strings.TrimSpace(*name)
 	}
 
-	verifier, ok := googleProvider.(GoogleTokenVerifier)
-	if !ok {
-		return nil, fmt.Errorf("google provider does not support token verification")
+	newAvatarURL := user.AvatarURL
+	if avatarURL != nil {
+		newAvatarURL = strings.TrimSpace(*avatarURL)
 	}
 
-	// Verify token and get user info
-	userInfo, err := verifier.VerifyIDToken(ctx, idToken)
+	if err := user.UpdateProfile(newName, newAvatarURL); err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	return user, nil
+}
+
+// SetNamePinned controls whether OAuth sign-ins keep the user's current name
+// instead of replacing it with the provider's
+func (s *AuthService) SetNamePinned(ctx context.Context, userID int64, pinned bool) (*domain.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Process OAuth user info (create or update user)
-	return s.processOAuthUser(ctx, userInfo)
+	if user.NamePinned == pinned {
+		return user, nil
+	}
+
+	user.NamePinned = pinned
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	return user, nil
 }
 
-// VerifyFacebookToken verifies a Facebook access token from frontend SDK
-func (s *AuthService) VerifyFacebookToken(ctx context.Context, accessToken string) (*dto.AuthResponse, error) {
-	// Get Facebook provider
-	facebookProvider, ok := s.oauthProviders[domain.AuthProviderFacebook]
+// SetLanguage sets the language the user's notifications are sent in. Tags
+// such as "th-TH" are accepted and stored as their base language.
+func (s *AuthService) SetLanguage(ctx context.Context, userID int64, language string) (*domain.User, error) {
+	language, ok := i18n.Match(language)
 	if !ok {
-		return nil, fmt.Errorf("facebook OAuth provider not registered")
+		return nil, domain.ErrUnsupportedLanguage
 	}
 
-	// Type assert to access VerifyAccessToken method
-	type FacebookTokenVerifier interface {
-		VerifyAccessToken(ctx context.Context, accessToken string) (*domain.OAuthUserInfo, error)
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	verifier, ok := facebookProvider.(FacebookTokenVerifier)
-	if !ok {
-		return nil, fmt.Errorf("facebook provider does not support token verification")
+	if user.Language == language {
+		return user, nil
 	}
 
-	// Verify token and get user info
-	userInfo, err := verifier.VerifyAccessToken(ctx, accessToken)
+	user.SetLanguage(language)
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	return user, nil
+}
+
+// ListUsers returns a page of users, newest first, and the total user count
+func (s *AuthService) ListUsers(ctx context.Context, limit, offset int) ([]*domain.User, int64, error) {
+	users, total, err := s.userRepo.List(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	return users, total, nil
+}
+
+// VerifyGoogleToken verifies a Google ID token from frontend SDK
+func (s *AuthService) VerifyGoogleToken(ctx context.Context, idToken string) (*dto.AuthResponse, error) {
+	userInfo, err := s.verifyProviderToken(ctx, domain.AuthProviderGoogle, idToken)
 	if err != nil {
 		return nil, err
 	}
@@ -308,6 +511,55 @@ func (s *AuthService) VerifyFacebookToken(ctx context.Context, accessToken strin
 	return s.processOAuthUser(ctx, userInfo)
 }
 
+// VerifyFacebookToken verifies a Facebook access token from frontend SDK
+func (s *AuthService) VerifyFacebookToken(ctx context.Context, accessToken string) (*dto.AuthResponse, error) {
+	userInfo, err := s.verifyProviderToken(ctx, domain.AuthProviderFacebook, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// Process OAuth user info (create or update user)
+	return s.processOAuthUser(ctx, userInfo)
+}
+
+// verifyProviderToken verifies a token issued to the frontend SDK by the
+// provider and returns the user it belongs to. Google sends ID tokens and
+// Facebook sends access tokens.
+func (s *AuthService) verifyProviderToken(ctx context.Context, provider domain.AuthProvider, token string) (*domain.OAuthUserInfo, error) {
+	oauthProvider, ok := s.oauthProviders[provider]
+	if !ok {
+		return nil, fmt.Errorf("%s OAuth provider not registered", provider)
+	}
+
+	switch provider {
+	case domain.AuthProviderGoogle:
+		// Type assert to access VerifyIDToken method
+		type GoogleTokenVerifier interface {
+			VerifyIDToken(ctx context.Context, idToken string) (*domain.OAuthUserInfo, error)
+		}
+
+		verifier, ok := oauthProvider.(GoogleTokenVerifier)
+		if !ok {
+			return nil, fmt.Errorf("google provider does not support token verification")
+		}
+		return verifier.VerifyIDToken(ctx, token)
+
+	case domain.AuthProviderFacebook:
+		// Type assert to access VerifyAccessToken method
+		type FacebookTokenVerifier interface {
+			VerifyAccessToken(ctx context.Context, accessToken string) (*domain.OAuthUserInfo, error)
+		}
+
+		verifier, ok := oauthProvider.(FacebookTokenVerifier)
+		if !ok {
+			return nil, fmt.Errorf("facebook provider does not support token verification")
+		}
+		return verifier.VerifyAccessToken(ctx, token)
+	}
+
+	return nil, fmt.Errorf("oauth provider %s not supported", provider)
+}
+
 // processOAuthUser handles creating or updating a user from OAuth info
 func (s *AuthService) processOAuthUser(ctx context.Context, userInfo *domain.OAuthUserInfo) (*dto.AuthResponse, error) {
 	// Check if user already exists with this provider
@@ -323,13 +575,11 @@ func (s *AuthService) processOAuthUser(ctx context.Context, userInfo *domain.OAu
 			return nil, domain.ErrUserInactive
 		}
 
-		// Update user info (name, avatar) if changed
-		if user.Name != userInfo.Name || user.AvatarURL != userInfo.AvatarURL {
-			user.Name = userInfo.Name
-			user.AvatarURL = userInfo.AvatarURL
+		// Update user info (name, avatar) if changed, keeping a pinned name
+		if user.ApplyOAuthProfile(userInfo) {
 			if err := s.userRepo.Update(ctx, user); err != nil {
 				// Log error but don't fail login
-				fmt.Printf("failed to update user info: %v\n", err)
+				s.logger.WithError(err).WithField("user_id", user.ID).Warn("Failed to update user info")
 			}
 		}
 
@@ -358,9 +608,10 @@ func (s *AuthService) processOAuthUser(ctx context.Context, userInfo *domain.OAu
 	return s.generateAuthResponse(newUser)
 }
 
-// generateAuthResponse generates access and refresh tokens
+// generateAuthResponse generates access and refresh tokens. The access token
+// carries the user's role; refresh goes back to the database for it.
 func (s *AuthService) generateAuthResponse(user *domain.User) (*dto.AuthResponse, error) {
-	accessToken, err := s.tokenService.GenerateToken(user.ID, user.Email)
+	accessToken, err := s.tokenService.GenerateTokenWithRole(user.ID, user.Email, string(user.Role))
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
@@ -370,6 +621,5 @@ func (s *AuthService) generateAuthResponse(user *domain.User) (*dto.AuthResponse
 		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
 	}
 
-	// ExpiresAt will be set by handler based on JWT expiration
-	return dto.NewAuthResponse(user, accessToken, refreshToken, 0), nil
+	return dto.NewAuthResponse(user, accessToken, refreshToken, s.tokenService.AccessTokenExpiry()), nil
 }
@@ -3,15 +3,35 @@ package services
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/yourusername/notinoteapp/internal/core/domain"
 	"github.com/yourusername/notinoteapp/internal/core/ports"
+	"github.com/yourusername/notinoteapp/pkg/utils"
+	"golang.org/x/crypto/bcrypt"
 )
 
+// newAuthServiceWithProviders builds an AuthService with the given OAuth
+// providers already registered.
+func newAuthServiceWithProviders(
+	userRepo ports.UserRepository,
+	passwordHasher ports.PasswordHasher,
+	tokenService ports.TokenService,
+	stateGenerator ports.StateGenerator,
+	providers map[domain.AuthProvider]ports.OAuthProvider,
+) *AuthService {
+	service := NewAuthService(userRepo, passwordHasher, tokenService, stateGenerator)
+	for name, provider := range providers {
+		service.oauthProviders[name] = provider
+	}
+	return service
+}
+
 // Mock implementations
 
 type MockUserRepository struct {
@@ -84,8 +104,14 @@ func (m *MockPasswordHasher) CheckPassword(password, hash string) bool {
 	return args.Bool(0)
 }
 
+func (m *MockPasswordHasher) NeedsRehash(hash string) bool {
+	args := m.Called(hash)
+	return args.Bool(0)
+}
+
 type MockTokenService struct {
 	mock.Mock
+	accessExpiry time.Duration
 }
 
 func (m *MockTokenService) GenerateToken(userID int64, email string) (string, error) {
@@ -93,6 +119,13 @@ func (m *MockTokenService) GenerateToken(userID int64, email string) (string, er
 	return args.String(0), args.Error(1)
 }
 
+// GenerateTokenWithRole records the call as GenerateToken so expectations
+// don't have to repeat the role; TestAuthService_Login_TokenCarriesRole
+// checks the role against a real JWTService.
+func (m *MockTokenService) GenerateTokenWithRole(userID int64, email, role string) (string, error) {
+	return m.GenerateToken(userID, email)
+}
+
 func (m *MockTokenService) GenerateRefreshToken(userID int64, email string) (string, error) {
 	args := m.Called(userID, email)
 	return args.String(0), args.Error(1)
@@ -108,6 +141,16 @@ func (m *MockTokenService) RefreshToken(refreshToken string) (string, error) {
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockTokenService) AccessTokenExpiry() time.Duration {
+	return m.accessExpiry
+}
+
+// A fixed PKCE verifier and its S256 challenge
+const (
+	testCodeVerifier  = "dBjftJeZ4CVP-mJ92K1qUkHNJz3ve1ZWSRpNcgZ_u3M"
+	testCodeChallenge = "komuKp9HaapX_38UI1coHSkgrTIJ16v7aJObwPFYSnw"
+)
+
 type MockStateGenerator struct {
 	mock.Mock
 }
@@ -122,27 +165,32 @@ func (m *MockStateGenerator) ValidateState(state, expected string) bool {
 	return args.Bool(0)
 }
 
-func (m *MockStateGenerator) StoreState(ctx context.Context, state string, ttl int) error {
-	args := m.Called(ctx, state, ttl)
+func (m *MockStateGenerator) GenerateCodeVerifier() (string, error) {
+	args := m.Called()
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockStateGenerator) StoreState(ctx context.Context, state, codeVerifier string, ttl int) error {
+	args := m.Called(ctx, state, codeVerifier, ttl)
 	return args.Error(0)
 }
 
-func (m *MockStateGenerator) GetState(ctx context.Context, state string) (bool, error) {
+func (m *MockStateGenerator) GetState(ctx context.Context, state string) (string, bool, error) {
 	args := m.Called(ctx, state)
-	return args.Bool(0), args.Error(1)
+	return args.String(0), args.Bool(1), args.Error(2)
 }
 
 type MockOAuthProvider struct {
 	mock.Mock
 }
 
-func (m *MockOAuthProvider) GetAuthURL(state string) string {
-	args := m.Called(state)
+func (m *MockOAuthProvider) GetAuthURL(state, codeChallenge string) string {
+	args := m.Called(state, codeChallenge)
 	return args.String(0)
 }
 
-func (m *MockOAuthProvider) ExchangeCode(ctx context.Context, code string) (*domain.OAuthUserInfo, error) {
-	args := m.Called(ctx, code)
+func (m *MockOAuthProvider) ExchangeCode(ctx context.Context, code, codeVerifier string) (*domain.OAuthUserInfo, error) {
+	args := m.Called(ctx, code, codeVerifier)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -188,6 +236,37 @@ func TestAuthService_Register_Success(t *testing.T) {
 	tokenService.AssertExpectations(t)
 }
 
+func TestAuthService_Register_LongPassword(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	tokenService := new(MockTokenService)
+	hasher := utils.NewBcryptPasswordHasherWithCost(bcrypt.MinCost)
+
+	password := "Aa1!" + strings.Repeat("correct horse battery staple ", 4)
+	require.Len(t, password, 120)
+
+	var created *domain.User
+	userRepo.On("FindByEmail", mock.Anything, "test@example.com").Return(nil, domain.ErrUserNotFound).Once()
+	userRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.User")).Run(func(args mock.Arguments) {
+		created = args.Get(1).(*domain.User)
+	}).Return(nil)
+	tokenService.On("GenerateToken", int64(1), "test@example.com").Return("access-token", nil)
+	tokenService.On("GenerateRefreshToken", int64(1), "test@example.com").Return("refresh-token", nil)
+
+	service := NewAuthService(userRepo, hasher, tokenService, nil)
+
+	_, err := service.Register(context.Background(), "test@example.com", password, "Test User")
+	require.NoError(t, err)
+	require.NotNil(t, created)
+
+	userRepo.On("FindByEmail", mock.Anything, "test@example.com").Return(created, nil)
+
+	_, err = service.Login(context.Background(), "test@example.com", password)
+	require.NoError(t, err)
+
+	_, err = service.Login(context.Background(), "test@example.com", password[:72])
+	assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+}
+
 func TestAuthService_Register_UserAlreadyExists(t *testing.T) {
 	userRepo := new(MockUserRepository)
 	passwordHasher := new(MockPasswordHasher)
@@ -249,6 +328,7 @@ func TestAuthService_Login_Success(t *testing.T) {
 
 	userRepo.On("FindByEmail", mock.Anything, "test@example.com").Return(user, nil)
 	passwordHasher.On("CheckPassword", "Password123!", "hashed-password").Return(true)
+	passwordHasher.On("NeedsRehash", "hashed-password").Return(false)
 	tokenService.On("GenerateToken", int64(1), "test@example.com").Return("access-token", nil)
 	tokenService.On("GenerateRefreshToken", int64(1), "test@example.com").Return("refresh-token", nil)
 
@@ -268,6 +348,99 @@ func TestAuthService_Login_Success(t *testing.T) {
 	tokenService.AssertExpectations(t)
 }
 
+func TestAuthService_Login_TokenCarriesRole(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	passwordHasher := new(MockPasswordHasher)
+	tokens := utils.NewJWTService("test-secret", "test", time.Hour, time.Hour)
+
+	user := &domain.User{
+		ID:           1,
+		Email:        "admin@example.com",
+		PasswordHash: "hashed-password",
+		Provider:     domain.AuthProviderEmail,
+		Role:         domain.RoleAdmin,
+		IsActive:     true,
+	}
+
+	userRepo.On("FindByEmail", mock.Anything, "admin@example.com").Return(user, nil)
+	passwordHasher.On("CheckPassword", "Password123!", "hashed-password").Return(true)
+	passwordHasher.On("NeedsRehash", "hashed-password").Return(false)
+
+	service := NewAuthService(userRepo, passwordHasher, tokens, nil)
+
+	resp, err := service.Login(context.Background(), "admin@example.com", "Password123!")
+	require.NoError(t, err)
+
+	claims, err := tokens.ParseToken(resp.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "admin", claims.Role)
+}
+
+func TestAuthService_Login_ReportsConfiguredExpiry(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	passwordHasher := new(MockPasswordHasher)
+	tokenService := &MockTokenService{accessExpiry: 2 * time.Hour}
+
+	user := &domain.User{
+		ID:           1,
+		Email:        "test@example.com",
+		PasswordHash: "hashed-password",
+		Provider:     domain.AuthProviderEmail,
+		IsActive:     true,
+	}
+
+	userRepo.On("FindByEmail", mock.Anything, "test@example.com").Return(user, nil)
+	passwordHasher.On("CheckPassword", "Password123!", "hashed-password").Return(true)
+	passwordHasher.On("NeedsRehash", "hashed-password").Return(false)
+	tokenService.On("GenerateToken", int64(1), "test@example.com").Return("access-token", nil)
+	tokenService.On("GenerateRefreshToken", int64(1), "test@example.com").Return("refresh-token", nil)
+
+	service := NewAuthService(userRepo, passwordHasher, tokenService, nil)
+
+	before := time.Now()
+	resp, err := service.Login(context.Background(), "test@example.com", "Password123!")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(7200), resp.ExpiresIn)
+	assert.InDelta(t, before.Add(2*time.Hour).Unix(), resp.ExpiresAt, 1)
+}
+
+func TestAuthService_Login_RehashesUnderCostPassword(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	tokenService := new(MockTokenService)
+
+	oldHash, err := utils.NewBcryptPasswordHasherWithCost(bcrypt.MinCost).HashPassword("Password123!")
+	require.NoError(t, err)
+	hasher := utils.NewBcryptPasswordHasherWithCost(bcrypt.MinCost + 1)
+
+	user := &domain.User{
+		ID:           1,
+		Email:        "test@example.com",
+		PasswordHash: oldHash,
+		Provider:     domain.AuthProviderEmail,
+		IsActive:     true,
+	}
+
+	userRepo.On("FindByEmail", mock.Anything, "test@example.com").Return(user, nil)
+	userRepo.On("Update", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+		cost, err := bcrypt.Cost([]byte(u.PasswordHash))
+		return err == nil && cost == bcrypt.MinCost+1 && hasher.CheckPassword("Password123!", u.PasswordHash)
+	})).Return(nil).Once()
+	tokenService.On("GenerateToken", int64(1), "test@example.com").Return("access-token", nil)
+	tokenService.On("GenerateRefreshToken", int64(1), "test@example.com").Return("refresh-token", nil)
+
+	service := NewAuthService(userRepo, hasher, tokenService, nil)
+
+	_, err = service.Login(context.Background(), "test@example.com", "Password123!")
+	require.NoError(t, err)
+	userRepo.AssertExpectations(t)
+
+	// The upgraded hash is current, so the next login doesn't write again
+	_, err = service.Login(context.Background(), "test@example.com", "Password123!")
+	require.NoError(t, err)
+	userRepo.AssertNumberOfCalls(t, "Update", 1)
+}
+
 func TestAuthService_Login_InvalidCredentials(t *testing.T) {
 	userRepo := new(MockUserRepository)
 	passwordHasher := new(MockPasswordHasher)
@@ -367,14 +540,15 @@ func TestAuthService_GetOAuthURL_Success(t *testing.T) {
 	oauthProvider := new(MockOAuthProvider)
 
 	stateGen.On("GenerateState").Return("random-state", nil)
-	stateGen.On("StoreState", mock.Anything, "random-state", 600).Return(nil)
-	oauthProvider.On("GetAuthURL", "random-state").Return("https://accounts.google.com/oauth?state=random-state", nil)
+	stateGen.On("GenerateCodeVerifier").Return(testCodeVerifier, nil)
+	stateGen.On("StoreState", mock.Anything, "random-state", testCodeVerifier, 600).Return(nil)
+	oauthProvider.On("GetAuthURL", "random-state", testCodeChallenge).Return("https://accounts.google.com/oauth?state=random-state", nil)
 
 	oauthProviders := map[domain.AuthProvider]ports.OAuthProvider{
 		domain.AuthProviderGoogle: oauthProvider,
 	}
 
-	service := NewAuthService(nil, nil, nil, stateGen, oauthProviders)
+	service := newAuthServiceWithProviders(nil, nil, nil, stateGen, oauthProviders)
 
 	ctx := context.Background()
 	authURL, err := service.GetOAuthURL(ctx, domain.AuthProviderGoogle)
@@ -387,8 +561,28 @@ func TestAuthService_GetOAuthURL_Success(t *testing.T) {
 	oauthProvider.AssertExpectations(t)
 }
 
+func TestAuthService_GetOAuthURL_CustomStateTTL(t *testing.T) {
+	stateGen := new(MockStateGenerator)
+	oauthProvider := new(MockOAuthProvider)
+
+	stateGen.On("GenerateState").Return("random-state", nil)
+	stateGen.On("GenerateCodeVerifier").Return(testCodeVerifier, nil)
+	stateGen.On("StoreState", mock.Anything, "random-state", testCodeVerifier, 1800).Return(nil)
+	oauthProvider.On("GetAuthURL", "random-state", testCodeChallenge).Return("https://accounts.google.com/oauth?state=random-state", nil)
+
+	service := newAuthServiceWithProviders(nil, nil, nil, stateGen, map[domain.AuthProvider]ports.OAuthProvider{
+		domain.AuthProviderGoogle: oauthProvider,
+	})
+	service.SetOAuthStateTTL(30 * time.Minute)
+
+	_, err := service.GetOAuthURL(context.Background(), domain.AuthProviderGoogle)
+	require.NoError(t, err)
+
+	stateGen.AssertExpectations(t)
+}
+
 func TestAuthService_GetOAuthURL_UnsupportedProvider(t *testing.T) {
-	service := NewAuthService(nil, nil, nil, nil, map[domain.AuthProvider]ports.OAuthProvider{})
+	service := newAuthServiceWithProviders(nil, nil, nil, nil, map[domain.AuthProvider]ports.OAuthProvider{})
 
 	ctx := context.Background()
 	authURL, err := service.GetOAuthURL(ctx, domain.AuthProviderGoogle)
@@ -398,6 +592,24 @@ func TestAuthService_GetOAuthURL_UnsupportedProvider(t *testing.T) {
 	assert.Contains(t, err.Error(), "not supported")
 }
 
+func TestAuthService_OAuthWithoutStateGenerator(t *testing.T) {
+	oauthProvider := new(MockOAuthProvider)
+	service := newAuthServiceWithProviders(nil, nil, nil, nil, map[domain.AuthProvider]ports.OAuthProvider{
+		domain.AuthProviderGoogle: oauthProvider,
+	})
+
+	ctx := context.Background()
+	authURL, err := service.GetOAuthURL(ctx, domain.AuthProviderGoogle)
+	assert.ErrorIs(t, err, domain.ErrOAuthUnavailable)
+	assert.Empty(t, authURL)
+
+	resp, err := service.HandleOAuthCallback(ctx, domain.AuthProviderGoogle, "auth-code", "some-state")
+	assert.ErrorIs(t, err, domain.ErrOAuthUnavailable)
+	assert.Nil(t, resp)
+
+	oauthProvider.AssertNotCalled(t, "ExchangeCode", mock.Anything, mock.Anything)
+}
+
 func TestAuthService_HandleOAuthCallback_NewUser(t *testing.T) {
 	userRepo := new(MockUserRepository)
 	tokenService := new(MockTokenService)
@@ -412,8 +624,8 @@ func TestAuthService_HandleOAuthCallback_NewUser(t *testing.T) {
 		AvatarURL:  "https://example.com/avatar.jpg",
 	}
 
-	stateGen.On("GetState", mock.Anything, "valid-state").Return(true, nil)
-	oauthProvider.On("ExchangeCode", mock.Anything, "auth-code").Return(oauthUserInfo, nil)
+	stateGen.On("GetState", mock.Anything, "valid-state").Return(testCodeVerifier, true, nil)
+	oauthProvider.On("ExchangeCode", mock.Anything, "auth-code", testCodeVerifier).Return(oauthUserInfo, nil)
 	userRepo.On("FindByProvider", mock.Anything, domain.AuthProviderGoogle, "google-123").Return(nil, domain.ErrUserNotFound)
 	userRepo.On("FindByEmail", mock.Anything, "newuser@gmail.com").Return(nil, domain.ErrUserNotFound)
 	userRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil)
@@ -424,7 +636,7 @@ func TestAuthService_HandleOAuthCallback_NewUser(t *testing.T) {
 		domain.AuthProviderGoogle: oauthProvider,
 	}
 
-	service := NewAuthService(userRepo, nil, tokenService, stateGen, oauthProviders)
+	service := newAuthServiceWithProviders(userRepo, nil, tokenService, stateGen, oauthProviders)
 
 	ctx := context.Background()
 	resp, err := service.HandleOAuthCallback(ctx, domain.AuthProviderGoogle, "auth-code", "valid-state")
@@ -463,8 +675,8 @@ func TestAuthService_HandleOAuthCallback_ExistingUser(t *testing.T) {
 		AvatarURL:  "https://example.com/new-avatar.jpg",
 	}
 
-	stateGen.On("GetState", mock.Anything, "valid-state").Return(true, nil)
-	oauthProvider.On("ExchangeCode", mock.Anything, "auth-code").Return(oauthUserInfo, nil)
+	stateGen.On("GetState", mock.Anything, "valid-state").Return(testCodeVerifier, true, nil)
+	oauthProvider.On("ExchangeCode", mock.Anything, "auth-code", testCodeVerifier).Return(oauthUserInfo, nil)
 	userRepo.On("FindByProvider", mock.Anything, domain.AuthProviderGoogle, "google-123").Return(existingUser, nil)
 	userRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil)
 	tokenService.On("GenerateToken", int64(1), "existing@gmail.com").Return("access-token", nil)
@@ -474,7 +686,7 @@ func TestAuthService_HandleOAuthCallback_ExistingUser(t *testing.T) {
 		domain.AuthProviderGoogle: oauthProvider,
 	}
 
-	service := NewAuthService(userRepo, nil, tokenService, stateGen, oauthProviders)
+	service := newAuthServiceWithProviders(userRepo, nil, tokenService, stateGen, oauthProviders)
 
 	ctx := context.Background()
 	resp, err := service.HandleOAuthCallback(ctx, domain.AuthProviderGoogle, "auth-code", "valid-state")
@@ -488,10 +700,54 @@ func TestAuthService_HandleOAuthCallback_ExistingUser(t *testing.T) {
 	tokenService.AssertExpectations(t)
 }
 
+func TestAuthService_HandleOAuthCallback_KeepsPinnedName(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	tokenService := new(MockTokenService)
+	stateGen := new(MockStateGenerator)
+	oauthProvider := new(MockOAuthProvider)
+
+	existingUser := &domain.User{
+		ID:         1,
+		Email:      "existing@gmail.com",
+		Name:       "Local Name",
+		Provider:   domain.AuthProviderGoogle,
+		ProviderID: "google-123",
+		NamePinned: true,
+		IsActive:   true,
+	}
+
+	oauthUserInfo := &domain.OAuthUserInfo{
+		Provider:   domain.AuthProviderGoogle,
+		ProviderID: "google-123",
+		Email:      "existing@gmail.com",
+		Name:       "Provider Name",
+		AvatarURL:  "https://example.com/new-avatar.jpg",
+	}
+
+	stateGen.On("GetState", mock.Anything, "valid-state").Return(testCodeVerifier, true, nil)
+	oauthProvider.On("ExchangeCode", mock.Anything, "auth-code", testCodeVerifier).Return(oauthUserInfo, nil)
+	userRepo.On("FindByProvider", mock.Anything, domain.AuthProviderGoogle, "google-123").Return(existingUser, nil)
+	userRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil)
+	tokenService.On("GenerateToken", int64(1), "existing@gmail.com").Return("access-token", nil)
+	tokenService.On("GenerateRefreshToken", int64(1), "existing@gmail.com").Return("refresh-token", nil)
+
+	oauthProviders := map[domain.AuthProvider]ports.OAuthProvider{
+		domain.AuthProviderGoogle: oauthProvider,
+	}
+
+	service := newAuthServiceWithProviders(userRepo, nil, tokenService, stateGen, oauthProviders)
+
+	resp, err := service.HandleOAuthCallback(context.Background(), domain.AuthProviderGoogle, "auth-code", "valid-state")
+
+	require.NoError(t, err)
+	assert.Equal(t, "Local Name", resp.User.Name)
+	assert.Equal(t, "https://example.com/new-avatar.jpg", resp.User.AvatarURL)
+}
+
 func TestAuthService_HandleOAuthCallback_InvalidState(t *testing.T) {
 	stateGen := new(MockStateGenerator)
 
-	stateGen.On("GetState", mock.Anything, "invalid-state").Return(false, nil)
+	stateGen.On("GetState", mock.Anything, "invalid-state").Return("", false, nil)
 
 	service := NewAuthService(nil, nil, nil, stateGen)
 
@@ -505,6 +761,24 @@ func TestAuthService_HandleOAuthCallback_InvalidState(t *testing.T) {
 	stateGen.AssertExpectations(t)
 }
 
+func TestAuthService_HandleOAuthCallback_MissingCodeVerifier(t *testing.T) {
+	stateGen := new(MockStateGenerator)
+	oauthProvider := new(MockOAuthProvider)
+
+	// A state stored before PKCE has no verifier to prove the code exchange with
+	stateGen.On("GetState", mock.Anything, "legacy-state").Return("", true, nil)
+
+	service := newAuthServiceWithProviders(nil, nil, nil, stateGen, map[domain.AuthProvider]ports.OAuthProvider{
+		domain.AuthProviderGoogle: oauthProvider,
+	})
+
+	resp, err := service.HandleOAuthCallback(context.Background(), domain.AuthProviderGoogle, "auth-code", "legacy-state")
+
+	assert.ErrorIs(t, err, domain.ErrOAuthStateMismatch)
+	assert.Nil(t, resp)
+	oauthProvider.AssertNotCalled(t, "ExchangeCode", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestAuthService_RefreshToken_Success(t *testing.T) {
 	tokenService := new(MockTokenService)
 
@@ -536,3 +810,155 @@ func TestAuthService_RefreshToken_InvalidToken(t *testing.T) {
 
 	tokenService.AssertExpectations(t)
 }
+
+func TestAuthService_UpdateProfile(t *testing.T) {
+	userRepo := new(MockUserRepository)
+
+	user := &domain.User{
+		ID:        1,
+		Email:     "test@example.com",
+		Name:      "Old Name",
+		AvatarURL: "https://example.com/old.jpg",
+		Provider:  domain.AuthProviderEmail,
+		IsActive:  true,
+	}
+
+	userRepo.On("FindByID", mock.Anything, int64(1)).Return(user, nil)
+	userRepo.On("Update", mock.Anything, user).Return(nil)
+
+	service := NewAuthService(userRepo, nil, nil, nil)
+
+	name := "  New Name "
+	updated, err := service.UpdateProfile(context.Background(), 1, &name, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "New Name", updated.Name)
+	assert.Equal(t, "https://example.com/old.jpg", updated.AvatarURL)
+	userRepo.AssertExpectations(t)
+}
+
+func TestAuthService_SetLanguage(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	user := &domain.User{ID: 1, Email: "test@example.com", Name: "Name", Language: domain.DefaultLanguage}
+	userRepo.On("FindByID", mock.Anything, int64(1)).Return(user, nil)
+	userRepo.On("Update", mock.Anything, user).Return(nil)
+
+	service := NewAuthService(userRepo, nil, nil, nil)
+
+	updated, err := service.SetLanguage(context.Background(), 1, "th-TH")
+	require.NoError(t, err)
+	assert.Equal(t, "th", updated.Language)
+
+	_, err = service.SetLanguage(context.Background(), 1, "fr")
+	assert.ErrorIs(t, err, domain.ErrUnsupportedLanguage)
+	userRepo.AssertNumberOfCalls(t, "Update", 1)
+}
+
+func TestAuthService_UpdateProfile_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name      string
+		newName   *string
+		avatarURL *string
+		wantErr   error
+	}{
+		{"blank name", stringPtr("   "), nil, domain.ErrInvalidName},
+		{"relative avatar", nil, stringPtr("/avatar.png"), domain.ErrInvalidAvatarURL},
+		{"non-http avatar", nil, stringPtr("javascript:alert(1)"), domain.ErrInvalidAvatarURL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(MockUserRepository)
+			userRepo.On("FindByID", mock.Anything, int64(1)).Return(&domain.User{ID: 1, Name: "Name"}, nil)
+
+			service := NewAuthService(userRepo, nil, nil, nil)
+
+			_, err := service.UpdateProfile(context.Background(), 1, tt.newName, tt.avatarURL)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAuthService_Deactivate_BlocksLoginAndKeepsData(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	hasher := new(MockPasswordHasher)
+
+	user := &domain.User{
+		ID:           1,
+		Email:        "test@example.com",
+		Name:         "Test User",
+		PasswordHash: "hashed-password",
+		Provider:     domain.AuthProviderEmail,
+		AvatarURL:    "https://example.com/avatar.jpg",
+		IsActive:     true,
+	}
+
+	userRepo.On("FindByID", mock.Anything, int64(1)).Return(user, nil)
+	userRepo.On("FindByEmail", mock.Anything, "test@example.com").Return(user, nil)
+	userRepo.On("Update", mock.Anything, user).Return(nil).Once()
+
+	service := NewAuthService(userRepo, hasher, nil, nil)
+	ctx := context.Background()
+
+	active, err := service.IsUserActive(ctx, 1)
+	require.NoError(t, err)
+	assert.True(t, active)
+
+	require.NoError(t, service.Deactivate(ctx, 1))
+
+	assert.False(t, user.IsActive)
+	active, err = service.IsUserActive(ctx, 1)
+	require.NoError(t, err)
+	assert.False(t, active, "existing tokens must stop working after deactivation")
+	assert.Equal(t, "Test User", user.Name)
+	assert.Equal(t, "hashed-password", user.PasswordHash)
+	assert.Equal(t, "https://example.com/avatar.jpg", user.AvatarURL)
+	userRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+
+	resp, err := service.Login(ctx, "test@example.com", "Password123!")
+
+	assert.ErrorIs(t, err, domain.ErrUserInactive)
+	assert.Nil(t, resp)
+	userRepo.AssertExpectations(t)
+}
+
+func TestAuthService_Reactivate(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	hasher := new(MockPasswordHasher)
+	tokenService := new(MockTokenService)
+
+	user := &domain.User{
+		ID:           1,
+		Email:        "test@example.com",
+		PasswordHash: "hashed-password",
+		Provider:     domain.AuthProviderEmail,
+		IsActive:     false,
+	}
+
+	userRepo.On("FindByEmail", mock.Anything, "test@example.com").Return(user, nil)
+	hasher.On("CheckPassword", "wrong", "hashed-password").Return(false)
+	hasher.On("CheckPassword", "Password123!", "hashed-password").Return(true)
+	hasher.On("NeedsRehash", "hashed-password").Return(false)
+	userRepo.On("Update", mock.Anything, user).Return(nil).Once()
+	tokenService.On("GenerateToken", int64(1), "test@example.com").Return("access-token", nil)
+	tokenService.On("GenerateRefreshToken", int64(1), "test@example.com").Return("refresh-token", nil)
+
+	service := NewAuthService(userRepo, hasher, tokenService, nil)
+	ctx := context.Background()
+
+	_, err := service.Reactivate(ctx, "test@example.com", "wrong")
+	assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+	assert.False(t, user.IsActive)
+
+	resp, err := service.Reactivate(ctx, "test@example.com", "Password123!")
+	require.NoError(t, err)
+	assert.True(t, user.IsActive)
+	assert.Equal(t, "access-token", resp.AccessToken)
+	userRepo.AssertExpectations(t)
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/yourusername/notinoteapp/pkg/config"
+)
+
+// DeviceReaper periodically deactivates devices that have gone unused and
+// deletes ones that stay inactive, so pushes aren't sent to dead tokens
+type DeviceReaper struct {
+	deviceSvc *DeviceService
+	config    *config.DeviceConfig
+	logger    *logrus.Logger
+	stopCh    chan struct{}
+	wg        sync.WaitGroup
+	running   bool
+	mu        sync.Mutex
+}
+
+// NewDeviceReaper creates a new device reaper
+func NewDeviceReaper(deviceSvc *DeviceService, cfg *config.DeviceConfig, logger *logrus.Logger) *DeviceReaper {
+	return &DeviceReaper{
+		deviceSvc: deviceSvc,
+		config:    cfg,
+		logger:    logger,
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// Start begins the reaper loop
+func (r *DeviceReaper) Start() {
+	r.mu.Lock()
+	if r.running {
+		r.mu.Unlock()
+		return
+	}
+	r.running = true
+	r.stopCh = make(chan struct{})
+	r.mu.Unlock()
+
+	r.wg.Add(1)
+	go r.run()
+
+	r.logger.WithFields(logrus.Fields{
+		"interval":     r.config.ReaperInterval,
+		"stale_after":  r.config.StaleAfter,
+		"delete_after": r.config.DeleteAfter,
+	}).Info("Device reaper started")
+}
+
+// Stop gracefully stops the reaper
+func (r *DeviceReaper) Stop() {
+	r.mu.Lock()
+	if !r.running {
+		r.mu.Unlock()
+		return
+	}
+	r.running = false
+	r.mu.Unlock()
+
+	close(r.stopCh)
+	r.wg.Wait()
+
+	r.logger.Info("Device reaper stopped")
+}
+
+// IsRunning returns whether the reaper is currently running
+func (r *DeviceReaper) IsRunning() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.running
+}
+
+func (r *DeviceReaper) run() {
+	defer r.wg.Done()
+
+	// Use configured interval, default to an hour
+	interval := r.config.ReaperInterval
+	if interval == 0 {
+		interval = time.Hour
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// Reap immediately on start
+	r.reap()
+
+	for {
+		select {
+		case <-r.stopCh:
+			return
+		case <-ticker.C:
+			r.reap()
+		}
+	}
+}
+
+// reap deactivates stale devices, then deletes long-inactive ones
+func (r *DeviceReaper) reap() {
+	ctx := context.Background()
+
+	deactivated, err := r.deviceSvc.DeactivateStaleDevices(ctx, r.config.StaleAfter)
+	if err != nil {
+		return
+	}
+
+	var deleted int64
+	if r.config.DeleteAfter > 0 {
+		deleted, err = r.deviceSvc.DeleteInactiveDevices(ctx, r.config.DeleteAfter)
+		if err != nil {
+			return
+		}
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"deactivated": deactivated,
+		"deleted":     deleted,
+	}).Info("Device reaper run completed")
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/notinoteapp/pkg/config"
+)
+
+func TestDeviceReaper_DeactivatesThenDeletes(t *testing.T) {
+	deviceRepo := new(MockDeviceRepository)
+	logger := newTestLogger()
+	reaper := NewDeviceReaper(NewDeviceService(deviceRepo, logger), &config.DeviceConfig{
+		ReaperInterval: time.Hour,
+		StaleAfter:     30 * 24 * time.Hour,
+		DeleteAfter:    90 * 24 * time.Hour,
+	}, logger)
+
+	olderThan := func(age time.Duration) interface{} {
+		return mock.MatchedBy(func(before time.Time) bool {
+			cutoff := time.Since(before)
+			return cutoff >= age && cutoff < age+time.Minute
+		})
+	}
+	deviceRepo.On("DeactivateStaleDevices", mock.Anything, olderThan(30*24*time.Hour)).Return(int64(3), nil)
+	deviceRepo.On("DeleteInactiveDevices", mock.Anything, olderThan(90*24*time.Hour)).Return(int64(1), nil)
+
+	reaper.reap()
+
+	deviceRepo.AssertExpectations(t)
+}
+
+func TestDeviceReaper_StopsAfterDeactivateFailure(t *testing.T) {
+	deviceRepo := new(MockDeviceRepository)
+	logger := newTestLogger()
+	reaper := NewDeviceReaper(NewDeviceService(deviceRepo, logger), &config.DeviceConfig{
+		StaleAfter:  time.Hour,
+		DeleteAfter: 2 * time.Hour,
+	}, logger)
+
+	deviceRepo.On("DeactivateStaleDevices", mock.Anything, mock.Anything).Return(int64(0), assert.AnError)
+
+	reaper.reap()
+
+	deviceRepo.AssertNotCalled(t, "DeleteInactiveDevices", mock.Anything, mock.Anything)
+}
@@ -31,29 +31,51 @@ type RegisterDeviceRequest struct {
 	DeviceToken string            `json:"device_token" binding:"required"`
 	DeviceType  domain.DeviceType `json:"device_type" binding:"required"`
 	DeviceName  string            `json:"device_name"`
+	AppVersion  string            `json:"app_version"`
 	BrowserInfo string            `json:"browser_info"`
 }
 
-// RegisterDevice registers a new device for push notifications
+// RegisterDevice registers a new device for push notifications. Registering a
+// token that is already known updates that device instead: the user's own
+// registration is refreshed, and one held by another user (the app was
+// reinstalled, or the device changed hands) is reassigned to this user.
 func (s *DeviceService) RegisterDevice(ctx context.Context, userID int64, req RegisterDeviceRequest) (*domain.Device, error) {
 	// Check if device already exists for this user
 	existingDevice, err := s.deviceRepo.FindByUserIDAndToken(ctx, userID, req.DeviceToken)
-	if err == nil && existingDevice != nil {
-		// Device exists, update last used time and reactivate if needed
-		existingDevice.Activate()
-		existingDevice.UpdateLastUsed()
-		if req.DeviceName != "" {
-			existingDevice.SetDeviceName(req.DeviceName)
-		}
-		if req.BrowserInfo != "" {
-			existingDevice.SetBrowserInfo(req.BrowserInfo)
-		}
-		
-		if err := s.deviceRepo.Update(ctx, existingDevice); err != nil {
-			s.logger.WithError(err).Error("Failed to update existing device")
+	if err == nil {
+		return s.refreshDevice(ctx, existingDevice, req)
+	}
+	if err != domain.ErrDeviceNotFound {
+		s.logger.WithError(err).Error("Failed to look up device")
+		return nil, err
+	}
+
+	// Check if another user holds the token
+	existingDevice, err = s.deviceRepo.FindByToken(ctx, req.DeviceToken)
+	if err == nil {
+		previousUserID := existingDevice.UserID
+		existingDevice.UserID = userID
+		// Don't carry over what the previous owner called the device
+		existingDevice.DeviceName = ""
+		existingDevice.AppVersion = ""
+		existingDevice.BrowserInfo = ""
+
+		device, err := s.refreshDevice(ctx, existingDevice, req)
+		if err != nil {
 			return nil, err
 		}
-		return existingDevice, nil
+
+		s.logger.WithFields(logrus.Fields{
+			"user_id":          userID,
+			"previous_user_id": previousUserID,
+			"device_id":        device.ID,
+		}).Info("Device reassigned to new user")
+
+		return device, nil
+	}
+	if err != domain.ErrDeviceNotFound {
+		s.logger.WithError(err).Error("Failed to look up device")
+		return nil, err
 	}
 
 	// Create new device
@@ -65,6 +87,9 @@ func (s *DeviceService) RegisterDevice(ctx context.Context, userID int64, req Re
 	if req.DeviceName != "" {
 		device.SetDeviceName(req.DeviceName)
 	}
+	if req.AppVersion != "" {
+		device.SetAppVersion(req.AppVersion)
+	}
 	if req.BrowserInfo != "" {
 		device.SetBrowserInfo(req.BrowserInfo)
 	}
@@ -82,6 +107,27 @@ func (s *DeviceService) RegisterDevice(ctx context.Context, userID int64, req Re
 	return device, nil
 }
 
+// refreshDevice reactivates an already registered device and updates its metadata
+func (s *DeviceService) refreshDevice(ctx context.Context, device *domain.Device, req RegisterDeviceRequest) (*domain.Device, error) {
+	device.Activate()
+	device.UpdateLastUsed()
+	if req.DeviceName != "" {
+		device.SetDeviceName(req.DeviceName)
+	}
+	if req.AppVersion != "" {
+		device.SetAppVersion(req.AppVersion)
+	}
+	if req.BrowserInfo != "" {
+		device.SetBrowserInfo(req.BrowserInfo)
+	}
+
+	if err := s.deviceRepo.Update(ctx, device); err != nil {
+		s.logger.WithError(err).Error("Failed to update existing device")
+		return nil, err
+	}
+	return device, nil
+}
+
 // ListUserDevices returns all devices for a user
 func (s *DeviceService) ListUserDevices(ctx context.Context, userID int64) ([]*domain.Device, error) {
 	devices, err := s.deviceRepo.FindByUserID(ctx, userID)
@@ -92,7 +138,8 @@ func (s *DeviceService) ListUserDevices(ctx context.Context, userID int64) ([]*d
 	return devices, nil
 }
 
-// UnregisterDevice removes a device registration
+// UnregisterDevice removes a device registration. Other users' devices are
+// reported as not found.
 func (s *DeviceService) UnregisterDevice(ctx context.Context, userID int64, deviceID int64) error {
 	// Verify ownership
 	device, err := s.deviceRepo.FindByID(ctx, deviceID)
@@ -100,7 +147,7 @@ func (s *DeviceService) UnregisterDevice(ctx context.Context, userID int64, devi
 		return err
 	}
 	if device.UserID != userID {
-		return domain.ErrUnauthorizedAccess
+		return domain.ErrDeviceNotFound
 	}
 
 	if err := s.deviceRepo.Delete(ctx, deviceID); err != nil {
@@ -116,6 +163,43 @@ func (s *DeviceService) UnregisterDevice(ctx context.Context, userID int64, devi
 	return nil
 }
 
+// Heartbeat records that one of the user's devices is still in use, so the
+// stale-device reaper leaves it alone. A device that had already been
+// deactivated as stale is reactivated. Other users' devices are reported as
+// not found.
+func (s *DeviceService) Heartbeat(ctx context.Context, userID int64, deviceID int64) (*domain.Device, error) {
+	device, err := s.deviceRepo.FindByID(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if device.UserID != userID {
+		return nil, domain.ErrDeviceNotFound
+	}
+
+	if device.IsActive {
+		if err := s.deviceRepo.UpdateLastUsed(ctx, deviceID); err != nil {
+			s.logger.WithError(err).Error("Failed to update device last used time")
+			return nil, err
+		}
+		device.UpdateLastUsed()
+		return device, nil
+	}
+
+	device.Activate()
+	device.UpdateLastUsed()
+	if err := s.deviceRepo.Update(ctx, device); err != nil {
+		s.logger.WithError(err).Error("Failed to reactivate device")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"user_id":   userID,
+		"device_id": deviceID,
+	}).Info("Device reactivated by heartbeat")
+
+	return device, nil
+}
+
 // UnregisterByToken removes a device by token
 func (s *DeviceService) UnregisterByToken(ctx context.Context, userID int64, token string) error {
 	if err := s.deviceRepo.DeleteByToken(ctx, userID, token); err != nil {
@@ -152,3 +236,19 @@ func (s *DeviceService) DeactivateStaleDevices(ctx context.Context, staleDuratio
 
 	return count, nil
 }
+
+// DeleteInactiveDevices deletes inactive devices not used in the given duration
+func (s *DeviceService) DeleteInactiveDevices(ctx context.Context, inactiveDuration time.Duration) (int64, error) {
+	before := time.Now().Add(-inactiveDuration)
+	count, err := s.deviceRepo.DeleteInactiveDevices(ctx, before)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to delete inactive devices")
+		return 0, err
+	}
+
+	if count > 0 {
+		s.logger.WithField("count", count).Info("Deleted inactive devices")
+	}
+
+	return count, nil
+}
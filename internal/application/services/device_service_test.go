@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+)
+
+func TestDeviceService_Heartbeat_UpdatesLastUsed(t *testing.T) {
+	deviceRepo := new(MockDeviceRepository)
+	service := NewDeviceService(deviceRepo, newTestLogger())
+
+	deviceRepo.On("FindByID", mock.Anything, int64(5)).Return(&domain.Device{ID: 5, UserID: 7, IsActive: true}, nil)
+	deviceRepo.On("UpdateLastUsed", mock.Anything, int64(5)).Return(nil)
+
+	device, err := service.Heartbeat(context.Background(), 7, 5)
+
+	require.NoError(t, err)
+	assert.NotNil(t, device.LastUsedAt)
+	deviceRepo.AssertExpectations(t)
+	deviceRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestDeviceService_Heartbeat_ReactivatesStaleDevice(t *testing.T) {
+	deviceRepo := new(MockDeviceRepository)
+	service := NewDeviceService(deviceRepo, newTestLogger())
+
+	deviceRepo.On("FindByID", mock.Anything, int64(5)).Return(&domain.Device{ID: 5, UserID: 7, IsActive: false}, nil)
+	deviceRepo.On("Update", mock.Anything, mock.MatchedBy(func(d *domain.Device) bool {
+		return d.IsActive && d.LastUsedAt != nil
+	})).Return(nil)
+
+	device, err := service.Heartbeat(context.Background(), 7, 5)
+
+	require.NoError(t, err)
+	assert.True(t, device.IsActive)
+	deviceRepo.AssertExpectations(t)
+}
+
+func TestDeviceService_Heartbeat_OtherUsersDevice(t *testing.T) {
+	deviceRepo := new(MockDeviceRepository)
+	service := NewDeviceService(deviceRepo, newTestLogger())
+
+	deviceRepo.On("FindByID", mock.Anything, int64(5)).Return(&domain.Device{ID: 5, UserID: 8, IsActive: true}, nil)
+
+	_, err := service.Heartbeat(context.Background(), 7, 5)
+
+	assert.ErrorIs(t, err, domain.ErrDeviceNotFound)
+	deviceRepo.AssertNotCalled(t, "UpdateLastUsed", mock.Anything, mock.Anything)
+}
+
+func TestDeviceService_RegisterDevice_StoresMetadata(t *testing.T) {
+	deviceRepo := new(MockDeviceRepository)
+	service := NewDeviceService(deviceRepo, newTestLogger())
+
+	deviceRepo.On("FindByUserIDAndToken", mock.Anything, int64(7), "token").Return(nil, domain.ErrDeviceNotFound)
+	deviceRepo.On("FindByToken", mock.Anything, "token").Return(nil, domain.ErrDeviceNotFound)
+	deviceRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Device")).Return(nil)
+
+	device, err := service.RegisterDevice(context.Background(), 7, RegisterDeviceRequest{
+		DeviceToken: "token",
+		DeviceType:  domain.DeviceTypeIOS,
+		DeviceName:  "Work iPhone",
+		AppVersion:  "2.4.1",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.DeviceTypeIOS, device.DeviceType)
+	assert.Equal(t, "Work iPhone", device.DeviceName)
+	assert.Equal(t, "2.4.1", device.AppVersion)
+}
+
+func TestDeviceService_RegisterDevice_InvalidPlatform(t *testing.T) {
+	deviceRepo := new(MockDeviceRepository)
+	service := NewDeviceService(deviceRepo, newTestLogger())
+
+	deviceRepo.On("FindByUserIDAndToken", mock.Anything, int64(7), "token").Return(nil, domain.ErrDeviceNotFound)
+	deviceRepo.On("FindByToken", mock.Anything, "token").Return(nil, domain.ErrDeviceNotFound)
+
+	_, err := service.RegisterDevice(context.Background(), 7, RegisterDeviceRequest{DeviceToken: "token", DeviceType: "windows"})
+
+	assert.ErrorIs(t, err, domain.ErrInvalidDeviceType)
+	deviceRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestDeviceService_UnregisterDevice_OtherUsersDevice(t *testing.T) {
+	deviceRepo := new(MockDeviceRepository)
+	service := NewDeviceService(deviceRepo, newTestLogger())
+
+	deviceRepo.On("FindByID", mock.Anything, int64(5)).Return(&domain.Device{ID: 5, UserID: 8}, nil)
+
+	err := service.UnregisterDevice(context.Background(), 7, 5)
+
+	assert.ErrorIs(t, err, domain.ErrDeviceNotFound)
+	deviceRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestDeviceService_UnregisterDevice(t *testing.T) {
+	deviceRepo := new(MockDeviceRepository)
+	service := NewDeviceService(deviceRepo, newTestLogger())
+
+	deviceRepo.On("FindByID", mock.Anything, int64(5)).Return(&domain.Device{ID: 5, UserID: 7}, nil)
+	deviceRepo.On("Delete", mock.Anything, int64(5)).Return(nil)
+
+	require.NoError(t, service.UnregisterDevice(context.Background(), 7, 5))
+	deviceRepo.AssertExpectations(t)
+}
+
+func TestDeviceService_RegisterDevice_SameUserRepeat(t *testing.T) {
+	deviceRepo := new(MockDeviceRepository)
+	service := NewDeviceService(deviceRepo, newTestLogger())
+
+	existing := &domain.Device{ID: 5, UserID: 7, DeviceToken: "token", DeviceType: domain.DeviceTypeAndroid, DeviceName: "Pixel", IsActive: false}
+	deviceRepo.On("FindByUserIDAndToken", mock.Anything, int64(7), "token").Return(existing, nil)
+	deviceRepo.On("Update", mock.Anything, existing).Return(nil)
+
+	device, err := service.RegisterDevice(context.Background(), 7, RegisterDeviceRequest{
+		DeviceToken: "token",
+		DeviceType:  domain.DeviceTypeAndroid,
+		AppVersion:  "2.5.0",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), device.ID)
+	assert.True(t, device.IsActive)
+	assert.NotNil(t, device.LastUsedAt)
+	assert.Equal(t, "Pixel", device.DeviceName)
+	assert.Equal(t, "2.5.0", device.AppVersion)
+	deviceRepo.AssertNotCalled(t, "FindByToken", mock.Anything, mock.Anything)
+	deviceRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestDeviceService_RegisterDevice_ReassignsOtherUsersToken(t *testing.T) {
+	deviceRepo := new(MockDeviceRepository)
+	service := NewDeviceService(deviceRepo, newTestLogger())
+
+	existing := &domain.Device{ID: 5, UserID: 8, DeviceToken: "token", DeviceType: domain.DeviceTypeIOS, DeviceName: "Old owner's iPhone", IsActive: true}
+	deviceRepo.On("FindByUserIDAndToken", mock.Anything, int64(7), "token").Return(nil, domain.ErrDeviceNotFound)
+	deviceRepo.On("FindByToken", mock.Anything, "token").Return(existing, nil)
+	deviceRepo.On("Update", mock.Anything, mock.MatchedBy(func(d *domain.Device) bool {
+		return d.ID == 5 && d.UserID == 7
+	})).Return(nil)
+
+	device, err := service.RegisterDevice(context.Background(), 7, RegisterDeviceRequest{
+		DeviceToken: "token",
+		DeviceType:  domain.DeviceTypeIOS,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), device.UserID)
+	assert.Empty(t, device.DeviceName)
+	deviceRepo.AssertExpectations(t)
+	deviceRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+)
+
+// notificationEventBuffer is the per-subscriber channel size. Events published
+// while a subscriber's buffer is full are dropped for that subscriber.
+const notificationEventBuffer = 16
+
+// NotificationEvent is a notification delivered to connected clients in real time
+type NotificationEvent struct {
+	Type       string            `json:"type"`
+	ReminderID *int64            `json:"reminder_id,omitempty"`
+	NoteID     int64             `json:"note_id,omitempty"`
+	Title      string            `json:"title"`
+	Body       string            `json:"body"`
+	RepeatType domain.RepeatType `json:"repeat_type,omitempty"`
+	Data       map[string]string `json:"data,omitempty"`
+	SentAt     time.Time         `json:"sent_at"`
+}
+
+// NotificationBroker is an in-process pub/sub that fans notification events out
+// to every open subscription for a user
+type NotificationBroker struct {
+	mu          sync.RWMutex
+	subscribers map[int64]map[chan NotificationEvent]struct{}
+}
+
+// NewNotificationBroker creates a new notification broker
+func NewNotificationBroker() *NotificationBroker {
+	return &NotificationBroker{
+		subscribers: make(map[int64]map[chan NotificationEvent]struct{}),
+	}
+}
+
+// Subscribe registers a subscription for the user's events. The returned
+// function removes the subscription and closes the channel; it must be called
+// when the subscriber goes away.
+func (b *NotificationBroker) Subscribe(userID int64) (<-chan NotificationEvent, func()) {
+	ch := make(chan NotificationEvent, notificationEventBuffer)
+
+	b.mu.Lock()
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = make(map[chan NotificationEvent]struct{})
+	}
+	b.subscribers[userID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers[userID], ch)
+			if len(b.subscribers[userID]) == 0 {
+				delete(b.subscribers, userID)
+			}
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+
+	return ch, unsubscribe
+}
+
+// Publish sends the event to all of the user's subscriptions without blocking.
+// It returns the number of subscriptions the event was delivered to.
+func (b *NotificationBroker) Publish(userID int64, event NotificationEvent) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	delivered := 0
+	for ch := range b.subscribers[userID] {
+		select {
+		case ch <- event:
+			delivered++
+		default:
+		}
+	}
+	return delivered
+}
+
+// SubscriberCount returns the number of open subscriptions for the user
+func (b *NotificationBroker) SubscriberCount(userID int64) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subscribers[userID])
+}
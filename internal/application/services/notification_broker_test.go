@@ -0,0 +1,51 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationBroker_PublishToUserOnly(t *testing.T) {
+	broker := NewNotificationBroker()
+
+	events, unsubscribe := broker.Subscribe(7)
+	defer unsubscribe()
+	other, unsubscribeOther := broker.Subscribe(8)
+	defer unsubscribeOther()
+
+	delivered := broker.Publish(7, NotificationEvent{Type: "reminder", Title: "Stand-up"})
+
+	assert.Equal(t, 1, delivered)
+	require.Len(t, events, 1)
+	assert.Equal(t, "Stand-up", (<-events).Title)
+	assert.Len(t, other, 0)
+}
+
+func TestNotificationBroker_UnsubscribeCleansUp(t *testing.T) {
+	broker := NewNotificationBroker()
+
+	events, unsubscribe := broker.Subscribe(7)
+	assert.Equal(t, 1, broker.SubscriberCount(7))
+
+	unsubscribe()
+	unsubscribe()
+
+	assert.Equal(t, 0, broker.SubscriberCount(7))
+	_, ok := <-events
+	assert.False(t, ok, "channel should be closed")
+	assert.Equal(t, 0, broker.Publish(7, NotificationEvent{Type: "reminder"}))
+}
+
+func TestNotificationBroker_SlowSubscriberDoesNotBlock(t *testing.T) {
+	broker := NewNotificationBroker()
+
+	_, unsubscribe := broker.Subscribe(7)
+	defer unsubscribe()
+
+	for i := 0; i < notificationEventBuffer; i++ {
+		assert.Equal(t, 1, broker.Publish(7, NotificationEvent{Type: "reminder"}))
+	}
+	assert.Equal(t, 0, broker.Publish(7, NotificationEvent{Type: "reminder"}))
+}
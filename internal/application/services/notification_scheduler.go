@@ -2,12 +2,14 @@ package services
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/yourusername/notinoteapp/internal/core/domain"
 	"github.com/yourusername/notinoteapp/internal/core/ports"
+	"github.com/yourusername/notinoteapp/pkg/clock"
 	"github.com/yourusername/notinoteapp/pkg/config"
 )
 
@@ -17,12 +19,26 @@ type NotificationScheduler struct {
 	notificationSvc *NotificationService
 	config          *config.NotificationConfig
 	logger          *logrus.Logger
+	clock           clock.Clock
 	stopCh          chan struct{}
+	wakeCh          chan struct{}
 	wg              sync.WaitGroup
 	running         bool
+	paused          bool
+	pausedAt        *time.Time
 	mu              sync.Mutex
 }
 
+// SchedulerStatus is a snapshot of the scheduler's state
+type SchedulerStatus struct {
+	Running   bool
+	Paused    bool
+	PausedAt  *time.Time
+	Interval  time.Duration
+	LookAhead time.Duration
+	BatchSize int
+}
+
 // NewNotificationScheduler creates a new notification scheduler
 func NewNotificationScheduler(
 	reminderRepo ports.ReminderRepository,
@@ -35,10 +51,18 @@ func NewNotificationScheduler(
 		notificationSvc: notificationSvc,
 		config:          cfg,
 		logger:          logger,
+		clock:           clock.Real{},
 		stopCh:          make(chan struct{}),
+		wakeCh:          make(chan struct{}, 1),
 	}
 }
 
+// SetClock replaces the clock the scheduler reads the current time from.
+// Call it before Start.
+func (s *NotificationScheduler) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
 // Start begins the scheduler loop
 func (s *NotificationScheduler) Start() {
 	s.mu.Lock()
@@ -53,7 +77,10 @@ func (s *NotificationScheduler) Start() {
 	s.wg.Add(1)
 	go s.run()
 
-	s.logger.WithField("interval", s.config.SchedulerInterval).Info("Notification scheduler started")
+	s.logger.WithFields(logrus.Fields{
+		"interval":   s.config.SchedulerInterval,
+		"look_ahead": s.config.LookAhead,
+	}).Info("Notification scheduler started")
 }
 
 // Stop gracefully stops the scheduler
@@ -79,16 +106,87 @@ func (s *NotificationScheduler) IsRunning() bool {
 	return s.running
 }
 
-func (s *NotificationScheduler) run() {
-	defer s.wg.Done()
+// Pause stops the scheduler from claiming due reminders until Resume is
+// called. The loop keeps running; a batch already in progress is finished.
+func (s *NotificationScheduler) Pause() {
+	s.mu.Lock()
+	if s.paused {
+		s.mu.Unlock()
+		return
+	}
+	now := s.clock.Now()
+	s.paused = true
+	s.pausedAt = &now
+	s.mu.Unlock()
+
+	s.logger.Info("Notification scheduler paused")
+}
+
+// Resume lets a paused scheduler claim reminders again. Reminders that came
+// due while paused are still due, so they fire on the next run, which starts
+// straight away.
+func (s *NotificationScheduler) Resume() {
+	s.mu.Lock()
+	if !s.paused {
+		s.mu.Unlock()
+		return
+	}
+	pausedFor := s.clock.Now().Sub(*s.pausedAt)
+	s.paused = false
+	s.pausedAt = nil
+	s.mu.Unlock()
+
+	// Don't wait for the next tick
+	select {
+	case s.wakeCh <- struct{}{}:
+	default:
+	}
+
+	s.logger.WithField("paused_for", pausedFor).Info("Notification scheduler resumed")
+}
+
+// IsPaused returns whether the scheduler is paused
+func (s *NotificationScheduler) IsPaused() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.paused
+}
+
+// Status returns the scheduler's current state and settings
+func (s *NotificationScheduler) Status() SchedulerStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return SchedulerStatus{
+		Running:   s.running,
+		Paused:    s.paused,
+		PausedAt:  s.pausedAt,
+		Interval:  s.interval(),
+		LookAhead: s.config.LookAhead,
+		BatchSize: s.batchSize(),
+	}
+}
+
+// interval returns the configured tick interval, defaulting to 30 seconds
+func (s *NotificationScheduler) interval() time.Duration {
+	if s.config.SchedulerInterval == 0 {
+		return 30 * time.Second
+	}
+	return s.config.SchedulerInterval
+}
 
-	// Use configured interval, default to 30 seconds
-	interval := s.config.SchedulerInterval
-	if interval == 0 {
-		interval = 30 * time.Second
+// batchSize returns the most reminders to claim per run, defaulting to 100
+func (s *NotificationScheduler) batchSize() int {
+	if s.config.BatchSize == 0 {
+		return 100
 	}
+	return s.config.BatchSize
+}
+
+func (s *NotificationScheduler) run() {
+	defer s.wg.Done()
 
-	ticker := time.NewTicker(interval)
+	ticker := time.NewTicker(s.interval())
 	defer ticker.Stop()
 
 	// Process immediately on start
@@ -101,15 +199,23 @@ func (s *NotificationScheduler) run() {
 			return
 		case <-ticker.C:
 			s.processReminders()
+		case <-s.wakeCh:
+			s.processReminders()
 		}
 	}
 }
 
 func (s *NotificationScheduler) processReminders() {
+	if s.IsPaused() {
+		s.logger.Debug("Notification scheduler paused, not claiming reminders")
+		return
+	}
+
 	ctx := context.Background()
+	now := s.clock.Now()
 
-	// Find all reminders that are due
-	dueReminders, err := s.reminderRepo.FindDueReminders(ctx, time.Now(), 100)
+	// Find reminders that are due, or will be within the look-ahead window
+	dueReminders, err := s.reminderRepo.FindDueReminders(ctx, now.Add(s.config.LookAhead), s.batchSize())
 	if err != nil {
 		s.logger.WithError(err).Error("Failed to find due reminders")
 		return
@@ -130,13 +236,36 @@ func (s *NotificationScheduler) processReminders() {
 	reminderChan := make(chan *domain.Reminder, len(dueReminders))
 	var processWg sync.WaitGroup
 
+	// Reminders already due are fired at now and marked together once the
+	// workers finish
+	var triggeredMu sync.Mutex
+	triggered := make([]*domain.Reminder, 0, len(dueReminders))
+
 	// Start workers
 	for i := 0; i < workerCount; i++ {
 		processWg.Add(1)
 		go func(workerID int) {
 			defer processWg.Done()
 			for reminder := range reminderChan {
-				s.triggerReminder(ctx, reminder)
+				// Reminders claimed early are held until their exact time,
+				// then re-read in case they changed while waiting
+				if reminder.NextTriggerAt.After(now) {
+					if !s.waitUntil(reminder.NextTriggerAt) {
+						continue
+					}
+					current, ok := s.refreshReminder(ctx, reminder)
+					if !ok {
+						continue
+					}
+					s.triggerReminder(ctx, current)
+					continue
+				}
+
+				if s.fireReminder(ctx, reminder, now) {
+					triggeredMu.Lock()
+					triggered = append(triggered, reminder)
+					triggeredMu.Unlock()
+				}
 			}
 		}(i)
 	}
@@ -150,16 +279,127 @@ func (s *NotificationScheduler) processReminders() {
 	// Wait for all workers to finish
 	processWg.Wait()
 
+	s.markTriggered(ctx, triggered, now)
+
 	s.logger.WithField("processed_count", len(dueReminders)).Info("Finished processing due reminders")
 }
 
+// markTriggered persists the new schedules of reminders fired in one run
+func (s *NotificationScheduler) markTriggered(ctx context.Context, reminders []*domain.Reminder, triggeredAt time.Time) {
+	if len(reminders) == 0 {
+		return
+	}
+
+	missing, err := s.reminderRepo.MarkTriggeredBatch(ctx, reminders, triggeredAt)
+	if err != nil {
+		s.logger.WithError(err).WithField("count", len(reminders)).Error("Failed to update reminders after trigger")
+		return
+	}
+
+	if len(missing) > 0 {
+		s.logger.WithField("reminder_ids", missing).Debug("Reminders deleted before they could be marked triggered")
+	}
+}
+
+// waitUntil sleeps until t, returning false if the scheduler is stopped first.
+// Unfired reminders stay due, so the next run picks them up.
+func (s *NotificationScheduler) waitUntil(t time.Time) bool {
+	delay := t.Sub(s.clock.Now())
+	if delay <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-s.stopCh:
+		return false
+	}
+}
+
+// refreshReminder re-reads a reminder that was held until its trigger time.
+// It returns false if the reminder was deleted, disabled, snoozed or
+// rescheduled since it was claimed; anything still due is picked up again by
+// the next run.
+func (s *NotificationScheduler) refreshReminder(ctx context.Context, claimed *domain.Reminder) (*domain.Reminder, bool) {
+	logger := s.logger.WithField("reminder_id", claimed.ID)
+
+	current, err := s.reminderRepo.FindByID(ctx, claimed.ID)
+	if err != nil {
+		if errors.Is(err, domain.ErrReminderNotFound) {
+			logger.Debug("Reminder deleted while waiting to fire")
+		} else {
+			logger.WithError(err).Error("Failed to reload reminder before firing")
+		}
+		return nil, false
+	}
+
+	if !current.IsEnabled || !current.NextTriggerAt.Equal(claimed.NextTriggerAt) {
+		logger.Debug("Reminder changed while waiting to fire")
+		return nil, false
+	}
+
+	// The owner's state is only loaded when claiming
+	current.User = claimed.User
+	return current, true
+}
+
+// triggerReminder fires a single reminder now and persists its new schedule
 func (s *NotificationScheduler) triggerReminder(ctx context.Context, reminder *domain.Reminder) {
+	triggeredAt := s.clock.Now()
+	if !s.fireReminder(ctx, reminder, triggeredAt) {
+		return
+	}
+
+	// Persist the new schedule and bump the trigger count in one round-trip
+	if err := s.reminderRepo.MarkTriggered(ctx, reminder.ID, reminder.NextTriggerAt, reminder.IsEnabled, triggeredAt); err != nil {
+		s.logger.WithError(err).WithField("reminder_id", reminder.ID).Error("Failed to update reminder after trigger")
+		return
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"reminder_id":     reminder.ID,
+		"next_trigger_at": reminder.NextTriggerAt,
+		"is_enabled":      reminder.IsEnabled,
+	}).Debug("Reminder updated after trigger")
+}
+
+// fireReminder sends the reminder and advances its schedule in memory as of
+// triggeredAt. It returns false if the reminder was skipped or rescheduled
+// instead, in which case it has already been saved.
+func (s *NotificationScheduler) fireReminder(ctx context.Context, reminder *domain.Reminder, triggeredAt time.Time) bool {
 	logger := s.logger.WithFields(logrus.Fields{
 		"reminder_id": reminder.ID,
 		"note_id":     reminder.NoteID,
 		"user_id":     reminder.UserID,
 	})
 
+	// Don't notify for archived or deleted notes, but keep the schedule moving
+	if !reminder.IsNoteActive() {
+		s.skipReminder(ctx, reminder, logger, "Skipped reminder for archived or deleted note")
+		return false
+	}
+
+	// Likewise for owners who have deactivated their account
+	if !reminder.IsOwnerActive() {
+		s.skipReminder(ctx, reminder, logger, "Skipped reminder for deactivated account")
+		return false
+	}
+
+	// A relative reminder follows its note's date, which may have moved later
+	// since the reminder was claimed
+	if reminder.ResolveRelative(reminder.Note) && reminder.NextTriggerAt.After(s.clock.Now()) {
+		if err := s.reminderRepo.Update(ctx, reminder); err != nil {
+			logger.WithError(err).Error("Failed to reschedule relative reminder")
+			return false
+		}
+		logger.WithField("next_trigger_at", reminder.NextTriggerAt).Info("Relative reminder moved with its note's date")
+		return false
+	}
+
 	// Send notification
 	err := s.notificationSvc.SendReminderNotification(ctx, reminder)
 	if err != nil {
@@ -170,12 +410,7 @@ func (s *NotificationScheduler) triggerReminder(ctx context.Context, reminder *d
 	}
 
 	// Update reminder after trigger
-	reminder.UpdateNextTrigger()
-
-	// Increment trigger count
-	if err := s.reminderRepo.IncrementTriggerCount(ctx, reminder.ID); err != nil {
-		logger.WithError(err).Warn("Failed to increment trigger count")
-	}
+	reminder.UpdateNextTrigger(triggeredAt)
 
 	// Check if reminder should be disabled
 	if reminder.RepeatType == domain.RepeatTypeOnce {
@@ -186,16 +421,21 @@ func (s *NotificationScheduler) triggerReminder(ctx context.Context, reminder *d
 		reminder.Disable()
 	}
 
-	// Save updated reminder
+	return true
+}
+
+func (s *NotificationScheduler) skipReminder(ctx context.Context, reminder *domain.Reminder, logger *logrus.Entry, reason string) {
+	reminder.SkipOccurrence(s.clock.Now())
+
 	if err := s.reminderRepo.Update(ctx, reminder); err != nil {
-		logger.WithError(err).Error("Failed to update reminder after trigger")
+		logger.WithError(err).Error("Failed to update skipped reminder")
 		return
 	}
 
 	logger.WithFields(logrus.Fields{
 		"next_trigger_at": reminder.NextTriggerAt,
 		"is_enabled":      reminder.IsEnabled,
-	}).Debug("Reminder updated after trigger")
+	}).Info(reason)
 }
 
 // ProcessSingleReminder allows manual triggering of a specific reminder (for testing)
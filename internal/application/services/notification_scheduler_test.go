@@ -0,0 +1,634 @@
+package services
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+	"github.com/yourusername/notinoteapp/internal/core/ports"
+	"github.com/yourusername/notinoteapp/pkg/clock"
+	"github.com/yourusername/notinoteapp/pkg/config"
+)
+
+type MockReminderRepository struct {
+	mock.Mock
+}
+
+func (m *MockReminderRepository) Create(ctx context.Context, reminder *domain.Reminder) error {
+	args := m.Called(ctx, reminder)
+	return args.Error(0)
+}
+
+func (m *MockReminderRepository) FindByID(ctx context.Context, id int64) (*domain.Reminder, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Reminder), args.Error(1)
+}
+
+func (m *MockReminderRepository) FindByNoteID(ctx context.Context, noteID int64) ([]*domain.Reminder, error) {
+	args := m.Called(ctx, noteID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Reminder), args.Error(1)
+}
+
+func (m *MockReminderRepository) FindByUserID(ctx context.Context, userID int64, params *ports.ReminderQueryParams) ([]*domain.Reminder, error) {
+	args := m.Called(ctx, userID, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Reminder), args.Error(1)
+}
+
+func (m *MockReminderRepository) CountByUserID(ctx context.Context, userID int64, params *ports.ReminderQueryParams) (int64, error) {
+	args := m.Called(ctx, userID, params)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockReminderRepository) FindDueReminders(ctx context.Context, until time.Time, limit int) ([]*domain.Reminder, error) {
+	args := m.Called(ctx, until, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Reminder), args.Error(1)
+}
+
+func (m *MockReminderRepository) Update(ctx context.Context, reminder *domain.Reminder) error {
+	args := m.Called(ctx, reminder)
+	return args.Error(0)
+}
+
+func (m *MockReminderRepository) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockReminderRepository) DeleteByNoteID(ctx context.Context, noteID int64) error {
+	args := m.Called(ctx, noteID)
+	return args.Error(0)
+}
+
+func (m *MockReminderRepository) UpdateNextTrigger(ctx context.Context, id int64, nextTrigger time.Time, lastTriggered time.Time) error {
+	args := m.Called(ctx, id, nextTrigger, lastTriggered)
+	return args.Error(0)
+}
+
+func (m *MockReminderRepository) IncrementTriggerCount(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockReminderRepository) MarkTriggered(ctx context.Context, id int64, nextTrigger time.Time, isEnabled bool, triggeredAt time.Time) error {
+	args := m.Called(ctx, id, nextTrigger, isEnabled, triggeredAt)
+	return args.Error(0)
+}
+
+func (m *MockReminderRepository) MarkTriggeredBatch(ctx context.Context, reminders []*domain.Reminder, triggeredAt time.Time) ([]int64, error) {
+	args := m.Called(ctx, reminders, triggeredAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+func (m *MockReminderRepository) CheckOwnership(ctx context.Context, reminderID, userID int64) (bool, error) {
+	args := m.Called(ctx, reminderID, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockDeviceRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeviceRepository) Create(ctx context.Context, device *domain.Device) error {
+	args := m.Called(ctx, device)
+	return args.Error(0)
+}
+
+func (m *MockDeviceRepository) FindByID(ctx context.Context, id int64) (*domain.Device, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Device), args.Error(1)
+}
+
+func (m *MockDeviceRepository) FindByUserID(ctx context.Context, userID int64) ([]*domain.Device, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Device), args.Error(1)
+}
+
+func (m *MockDeviceRepository) FindActiveByUserID(ctx context.Context, userID int64) ([]*domain.Device, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Device), args.Error(1)
+}
+
+func (m *MockDeviceRepository) FindByToken(ctx context.Context, token string) (*domain.Device, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Device), args.Error(1)
+}
+
+func (m *MockDeviceRepository) FindByUserIDAndToken(ctx context.Context, userID int64, token string) (*domain.Device, error) {
+	args := m.Called(ctx, userID, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Device), args.Error(1)
+}
+
+func (m *MockDeviceRepository) Update(ctx context.Context, device *domain.Device) error {
+	args := m.Called(ctx, device)
+	return args.Error(0)
+}
+
+func (m *MockDeviceRepository) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockDeviceRepository) DeleteByToken(ctx context.Context, userID int64, token string) error {
+	args := m.Called(ctx, userID, token)
+	return args.Error(0)
+}
+
+func (m *MockDeviceRepository) UpdateLastUsed(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockDeviceRepository) DeactivateStaleDevices(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockDeviceRepository) DeleteInactiveDevices(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type MockNotificationLogRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationLogRepository) Create(ctx context.Context, log *domain.NotificationLog) error {
+	args := m.Called(ctx, log)
+	return args.Error(0)
+}
+
+func (m *MockNotificationLogRepository) FindByID(ctx context.Context, id int64) (*domain.NotificationLog, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.NotificationLog), args.Error(1)
+}
+
+func (m *MockNotificationLogRepository) FindByUserID(ctx context.Context, userID int64, limit, offset int) ([]*domain.NotificationLog, int64, error) {
+	args := m.Called(ctx, userID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*domain.NotificationLog), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockNotificationLogRepository) ListByUser(ctx context.Context, userID int64, params *ports.NotificationLogQueryParams) ([]*domain.NotificationLog, int64, error) {
+	args := m.Called(ctx, userID, params)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*domain.NotificationLog), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockNotificationLogRepository) FindByReminderID(ctx context.Context, reminderID int64) ([]*domain.NotificationLog, error) {
+	args := m.Called(ctx, reminderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.NotificationLog), args.Error(1)
+}
+
+func (m *MockNotificationLogRepository) FindPendingLogs(ctx context.Context, limit int) ([]*domain.NotificationLog, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.NotificationLog), args.Error(1)
+}
+
+func (m *MockNotificationLogRepository) UpdateStatus(ctx context.Context, id int64, status domain.NotificationStatus, errorMessage string) error {
+	args := m.Called(ctx, id, status, errorMessage)
+	return args.Error(0)
+}
+
+func (m *MockNotificationLogRepository) MarkAsSent(ctx context.Context, id int64, fcmMessageID string) error {
+	args := m.Called(ctx, id, fcmMessageID)
+	return args.Error(0)
+}
+
+func (m *MockNotificationLogRepository) DeleteOldLogs(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type MockNotificationPreferencesRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationPreferencesRepository) FindByUserID(ctx context.Context, userID int64) (*domain.NotificationPreferences, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.NotificationPreferences), args.Error(1)
+}
+
+func (m *MockNotificationPreferencesRepository) Upsert(ctx context.Context, prefs *domain.NotificationPreferences) error {
+	args := m.Called(ctx, prefs)
+	return args.Error(0)
+}
+
+// newDefaultPrefsRepository returns a preferences repository with no saved preferences
+func newDefaultPrefsRepository() *MockNotificationPreferencesRepository {
+	prefsRepo := new(MockNotificationPreferencesRepository)
+	prefsRepo.On("FindByUserID", mock.Anything, mock.Anything).Return(nil, domain.ErrNotificationPreferencesNotFound)
+	return prefsRepo
+}
+
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) Create(ctx context.Context, webhook *domain.Webhook) error {
+	args := m.Called(ctx, webhook)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) FindByID(ctx context.Context, id int64) (*domain.Webhook, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) FindActiveByUserID(ctx context.Context, userID int64) ([]*domain.Webhook, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Webhook), args.Error(1)
+}
+
+type MockDeadLetterRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeadLetterRepository) Create(ctx context.Context, letter *domain.DeadLetter) error {
+	args := m.Called(ctx, letter)
+	return args.Error(0)
+}
+
+func (m *MockDeadLetterRepository) FindByID(ctx context.Context, id int64) (*domain.DeadLetter, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.DeadLetter), args.Error(1)
+}
+
+func (m *MockDeadLetterRepository) List(ctx context.Context, params *ports.DeadLetterQueryParams) ([]*domain.DeadLetter, int64, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*domain.DeadLetter), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockDeadLetterRepository) UpdateReplay(ctx context.Context, letter *domain.DeadLetter) error {
+	args := m.Called(ctx, letter)
+	return args.Error(0)
+}
+
+type MockNotificationSender struct {
+	mock.Mock
+}
+
+func (m *MockNotificationSender) SendPushNotification(ctx context.Context, deviceToken, title, body string, data map[string]string) error {
+	args := m.Called(ctx, deviceToken, title, body, data)
+	return args.Error(0)
+}
+
+func (m *MockNotificationSender) SendToMultipleDevices(ctx context.Context, deviceTokens []string, title, body string, data map[string]string) error {
+	args := m.Called(ctx, deviceTokens, title, body, data)
+	return args.Error(0)
+}
+
+func newTestLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+func newTestScheduler(reminderRepo ports.ReminderRepository, deviceRepo ports.DeviceRepository, logRepo ports.NotificationLogRepository, sender ports.NotificationSender) *NotificationScheduler {
+	logger := newTestLogger()
+	notificationSvc := NewNotificationService(deviceRepo, logRepo, newDefaultPrefsRepository(), sender, logger)
+	return NewNotificationScheduler(reminderRepo, notificationSvc, &config.NotificationConfig{WorkerCount: 1}, logger)
+}
+
+func newDailyReminder(note *domain.Note) *domain.Reminder {
+	scheduledAt := time.Now().Add(-time.Minute)
+	return &domain.Reminder{
+		ID:            10,
+		NoteID:        note.ID,
+		UserID:        note.UserID,
+		Title:         "Daily review",
+		ScheduledAt:   scheduledAt,
+		RepeatType:    domain.RepeatTypeDaily,
+		IsEnabled:     true,
+		NextTriggerAt: scheduledAt,
+		Note:          note,
+	}
+}
+
+func TestNotificationScheduler_SkipsArchivedNote(t *testing.T) {
+	reminderRepo := new(MockReminderRepository)
+	deviceRepo := new(MockDeviceRepository)
+	logRepo := new(MockNotificationLogRepository)
+	sender := new(MockNotificationSender)
+
+	reminder := newDailyReminder(&domain.Note{ID: 1, UserID: 1, Title: "Archived", IsArchived: true})
+	previousTrigger := reminder.NextTriggerAt
+
+	reminderRepo.On("FindDueReminders", mock.Anything, mock.Anything, 100).Return([]*domain.Reminder{reminder}, nil)
+	reminderRepo.On("Update", mock.Anything, reminder).Return(nil)
+
+	scheduler := newTestScheduler(reminderRepo, deviceRepo, logRepo, sender)
+	scheduler.processReminders()
+
+	assert.True(t, reminder.IsEnabled)
+	assert.True(t, reminder.NextTriggerAt.After(previousTrigger))
+	assert.True(t, reminder.NextTriggerAt.After(time.Now()))
+	assert.Equal(t, 0, reminder.TriggerCount)
+	assert.Nil(t, reminder.LastTriggeredAt)
+
+	reminderRepo.AssertExpectations(t)
+	reminderRepo.AssertNotCalled(t, "MarkTriggeredBatch", mock.Anything, mock.Anything, mock.Anything)
+	deviceRepo.AssertNotCalled(t, "FindActiveByUserID", mock.Anything, mock.Anything)
+	sender.AssertNotCalled(t, "SendPushNotification", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestNotificationScheduler_SkipsDeletedOneTimeReminder(t *testing.T) {
+	reminderRepo := new(MockReminderRepository)
+	sender := new(MockNotificationSender)
+
+	reminder := newDailyReminder(&domain.Note{ID: 1, UserID: 1, Title: "Deleted", IsDeleted: true})
+	reminder.RepeatType = domain.RepeatTypeOnce
+
+	reminderRepo.On("FindDueReminders", mock.Anything, mock.Anything, 100).Return([]*domain.Reminder{reminder}, nil)
+	reminderRepo.On("Update", mock.Anything, reminder).Return(nil)
+
+	scheduler := newTestScheduler(reminderRepo, new(MockDeviceRepository), new(MockNotificationLogRepository), sender)
+	scheduler.processReminders()
+
+	assert.False(t, reminder.IsEnabled)
+
+	reminderRepo.AssertExpectations(t)
+	sender.AssertNotCalled(t, "SendPushNotification", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestNotificationScheduler_SkipsDeactivatedOwner(t *testing.T) {
+	reminderRepo := new(MockReminderRepository)
+	deviceRepo := new(MockDeviceRepository)
+	sender := new(MockNotificationSender)
+
+	reminder := newDailyReminder(&domain.Note{ID: 1, UserID: 1, Title: "Active"})
+	reminder.User = &domain.User{ID: 1, IsActive: false}
+	previousTrigger := reminder.NextTriggerAt
+
+	reminderRepo.On("FindDueReminders", mock.Anything, mock.Anything, 100).Return([]*domain.Reminder{reminder}, nil)
+	reminderRepo.On("Update", mock.Anything, reminder).Return(nil)
+
+	scheduler := newTestScheduler(reminderRepo, deviceRepo, new(MockNotificationLogRepository), sender)
+	scheduler.processReminders()
+
+	assert.True(t, reminder.IsEnabled)
+	assert.True(t, reminder.NextTriggerAt.After(previousTrigger))
+
+	reminderRepo.AssertExpectations(t)
+	deviceRepo.AssertNotCalled(t, "FindActiveByUserID", mock.Anything, mock.Anything)
+	sender.AssertNotCalled(t, "SendPushNotification", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestNotificationScheduler_SendsForActiveNote(t *testing.T) {
+	reminderRepo := new(MockReminderRepository)
+	deviceRepo := new(MockDeviceRepository)
+	logRepo := new(MockNotificationLogRepository)
+	sender := new(MockNotificationSender)
+
+	reminder := newDailyReminder(&domain.Note{ID: 1, UserID: 1, Title: "Active"})
+	device := &domain.Device{ID: 5, UserID: 1, DeviceToken: "token-1", IsActive: true}
+
+	reminderRepo.On("FindDueReminders", mock.Anything, mock.Anything, 100).Return([]*domain.Reminder{reminder}, nil)
+	reminderRepo.On("MarkTriggeredBatch", mock.Anything, []*domain.Reminder{reminder}, mock.AnythingOfType("time.Time")).Return(nil, nil)
+	deviceRepo.On("FindActiveByUserID", mock.Anything, int64(1)).Return([]*domain.Device{device}, nil)
+	deviceRepo.On("UpdateLastUsed", mock.Anything, device.ID).Return(nil)
+	logRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.NotificationLog")).Return(nil)
+	sender.On("SendPushNotification", mock.Anything, "token-1", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	scheduler := newTestScheduler(reminderRepo, deviceRepo, logRepo, sender)
+	scheduler.processReminders()
+
+	assert.True(t, reminder.IsEnabled)
+	assert.NotNil(t, reminder.LastTriggeredAt)
+
+	reminderRepo.AssertExpectations(t)
+	deviceRepo.AssertExpectations(t)
+	sender.AssertExpectations(t)
+}
+
+func TestNotificationScheduler_FrozenClockAdvancesDailyReminder(t *testing.T) {
+	reminderRepo := new(MockReminderRepository)
+	deviceRepo := new(MockDeviceRepository)
+	logRepo := new(MockNotificationLogRepository)
+	sender := new(MockNotificationSender)
+
+	now := time.Date(2025, time.March, 10, 9, 0, 30, 0, time.UTC)
+	reminder := newDailyReminder(&domain.Note{ID: 1, UserID: 1, Title: "Active"})
+	reminder.ScheduledAt = time.Date(2025, time.March, 10, 9, 0, 0, 0, time.UTC)
+	reminder.NextTriggerAt = reminder.ScheduledAt
+	expectedNext := reminder.ScheduledAt.AddDate(0, 0, 1)
+	device := &domain.Device{ID: 5, UserID: 1, DeviceToken: "token-1", IsActive: true}
+
+	reminderRepo.On("FindDueReminders", mock.Anything, now, 100).Return([]*domain.Reminder{reminder}, nil)
+	reminderRepo.On("MarkTriggeredBatch", mock.Anything, mock.MatchedBy(func(reminders []*domain.Reminder) bool {
+		return len(reminders) == 1 && reminders[0].NextTriggerAt.Equal(expectedNext) && reminders[0].IsEnabled
+	}), now).Return(nil, nil)
+	deviceRepo.On("FindActiveByUserID", mock.Anything, int64(1)).Return([]*domain.Device{device}, nil)
+	deviceRepo.On("UpdateLastUsed", mock.Anything, device.ID).Return(nil)
+	logRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.NotificationLog")).Return(nil)
+	sender.On("SendPushNotification", mock.Anything, "token-1", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	scheduler := newTestScheduler(reminderRepo, deviceRepo, logRepo, sender)
+	scheduler.SetClock(clock.NewFake(now))
+	scheduler.processReminders()
+
+	assert.Equal(t, expectedNext, reminder.NextTriggerAt)
+	require.NotNil(t, reminder.LastTriggeredAt)
+	assert.Equal(t, now, *reminder.LastTriggeredAt)
+	reminderRepo.AssertExpectations(t)
+}
+
+func TestNotificationScheduler_ReschedulesMovedRelativeReminder(t *testing.T) {
+	reminderRepo := new(MockReminderRepository)
+	sender := new(MockNotificationSender)
+
+	now := time.Date(2025, time.March, 18, 9, 0, 0, 0, time.UTC)
+	// Claimed for a due date of 19 March, since pushed back to the 25th
+	note := &domain.Note{ID: 1, UserID: 1, Title: "Launch", Properties: map[string]interface{}{"due": "2025-03-25T09:00:00Z"}}
+	reminder := newDailyReminder(note)
+	reminder.RepeatType = domain.RepeatTypeOnce
+	reminder.NextTriggerAt = now.Add(-time.Minute)
+	reminder.Relative = &domain.RelativeSchedule{PropertyID: "due", OffsetMinutes: -24 * 60}
+
+	reminderRepo.On("FindDueReminders", mock.Anything, now, 100).Return([]*domain.Reminder{reminder}, nil)
+	reminderRepo.On("Update", mock.Anything, reminder).Return(nil)
+
+	scheduler := newTestScheduler(reminderRepo, new(MockDeviceRepository), new(MockNotificationLogRepository), sender)
+	scheduler.SetClock(clock.NewFake(now))
+	scheduler.processReminders()
+
+	assert.Equal(t, time.Date(2025, time.March, 24, 9, 0, 0, 0, time.UTC), reminder.NextTriggerAt)
+	assert.True(t, reminder.IsEnabled)
+	reminderRepo.AssertExpectations(t)
+	reminderRepo.AssertNotCalled(t, "MarkTriggeredBatch", mock.Anything, mock.Anything, mock.Anything)
+	sender.AssertNotCalled(t, "SendPushNotification", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestNotificationScheduler_LookAheadWaitsForTriggerTime(t *testing.T) {
+	reminderRepo := new(MockReminderRepository)
+	deviceRepo := new(MockDeviceRepository)
+	logRepo := new(MockNotificationLogRepository)
+	sender := new(MockNotificationSender)
+
+	reminder := newDailyReminder(&domain.Note{ID: 1, UserID: 1, Title: "Active"})
+	reminder.NextTriggerAt = time.Now().Add(50 * time.Millisecond)
+	dueAt := reminder.NextTriggerAt
+	device := &domain.Device{ID: 5, UserID: 1, DeviceToken: "token-1", IsActive: true}
+
+	var sentAt time.Time
+	reminderRepo.On("FindDueReminders", mock.Anything, mock.MatchedBy(func(until time.Time) bool {
+		return !until.Before(dueAt)
+	}), 10).Return([]*domain.Reminder{reminder}, nil)
+	reminderRepo.On("FindByID", mock.Anything, reminder.ID).Return(reminder, nil)
+	reminderRepo.On("MarkTriggered", mock.Anything, reminder.ID, mock.AnythingOfType("time.Time"), true, mock.AnythingOfType("time.Time")).Return(nil)
+	deviceRepo.On("FindActiveByUserID", mock.Anything, int64(1)).Return([]*domain.Device{device}, nil)
+	deviceRepo.On("UpdateLastUsed", mock.Anything, device.ID).Return(nil)
+	logRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.NotificationLog")).Return(nil)
+	sender.On("SendPushNotification", mock.Anything, "token-1", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) { sentAt = time.Now() }).Return(nil)
+
+	scheduler := newTestScheduler(reminderRepo, deviceRepo, logRepo, sender)
+	scheduler.config.LookAhead = time.Second
+	scheduler.config.BatchSize = 10
+	scheduler.processReminders()
+
+	sender.AssertExpectations(t)
+	assert.False(t, sentAt.Before(dueAt), "reminder sent before it was due")
+}
+
+func TestNotificationScheduler_LookAheadSkipsReminderChangedWhileWaiting(t *testing.T) {
+	note := &domain.Note{ID: 1, UserID: 1, Title: "Active"}
+
+	tests := []struct {
+		name   string
+		change func(current *domain.Reminder) (*domain.Reminder, error)
+	}{
+		{"disabled", func(current *domain.Reminder) (*domain.Reminder, error) {
+			current.IsEnabled = false
+			return current, nil
+		}},
+		{"snoozed", func(current *domain.Reminder) (*domain.Reminder, error) {
+			current.NextTriggerAt = current.NextTriggerAt.Add(10 * time.Minute)
+			return current, nil
+		}},
+		{"deleted", func(current *domain.Reminder) (*domain.Reminder, error) {
+			return nil, domain.ErrReminderNotFound
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reminderRepo := new(MockReminderRepository)
+			sender := new(MockNotificationSender)
+
+			claimed := newDailyReminder(note)
+			claimed.NextTriggerAt = time.Now().Add(50 * time.Millisecond)
+			current := *claimed
+			changed, err := tt.change(&current)
+
+			reminderRepo.On("FindDueReminders", mock.Anything, mock.Anything, 10).Return([]*domain.Reminder{claimed}, nil)
+			reminderRepo.On("FindByID", mock.Anything, claimed.ID).Return(changed, err)
+
+			scheduler := newTestScheduler(reminderRepo, new(MockDeviceRepository), new(MockNotificationLogRepository), sender)
+			scheduler.config.LookAhead = time.Second
+			scheduler.config.BatchSize = 10
+			scheduler.processReminders()
+
+			reminderRepo.AssertExpectations(t)
+			reminderRepo.AssertNotCalled(t, "MarkTriggered", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			sender.AssertNotCalled(t, "SendPushNotification", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestNotificationScheduler_PauseAndResume(t *testing.T) {
+	reminderRepo := new(MockReminderRepository)
+	claimed := make(chan struct{}, 1)
+	reminderRepo.On("FindDueReminders", mock.Anything, mock.Anything, 100).
+		Run(func(mock.Arguments) { claimed <- struct{}{} }).
+		Return([]*domain.Reminder{}, nil)
+
+	scheduler := newTestScheduler(reminderRepo, new(MockDeviceRepository), new(MockNotificationLogRepository), new(MockNotificationSender))
+	scheduler.config.SchedulerInterval = time.Hour
+
+	scheduler.Pause()
+	assert.True(t, scheduler.Status().Paused)
+	assert.NotNil(t, scheduler.Status().PausedAt)
+
+	scheduler.Start()
+	defer scheduler.Stop()
+
+	scheduler.processReminders()
+	select {
+	case <-claimed:
+		t.Fatal("paused scheduler claimed reminders")
+	default:
+	}
+
+	// Resuming runs straight away rather than waiting an interval
+	scheduler.Resume()
+	assert.False(t, scheduler.IsPaused())
+	select {
+	case <-claimed:
+	case <-time.After(time.Second):
+		t.Fatal("resumed scheduler did not claim reminders")
+	}
+}
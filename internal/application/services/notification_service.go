@@ -2,35 +2,143 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/yourusername/notinoteapp/internal/core/domain"
 	"github.com/yourusername/notinoteapp/internal/core/ports"
+	"github.com/yourusername/notinoteapp/pkg/i18n"
 )
 
 // NotificationService handles sending notifications to users
 type NotificationService struct {
 	deviceRepo ports.DeviceRepository
 	logRepo    ports.NotificationLogRepository
+	prefsRepo  ports.NotificationPreferencesRepository
 	fcmSender  ports.NotificationSender
 	logger     *logrus.Logger
+
+	// Optional email channel, see SetEmailChannel
+	emailSender ports.NotificationSender
+	userRepo    ports.UserRepository
+
+	// Optional webhook channel, see SetWebhookChannel. Deliveries run in the
+	// background under webhookCtx so retries never hold up the scheduler.
+	webhookSender  ports.NotificationSender
+	webhookRepo    ports.WebhookRepository
+	webhookCtx     context.Context
+	cancelWebhooks context.CancelFunc
+	webhookWG      sync.WaitGroup
+
+	// Optional real-time stream, see SetBroker
+	broker *NotificationBroker
+
+	// Optional store for undeliverable notifications, see SetDeadLetterRepository
+	deadLetterRepo ports.DeadLetterRepository
 }
 
 // NewNotificationService creates a new notification service
 func NewNotificationService(
 	deviceRepo ports.DeviceRepository,
 	logRepo ports.NotificationLogRepository,
+	prefsRepo ports.NotificationPreferencesRepository,
 	fcmSender ports.NotificationSender,
 	logger *logrus.Logger,
 ) *NotificationService {
+	webhookCtx, cancelWebhooks := context.WithCancel(context.Background())
+
 	return &NotificationService{
-		deviceRepo: deviceRepo,
-		logRepo:    logRepo,
-		fcmSender:  fcmSender,
-		logger:     logger,
+		deviceRepo:     deviceRepo,
+		logRepo:        logRepo,
+		prefsRepo:      prefsRepo,
+		fcmSender:      fcmSender,
+		logger:         logger,
+		webhookCtx:     webhookCtx,
+		cancelWebhooks: cancelWebhooks,
+	}
+}
+
+// SetEmailChannel enables email delivery. Email is used when the user has it
+// enabled in their preferences, or as a fallback when they have no active devices.
+func (s *NotificationService) SetEmailChannel(emailSender ports.NotificationSender, userRepo ports.UserRepository) {
+	s.emailSender = emailSender
+	s.userRepo = userRepo
+}
+
+// SetUserRepository lets notifications be sent in each user's language.
+// Without it, or an email channel, everything is sent in the default language.
+func (s *NotificationService) SetUserRepository(userRepo ports.UserRepository) {
+	s.userRepo = userRepo
+}
+
+// SetWebhookChannel enables webhook delivery. Reminders are POSTed to every
+// active webhook the user has registered, in addition to the other channels.
+// Deliveries happen in the background; call Close on shutdown.
+func (s *NotificationService) SetWebhookChannel(webhookSender ports.NotificationSender, webhookRepo ports.WebhookRepository) {
+	s.webhookSender = webhookSender
+	s.webhookRepo = webhookRepo
+}
+
+// SetBroker enables real-time delivery. Reminders are published to the broker
+// for any connected stream subscribers, in addition to the other channels.
+func (s *NotificationService) SetBroker(broker *NotificationBroker) {
+	s.broker = broker
+}
+
+// SetDeadLetterRepository enables the dead-letter queue. Notifications that fail
+// on any channel are stored there so admins can inspect and replay them.
+func (s *NotificationService) SetDeadLetterRepository(deadLetterRepo ports.DeadLetterRepository) {
+	s.deadLetterRepo = deadLetterRepo
+}
+
+// Close cancels webhook deliveries that are still retrying and waits for them
+// to finish. Cancelled deliveries are dead-lettered like any other failure.
+func (s *NotificationService) Close() {
+	s.cancelWebhooks()
+	s.webhookWG.Wait()
+}
+
+// Subscribe opens a real-time subscription to the user's notifications.
+// The returned function must be called to release the subscription.
+func (s *NotificationService) Subscribe(userID int64) (<-chan NotificationEvent, func(), error) {
+	if s.broker == nil {
+		return nil, nil, domain.ErrStreamUnavailable
 	}
+	events, unsubscribe := s.broker.Subscribe(userID)
+	return events, unsubscribe, nil
+}
+
+// webhookSecretBytes is the size of generated webhook signing secrets
+const webhookSecretBytes = 32
+
+// RegisterWebhook registers a webhook URL for the user and generates its signing secret
+func (s *NotificationService) RegisterWebhook(ctx context.Context, userID int64, url string) (*domain.Webhook, error) {
+	buf := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	webhook, err := domain.NewWebhook(userID, url, hex.EncodeToString(buf))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.webhookRepo.Create(ctx, webhook); err != nil {
+		s.logger.WithError(err).WithField("user_id", userID).Error("Failed to register webhook")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"user_id":    userID,
+		"webhook_id": webhook.ID,
+	}).Info("Webhook registered")
+
+	return webhook, nil
 }
 
 // NotificationPayload represents the notification content
@@ -40,20 +148,204 @@ type NotificationPayload struct {
 	Data  map[string]string
 }
 
+// UpdateNotificationPreferencesRequest represents a request to change notification preferences.
+// Nil fields are left unchanged.
+type UpdateNotificationPreferencesRequest struct {
+	PushEnabled      *bool                `json:"push_enabled"`
+	EmailEnabled     *bool                `json:"email_enabled"`
+	MutedRepeatTypes *[]domain.RepeatType `json:"muted_repeat_types"`
+}
+
+// GetPreferences returns the user's notification preferences, or the defaults if none were saved
+func (s *NotificationService) GetPreferences(ctx context.Context, userID int64) (*domain.NotificationPreferences, error) {
+	prefs, err := s.prefsRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		if err == domain.ErrNotificationPreferencesNotFound {
+			return domain.DefaultNotificationPreferences(userID), nil
+		}
+		return nil, err
+	}
+	return prefs, nil
+}
+
+// UpdatePreferences updates the user's notification preferences
+func (s *NotificationService) UpdatePreferences(ctx context.Context, userID int64, req UpdateNotificationPreferencesRequest) (*domain.NotificationPreferences, error) {
+	prefs, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.PushEnabled != nil {
+		prefs.PushEnabled = *req.PushEnabled
+	}
+	if req.EmailEnabled != nil {
+		prefs.EmailEnabled = *req.EmailEnabled
+	}
+	if req.MutedRepeatTypes != nil {
+		if err := prefs.SetMutedRepeatTypes(*req.MutedRepeatTypes); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.prefsRepo.Upsert(ctx, prefs); err != nil {
+		s.logger.WithError(err).WithField("user_id", userID).Error("Failed to save notification preferences")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"user_id":       userID,
+		"push_enabled":  prefs.PushEnabled,
+		"email_enabled": prefs.EmailEnabled,
+	}).Info("Notification preferences updated")
+
+	return prefs, nil
+}
+
+// preferencesForSend loads preferences for delivery, falling back to defaults on error
+// so a preferences lookup failure never silently drops notifications
+func (s *NotificationService) preferencesForSend(ctx context.Context, userID int64) *domain.NotificationPreferences {
+	prefs, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		s.logger.WithError(err).WithField("user_id", userID).Warn("Failed to load notification preferences, using defaults")
+		return domain.DefaultNotificationPreferences(userID)
+	}
+	return prefs
+}
+
+// logSkipped records a notification that was not sent because of the user's preferences
+func (s *NotificationService) logSkipped(ctx context.Context, userID int64, reminderID *int64, payload *NotificationPayload, reason string) {
+	log := domain.NewNotificationLog(userID, reminderID, nil, payload.Title, payload.Body)
+	log.SetData(payload.Data)
+	log.MarkAsSkippedByPreference(reason)
+
+	if err := s.logRepo.Create(ctx, log); err != nil {
+		s.logger.WithError(err).Warn("Failed to create notification log")
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"user_id": userID,
+		"reason":  reason,
+	}).Info("Notification skipped by user preference")
+}
+
 // SendToUser sends a notification to all active devices for a user
 func (s *NotificationService) SendToUser(ctx context.Context, userID int64, reminderID *int64, payload *NotificationPayload) error {
-	// Get all active devices for the user
-	devices, err := s.deviceRepo.FindActiveByUserID(ctx, userID)
-	if err != nil {
-		s.logger.WithError(err).WithField("user_id", userID).Error("Failed to get user devices")
-		return fmt.Errorf("failed to get user devices: %w", err)
+	return s.sendToUser(ctx, userID, reminderID, payload, s.preferencesForSend(ctx, userID))
+}
+
+func (s *NotificationService) sendToUser(ctx context.Context, userID int64, reminderID *int64, payload *NotificationPayload, prefs *domain.NotificationPreferences) error {
+	pushEnabled := prefs.IsChannelEnabled(domain.NotificationChannelPush)
+
+	// Get all active devices for the user (push requires an FCM sender)
+	var devices []*domain.Device
+	if pushEnabled && s.fcmSender != nil {
+		var err error
+		devices, err = s.deviceRepo.FindActiveByUserID(ctx, userID)
+		if err != nil {
+			s.logger.WithError(err).WithField("user_id", userID).Error("Failed to get user devices")
+			return fmt.Errorf("failed to get user devices: %w", err)
+		}
 	}
 
-	if len(devices) == 0 {
-		s.logger.WithField("user_id", userID).Warn("No active devices found for user")
+	useEmail := s.emailSender != nil &&
+		(prefs.IsChannelEnabled(domain.NotificationChannelEmail) || (pushEnabled && len(devices) == 0))
+
+	if !pushEnabled && !useEmail {
+		s.logSkipped(ctx, userID, reminderID, payload, "push notifications disabled")
 		return nil
 	}
 
+	var pushErr, emailErr error
+	if len(devices) > 0 {
+		pushErr = s.sendPush(ctx, userID, reminderID, payload, devices)
+	} else if pushEnabled {
+		s.logger.WithField("user_id", userID).Warn("No active devices found for user")
+	}
+
+	if useEmail {
+		emailErr = s.sendEmail(ctx, userID, reminderID, payload)
+	}
+
+	// Only fail if no channel delivered the notification
+	if pushErr != nil && (!useEmail || emailErr != nil) {
+		return pushErr
+	}
+	if emailErr != nil && len(devices) == 0 {
+		return emailErr
+	}
+
+	return nil
+}
+
+// userLanguage returns the language to notify a user in, falling back to the
+// default if the user can't be loaded
+func (s *NotificationService) userLanguage(ctx context.Context, userID int64) string {
+	if s.userRepo == nil {
+		return i18n.Default
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		s.logger.WithError(err).WithField("user_id", userID).Warn("Failed to load user language, using the default")
+		return i18n.Default
+	}
+
+	if language, ok := i18n.Match(user.Language); ok {
+		return language
+	}
+	return i18n.Default
+}
+
+// sendEmail emails the notification to the user's account address
+func (s *NotificationService) sendEmail(ctx context.Context, userID int64, reminderID *int64, payload *NotificationPayload) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		s.logger.WithError(err).WithField("user_id", userID).Error("Failed to load user for email notification")
+		return fmt.Errorf("failed to load user: %w", err)
+	}
+
+	data := make(map[string]string, len(payload.Data)+1)
+	for k, v := range payload.Data {
+		data[k] = v
+	}
+	data["channel"] = string(domain.NotificationChannelEmail)
+
+	log := domain.NewNotificationLog(userID, reminderID, nil, payload.Title, payload.Body)
+	log.SetData(data)
+
+	if err := s.logRepo.Create(ctx, log); err != nil {
+		s.logger.WithError(err).Warn("Failed to create notification log")
+	}
+
+	if err := s.emailSender.SendPushNotification(ctx, user.Email, emailSubject(user, payload.Title, payload.Data), payload.Body, payload.Data); err != nil {
+		s.logger.WithError(err).WithField("user_id", userID).Error("Failed to send email notification")
+		if log.ID != 0 {
+			s.logRepo.UpdateStatus(ctx, log.ID, domain.NotificationStatusFailed, err.Error())
+		}
+		s.deadLetter(ctx, log, domain.NotificationChannelEmail, nil, err)
+		return fmt.Errorf("failed to send email notification: %w", err)
+	}
+
+	if log.ID != 0 {
+		s.logRepo.MarkAsSent(ctx, log.ID, "")
+	}
+
+	s.logger.WithField("user_id", userID).Info("Email notification sent")
+	return nil
+}
+
+// emailSubject returns the subject line for a notification email, prefixed in
+// the user's language for reminders
+func emailSubject(user *domain.User, title string, data map[string]string) string {
+	if data["type"] != "reminder" {
+		return title
+	}
+	language, _ := i18n.Match(user.Language)
+	return i18n.T(language, i18n.ReminderSubject, title)
+}
+
+// sendPush sends the notification to each of the given devices
+func (s *NotificationService) sendPush(ctx context.Context, userID int64, reminderID *int64, payload *NotificationPayload, devices []*domain.Device) error {
 	// Send to each device
 	var lastErr error
 	successCount := 0
@@ -86,6 +378,7 @@ func (s *NotificationService) SendToUser(ctx context.Context, userID int64, remi
 			if log.ID != 0 {
 				s.logRepo.UpdateStatus(ctx, log.ID, domain.NotificationStatusFailed, err.Error())
 			}
+			s.deadLetter(ctx, log, domain.NotificationChannelPush, device, err)
 		} else {
 			successCount++
 			// Update log with success
@@ -134,6 +427,7 @@ func (s *NotificationService) SendToDevice(ctx context.Context, device *domain.D
 		if log.ID != 0 {
 			s.logRepo.UpdateStatus(ctx, log.ID, domain.NotificationStatusFailed, err.Error())
 		}
+		s.deadLetter(ctx, log, domain.NotificationChannelPush, device, err)
 		return fmt.Errorf("failed to send notification: %w", err)
 	}
 
@@ -161,11 +455,316 @@ func (s *NotificationService) SendReminderNotification(ctx context.Context, remi
 		},
 	}
 
+	language := s.userLanguage(ctx, reminder.UserID)
+	payload.Data["language"] = language
 	if payload.Body == "" {
-		payload.Body = "You have a reminder for this note"
+		payload.Body = i18n.T(language, i18n.ReminderDefaultBody)
+	}
+
+	if options := reminder.Payload; options != nil {
+		if options.ClickURL != "" {
+			payload.Data["click_url"] = options.ClickURL
+		}
+		if options.Sound != "" {
+			payload.Data["sound"] = options.Sound
+		}
+		if options.Badge != nil {
+			payload.Data["badge"] = strconv.Itoa(*options.Badge)
+		}
+	}
+
+	prefs := s.preferencesForSend(ctx, reminder.UserID)
+	if prefs.IsMuted(reminder.RepeatType) {
+		s.logSkipped(ctx, reminder.UserID, &reminder.ID, payload, fmt.Sprintf("%s reminders muted", reminder.RepeatType))
+		return nil
+	}
+
+	s.publish(reminder, payload)
+
+	err := s.sendToUser(ctx, reminder.UserID, &reminder.ID, payload, prefs)
+
+	// Webhook failures are logged but never fail the reminder
+	s.sendWebhooks(ctx, reminder.UserID, &reminder.ID, payload)
+
+	return err
+}
+
+// publish pushes the reminder to the user's open stream subscriptions
+func (s *NotificationService) publish(reminder *domain.Reminder, payload *NotificationPayload) {
+	if s.broker == nil {
+		return
+	}
+
+	delivered := s.broker.Publish(reminder.UserID, NotificationEvent{
+		Type:       "reminder",
+		ReminderID: &reminder.ID,
+		NoteID:     reminder.NoteID,
+		Title:      payload.Title,
+		Body:       payload.Body,
+		RepeatType: reminder.RepeatType,
+		Data:       payload.Data,
+		SentAt:     time.Now().UTC(),
+	})
+
+	if delivered > 0 {
+		s.logger.WithFields(logrus.Fields{
+			"user_id":     reminder.UserID,
+			"reminder_id": reminder.ID,
+			"subscribers": delivered,
+		}).Debug("Reminder published to stream subscribers")
+	}
+}
+
+// sendWebhooks logs the notification for each of the user's active webhooks and
+// delivers it in the background, so a slow or retrying receiver cannot delay the caller
+func (s *NotificationService) sendWebhooks(ctx context.Context, userID int64, reminderID *int64, payload *NotificationPayload) {
+	if s.webhookSender == nil || s.webhookRepo == nil {
+		return
+	}
+
+	webhooks, err := s.webhookRepo.FindActiveByUserID(ctx, userID)
+	if err != nil {
+		s.logger.WithError(err).WithField("user_id", userID).Error("Failed to get user webhooks")
+		return
+	}
+
+	title, body := payload.Title, payload.Body
+	sendData := make(map[string]string, len(payload.Data))
+	for k, v := range payload.Data {
+		sendData[k] = v
+	}
+
+	for _, webhook := range webhooks {
+		data := make(map[string]string, len(sendData)+2)
+		for k, v := range sendData {
+			data[k] = v
+		}
+		data["channel"] = string(domain.NotificationChannelWebhook)
+		data["webhook_id"] = strconv.FormatInt(webhook.ID, 10)
+
+		log := domain.NewNotificationLog(userID, reminderID, nil, title, body)
+		log.SetData(data)
+
+		if err := s.logRepo.Create(ctx, log); err != nil {
+			s.logger.WithError(err).Warn("Failed to create notification log")
+		}
+
+		s.webhookWG.Add(1)
+		go func() {
+			defer s.webhookWG.Done()
+			s.deliverWebhook(webhook, log, title, body, sendData)
+		}()
+	}
+}
+
+// deliverWebhook sends one webhook notification and records the outcome on its log
+func (s *NotificationService) deliverWebhook(webhook *domain.Webhook, log *domain.NotificationLog, title, body string, data map[string]string) {
+	err := s.webhookSender.SendPushNotification(s.webhookCtx, strconv.FormatInt(webhook.ID, 10), title, body, data)
+
+	// Record the outcome even if the delivery was cancelled by Close
+	ctx := context.WithoutCancel(s.webhookCtx)
+	if err != nil {
+		s.logger.WithError(err).WithFields(logrus.Fields{
+			"user_id":    log.UserID,
+			"webhook_id": webhook.ID,
+		}).Error("Failed to send webhook notification")
+		if log.ID != 0 {
+			s.logRepo.UpdateStatus(ctx, log.ID, domain.NotificationStatusFailed, err.Error())
+		}
+		s.deadLetter(ctx, log, domain.NotificationChannelWebhook, nil, err)
+		return
+	}
+
+	if log.ID != 0 {
+		s.logRepo.MarkAsSent(ctx, log.ID, "")
+	}
+}
+
+// deadLetter stores a notification whose delivery failed, so it can be replayed
+func (s *NotificationService) deadLetter(ctx context.Context, log *domain.NotificationLog, channel domain.NotificationChannel, device *domain.Device, sendErr error) {
+	if s.deadLetterRepo == nil {
+		return
+	}
+
+	letter := domain.NewDeadLetter(log, channel, device, sendErr.Error())
+	if err := s.deadLetterRepo.Create(ctx, letter); err != nil {
+		s.logger.WithError(err).WithField("user_id", log.UserID).Warn("Failed to store dead letter")
+	}
+}
+
+// Dead letter listing limits
+const (
+	DefaultDeadLetterLimit = 20
+	MaxDeadLetterLimit     = 100
+)
+
+// ListDeadLetters returns notifications that could not be delivered, newest first
+func (s *NotificationService) ListDeadLetters(ctx context.Context, params ports.DeadLetterQueryParams) ([]*domain.DeadLetter, int64, error) {
+	if s.deadLetterRepo == nil {
+		return []*domain.DeadLetter{}, 0, nil
+	}
+
+	if params.Limit <= 0 {
+		params.Limit = DefaultDeadLetterLimit
+	}
+	if params.Limit > MaxDeadLetterLimit {
+		params.Limit = MaxDeadLetterLimit
+	}
+	if params.Offset < 0 {
+		params.Offset = 0
+	}
+
+	letters, total, err := s.deadLetterRepo.List(ctx, &params)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list dead letters")
+		return nil, 0, err
+	}
+
+	return letters, total, nil
+}
+
+// ReplayDeadLetter re-attempts delivery of a dead letter on its original
+// channel. The attempt is recorded on the dead letter either way; on failure
+// the returned error wraps domain.ErrNotificationFailed and no new dead letter
+// is created.
+func (s *NotificationService) ReplayDeadLetter(ctx context.Context, id int64) (*domain.DeadLetter, error) {
+	if s.deadLetterRepo == nil {
+		return nil, domain.ErrDeadLetterNotFound
+	}
+
+	letter, err := s.deadLetterRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if letter.IsResolved() {
+		return letter, domain.ErrDeadLetterResolved
+	}
+
+	sendErr := s.redeliver(ctx, letter)
+	letter.RecordReplay(sendErr)
+
+	if err := s.deadLetterRepo.UpdateReplay(ctx, letter); err != nil {
+		s.logger.WithError(err).WithField("dead_letter_id", id).Error("Failed to record dead letter replay")
+		return nil, err
+	}
+
+	fields := logrus.Fields{
+		"dead_letter_id": id,
+		"user_id":        letter.UserID,
+		"channel":        letter.Channel,
+		"replay_count":   letter.ReplayCount,
+	}
+	if sendErr != nil {
+		s.logger.WithError(sendErr).WithFields(fields).Warn("Dead letter replay failed")
+		return letter, fmt.Errorf("%w: %v", domain.ErrNotificationFailed, sendErr)
+	}
+
+	if letter.NotificationLogID != nil {
+		if err := s.logRepo.MarkAsSent(ctx, *letter.NotificationLogID, ""); err != nil && err != domain.ErrNotificationLogNotFound {
+			s.logger.WithError(err).WithFields(fields).Warn("Failed to mark replayed notification log as sent")
+		}
+	}
+
+	s.logger.WithFields(fields).Info("Dead letter replayed")
+	return letter, nil
+}
+
+// redeliver sends a dead letter again through the channel it failed on
+func (s *NotificationService) redeliver(ctx context.Context, letter *domain.DeadLetter) error {
+	// Strip the routing keys added when the notification was logged
+	data := make(map[string]string, len(letter.Data))
+	for k, v := range letter.Data {
+		if k != "channel" && k != "webhook_id" {
+			data[k] = v
+		}
+	}
+
+	switch letter.Channel {
+	case domain.NotificationChannelPush:
+		if s.fcmSender == nil {
+			return domain.ErrChannelUnavailable
+		}
+		if letter.DeviceID == nil {
+			return domain.ErrDeviceNotFound
+		}
+		device, err := s.deviceRepo.FindByID(ctx, *letter.DeviceID)
+		if err != nil {
+			return err
+		}
+		if !device.IsActive {
+			return domain.ErrDeviceNotFound
+		}
+		if err := s.fcmSender.SendPushNotification(ctx, device.DeviceToken, letter.Title, letter.Body, data); err != nil {
+			return err
+		}
+		s.deviceRepo.UpdateLastUsed(ctx, device.ID)
+		return nil
+
+	case domain.NotificationChannelEmail:
+		if s.emailSender == nil || s.userRepo == nil {
+			return domain.ErrChannelUnavailable
+		}
+		user, err := s.userRepo.FindByID(ctx, letter.UserID)
+		if err != nil {
+			return err
+		}
+		return s.emailSender.SendPushNotification(ctx, user.Email, emailSubject(user, letter.Title, data), letter.Body, data)
+
+	case domain.NotificationChannelWebhook:
+		if s.webhookSender == nil || s.webhookRepo == nil {
+			return domain.ErrChannelUnavailable
+		}
+		webhookID, err := strconv.ParseInt(letter.Data["webhook_id"], 10, 64)
+		if err != nil {
+			return domain.ErrWebhookNotFound
+		}
+		webhook, err := s.webhookRepo.FindByID(ctx, webhookID)
+		if err != nil {
+			return err
+		}
+		if !webhook.IsActive {
+			return domain.ErrWebhookNotFound
+		}
+		return s.webhookSender.SendPushNotification(ctx, strconv.FormatInt(webhook.ID, 10), letter.Title, letter.Body, data)
+
+	default:
+		return domain.ErrChannelUnavailable
+	}
+}
+
+// Notification log listing limits
+const (
+	DefaultNotificationLogLimit = 20
+	MaxNotificationLogLimit     = 100
+)
+
+// ListLogs returns the user's notification history matching the given filters.
+// Results are always scoped to userID regardless of params.
+func (s *NotificationService) ListLogs(ctx context.Context, userID int64, params ports.NotificationLogQueryParams) ([]*domain.NotificationLog, int64, error) {
+	if params.Status != nil && !domain.IsValidNotificationStatus(*params.Status) {
+		return nil, 0, domain.ErrInvalidNotificationStatus
+	}
+	if params.FromDate != nil && params.ToDate != nil && params.FromDate.After(*params.ToDate) {
+		return nil, 0, domain.ErrValidation
+	}
+
+	if params.Limit <= 0 {
+		params.Limit = DefaultNotificationLogLimit
+	}
+	if params.Limit > MaxNotificationLogLimit {
+		params.Limit = MaxNotificationLogLimit
+	}
+	if params.Offset < 0 {
+		params.Offset = 0
+	}
+
+	logs, total, err := s.logRepo.ListByUser(ctx, userID, &params)
+	if err != nil {
+		s.logger.WithError(err).WithField("user_id", userID).Error("Failed to list notification logs")
+		return nil, 0, err
 	}
 
-	return s.SendToUser(ctx, reminder.UserID, &reminder.ID, payload)
+	return logs, total, nil
 }
 
 // GetUserNotificationLogs returns notification logs for a user
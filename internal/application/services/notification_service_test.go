@@ -0,0 +1,449 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+	"github.com/yourusername/notinoteapp/internal/core/ports"
+)
+
+func TestNotificationService_ListLogs_ScopesToUser(t *testing.T) {
+	logRepo := new(MockNotificationLogRepository)
+	service := NewNotificationService(new(MockDeviceRepository), logRepo, new(MockNotificationPreferencesRepository), new(MockNotificationSender), newTestLogger())
+
+	failed := domain.NotificationStatusFailed
+	logs := []*domain.NotificationLog{{ID: 1, UserID: 7, Status: failed}}
+
+	logRepo.On("ListByUser", mock.Anything, int64(7), mock.MatchedBy(func(p *ports.NotificationLogQueryParams) bool {
+		return *p.Status == failed && p.Limit == DefaultNotificationLogLimit && p.Offset == 0
+	})).Return(logs, int64(1), nil)
+
+	result, total, err := service.ListLogs(context.Background(), 7, ports.NotificationLogQueryParams{Status: &failed})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, logs, result)
+	logRepo.AssertExpectations(t)
+}
+
+func TestNotificationService_ListLogs_ClampsLimit(t *testing.T) {
+	logRepo := new(MockNotificationLogRepository)
+	service := NewNotificationService(new(MockDeviceRepository), logRepo, new(MockNotificationPreferencesRepository), new(MockNotificationSender), newTestLogger())
+
+	logRepo.On("ListByUser", mock.Anything, int64(7), mock.MatchedBy(func(p *ports.NotificationLogQueryParams) bool {
+		return p.Limit == MaxNotificationLogLimit
+	})).Return([]*domain.NotificationLog{}, int64(0), nil)
+
+	_, _, err := service.ListLogs(context.Background(), 7, ports.NotificationLogQueryParams{Limit: 1000})
+
+	require.NoError(t, err)
+	logRepo.AssertExpectations(t)
+}
+
+func TestNotificationService_ListLogs_InvalidStatus(t *testing.T) {
+	logRepo := new(MockNotificationLogRepository)
+	service := NewNotificationService(new(MockDeviceRepository), logRepo, new(MockNotificationPreferencesRepository), new(MockNotificationSender), newTestLogger())
+
+	status := domain.NotificationStatus("bogus")
+	_, _, err := service.ListLogs(context.Background(), 7, ports.NotificationLogQueryParams{Status: &status})
+
+	assert.ErrorIs(t, err, domain.ErrInvalidNotificationStatus)
+	logRepo.AssertNotCalled(t, "ListByUser", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestNotificationService_SendReminderNotification_PushDisabled(t *testing.T) {
+	deviceRepo := new(MockDeviceRepository)
+	logRepo := new(MockNotificationLogRepository)
+	prefsRepo := new(MockNotificationPreferencesRepository)
+	sender := new(MockNotificationSender)
+	service := NewNotificationService(deviceRepo, logRepo, prefsRepo, sender, newTestLogger())
+
+	prefs := domain.DefaultNotificationPreferences(7)
+	prefs.PushEnabled = false
+	prefsRepo.On("FindByUserID", mock.Anything, int64(7)).Return(prefs, nil)
+	logRepo.On("Create", mock.Anything, mock.MatchedBy(func(log *domain.NotificationLog) bool {
+		return log.Status == domain.NotificationStatusSkippedByPreference
+	})).Return(nil)
+
+	reminder := &domain.Reminder{ID: 3, NoteID: 1, UserID: 7, Title: "Stand-up", RepeatType: domain.RepeatTypeDaily}
+	err := service.SendReminderNotification(context.Background(), reminder)
+
+	require.NoError(t, err)
+	logRepo.AssertExpectations(t)
+	deviceRepo.AssertNotCalled(t, "FindActiveByUserID", mock.Anything, mock.Anything)
+	sender.AssertNotCalled(t, "SendPushNotification", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestNotificationService_SendReminderNotification_MutedRepeatType(t *testing.T) {
+	deviceRepo := new(MockDeviceRepository)
+	logRepo := new(MockNotificationLogRepository)
+	prefsRepo := new(MockNotificationPreferencesRepository)
+	sender := new(MockNotificationSender)
+	service := NewNotificationService(deviceRepo, logRepo, prefsRepo, sender, newTestLogger())
+
+	prefs := domain.DefaultNotificationPreferences(7)
+	require.NoError(t, prefs.SetMutedRepeatTypes([]domain.RepeatType{domain.RepeatTypeWeekly}))
+	prefsRepo.On("FindByUserID", mock.Anything, int64(7)).Return(prefs, nil)
+	logRepo.On("Create", mock.Anything, mock.MatchedBy(func(log *domain.NotificationLog) bool {
+		return log.Status == domain.NotificationStatusSkippedByPreference
+	})).Return(nil)
+
+	reminder := &domain.Reminder{ID: 3, NoteID: 1, UserID: 7, Title: "Weekly review", RepeatType: domain.RepeatTypeWeekly}
+	err := service.SendReminderNotification(context.Background(), reminder)
+
+	require.NoError(t, err)
+	logRepo.AssertExpectations(t)
+	sender.AssertNotCalled(t, "SendPushNotification", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestNotificationService_GetPreferences_DefaultsWhenMissing(t *testing.T) {
+	prefsRepo := newDefaultPrefsRepository()
+	service := NewNotificationService(new(MockDeviceRepository), new(MockNotificationLogRepository), prefsRepo, new(MockNotificationSender), newTestLogger())
+
+	prefs, err := service.GetPreferences(context.Background(), 7)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), prefs.UserID)
+	assert.True(t, prefs.PushEnabled)
+	assert.False(t, prefs.EmailEnabled)
+	assert.Empty(t, prefs.MutedRepeatTypes)
+}
+
+func TestNotificationService_SendToUser_EmailFallbackWithoutDevices(t *testing.T) {
+	deviceRepo := new(MockDeviceRepository)
+	logRepo := new(MockNotificationLogRepository)
+	userRepo := new(MockUserRepository)
+	pushSender := new(MockNotificationSender)
+	emailSender := new(MockNotificationSender)
+	service := NewNotificationService(deviceRepo, logRepo, newDefaultPrefsRepository(), pushSender, newTestLogger())
+	service.SetEmailChannel(emailSender, userRepo)
+
+	deviceRepo.On("FindActiveByUserID", mock.Anything, int64(7)).Return([]*domain.Device{}, nil)
+	userRepo.On("FindByID", mock.Anything, int64(7)).Return(&domain.User{ID: 7, Email: "user@example.com"}, nil)
+	logRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.NotificationLog")).Return(nil)
+	emailSender.On("SendPushNotification", mock.Anything, "user@example.com", "Stand-up", "Daily stand-up", mock.Anything).Return(nil)
+
+	err := service.SendToUser(context.Background(), 7, nil, &NotificationPayload{Title: "Stand-up", Body: "Daily stand-up"})
+
+	require.NoError(t, err)
+	emailSender.AssertExpectations(t)
+	pushSender.AssertNotCalled(t, "SendPushNotification", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestNotificationService_SendReminderNotification_UserLanguage(t *testing.T) {
+	deviceRepo := new(MockDeviceRepository)
+	logRepo := new(MockNotificationLogRepository)
+	userRepo := new(MockUserRepository)
+	emailSender := new(MockNotificationSender)
+	service := NewNotificationService(deviceRepo, logRepo, newDefaultPrefsRepository(), new(MockNotificationSender), newTestLogger())
+	service.SetEmailChannel(emailSender, userRepo)
+
+	deviceRepo.On("FindActiveByUserID", mock.Anything, int64(7)).Return([]*domain.Device{}, nil)
+	userRepo.On("FindByID", mock.Anything, int64(7)).Return(&domain.User{ID: 7, Email: "user@example.com", Language: "th"}, nil)
+	logRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.NotificationLog")).Return(nil)
+	emailSender.On("SendPushNotification", mock.Anything, "user@example.com", "เตือนความจำ: Stand-up", "คุณมีการเตือนความจำสำหรับโน้ตนี้",
+		mock.MatchedBy(func(data map[string]string) bool { return data["language"] == "th" })).Return(nil)
+
+	reminder := &domain.Reminder{ID: 3, NoteID: 1, UserID: 7, Title: "Stand-up", RepeatType: domain.RepeatTypeOnce}
+	require.NoError(t, service.SendReminderNotification(context.Background(), reminder))
+
+	emailSender.AssertExpectations(t)
+}
+
+func TestNotificationService_SendToUser_EmailOnlyPreference(t *testing.T) {
+	deviceRepo := new(MockDeviceRepository)
+	logRepo := new(MockNotificationLogRepository)
+	prefsRepo := new(MockNotificationPreferencesRepository)
+	userRepo := new(MockUserRepository)
+	emailSender := new(MockNotificationSender)
+	service := NewNotificationService(deviceRepo, logRepo, prefsRepo, new(MockNotificationSender), newTestLogger())
+	service.SetEmailChannel(emailSender, userRepo)
+
+	prefs := domain.DefaultNotificationPreferences(7)
+	prefs.PushEnabled = false
+	prefs.EmailEnabled = true
+	prefsRepo.On("FindByUserID", mock.Anything, int64(7)).Return(prefs, nil)
+	userRepo.On("FindByID", mock.Anything, int64(7)).Return(&domain.User{ID: 7, Email: "user@example.com"}, nil)
+	logRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.NotificationLog")).Return(nil)
+	emailSender.On("SendPushNotification", mock.Anything, "user@example.com", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	err := service.SendToUser(context.Background(), 7, nil, &NotificationPayload{Title: "Stand-up", Body: "Daily stand-up"})
+
+	require.NoError(t, err)
+	emailSender.AssertExpectations(t)
+	deviceRepo.AssertNotCalled(t, "FindActiveByUserID", mock.Anything, mock.Anything)
+}
+
+func TestNotificationService_RegisterWebhook(t *testing.T) {
+	webhookRepo := new(MockWebhookRepository)
+	service := NewNotificationService(new(MockDeviceRepository), new(MockNotificationLogRepository), newDefaultPrefsRepository(), nil, newTestLogger())
+	service.SetWebhookChannel(new(MockNotificationSender), webhookRepo)
+
+	webhookRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Webhook")).Return(nil)
+
+	webhook, err := service.RegisterWebhook(context.Background(), 7, "https://hooks.example.com/notinote")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), webhook.UserID)
+	assert.Len(t, webhook.Secret, 64)
+	assert.True(t, webhook.IsActive)
+
+	_, err = service.RegisterWebhook(context.Background(), 7, "ftp://hooks.example.com")
+	assert.ErrorIs(t, err, domain.ErrInvalidWebhookURL)
+
+	for _, url := range []string{"http://localhost:8080/hook", "http://127.0.0.1/hook", "http://169.254.169.254/latest/meta-data", "http://[::1]/hook", "https://10.0.0.5/hook"} {
+		_, err = service.RegisterWebhook(context.Background(), 7, url)
+		assert.ErrorIs(t, err, domain.ErrWebhookURLNotAllowed, url)
+	}
+	webhookRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestNotificationService_SendReminderNotification_WebhookFailureDoesNotFailSend(t *testing.T) {
+	deviceRepo := new(MockDeviceRepository)
+	logRepo := new(MockNotificationLogRepository)
+	webhookRepo := new(MockWebhookRepository)
+	pushSender := new(MockNotificationSender)
+	webhookSender := new(MockNotificationSender)
+	service := NewNotificationService(deviceRepo, logRepo, newDefaultPrefsRepository(), pushSender, newTestLogger())
+	service.SetWebhookChannel(webhookSender, webhookRepo)
+
+	device := &domain.Device{ID: 2, UserID: 7, DeviceToken: "token"}
+	deviceRepo.On("FindActiveByUserID", mock.Anything, int64(7)).Return([]*domain.Device{device}, nil)
+	deviceRepo.On("UpdateLastUsed", mock.Anything, int64(2)).Return(nil)
+	webhookRepo.On("FindActiveByUserID", mock.Anything, int64(7)).Return([]*domain.Webhook{{ID: 5, UserID: 7}}, nil)
+	logRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.NotificationLog")).Return(nil)
+	pushSender.On("SendPushNotification", mock.Anything, "token", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	webhookSender.On("SendPushNotification", mock.Anything, "5", mock.Anything, mock.Anything, mock.Anything).Return(assert.AnError)
+
+	reminder := &domain.Reminder{ID: 3, NoteID: 1, UserID: 7, Title: "Stand-up", RepeatType: domain.RepeatTypeDaily}
+	err := service.SendReminderNotification(context.Background(), reminder)
+	service.Close()
+
+	require.NoError(t, err)
+	webhookSender.AssertExpectations(t)
+	pushSender.AssertExpectations(t)
+}
+
+func TestNotificationService_SendReminderNotification_DoesNotWaitForWebhooks(t *testing.T) {
+	logRepo := new(MockNotificationLogRepository)
+	webhookRepo := new(MockWebhookRepository)
+	webhookSender := new(MockNotificationSender)
+	deadLetterRepo := new(MockDeadLetterRepository)
+	deviceRepo := new(MockDeviceRepository)
+	service := NewNotificationService(deviceRepo, logRepo, newDefaultPrefsRepository(), new(MockNotificationSender), newTestLogger())
+	service.SetWebhookChannel(webhookSender, webhookRepo)
+	service.SetDeadLetterRepository(deadLetterRepo)
+
+	deviceRepo.On("FindActiveByUserID", mock.Anything, int64(7)).Return([]*domain.Device{}, nil)
+	webhookRepo.On("FindActiveByUserID", mock.Anything, int64(7)).Return([]*domain.Webhook{{ID: 5, UserID: 7}}, nil)
+	logRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.NotificationLog")).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.NotificationLog).ID = 12
+	}).Return(nil)
+	logRepo.On("UpdateStatus", mock.Anything, int64(12), domain.NotificationStatusFailed, context.Canceled.Error()).Return(nil)
+
+	// The receiver keeps retrying until the service is closed
+	started := make(chan struct{})
+	webhookSender.On("SendPushNotification", mock.Anything, "5", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		close(started)
+		<-args.Get(0).(context.Context).Done()
+	}).Return(context.Canceled)
+	deadLetterRepo.On("Create", mock.Anything, mock.MatchedBy(func(letter *domain.DeadLetter) bool {
+		return *letter.NotificationLogID == 12 && letter.Channel == domain.NotificationChannelWebhook
+	})).Return(nil)
+
+	reminder := &domain.Reminder{ID: 3, NoteID: 1, UserID: 7, Title: "Stand-up", RepeatType: domain.RepeatTypeDaily}
+	done := make(chan error, 1)
+	go func() { done <- service.SendReminderNotification(context.Background(), reminder) }()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("SendReminderNotification waited for the webhook delivery")
+	}
+
+	<-started
+	service.Close()
+
+	logRepo.AssertExpectations(t)
+	deadLetterRepo.AssertExpectations(t)
+}
+
+func TestNotificationService_SendReminderNotification_PublishesToStream(t *testing.T) {
+	logRepo := new(MockNotificationLogRepository)
+	prefsRepo := new(MockNotificationPreferencesRepository)
+	service := NewNotificationService(new(MockDeviceRepository), logRepo, prefsRepo, new(MockNotificationSender), newTestLogger())
+	broker := NewNotificationBroker()
+	service.SetBroker(broker)
+
+	events, unsubscribe, err := service.Subscribe(7)
+	require.NoError(t, err)
+	defer unsubscribe()
+
+	prefs := domain.DefaultNotificationPreferences(7)
+	prefs.PushEnabled = false
+	prefsRepo.On("FindByUserID", mock.Anything, int64(7)).Return(prefs, nil)
+	logRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.NotificationLog")).Return(nil)
+
+	reminder := &domain.Reminder{ID: 3, NoteID: 1, UserID: 7, Title: "Stand-up", RepeatType: domain.RepeatTypeDaily}
+	require.NoError(t, service.SendReminderNotification(context.Background(), reminder))
+
+	require.Len(t, events, 1)
+	event := <-events
+	assert.Equal(t, "reminder", event.Type)
+	assert.Equal(t, int64(3), *event.ReminderID)
+	assert.Equal(t, "Stand-up", event.Title)
+}
+
+func TestNotificationService_SendReminderNotification_Payload(t *testing.T) {
+	deviceRepo := new(MockDeviceRepository)
+	logRepo := new(MockNotificationLogRepository)
+	pushSender := new(MockNotificationSender)
+	service := NewNotificationService(deviceRepo, logRepo, newDefaultPrefsRepository(), pushSender, newTestLogger())
+
+	device := &domain.Device{ID: 2, UserID: 7, DeviceToken: "token"}
+	deviceRepo.On("FindActiveByUserID", mock.Anything, int64(7)).Return([]*domain.Device{device}, nil)
+	deviceRepo.On("UpdateLastUsed", mock.Anything, int64(2)).Return(nil)
+	logRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.NotificationLog")).Return(nil)
+
+	var sent []map[string]string
+	pushSender.On("SendPushNotification", mock.Anything, "token", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { sent = append(sent, args.Get(4).(map[string]string)) }).
+		Return(nil)
+
+	reminder := &domain.Reminder{ID: 3, NoteID: 1, UserID: 7, Title: "Stand-up", RepeatType: domain.RepeatTypeOnce}
+	require.NoError(t, service.SendReminderNotification(context.Background(), reminder))
+
+	badge := 0
+	reminder.Payload = &domain.ReminderPayload{ClickURL: "notinote://notes/1", Sound: domain.SoundNone, Badge: &badge}
+	require.NoError(t, service.SendReminderNotification(context.Background(), reminder))
+
+	require.Len(t, sent, 2)
+	assert.Equal(t, "1", sent[0]["note_id"])
+	assert.Equal(t, "/notes?id=1", sent[0]["click_url"], "defaults to opening the note")
+	assert.NotContains(t, sent[0], "sound")
+	assert.NotContains(t, sent[0], "badge")
+
+	assert.Equal(t, "1", sent[1]["note_id"])
+	assert.Equal(t, "notinote://notes/1", sent[1]["click_url"])
+	assert.Equal(t, "none", sent[1]["sound"])
+	assert.Equal(t, "0", sent[1]["badge"])
+}
+
+func TestNotificationService_Subscribe_WithoutBroker(t *testing.T) {
+	service := NewNotificationService(new(MockDeviceRepository), new(MockNotificationLogRepository), newDefaultPrefsRepository(), nil, newTestLogger())
+
+	_, _, err := service.Subscribe(7)
+
+	assert.ErrorIs(t, err, domain.ErrStreamUnavailable)
+}
+
+func TestNotificationService_SendToUser_DeadLettersFailedPush(t *testing.T) {
+	deviceRepo := new(MockDeviceRepository)
+	logRepo := new(MockNotificationLogRepository)
+	deadLetterRepo := new(MockDeadLetterRepository)
+	sender := new(MockNotificationSender)
+	service := NewNotificationService(deviceRepo, logRepo, newDefaultPrefsRepository(), sender, newTestLogger())
+	service.SetDeadLetterRepository(deadLetterRepo)
+
+	device := &domain.Device{ID: 2, UserID: 7, DeviceToken: "token", DeviceType: domain.DeviceTypeAndroid, DeviceName: "Pixel"}
+	deviceRepo.On("FindActiveByUserID", mock.Anything, int64(7)).Return([]*domain.Device{device}, nil)
+	logRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.NotificationLog")).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.NotificationLog).ID = 11
+	}).Return(nil)
+	logRepo.On("UpdateStatus", mock.Anything, int64(11), domain.NotificationStatusFailed, assert.AnError.Error()).Return(nil)
+	sender.On("SendPushNotification", mock.Anything, "token", mock.Anything, mock.Anything, mock.Anything).Return(assert.AnError)
+	deadLetterRepo.On("Create", mock.Anything, mock.MatchedBy(func(letter *domain.DeadLetter) bool {
+		return *letter.NotificationLogID == 11 && *letter.DeviceID == 2 &&
+			letter.Channel == domain.NotificationChannelPush &&
+			letter.DeviceType == domain.DeviceTypeAndroid && letter.DeviceName == "Pixel" &&
+			letter.ErrorMessage == assert.AnError.Error()
+	})).Return(nil)
+
+	err := service.SendToUser(context.Background(), 7, nil, &NotificationPayload{Title: "Hi"})
+
+	assert.Error(t, err)
+	deadLetterRepo.AssertExpectations(t)
+}
+
+func TestNotificationService_ReplayDeadLetter_Push(t *testing.T) {
+	deviceRepo := new(MockDeviceRepository)
+	logRepo := new(MockNotificationLogRepository)
+	deadLetterRepo := new(MockDeadLetterRepository)
+	sender := new(MockNotificationSender)
+	service := NewNotificationService(deviceRepo, logRepo, new(MockNotificationPreferencesRepository), sender, newTestLogger())
+	service.SetDeadLetterRepository(deadLetterRepo)
+
+	logID, deviceID := int64(11), int64(2)
+	letter := &domain.DeadLetter{
+		ID:                4,
+		NotificationLogID: &logID,
+		UserID:            7,
+		DeviceID:          &deviceID,
+		Channel:           domain.NotificationChannelPush,
+		Title:             "Hi",
+		Data:              map[string]string{"type": "reminder"},
+		ErrorMessage:      "unavailable",
+	}
+	deadLetterRepo.On("FindByID", mock.Anything, int64(4)).Return(letter, nil)
+	deadLetterRepo.On("UpdateReplay", mock.Anything, letter).Return(nil)
+	deviceRepo.On("FindByID", mock.Anything, int64(2)).Return(&domain.Device{ID: 2, DeviceToken: "token", IsActive: true}, nil)
+	deviceRepo.On("UpdateLastUsed", mock.Anything, int64(2)).Return(nil)
+	sender.On("SendPushNotification", mock.Anything, "token", "Hi", "", map[string]string{"type": "reminder"}).Return(nil)
+	logRepo.On("MarkAsSent", mock.Anything, int64(11), "").Return(nil)
+
+	replayed, err := service.ReplayDeadLetter(context.Background(), 4)
+
+	require.NoError(t, err)
+	assert.True(t, replayed.IsResolved())
+	assert.Equal(t, 1, replayed.ReplayCount)
+	sender.AssertExpectations(t)
+	logRepo.AssertExpectations(t)
+}
+
+func TestNotificationService_ReplayDeadLetter_FailureIsRecorded(t *testing.T) {
+	deadLetterRepo := new(MockDeadLetterRepository)
+	webhookRepo := new(MockWebhookRepository)
+	webhookSender := new(MockNotificationSender)
+	service := NewNotificationService(new(MockDeviceRepository), new(MockNotificationLogRepository), new(MockNotificationPreferencesRepository), nil, newTestLogger())
+	service.SetWebhookChannel(webhookSender, webhookRepo)
+	service.SetDeadLetterRepository(deadLetterRepo)
+
+	letter := &domain.DeadLetter{
+		ID:      4,
+		UserID:  7,
+		Channel: domain.NotificationChannelWebhook,
+		Title:   "Hi",
+		Data:    map[string]string{"channel": "webhook", "webhook_id": "5"},
+	}
+	deadLetterRepo.On("FindByID", mock.Anything, int64(4)).Return(letter, nil)
+	deadLetterRepo.On("UpdateReplay", mock.Anything, letter).Return(nil)
+	webhookRepo.On("FindByID", mock.Anything, int64(5)).Return(&domain.Webhook{ID: 5, IsActive: true}, nil)
+	webhookSender.On("SendPushNotification", mock.Anything, "5", "Hi", "", map[string]string{}).Return(assert.AnError)
+
+	replayed, err := service.ReplayDeadLetter(context.Background(), 4)
+
+	assert.ErrorIs(t, err, domain.ErrNotificationFailed)
+	assert.False(t, replayed.IsResolved())
+	assert.Equal(t, 1, replayed.ReplayCount)
+	assert.Equal(t, assert.AnError.Error(), replayed.ErrorMessage)
+	deadLetterRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestNotificationService_ReplayDeadLetter_AlreadyResolved(t *testing.T) {
+	deadLetterRepo := new(MockDeadLetterRepository)
+	service := NewNotificationService(new(MockDeviceRepository), new(MockNotificationLogRepository), new(MockNotificationPreferencesRepository), new(MockNotificationSender), newTestLogger())
+	service.SetDeadLetterRepository(deadLetterRepo)
+
+	now := time.Now()
+	deadLetterRepo.On("FindByID", mock.Anything, int64(4)).Return(&domain.DeadLetter{ID: 4, ResolvedAt: &now}, nil)
+
+	_, err := service.ReplayDeadLetter(context.Background(), 4)
+
+	assert.ErrorIs(t, err, domain.ErrDeadLetterResolved)
+	deadLetterRepo.AssertNotCalled(t, "UpdateReplay", mock.Anything, mock.Anything)
+}
@@ -2,52 +2,85 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/yourusername/notinoteapp/internal/core/domain"
 	"github.com/yourusername/notinoteapp/internal/core/ports"
+	"github.com/yourusername/notinoteapp/pkg/clock"
 )
 
 // ReminderService handles reminder CRUD operations
 type ReminderService struct {
-	reminderRepo ports.ReminderRepository
-	noteRepo     ports.NoteRepository
-	logger       *logrus.Logger
+	reminderRepo      ports.ReminderRepository
+	noteRepo          ports.NoteRepository
+	calendarFeedRepo  ports.CalendarFeedRepository
+	maxSnoozes        int
+	scheduleTolerance time.Duration
+	logger            *logrus.Logger
+	clock             clock.Clock
 }
 
 // NewReminderService creates a new reminder service
 func NewReminderService(
 	reminderRepo ports.ReminderRepository,
 	noteRepo ports.NoteRepository,
+	maxSnoozes int,
 	logger *logrus.Logger,
 ) *ReminderService {
 	return &ReminderService{
-		reminderRepo: reminderRepo,
-		noteRepo:     noteRepo,
-		logger:       logger,
+		reminderRepo:      reminderRepo,
+		noteRepo:          noteRepo,
+		maxSnoozes:        maxSnoozes,
+		scheduleTolerance: domain.DefaultScheduleTolerance,
+		logger:            logger,
+		clock:             clock.Real{},
 	}
 }
 
+// SetScheduleTolerance sets how far in the past a reminder may be scheduled
+// and still be accepted
+func (s *ReminderService) SetScheduleTolerance(tolerance time.Duration) {
+	s.scheduleTolerance = tolerance
+}
+
+// SetClock replaces the clock used for snoozes and trigger calculations
+func (s *ReminderService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// SetCalendarFeedRepository enables feed-only tokens for the calendar export
+func (s *ReminderService) SetCalendarFeedRepository(repo ports.CalendarFeedRepository) {
+	s.calendarFeedRepo = repo
+}
+
 // CreateReminderRequest represents a request to create a reminder
 type CreateReminderRequest struct {
-	Title        string               `json:"title" binding:"required"`
-	Message      string               `json:"message"`
-	ScheduledAt  time.Time            `json:"scheduled_at" binding:"required"`
-	RepeatType   domain.RepeatType    `json:"repeat_type"`
-	RepeatConfig *domain.RepeatConfig `json:"repeat_config"`
-	RepeatEndAt  *time.Time           `json:"repeat_end_at"`
+	Title        string                   `json:"title" binding:"required"`
+	Message      string                   `json:"message"`
+	ScheduledAt  time.Time                `json:"scheduled_at" binding:"required"`
+	RepeatType   domain.RepeatType        `json:"repeat_type"`
+	RepeatConfig *domain.RepeatConfig     `json:"repeat_config"`
+	RepeatEndAt  *time.Time               `json:"repeat_end_at"`
+	Payload      *domain.ReminderPayload  `json:"payload"`
+	Relative     *domain.RelativeSchedule `json:"relative"` // ScheduledAt is the fallback while the note has no such date
 }
 
 // UpdateReminderRequest represents a request to update a reminder
 type UpdateReminderRequest struct {
-	Title        *string              `json:"title"`
-	Message      *string              `json:"message"`
-	ScheduledAt  *time.Time           `json:"scheduled_at"`
-	RepeatType   *domain.RepeatType   `json:"repeat_type"`
-	RepeatConfig *domain.RepeatConfig `json:"repeat_config"`
-	RepeatEndAt  *time.Time           `json:"repeat_end_at"`
-	IsEnabled    *bool                `json:"is_enabled"`
+	Title        *string                  `json:"title"`
+	Message      *string                  `json:"message"`
+	ScheduledAt  *time.Time               `json:"scheduled_at"`
+	RepeatType   *domain.RepeatType       `json:"repeat_type"`
+	RepeatConfig *domain.RepeatConfig     `json:"repeat_config"`
+	RepeatEndAt  *time.Time               `json:"repeat_end_at"`
+	IsEnabled    *bool                    `json:"is_enabled"`
+	Payload      *domain.ReminderPayload  `json:"payload"`  // nil leaves it unchanged; empty restores the defaults
+	Relative     *domain.RelativeSchedule `json:"relative"` // nil leaves it unchanged; an empty property ID makes it absolute
 }
 
 // CreateReminder creates a new reminder for a note
@@ -59,24 +92,21 @@ func (s *ReminderService) CreateReminder(ctx context.Context, userID int64, note
 		return nil, err
 	}
 	if !isOwner {
-		return nil, domain.ErrUnauthorizedAccess
+		// Other users' notes are indistinguishable from missing ones
+		return nil, domain.ErrNoteNotFound
 	}
 
-	// Create reminder
-	reminder, err := domain.NewReminder(noteID, userID, req.Title, req.ScheduledAt)
+	reminder, err := newReminderFromRequest(noteID, userID, req, s.scheduleTolerance, s.clock.Now())
 	if err != nil {
 		return nil, err
 	}
 
-	if req.Message != "" {
-		reminder.UpdateMessage(req.Message)
+	if err := s.resolveRelative(ctx, reminder); err != nil {
+		return nil, err
 	}
 
-	// Set repeat configuration if provided
-	if req.RepeatType != "" && req.RepeatType != domain.RepeatTypeOnce {
-		if err := reminder.SetRepeat(req.RepeatType, req.RepeatConfig, req.RepeatEndAt); err != nil {
-			return nil, err
-		}
+	if err := reminder.EnsureNextTrigger(); err != nil {
+		return nil, err
 	}
 
 	if err := s.reminderRepo.Create(ctx, reminder); err != nil {
@@ -93,6 +123,100 @@ func (s *ReminderService) CreateReminder(ctx context.Context, userID int64, note
 	return reminder, nil
 }
 
+// MaxPreviewOccurrences caps how many trigger times a preview returns
+const MaxPreviewOccurrences = 50
+
+// PreviewOccurrences returns the next count times a reminder built from req
+// would fire, without saving anything
+func (s *ReminderService) PreviewOccurrences(ctx context.Context, req CreateReminderRequest, count int) ([]time.Time, error) {
+	reminder, err := newReminderFromRequest(0, 0, req, s.scheduleTolerance, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if count > MaxPreviewOccurrences {
+		count = MaxPreviewOccurrences
+	}
+	return reminder.NextOccurrences(count), nil
+}
+
+// newReminderFromRequest builds and validates an unsaved reminder
+func newReminderFromRequest(noteID, userID int64, req CreateReminderRequest, tolerance time.Duration, now time.Time) (*domain.Reminder, error) {
+	reminder, err := domain.NewReminder(noteID, userID, req.Title, req.ScheduledAt, tolerance, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Message != "" {
+		reminder.UpdateMessage(req.Message)
+	}
+
+	// Set repeat configuration if provided
+	if req.RepeatType != "" && req.RepeatType != domain.RepeatTypeOnce {
+		if err := reminder.SetRepeat(req.RepeatType, req.RepeatConfig, req.RepeatEndAt); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Payload != nil {
+		if err := reminder.SetPayload(req.Payload); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Relative != nil {
+		if err := reminder.SetRelative(req.Relative); err != nil {
+			return nil, err
+		}
+	}
+
+	return reminder, nil
+}
+
+// resolveRelative sets a relative reminder's trigger time from its note,
+// rejecting an enabled one that resolves to a time already past
+func (s *ReminderService) resolveRelative(ctx context.Context, reminder *domain.Reminder) error {
+	if reminder.Relative == nil {
+		return nil
+	}
+
+	note, err := s.noteRepo.FindByID(ctx, reminder.NoteID)
+	if err != nil {
+		return err
+	}
+	reminder.ResolveRelative(note)
+
+	if reminder.IsEnabled && reminder.NextTriggerAt.Before(s.clock.Now().Add(-s.scheduleTolerance)) {
+		return domain.ErrInvalidScheduleTime
+	}
+	return nil
+}
+
+// RescheduleForNote re-resolves the note's relative reminders after its
+// properties changed
+func (s *ReminderService) RescheduleForNote(ctx context.Context, note *domain.Note) {
+	reminders, err := s.reminderRepo.FindByNoteID(ctx, note.ID)
+	if err != nil {
+		s.logger.WithError(err).WithField("note_id", note.ID).Error("Failed to load reminders to reschedule")
+		return
+	}
+
+	for _, reminder := range reminders {
+		if !reminder.ResolveRelative(note) {
+			continue
+		}
+		if err := s.reminderRepo.Update(ctx, reminder); err != nil {
+			s.logger.WithError(err).WithField("reminder_id", reminder.ID).Error("Failed to reschedule relative reminder")
+			continue
+		}
+		s.logger.WithFields(logrus.Fields{
+			"note_id":         note.ID,
+			"reminder_id":     reminder.ID,
+			"next_trigger_at": reminder.NextTriggerAt,
+		}).Info("Relative reminder rescheduled")
+	}
+}
+
 // GetReminder gets a reminder by ID
 func (s *ReminderService) GetReminder(ctx context.Context, userID int64, reminderID int64) (*domain.Reminder, error) {
 	reminder, err := s.reminderRepo.FindByID(ctx, reminderID)
@@ -107,14 +231,154 @@ func (s *ReminderService) GetReminder(ctx context.Context, userID int64, reminde
 	return reminder, nil
 }
 
-// ListUserReminders returns all reminders for a user
-func (s *ReminderService) ListUserReminders(ctx context.Context, userID int64, params *ports.ReminderQueryParams) ([]*domain.Reminder, error) {
+// Reminder listing limits
+const (
+	DefaultReminderListLimit = 20
+	MaxReminderListLimit     = 100
+)
+
+// ListUserReminders returns a page of a user's reminders and how many match
+// the filters in total
+func (s *ReminderService) ListUserReminders(ctx context.Context, userID int64, params *ports.ReminderQueryParams) ([]*domain.Reminder, int64, error) {
+	total, err := s.reminderRepo.CountByUserID(ctx, userID, params)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to count user reminders")
+		return nil, 0, err
+	}
+
 	reminders, err := s.reminderRepo.FindByUserID(ctx, userID, params)
 	if err != nil {
 		s.logger.WithError(err).Error("Failed to list user reminders")
+		return nil, 0, err
+	}
+	return reminders, total, nil
+}
+
+// MaxUpcomingDays is the widest window UpcomingReminders looks ahead
+const MaxUpcomingDays = 31
+
+// maxOccurrencesPerReminder bounds how many times one reminder is listed in a
+// window; a daily reminder fires at most MaxUpcomingDays+1 times in it
+const maxOccurrencesPerReminder = MaxUpcomingDays + 1
+
+// UpcomingOccurrence is one time a reminder will fire
+type UpcomingOccurrence struct {
+	TriggerAt time.Time
+	Reminder  *domain.Reminder
+}
+
+// UpcomingReminders returns every time the user's enabled reminders will fire
+// in the next days, recurring ones expanded, sorted by trigger time. Expired
+// reminders and those on archived or deleted notes are left out.
+func (s *ReminderService) UpcomingReminders(ctx context.Context, userID int64, days int) ([]UpcomingOccurrence, error) {
+	if days > MaxUpcomingDays {
+		days = MaxUpcomingDays
+	}
+
+	enabled := true
+	until := s.clock.Now().AddDate(0, 0, days)
+	reminders, err := s.reminderRepo.FindByUserID(ctx, userID, &ports.ReminderQueryParams{
+		IsEnabled:   &enabled,
+		ToDate:      &until,
+		IncludeNote: true,
+	})
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list upcoming reminders")
 		return nil, err
 	}
-	return reminders, nil
+
+	occurrences := []UpcomingOccurrence{}
+	for _, reminder := range reminders {
+		// Notes are loaded with the reminders, so a missing one has been deleted
+		if reminder.Note == nil || !reminder.IsNoteActive() || reminder.IsExpired() {
+			continue
+		}
+		for _, at := range reminder.OccurrencesUntil(until, maxOccurrencesPerReminder) {
+			occurrences = append(occurrences, UpcomingOccurrence{TriggerAt: at, Reminder: reminder})
+		}
+	}
+
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].TriggerAt.Before(occurrences[j].TriggerAt)
+	})
+
+	return occurrences, nil
+}
+
+// CalendarReminders returns the user's enabled reminders whose notes are still
+// active, with the notes loaded, for exporting as a calendar feed
+func (s *ReminderService) CalendarReminders(ctx context.Context, userID int64) ([]*domain.Reminder, error) {
+	enabled := true
+	reminders, err := s.reminderRepo.FindByUserID(ctx, userID, &ports.ReminderQueryParams{
+		IsEnabled:   &enabled,
+		IncludeNote: true,
+	})
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list calendar reminders")
+		return nil, err
+	}
+
+	active := make([]*domain.Reminder, 0, len(reminders))
+	for _, reminder := range reminders {
+		// Notes are loaded with the reminders, so a missing one has been deleted
+		if reminder.Note == nil || !reminder.IsNoteActive() {
+			continue
+		}
+		active = append(active, reminder)
+	}
+	return active, nil
+}
+
+// calendarFeedTokenBytes is the size of generated calendar feed tokens
+const calendarFeedTokenBytes = 32
+
+// CreateCalendarFeedToken generates a token that only grants access to the
+// user's calendar feed, replacing any previous one. The token is returned once;
+// only its hash is stored.
+func (s *ReminderService) CreateCalendarFeedToken(ctx context.Context, userID int64) (string, error) {
+	if s.calendarFeedRepo == nil {
+		return "", domain.ErrCalendarFeedUnavailable
+	}
+
+	buf := make([]byte, calendarFeedTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate calendar feed token: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+
+	if err := s.calendarFeedRepo.Save(ctx, domain.NewCalendarFeed(userID, token)); err != nil {
+		s.logger.WithError(err).WithField("user_id", userID).Error("Failed to save calendar feed")
+		return "", err
+	}
+
+	return token, nil
+}
+
+// RevokeCalendarFeedToken invalidates the user's calendar feed token
+func (s *ReminderService) RevokeCalendarFeedToken(ctx context.Context, userID int64) error {
+	if s.calendarFeedRepo == nil {
+		return domain.ErrCalendarFeedUnavailable
+	}
+
+	if err := s.calendarFeedRepo.DeleteByUserID(ctx, userID); err != nil {
+		s.logger.WithError(err).WithField("user_id", userID).Error("Failed to revoke calendar feed")
+		return err
+	}
+	return nil
+}
+
+// ResolveCalendarFeedToken returns the ID of the user a calendar feed token
+// belongs to, or domain.ErrCalendarFeedNotFound
+func (s *ReminderService) ResolveCalendarFeedToken(ctx context.Context, token string) (int64, error) {
+	if s.calendarFeedRepo == nil {
+		return 0, domain.ErrCalendarFeedNotFound
+	}
+
+	feed, err := s.calendarFeedRepo.FindByTokenHash(ctx, domain.HashCalendarFeedToken(token))
+	if err != nil {
+		return 0, err
+	}
+	return feed.UserID, nil
 }
 
 // ListNoteReminders returns all reminders for a note
@@ -126,7 +390,8 @@ func (s *ReminderService) ListNoteReminders(ctx context.Context, userID int64, n
 		return nil, err
 	}
 	if !isOwner {
-		return nil, domain.ErrUnauthorizedAccess
+		// Other users' notes are indistinguishable from missing ones
+		return nil, domain.ErrNoteNotFound
 	}
 
 	reminders, err := s.reminderRepo.FindByNoteID(ctx, noteID)
@@ -162,7 +427,16 @@ func (s *ReminderService) UpdateReminder(ctx context.Context, userID int64, remi
 	}
 
 	if req.ScheduledAt != nil {
-		if err := reminder.UpdateScheduledAt(*req.ScheduledAt); err != nil {
+		if err := reminder.UpdateScheduledAt(*req.ScheduledAt, s.scheduleTolerance, s.clock.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	// Only one-time reminders can be relative, so a relative schedule is
+	// cleared before the repeat changes and set after it
+	clearRelative := req.Relative != nil && req.Relative.PropertyID == ""
+	if clearRelative {
+		if err := reminder.SetRelative(nil); err != nil {
 			return nil, err
 		}
 	}
@@ -173,6 +447,18 @@ func (s *ReminderService) UpdateReminder(ctx context.Context, userID int64, remi
 		}
 	}
 
+	if req.Relative != nil && !clearRelative {
+		if err := reminder.SetRelative(req.Relative); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Payload != nil {
+		if err := reminder.SetPayload(req.Payload); err != nil {
+			return nil, err
+		}
+	}
+
 	if req.IsEnabled != nil {
 		if *req.IsEnabled {
 			reminder.Enable()
@@ -181,6 +467,17 @@ func (s *ReminderService) UpdateReminder(ctx context.Context, userID int64, remi
 		}
 	}
 
+	// A new fallback time or property moves a relative reminder
+	if req.ScheduledAt != nil || req.Relative != nil {
+		if err := s.resolveRelative(ctx, reminder); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := reminder.EnsureNextTrigger(); err != nil {
+		return nil, err
+	}
+
 	if err := s.reminderRepo.Update(ctx, reminder); err != nil {
 		s.logger.WithError(err).Error("Failed to update reminder")
 		return nil, err
@@ -256,7 +553,9 @@ func (s *ReminderService) SnoozeReminder(ctx context.Context, userID int64, remi
 		return nil, domain.ErrReminderAccessDenied
 	}
 
-	reminder.Snooze(duration)
+	if err := reminder.Snooze(duration, s.maxSnoozes, s.clock.Now()); err != nil {
+		return nil, err
+	}
 
 	if err := s.reminderRepo.Update(ctx, reminder); err != nil {
 		s.logger.WithError(err).Error("Failed to snooze reminder")
@@ -267,6 +566,7 @@ func (s *ReminderService) SnoozeReminder(ctx context.Context, userID int64, remi
 		"user_id":         userID,
 		"reminder_id":     reminderID,
 		"next_trigger_at": reminder.NextTriggerAt,
+		"snooze_count":    reminder.SnoozeCount,
 	}).Info("Reminder snoozed successfully")
 
 	return reminder, nil
@@ -274,21 +574,18 @@ func (s *ReminderService) SnoozeReminder(ctx context.Context, userID int64, remi
 
 // FindDueReminders finds reminders that are due for triggering
 func (s *ReminderService) FindDueReminders(ctx context.Context, limit int) ([]*domain.Reminder, error) {
-	return s.reminderRepo.FindDueReminders(ctx, time.Now(), limit)
+	return s.reminderRepo.FindDueReminders(ctx, s.clock.Now(), limit)
 }
 
 // MarkReminderTriggered updates a reminder after it has been triggered
 func (s *ReminderService) MarkReminderTriggered(ctx context.Context, reminder *domain.Reminder) error {
-	reminder.UpdateNextTrigger()
+	now := s.clock.Now()
+	reminder.UpdateNextTrigger(now)
 
-	if err := s.reminderRepo.Update(ctx, reminder); err != nil {
+	if err := s.reminderRepo.MarkTriggered(ctx, reminder.ID, reminder.NextTriggerAt, reminder.IsEnabled, now); err != nil {
 		s.logger.WithError(err).Error("Failed to update reminder after trigger")
 		return err
 	}
 
-	if err := s.reminderRepo.IncrementTriggerCount(ctx, reminder.ID); err != nil {
-		s.logger.WithError(err).Warn("Failed to increment trigger count")
-	}
-
 	return nil
 }
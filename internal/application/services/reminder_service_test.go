@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+	"github.com/yourusername/notinoteapp/internal/core/ports"
+	"github.com/yourusername/notinoteapp/pkg/clock"
+)
+
+func TestReminderService_UpcomingReminders(t *testing.T) {
+	start := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+	note := &domain.Note{ID: 1, Title: "Groceries"}
+
+	daily := &domain.Reminder{ID: 1, Title: "Daily", RepeatType: domain.RepeatTypeDaily, ScheduledAt: start, NextTriggerAt: start, IsEnabled: true, Note: note}
+	once := &domain.Reminder{ID: 2, Title: "Once", RepeatType: domain.RepeatTypeOnce, ScheduledAt: start.Add(30 * time.Hour), NextTriggerAt: start.Add(30 * time.Hour), IsEnabled: true, Note: note}
+	expired := &domain.Reminder{ID: 3, Title: "Expired", RepeatType: domain.RepeatTypeDaily, ScheduledAt: start, NextTriggerAt: start, RepeatEndAt: &past, IsEnabled: true, Note: note}
+	trashed := &domain.Reminder{ID: 4, Title: "Trashed", RepeatType: domain.RepeatTypeOnce, ScheduledAt: start, NextTriggerAt: start, IsEnabled: true, Note: &domain.Note{ID: 2, IsDeleted: true}}
+	orphaned := &domain.Reminder{ID: 5, Title: "Orphaned", RepeatType: domain.RepeatTypeOnce, ScheduledAt: start, NextTriggerAt: start, IsEnabled: true}
+
+	reminderRepo := new(MockReminderRepository)
+	reminderRepo.On("FindByUserID", mock.Anything, int64(7), mock.MatchedBy(func(params *ports.ReminderQueryParams) bool {
+		return params.IsEnabled != nil && *params.IsEnabled && params.ToDate != nil && params.IncludeNote
+	})).Return([]*domain.Reminder{daily, once, expired, trashed, orphaned}, nil)
+
+	service := NewReminderService(reminderRepo, nil, 0, newTestLogger())
+
+	upcoming, err := service.UpcomingReminders(context.Background(), 7, 2)
+
+	require.NoError(t, err)
+	var titles []string
+	for _, occurrence := range upcoming {
+		titles = append(titles, occurrence.Reminder.Title)
+	}
+	// Daily fires in 1h and 25h (49h is past the window), Once in 31h
+	assert.Equal(t, []string{"Daily", "Daily", "Once"}, titles)
+	assert.Equal(t, start, upcoming[0].TriggerAt)
+	reminderRepo.AssertExpectations(t)
+}
+
+func TestReminderService_SnoozeReminder_UsesClock(t *testing.T) {
+	now := time.Date(2025, time.March, 10, 9, 0, 0, 0, time.UTC)
+	reminder := &domain.Reminder{ID: 1, UserID: 7, RepeatType: domain.RepeatTypeOnce, ScheduledAt: now, NextTriggerAt: now, IsEnabled: true}
+
+	reminderRepo := new(MockReminderRepository)
+	reminderRepo.On("FindByID", mock.Anything, int64(1)).Return(reminder, nil)
+	reminderRepo.On("Update", mock.Anything, reminder).Return(nil)
+
+	service := NewReminderService(reminderRepo, nil, 0, newTestLogger())
+	service.SetClock(clock.NewFake(now))
+
+	snoozed, err := service.SnoozeReminder(context.Background(), 7, 1, 15*time.Minute)
+
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(15*time.Minute), snoozed.NextTriggerAt)
+	assert.Equal(t, 1, snoozed.SnoozeCount)
+	reminderRepo.AssertExpectations(t)
+}
+
+func TestReminderService_CreateReminder_ChecksScheduleAgainstClock(t *testing.T) {
+	now := time.Date(2025, time.March, 10, 9, 0, 0, 0, time.UTC)
+	noteRepo := &stubReminderNoteRepository{note: &domain.Note{ID: 1, UserID: 7}}
+
+	reminderRepo := new(MockReminderRepository)
+	reminderRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Reminder")).Return(nil)
+	service := NewReminderService(reminderRepo, noteRepo, 0, newTestLogger())
+	service.SetClock(clock.NewFake(now))
+
+	// In the past by the wall clock, but in the future by the service's clock
+	reminder, err := service.CreateReminder(context.Background(), 7, 1, CreateReminderRequest{Title: "Frozen", ScheduledAt: now.Add(time.Hour)})
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Hour), reminder.NextTriggerAt)
+
+	_, err = service.CreateReminder(context.Background(), 7, 1, CreateReminderRequest{Title: "Stale", ScheduledAt: now.Add(-time.Hour)})
+	assert.ErrorIs(t, err, domain.ErrInvalidScheduleTime)
+	reminderRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestReminderService_MarkReminderTriggered_FrozenRecurrence(t *testing.T) {
+	// Monday 10 March 2025, 09:00 UTC; fires Mondays and Thursdays
+	scheduled := time.Date(2025, time.March, 10, 9, 0, 0, 0, time.UTC)
+	reminder := &domain.Reminder{
+		ID:            1,
+		UserID:        7,
+		RepeatType:    domain.RepeatTypeWeekly,
+		RepeatConfig:  &domain.RepeatConfig{Days: []int{1, 4}},
+		ScheduledAt:   scheduled,
+		NextTriggerAt: scheduled,
+		IsEnabled:     true,
+	}
+	thursday := scheduled.AddDate(0, 0, 3)
+	nextMonday := scheduled.AddDate(0, 0, 7)
+
+	reminderRepo := new(MockReminderRepository)
+	reminderRepo.On("MarkTriggered", mock.Anything, int64(1), thursday, true, scheduled.Add(time.Second)).Return(nil).Once()
+	reminderRepo.On("MarkTriggered", mock.Anything, int64(1), nextMonday, true, thursday.Add(time.Second)).Return(nil).Once()
+
+	fake := clock.NewFake(scheduled.Add(time.Second))
+	service := NewReminderService(reminderRepo, nil, 0, newTestLogger())
+	service.SetClock(fake)
+
+	require.NoError(t, service.MarkReminderTriggered(context.Background(), reminder))
+	assert.Equal(t, thursday, reminder.NextTriggerAt)
+
+	fake.Set(thursday.Add(time.Second))
+	require.NoError(t, service.MarkReminderTriggered(context.Background(), reminder))
+	assert.Equal(t, nextMonday, reminder.NextTriggerAt)
+	assert.Equal(t, 2, reminder.TriggerCount)
+	reminderRepo.AssertExpectations(t)
+}
+
+// stubReminderNoteRepository holds a single note
+type stubReminderNoteRepository struct {
+	ports.NoteRepository
+	note *domain.Note
+}
+
+func (r *stubReminderNoteRepository) CheckOwnership(ctx context.Context, noteID, userID int64) (bool, error) {
+	return noteID == r.note.ID && userID == r.note.UserID, nil
+}
+
+func (r *stubReminderNoteRepository) FindByID(ctx context.Context, id int64) (*domain.Note, error) {
+	if id != r.note.ID {
+		return nil, domain.ErrNoteNotFound
+	}
+	return r.note, nil
+}
+
+func TestReminderService_CreateReminder_Relative(t *testing.T) {
+	due := time.Now().Add(72 * time.Hour).UTC().Truncate(time.Second)
+	fallback := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	noteRepo := &stubReminderNoteRepository{note: &domain.Note{ID: 1, UserID: 7, Properties: map[string]interface{}{"due": due.Format(time.RFC3339)}}}
+
+	reminderRepo := new(MockReminderRepository)
+	reminderRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Reminder")).Return(nil)
+	service := NewReminderService(reminderRepo, noteRepo, 0, newTestLogger())
+
+	req := CreateReminderRequest{
+		Title:       "Prepare",
+		ScheduledAt: fallback,
+		Relative:    &domain.RelativeSchedule{PropertyID: "due", OffsetMinutes: -24 * 60},
+	}
+	reminder, err := service.CreateReminder(context.Background(), 7, 1, req)
+	require.NoError(t, err)
+	assert.Equal(t, due.Add(-24*time.Hour), reminder.NextTriggerAt)
+	assert.Equal(t, fallback, reminder.ScheduledAt)
+
+	// A note without the date falls back to the absolute schedule
+	delete(noteRepo.note.Properties, "due")
+	reminder, err = service.CreateReminder(context.Background(), 7, 1, req)
+	require.NoError(t, err)
+	assert.Equal(t, fallback, reminder.NextTriggerAt)
+
+	// A date that already passed is rejected like a past schedule
+	noteRepo.note.Properties["due"] = time.Now().Add(-time.Hour).Format(time.RFC3339)
+	_, err = service.CreateReminder(context.Background(), 7, 1, req)
+	assert.ErrorIs(t, err, domain.ErrInvalidScheduleTime)
+
+	req.RepeatType = domain.RepeatTypeDaily
+	_, err = service.CreateReminder(context.Background(), 7, 1, req)
+	assert.ErrorIs(t, err, domain.ErrInvalidRelativeSchedule)
+}
+
+func TestReminderService_RescheduleForNote(t *testing.T) {
+	due := time.Date(2030, time.March, 20, 9, 0, 0, 0, time.UTC)
+	note := &domain.Note{ID: 1, UserID: 7, Properties: map[string]interface{}{"due": due.Format(time.RFC3339)}}
+	stale := due.AddDate(0, 0, -7)
+
+	relative := &domain.Reminder{ID: 1, NoteID: 1, RepeatType: domain.RepeatTypeOnce, ScheduledAt: stale, NextTriggerAt: stale, IsEnabled: true,
+		Relative: &domain.RelativeSchedule{PropertyID: "due", OffsetMinutes: -60}}
+	absolute := &domain.Reminder{ID: 2, NoteID: 1, RepeatType: domain.RepeatTypeOnce, ScheduledAt: stale, NextTriggerAt: stale, IsEnabled: true}
+
+	reminderRepo := new(MockReminderRepository)
+	reminderRepo.On("FindByNoteID", mock.Anything, int64(1)).Return([]*domain.Reminder{relative, absolute}, nil)
+	reminderRepo.On("Update", mock.Anything, relative).Return(nil).Once()
+
+	service := NewReminderService(reminderRepo, nil, 0, newTestLogger())
+	service.RescheduleForNote(context.Background(), note)
+
+	assert.Equal(t, due.Add(-time.Hour), relative.NextTriggerAt)
+	assert.Equal(t, stale, absolute.NextTriggerAt)
+	reminderRepo.AssertExpectations(t)
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// CalendarFeed lets calendar apps read a user's reminders through a
+// long-lived token that grants nothing else. Only the token's hash is stored;
+// creating a new feed replaces the previous token.
+type CalendarFeed struct {
+	UserID    int64     `json:"-"`
+	TokenHash string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Calendar feed errors
+var (
+	ErrCalendarFeedNotFound    = errors.New("calendar feed not found")
+	ErrCalendarFeedUnavailable = errors.New("calendar feeds are not available")
+)
+
+// NewCalendarFeed creates a calendar feed for the user from a freshly generated token
+func NewCalendarFeed(userID int64, token string) *CalendarFeed {
+	return &CalendarFeed{
+		UserID:    userID,
+		TokenHash: HashCalendarFeedToken(token),
+		CreatedAt: time.Now(),
+	}
+}
+
+// HashCalendarFeedToken returns the hex-encoded SHA-256 of a feed token, as stored
+func HashCalendarFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
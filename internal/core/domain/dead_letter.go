@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"time"
+)
+
+// DeadLetter is a notification that could not be delivered, kept so it can be
+// inspected and replayed. It copies the failed NotificationLog, so it outlives
+// log cleanup, along with the channel and device it was sent to.
+type DeadLetter struct {
+	ID                int64               `json:"id"`
+	NotificationLogID *int64              `json:"notification_log_id,omitempty"` // Null once the log is cleaned up
+	UserID            int64               `json:"user_id"`
+	ReminderID        *int64              `json:"reminder_id,omitempty"`
+	DeviceID          *int64              `json:"device_id,omitempty"`
+	Channel           NotificationChannel `json:"channel"`
+	DeviceType        DeviceType          `json:"device_type,omitempty"`
+	DeviceName        string              `json:"device_name,omitempty"`
+	Title             string              `json:"title"`
+	Body              string              `json:"body,omitempty"`
+	Data              map[string]string   `json:"data,omitempty"`
+	ErrorMessage      string              `json:"error_message"`
+	ReplayCount       int                 `json:"replay_count"`
+	LastReplayedAt    *time.Time          `json:"last_replayed_at,omitempty"`
+	ResolvedAt        *time.Time          `json:"resolved_at,omitempty"`
+	CreatedAt         time.Time           `json:"created_at"`
+}
+
+// NewDeadLetter creates a dead letter from a failed notification log. device
+// is nil for channels that don't deliver to a device.
+func NewDeadLetter(log *NotificationLog, channel NotificationChannel, device *Device, errorMessage string) *DeadLetter {
+	letter := &DeadLetter{
+		UserID:       log.UserID,
+		ReminderID:   log.ReminderID,
+		DeviceID:     log.DeviceID,
+		Channel:      channel,
+		Title:        log.Title,
+		Body:         log.Body,
+		Data:         log.Data,
+		ErrorMessage: errorMessage,
+		CreatedAt:    time.Now(),
+	}
+	if log.ID != 0 {
+		logID := log.ID
+		letter.NotificationLogID = &logID
+	}
+	if device != nil {
+		deviceID := device.ID
+		letter.DeviceID = &deviceID
+		letter.DeviceType = device.DeviceType
+		letter.DeviceName = device.DeviceName
+	}
+	return letter
+}
+
+// IsResolved reports whether a replay has delivered the notification
+func (d *DeadLetter) IsResolved() bool {
+	return d.ResolvedAt != nil
+}
+
+// RecordReplay records a replay attempt. A nil error resolves the dead letter;
+// otherwise the error replaces the last one.
+func (d *DeadLetter) RecordReplay(err error) {
+	now := time.Now()
+	d.ReplayCount++
+	d.LastReplayedAt = &now
+	if err != nil {
+		d.ErrorMessage = err.Error()
+		return
+	}
+	d.ResolvedAt = &now
+}
@@ -21,6 +21,7 @@ type Device struct {
 	DeviceToken string     `json:"device_token"`
 	DeviceType  DeviceType `json:"device_type"`
 	DeviceName  string     `json:"device_name,omitempty"`
+	AppVersion  string     `json:"app_version,omitempty"`
 	BrowserInfo string     `json:"browser_info,omitempty"`
 	IsActive    bool       `json:"is_active"`
 	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
@@ -71,6 +72,12 @@ func (d *Device) SetDeviceName(name string) {
 	d.UpdatedAt = time.Now()
 }
 
+// SetAppVersion sets the version of the app running on the device
+func (d *Device) SetAppVersion(version string) {
+	d.AppVersion = version
+	d.UpdatedAt = time.Now()
+}
+
 // SetBrowserInfo sets the browser information (for web devices)
 func (d *Device) SetBrowserInfo(info string) {
 	d.BrowserInfo = info
@@ -18,30 +18,36 @@ var (
 	ErrOAuthCodeExchange  = errors.New("failed to exchange oauth code for token")
 	ErrOAuthUserInfo      = errors.New("failed to get user info from oauth provider")
 	ErrOAuthProviderError = errors.New("oauth provider returned an error")
+	ErrOAuthUnavailable   = errors.New("oauth sign-in is temporarily unavailable")
 )
 
 // Note errors
 var (
-	ErrNoteNotFound      = errors.New("note not found")
-	ErrInvalidNoteData   = errors.New("invalid note data")
+	ErrNoteNotFound       = errors.New("note not found")
+	ErrInvalidNoteData    = errors.New("invalid note data")
 	ErrUnauthorizedAccess = errors.New("unauthorized access to resource")
 )
 
 // Notification errors
 var (
-	ErrNotificationNotFound    = errors.New("notification not found")
-	ErrNotificationLogNotFound = errors.New("notification log not found")
-	ErrInvalidScheduleTime     = errors.New("schedule time must be in the future")
-	ErrNotificationCancelled   = errors.New("notification has been cancelled")
-	ErrNotificationFailed      = errors.New("failed to send notification")
+	ErrNotificationNotFound      = errors.New("notification not found")
+	ErrNotificationLogNotFound   = errors.New("notification log not found")
+	ErrInvalidScheduleTime       = errors.New("schedule time must be in the future")
+	ErrNotificationCancelled     = errors.New("notification has been cancelled")
+	ErrNotificationFailed        = errors.New("failed to send notification")
+	ErrInvalidNotificationStatus = errors.New("invalid notification status")
+	ErrStreamUnavailable         = errors.New("notification stream is not available")
+	ErrDeadLetterNotFound        = errors.New("dead letter not found")
+	ErrDeadLetterResolved        = errors.New("dead letter has already been delivered")
+	ErrChannelUnavailable        = errors.New("notification channel is not configured")
 )
 
 // Device errors
 var (
-	ErrDeviceNotFound      = errors.New("device not found")
-	ErrInvalidDeviceToken  = errors.New("invalid device token")
-	ErrNoActiveDevices     = errors.New("no active devices found for user")
-	ErrFCMSendFailed       = errors.New("failed to send FCM notification")
+	ErrDeviceNotFound     = errors.New("device not found")
+	ErrInvalidDeviceToken = errors.New("invalid device token")
+	ErrNoActiveDevices    = errors.New("no active devices found for user")
+	ErrFCMSendFailed      = errors.New("failed to send FCM notification")
 )
 
 // Reminder errors
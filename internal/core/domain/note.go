@@ -1,7 +1,10 @@
 package domain
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -109,6 +112,48 @@ type ViewFilter struct {
 	Value      interface{} `json:"value"`
 }
 
+// Filter operators supported in view filters
+const (
+	FilterEquals             = "equals"
+	FilterNotEquals          = "not_equals"
+	FilterContains           = "contains"
+	FilterNotContains        = "not_contains"
+	FilterIsEmpty            = "is_empty"
+	FilterIsNotEmpty         = "is_not_empty"
+	FilterGreaterThan        = "greater_than"
+	FilterGreaterThanOrEqual = "greater_than_or_equal"
+	FilterLessThan           = "less_than"
+	FilterLessThanOrEqual    = "less_than_or_equal"
+)
+
+var (
+	textFilterOperators       = []string{FilterEquals, FilterNotEquals, FilterContains, FilterNotContains, FilterIsEmpty, FilterIsNotEmpty}
+	comparableFilterOperators = []string{FilterEquals, FilterNotEquals, FilterGreaterThan, FilterGreaterThanOrEqual, FilterLessThan, FilterLessThanOrEqual, FilterIsEmpty, FilterIsNotEmpty}
+)
+
+// filterOperators lists the operators that make sense for each property type
+var filterOperators = map[PropertyType][]string{
+	PropertyTypeText:        textFilterOperators,
+	PropertyTypeURL:         textFilterOperators,
+	PropertyTypeEmail:       textFilterOperators,
+	PropertyTypePerson:      textFilterOperators,
+	PropertyTypeNumber:      comparableFilterOperators,
+	PropertyTypeDate:        comparableFilterOperators,
+	PropertyTypeSelect:      {FilterEquals, FilterNotEquals, FilterIsEmpty, FilterIsNotEmpty},
+	PropertyTypeMultiSelect: {FilterContains, FilterNotContains, FilterIsEmpty, FilterIsNotEmpty},
+	PropertyTypeCheckbox:    {FilterEquals, FilterNotEquals},
+}
+
+// SupportsFilterOperator returns true if operator can filter properties of this type
+func (t PropertyType) SupportsFilterOperator(operator string) bool {
+	for _, op := range filterOperators[t] {
+		if op == operator {
+			return true
+		}
+	}
+	return false
+}
+
 // ViewSort represents a sort configuration in database views
 type ViewSort struct {
 	PropertyID string `json:"property_id"`
@@ -117,10 +162,81 @@ type ViewSort struct {
 
 // ViewMetadata contains configuration for database views
 type ViewMetadata struct {
-	ViewType   ViewType       `json:"view_type"`
-	Properties []ViewProperty `json:"properties"`
-	Filters    []ViewFilter   `json:"filters,omitempty"`
-	Sorts      []ViewSort     `json:"sorts,omitempty"`
+	ViewType      ViewType       `json:"view_type"`
+	Properties    []ViewProperty `json:"properties"`
+	Filters       []ViewFilter   `json:"filters,omitempty"`
+	Sorts         []ViewSort     `json:"sorts,omitempty"`
+	GroupBy       string         `json:"group_by,omitempty"`       // Board: select property whose options are the columns
+	CoverProperty string         `json:"cover_property,omitempty"` // Gallery: URL property holding each card's cover image
+}
+
+// Property returns the view property with the given ID, or nil
+func (m *ViewMetadata) Property(id string) *ViewProperty {
+	for i := range m.Properties {
+		if m.Properties[i].ID == id {
+			return &m.Properties[i]
+		}
+	}
+	return nil
+}
+
+// Validate checks the view type, the configuration that type requires, and
+// that filters and sorts refer to defined properties
+func (m *ViewMetadata) Validate() error {
+	if err := m.validateLayout(); err != nil {
+		return err
+	}
+
+	for _, filter := range m.Filters {
+		property := m.Property(filter.PropertyID)
+		if property == nil {
+			return fmt.Errorf("%w: filter references unknown property %q", ErrInvalidViewConfig, filter.PropertyID)
+		}
+		if !property.Type.SupportsFilterOperator(filter.Operator) {
+			return fmt.Errorf("%w: operator %q can't filter %s property %q", ErrInvalidViewConfig, filter.Operator, property.Type, property.ID)
+		}
+	}
+
+	for _, sort := range m.Sorts {
+		if m.Property(sort.PropertyID) == nil {
+			return fmt.Errorf("%w: sort references unknown property %q", ErrInvalidViewConfig, sort.PropertyID)
+		}
+		if sort.Direction != "asc" && sort.Direction != "desc" {
+			return fmt.Errorf("%w: sort direction must be asc or desc", ErrInvalidViewConfig)
+		}
+	}
+
+	return nil
+}
+
+// validateLayout checks the view type and its type-specific configuration
+func (m *ViewMetadata) validateLayout() error {
+	switch m.ViewType {
+	case ViewTypeTable, ViewTypeList:
+		return nil
+
+	case ViewTypeBoard:
+		if m.GroupBy == "" {
+			return fmt.Errorf("%w: board view requires group_by", ErrInvalidViewConfig)
+		}
+		property := m.Property(m.GroupBy)
+		if property == nil || property.Type != PropertyTypeSelect {
+			return fmt.Errorf("%w: board view must group by a select property", ErrInvalidViewConfig)
+		}
+		return nil
+
+	case ViewTypeGallery:
+		if m.CoverProperty == "" {
+			return fmt.Errorf("%w: gallery view requires cover_property", ErrInvalidViewConfig)
+		}
+		property := m.Property(m.CoverProperty)
+		if property == nil || property.Type != PropertyTypeURL {
+			return fmt.Errorf("%w: gallery cover must be a url property", ErrInvalidViewConfig)
+		}
+		return nil
+	}
+
+	return ErrInvalidViewType
 }
 
 // Tag represents a tag entity for categorizing notes
@@ -133,6 +249,35 @@ type Tag struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// Tag field limits, matching the tags table
+const (
+	MaxTagNameLength  = 100
+	MaxTagColorLength = 50
+)
+
+// Rename changes the tag name, trimming surrounding whitespace
+func (t *Tag) Rename(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > MaxTagNameLength {
+		return ErrInvalidTagName
+	}
+
+	t.Name = name
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// SetColor changes the tag color
+func (t *Tag) SetColor(color string) error {
+	if len(color) > MaxTagColorLength {
+		return ErrInvalidTagColor
+	}
+
+	t.Color = color
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
 // Note represents a note entity in the domain (similar to Notion pages)
 type Note struct {
 	ID           int64                  `json:"id"`
@@ -159,7 +304,7 @@ type Note struct {
 var (
 	// ErrNoteNotFound is defined in errors.go
 	// ErrUnauthorizedAccess is defined in errors.go
-	ErrInvalidNoteTitle     = errors.New("note title is required and must be between 1 and 500 characters")
+	ErrInvalidNoteTitle     = errors.New("note title is required and must not exceed the maximum title length")
 	ErrInvalidParentNote    = errors.New("invalid parent note")
 	ErrCircularReference    = errors.New("circular reference detected in hierarchy")
 	ErrInvalidBlockType     = errors.New("invalid block type")
@@ -168,15 +313,109 @@ var (
 	ErrMaxDepthExceeded     = errors.New("maximum nesting depth (10 levels) exceeded")
 	ErrInvalidBlockID       = errors.New("block ID is required")
 	ErrBlockNotFound        = errors.New("block not found")
+	ErrBlockNotText         = errors.New("block type does not hold rich text")
 	ErrInvalidViewType      = errors.New("invalid view type")
+	ErrInvalidViewConfig    = errors.New("invalid view configuration")
+	ErrNoteNotView          = errors.New("note has no database view")
+	ErrTooManyNoteIDs       = errors.New("too many note IDs requested")
+	ErrTooManyBlocks        = errors.New("note exceeds the maximum number of blocks")
+	ErrQuotaExceeded        = errors.New("storage quota exceeded")
+	ErrTagNotFound          = errors.New("tag not found")
+	ErrInvalidTagName       = errors.New("tag name is required and must not exceed 100 characters")
+	ErrInvalidTagColor      = errors.New("tag color must not exceed 50 characters")
+	ErrTagNameTaken         = errors.New("a tag with this name already exists")
+	ErrMergeTagIntoItself   = errors.New("cannot merge a tag into itself")
 )
 
 const (
 	MaxNestingDepth  = 10
 	MaxTitleLength   = 500
 	MinTitleLength   = 1
+
+	// DefaultMaxBlocksPerNote is used when no block limit is configured
+	DefaultMaxBlocksPerNote = 1000
 )
 
+// NoteLimits are the deployment-configurable size limits for notes
+type NoteLimits struct {
+	MaxTitleLength   int `json:"max_title_length"`
+	MaxBlocksPerNote int `json:"max_blocks_per_note"`
+}
+
+// NewNoteLimits creates note limits, falling back to the defaults for
+// non-positive values. The title limit can't exceed MaxTitleLength, which is
+// the size of the title column.
+func NewNoteLimits(maxTitleLength, maxBlocksPerNote int) NoteLimits {
+	if maxTitleLength <= 0 || maxTitleLength > MaxTitleLength {
+		maxTitleLength = MaxTitleLength
+	}
+	if maxBlocksPerNote <= 0 {
+		maxBlocksPerNote = DefaultMaxBlocksPerNote
+	}
+
+	return NoteLimits{
+		MaxTitleLength:   maxTitleLength,
+		MaxBlocksPerNote: maxBlocksPerNote,
+	}
+}
+
+// DefaultNoteLimits returns the limits used when none are configured
+func DefaultNoteLimits() NoteLimits {
+	return NewNoteLimits(MaxTitleLength, DefaultMaxBlocksPerNote)
+}
+
+// ValidateTitle checks the title length against the limits
+func (l NoteLimits) ValidateTitle(title string) error {
+	if len(title) < MinTitleLength || len(title) > l.MaxTitleLength {
+		return ErrInvalidNoteTitle
+	}
+	return nil
+}
+
+// ValidateBlockCount checks that a note may hold count blocks
+func (l NoteLimits) ValidateBlockCount(count int) error {
+	if count > l.MaxBlocksPerNote {
+		return ErrTooManyBlocks
+	}
+	return nil
+}
+
+// StorageQuota caps how much a single user may store. Zero means unlimited.
+// Notes in the trash count until they are purged.
+type StorageQuota struct {
+	MaxNotes      int64 `json:"max_notes"`
+	MaxBlockBytes int64 `json:"max_block_bytes"`
+}
+
+// CheckNewNote returns ErrQuotaExceeded if a user with noteCount notes may not create another
+func (q StorageQuota) CheckNewNote(noteCount int64) error {
+	if q.MaxNotes > 0 && noteCount >= q.MaxNotes {
+		return ErrQuotaExceeded
+	}
+	return nil
+}
+
+// CheckBlockGrowth returns ErrQuotaExceeded if growing a user's block storage
+// from usedBytes by delta bytes would exceed the quota. Shrinking is always allowed.
+func (q StorageQuota) CheckBlockGrowth(usedBytes, delta int64) error {
+	if q.MaxBlockBytes > 0 && delta > 0 && usedBytes+delta > q.MaxBlockBytes {
+		return ErrQuotaExceeded
+	}
+	return nil
+}
+
+// BlocksSize returns the serialized size of blocks in bytes, as counted against the storage quota
+func BlocksSize(blocks []Block) int64 {
+	if len(blocks) == 0 {
+		return 0
+	}
+	data, err := json.Marshal(blocks)
+	if err != nil {
+		return 0
+	}
+	return int64(len(data))
+}
+
 // NewNote creates a new note with validation
 func NewNote(userID int64, title string) (*Note, error) {
 	if err := ValidateNoteTitle(title); err != nil {
@@ -206,6 +445,15 @@ func ValidateNoteTitle(title string) error {
 	return nil
 }
 
+// PathDepth is the note's depth according to its materialized path, which
+// stays correct when Depth has gone stale. Falls back to Depth without a path.
+func (n *Note) PathDepth() int {
+	if n.Path == "" {
+		return n.Depth
+	}
+	return strings.Count(strings.Trim(n.Path, "/"), "/")
+}
+
 // SetParent sets the parent note and validates hierarchy
 func (n *Note) SetParent(parentID *int64, parentDepth int) error {
 	if parentID == nil {
@@ -266,6 +514,30 @@ func (n *Note) UpdateBlock(blockID string, content *BlockContent) error {
 	return ErrBlockNotFound
 }
 
+// UpdateBlockText replaces only the rich text of a block, keeping the rest of its content
+func (n *Note) UpdateBlockText(blockID string, richText []RichTextSegment) error {
+	if blockID == "" {
+		return ErrInvalidBlockID
+	}
+
+	for i, block := range n.Blocks {
+		if block.ID == blockID {
+			if !IsTextBlockType(block.Type) {
+				return ErrBlockNotText
+			}
+			content := BlockContent{}
+			if block.Content != nil {
+				content = *block.Content
+			}
+			content.RichText = richText
+			n.Blocks[i].Content = &content
+			n.UpdatedAt = time.Now()
+			return nil
+		}
+	}
+	return ErrBlockNotFound
+}
+
 // DeleteBlock removes a block from the note by ID
 func (n *Note) DeleteBlock(blockID string) error {
 	if blockID == "" {
@@ -400,6 +672,18 @@ func (n *Note) DeleteProperty(key string) {
 	}
 }
 
+// MergeProperties applies a partial update to the custom properties: keys with
+// a nil value are removed, others are set, and the rest are left untouched
+func (n *Note) MergeProperties(patch map[string]interface{}) {
+	for key, value := range patch {
+		if value == nil {
+			n.DeleteProperty(key)
+			continue
+		}
+		n.SetProperty(key, value)
+	}
+}
+
 // SetViewMetadata sets the view configuration for database views
 func (n *Note) SetViewMetadata(metadata *ViewMetadata) {
 	n.ViewMetadata = metadata
@@ -425,3 +709,8 @@ func IsValidBlockType(blockType BlockType) bool {
 	}
 	return validTypes[blockType]
 }
+
+// IsTextBlockType reports whether blocks of this type carry rich text
+func IsTextBlockType(blockType BlockType) bool {
+	return IsValidBlockType(blockType) && blockType != BlockTypeCode && blockType != BlockTypeDivider
+}
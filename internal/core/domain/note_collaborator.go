@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CollaboratorRole is the access level granted to a collaborator
+type CollaboratorRole string
+
+const (
+	CollaboratorRoleViewer CollaboratorRole = "viewer"
+	CollaboratorRoleEditor CollaboratorRole = "editor"
+)
+
+// NoteCollaborator grants another user access to a note and all of its descendants
+type NoteCollaborator struct {
+	ID        int64            `json:"id"`
+	NoteID    int64            `json:"note_id"`
+	UserID    int64            `json:"user_id"`
+	Role      CollaboratorRole `json:"role"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// Collaborator errors
+var (
+	ErrCollaboratorNotFound    = errors.New("collaborator not found")
+	ErrInvalidCollaboratorRole = errors.New("invalid collaborator role")
+	ErrCannotCollaborateOwn    = errors.New("cannot add the note owner as a collaborator")
+)
+
+// IsValidCollaboratorRole checks if the role is supported
+func IsValidCollaboratorRole(role CollaboratorRole) bool {
+	return role == CollaboratorRoleViewer || role == CollaboratorRoleEditor
+}
+
+// NewNoteCollaborator creates a new NoteCollaborator with validation
+func NewNoteCollaborator(noteID, userID int64, role CollaboratorRole) (*NoteCollaborator, error) {
+	if !IsValidCollaboratorRole(role) {
+		return nil, ErrInvalidCollaboratorRole
+	}
+
+	now := time.Now()
+	return &NoteCollaborator{
+		NoteID:    noteID,
+		UserID:    userID,
+		Role:      role,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// CanEdit returns true if the collaborator may modify note content
+func (c *NoteCollaborator) CanEdit() bool {
+	return c.Role == CollaboratorRoleEditor
+}
+
+// PathIDs returns the IDs in the note's hierarchy path, root first and ending
+// with the note itself. Path format: "/1/23/456/" -> [1, 23, 456]
+func (n *Note) PathIDs() []int64 {
+	ids := []int64{}
+	for _, part := range strings.Split(strings.Trim(n.Path, "/"), "/") {
+		if id, err := strconv.ParseInt(part, 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) == 0 || ids[len(ids)-1] != n.ID {
+		ids = append(ids, n.ID)
+	}
+
+	return ids
+}
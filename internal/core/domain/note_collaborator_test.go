@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewNoteCollaborator(t *testing.T) {
+	collaborator, err := NewNoteCollaborator(1, 2, CollaboratorRoleEditor)
+	require.NoError(t, err)
+	assert.True(t, collaborator.CanEdit())
+
+	collaborator, err = NewNoteCollaborator(1, 2, CollaboratorRoleViewer)
+	require.NoError(t, err)
+	assert.False(t, collaborator.CanEdit())
+
+	_, err = NewNoteCollaborator(1, 2, "owner")
+	assert.ErrorIs(t, err, ErrInvalidCollaboratorRole)
+}
+
+func TestNote_PathIDs(t *testing.T) {
+	note := &Note{ID: 456, Path: "/1/23/456/"}
+	assert.Equal(t, []int64{1, 23, 456}, note.PathIDs())
+
+	// Path not yet populated by the database
+	note = &Note{ID: 7}
+	assert.Equal(t, []int64{7}, note.PathIDs())
+}
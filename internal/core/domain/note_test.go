@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewNoteLimits(t *testing.T) {
+	limits := NewNoteLimits(0, 0)
+	assert.Equal(t, DefaultNoteLimits(), limits)
+
+	limits = NewNoteLimits(1000, 5)
+	assert.Equal(t, MaxTitleLength, limits.MaxTitleLength, "title limit is capped by the column size")
+	assert.Equal(t, 5, limits.MaxBlocksPerNote)
+}
+
+func TestNoteLimits_Validate(t *testing.T) {
+	limits := NewNoteLimits(10, 2)
+
+	assert.NoError(t, limits.ValidateTitle("Groceries"))
+	assert.ErrorIs(t, limits.ValidateTitle(""), ErrInvalidNoteTitle)
+	assert.ErrorIs(t, limits.ValidateTitle(strings.Repeat("a", 11)), ErrInvalidNoteTitle)
+
+	assert.NoError(t, limits.ValidateBlockCount(2))
+	assert.ErrorIs(t, limits.ValidateBlockCount(3), ErrTooManyBlocks)
+}
+
+func TestViewMetadata_Validate(t *testing.T) {
+	properties := []ViewProperty{
+		{ID: "status", Name: "Status", Type: PropertyTypeSelect, Options: []string{"Todo", "Done"}},
+		{ID: "title", Name: "Title", Type: PropertyTypeText},
+		{ID: "image", Name: "Image", Type: PropertyTypeURL},
+	}
+
+	tests := []struct {
+		name    string
+		view    ViewMetadata
+		wantErr error
+	}{
+		{"table", ViewMetadata{ViewType: ViewTypeTable, Properties: properties}, nil},
+		{"list", ViewMetadata{ViewType: ViewTypeList}, nil},
+		{"board grouped by select", ViewMetadata{ViewType: ViewTypeBoard, Properties: properties, GroupBy: "status"}, nil},
+		{"board without group_by", ViewMetadata{ViewType: ViewTypeBoard, Properties: properties}, ErrInvalidViewConfig},
+		{"board grouped by text", ViewMetadata{ViewType: ViewTypeBoard, Properties: properties, GroupBy: "title"}, ErrInvalidViewConfig},
+		{"board grouped by missing property", ViewMetadata{ViewType: ViewTypeBoard, Properties: properties, GroupBy: "nope"}, ErrInvalidViewConfig},
+		{"gallery with url cover", ViewMetadata{ViewType: ViewTypeGallery, Properties: properties, CoverProperty: "image"}, nil},
+		{"gallery without cover", ViewMetadata{ViewType: ViewTypeGallery, Properties: properties}, ErrInvalidViewConfig},
+		{"gallery with text cover", ViewMetadata{ViewType: ViewTypeGallery, Properties: properties, CoverProperty: "title"}, ErrInvalidViewConfig},
+		{"unknown type", ViewMetadata{ViewType: "calendar"}, ErrInvalidViewType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.view.Validate()
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestViewMetadata_ValidateFiltersAndSorts(t *testing.T) {
+	properties := []ViewProperty{
+		{ID: "name", Name: "Name", Type: PropertyTypeText},
+		{ID: "due", Name: "Due", Type: PropertyTypeDate},
+		{ID: "done", Name: "Done", Type: PropertyTypeCheckbox},
+	}
+
+	tests := []struct {
+		name    string
+		filters []ViewFilter
+		sorts   []ViewSort
+		wantErr bool
+	}{
+		{"valid", []ViewFilter{{PropertyID: "due", Operator: FilterGreaterThan, Value: "2024-01-01"}}, []ViewSort{{PropertyID: "name", Direction: "asc"}}, false},
+		{"text contains", []ViewFilter{{PropertyID: "name", Operator: FilterContains, Value: "plan"}}, nil, false},
+		{"unknown filter property", []ViewFilter{{PropertyID: "missing", Operator: FilterEquals}}, nil, true},
+		{"greater_than on text", []ViewFilter{{PropertyID: "name", Operator: FilterGreaterThan, Value: "a"}}, nil, true},
+		{"contains on checkbox", []ViewFilter{{PropertyID: "done", Operator: FilterContains, Value: true}}, nil, true},
+		{"unknown operator", []ViewFilter{{PropertyID: "name", Operator: "like"}}, nil, true},
+		{"unknown sort property", nil, []ViewSort{{PropertyID: "missing", Direction: "asc"}}, true},
+		{"bad sort direction", nil, []ViewSort{{PropertyID: "due", Direction: "up"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			view := ViewMetadata{ViewType: ViewTypeTable, Properties: properties, Filters: tt.filters, Sorts: tt.sorts}
+			err := view.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidViewConfig)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -12,6 +12,8 @@ const (
 	NotificationStatusSent      NotificationStatus = "sent"
 	NotificationStatusFailed    NotificationStatus = "failed"
 	NotificationStatusCancelled NotificationStatus = "cancelled"
+
+	NotificationStatusSkippedByPreference NotificationStatus = "skipped_by_preference"
 )
 
 // NotificationLog represents a log entry for a sent notification
@@ -65,6 +67,12 @@ func (nl *NotificationLog) MarkAsCancelled() {
 	nl.Status = NotificationStatusCancelled
 }
 
+// MarkAsSkippedByPreference marks the notification as not sent because of the user's preferences
+func (nl *NotificationLog) MarkAsSkippedByPreference(reason string) {
+	nl.Status = NotificationStatusSkippedByPreference
+	nl.ErrorMessage = reason
+}
+
 // SetData sets additional data payload for the notification
 func (nl *NotificationLog) SetData(data map[string]string) {
 	nl.Data = data
@@ -73,7 +81,8 @@ func (nl *NotificationLog) SetData(data map[string]string) {
 // IsValidNotificationStatus checks if a status is valid
 func IsValidNotificationStatus(status NotificationStatus) bool {
 	switch status {
-	case NotificationStatusPending, NotificationStatusSent, NotificationStatusFailed, NotificationStatusCancelled:
+	case NotificationStatusPending, NotificationStatusSent, NotificationStatusFailed, NotificationStatusCancelled,
+		NotificationStatusSkippedByPreference:
 		return true
 	default:
 		return false
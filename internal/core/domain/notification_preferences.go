@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// NotificationChannel represents a delivery channel for notifications
+type NotificationChannel string
+
+const (
+	NotificationChannelPush    NotificationChannel = "push"
+	NotificationChannelEmail   NotificationChannel = "email"
+	NotificationChannelWebhook NotificationChannel = "webhook"
+)
+
+// NotificationPreferences holds a user's choices about how they are notified
+type NotificationPreferences struct {
+	UserID           int64        `json:"user_id"`
+	PushEnabled      bool         `json:"push_enabled"`
+	EmailEnabled     bool         `json:"email_enabled"`
+	MutedRepeatTypes []RepeatType `json:"muted_repeat_types"`
+	CreatedAt        time.Time    `json:"created_at"`
+	UpdatedAt        time.Time    `json:"updated_at"`
+}
+
+// Notification preferences domain errors
+var (
+	ErrNotificationPreferencesNotFound = errors.New("notification preferences not found")
+)
+
+// DefaultNotificationPreferences returns the preferences used for users who never saved any
+func DefaultNotificationPreferences(userID int64) *NotificationPreferences {
+	now := time.Now()
+	return &NotificationPreferences{
+		UserID:           userID,
+		PushEnabled:      true,
+		EmailEnabled:     false,
+		MutedRepeatTypes: []RepeatType{},
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+}
+
+// SetMutedRepeatTypes replaces the muted reminder types, ignoring duplicates
+func (p *NotificationPreferences) SetMutedRepeatTypes(repeatTypes []RepeatType) error {
+	muted := make([]RepeatType, 0, len(repeatTypes))
+	seen := make(map[RepeatType]bool)
+	for _, repeatType := range repeatTypes {
+		if !IsValidRepeatType(repeatType) {
+			return ErrInvalidRepeatType
+		}
+		if seen[repeatType] {
+			continue
+		}
+		seen[repeatType] = true
+		muted = append(muted, repeatType)
+	}
+
+	p.MutedRepeatTypes = muted
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// IsChannelEnabled reports whether the user accepts notifications on the channel
+func (p *NotificationPreferences) IsChannelEnabled(channel NotificationChannel) bool {
+	switch channel {
+	case NotificationChannelPush:
+		return p.PushEnabled
+	case NotificationChannelEmail:
+		return p.EmailEnabled
+	default:
+		return false
+	}
+}
+
+// IsMuted reports whether reminders of the given repeat type are muted
+func (p *NotificationPreferences) IsMuted(repeatType RepeatType) bool {
+	for _, muted := range p.MutedRepeatTypes {
+		if muted == repeatType {
+			return true
+		}
+	}
+	return false
+}
@@ -2,7 +2,9 @@ package domain
 
 import (
 	"errors"
+	"net/url"
 	"sort"
+	"strings"
 	"time"
 )
 
@@ -22,48 +24,163 @@ type RepeatConfig struct {
 	Days []int `json:"days,omitempty"`
 	// Day is used for monthly repeat: 1-31 for specific day, -1 for last day of month
 	Day int `json:"day,omitempty"`
+	// SkipMissingDay makes monthly reminders skip months that don't have Day
+	// (e.g. February for day 31) instead of firing on the month's last day
+	SkipMissingDay bool `json:"skip_missing_day,omitempty"`
+}
+
+// ReminderPayload customizes the push notification a reminder sends. Empty
+// fields fall back to the defaults: open the note, default sound, badge of 1.
+type ReminderPayload struct {
+	// ClickURL is where tapping the notification leads: an app path such as
+	// "/notes?id=5" or an absolute URL, including app deep links
+	ClickURL string `json:"click_url,omitempty"`
+	// Sound names the sound to play; "none" sends the notification silently
+	Sound string `json:"sound,omitempty"`
+	// Badge sets the app icon badge count where the platform supports it
+	Badge *int `json:"badge,omitempty"`
+}
+
+// Reminder payload limits
+const (
+	MaxReminderClickURLLength = 2048
+	MaxReminderSoundLength    = 100
+	MaxReminderBadge          = 9999
+)
+
+// SoundNone is the ReminderPayload sound that disables the notification sound
+const SoundNone = "none"
+
+// Validate checks the payload's fields
+func (p *ReminderPayload) Validate() error {
+	if p.ClickURL != "" {
+		if len(p.ClickURL) > MaxReminderClickURLLength {
+			return ErrInvalidReminderPayload
+		}
+		if strings.HasPrefix(p.ClickURL, "/") {
+			if strings.HasPrefix(p.ClickURL, "//") {
+				return ErrInvalidReminderPayload
+			}
+		} else if u, err := url.Parse(p.ClickURL); err != nil || u.Scheme == "" || u.Host == "" {
+			return ErrInvalidReminderPayload
+		}
+	}
+
+	if len(p.Sound) > MaxReminderSoundLength {
+		return ErrInvalidReminderPayload
+	}
+	for _, r := range p.Sound {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-' || r == '.') {
+			return ErrInvalidReminderPayload
+		}
+	}
+
+	if p.Badge != nil && (*p.Badge < 0 || *p.Badge > MaxReminderBadge) {
+		return ErrInvalidReminderPayload
+	}
+	return nil
+}
+
+// IsEmpty reports whether the payload leaves everything at the defaults
+func (p *ReminderPayload) IsEmpty() bool {
+	return p.ClickURL == "" && p.Sound == "" && p.Badge == nil
+}
+
+// RelativeSchedule makes a reminder fire relative to a date property of its
+// note, e.g. one day before "due", instead of at a fixed time. The reminder's
+// ScheduledAt is used while the note has no date in that property.
+type RelativeSchedule struct {
+	PropertyID string `json:"property_id"`
+	// OffsetMinutes is added to the date; negative fires before it
+	OffsetMinutes int `json:"offset_minutes"`
+}
+
+// Relative schedule limits
+const (
+	MaxRelativePropertyIDLength = 100
+	MaxRelativeOffsetMinutes    = 365 * 24 * 60
+)
+
+// Validate checks the property ID and offset
+func (s *RelativeSchedule) Validate() error {
+	if s.PropertyID == "" || len(s.PropertyID) > MaxRelativePropertyIDLength {
+		return ErrInvalidRelativeSchedule
+	}
+	if s.OffsetMinutes < -MaxRelativeOffsetMinutes || s.OffsetMinutes > MaxRelativeOffsetMinutes {
+		return ErrInvalidRelativeSchedule
+	}
+	return nil
+}
+
+// Resolve returns the trigger time for a note with the given properties, or
+// false if the property is missing or isn't a date
+func (s *RelativeSchedule) Resolve(properties map[string]interface{}) (time.Time, bool) {
+	date, ok := toTime(properties[s.PropertyID])
+	if !ok {
+		return time.Time{}, false
+	}
+	return date.Add(time.Duration(s.OffsetMinutes) * time.Minute), true
 }
 
 // Reminder represents a scheduled notification for a note
 type Reminder struct {
-	ID              int64         `json:"id"`
-	NoteID          int64         `json:"note_id"`
-	UserID          int64         `json:"user_id"`
-	Title           string        `json:"title"`
-	Message         string        `json:"message,omitempty"`
-	ScheduledAt     time.Time     `json:"scheduled_at"`
-	RepeatType      RepeatType    `json:"repeat_type"`
-	RepeatConfig    *RepeatConfig `json:"repeat_config,omitempty"`
-	RepeatEndAt     *time.Time    `json:"repeat_end_at,omitempty"`
-	IsEnabled       bool          `json:"is_enabled"`
-	NextTriggerAt   time.Time     `json:"next_trigger_at"`
-	LastTriggeredAt *time.Time    `json:"last_triggered_at,omitempty"`
-	TriggerCount    int           `json:"trigger_count"`
-	CreatedAt       time.Time     `json:"created_at"`
-	UpdatedAt       time.Time     `json:"updated_at"`
+	ID              int64             `json:"id"`
+	NoteID          int64             `json:"note_id"`
+	UserID          int64             `json:"user_id"`
+	Title           string            `json:"title"`
+	Message         string            `json:"message,omitempty"`
+	ScheduledAt     time.Time         `json:"scheduled_at"`
+	RepeatType      RepeatType        `json:"repeat_type"`
+	RepeatConfig    *RepeatConfig     `json:"repeat_config,omitempty"`
+	RepeatEndAt     *time.Time        `json:"repeat_end_at,omitempty"`
+	Payload         *ReminderPayload  `json:"payload,omitempty"`
+	Relative        *RelativeSchedule `json:"relative,omitempty"`
+	IsEnabled       bool              `json:"is_enabled"`
+	NextTriggerAt   time.Time         `json:"next_trigger_at"`
+	LastTriggeredAt *time.Time        `json:"last_triggered_at,omitempty"`
+	TriggerCount    int               `json:"trigger_count"`
+	SnoozeCount     int               `json:"snooze_count"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
 
 	// Relations (loaded optionally)
 	Note *Note `json:"note,omitempty"`
+	User *User `json:"-"`
+	// NoteLoaded is set when the note was loaded with the reminder, so a nil
+	// Note means the note no longer exists
+	NoteLoaded bool `json:"-"`
 }
 
 // Reminder-specific domain errors
 var (
-	ErrReminderNotFound     = errors.New("reminder not found")
-	ErrInvalidRepeatConfig  = errors.New("invalid repeat configuration")
-	ErrInvalidRepeatType    = errors.New("invalid repeat type")
-	ErrInvalidReminderTitle = errors.New("reminder title is required")
+	ErrReminderNotFound        = errors.New("reminder not found")
+	ErrInvalidRepeatConfig     = errors.New("invalid repeat configuration")
+	ErrInvalidRepeatType       = errors.New("invalid repeat type")
+	ErrInvalidReminderTitle    = errors.New("reminder title is required")
+	ErrInvalidSnoozeDuration   = errors.New("invalid snooze duration")
+	ErrSnoozeLimitReached      = errors.New("snooze limit reached for this reminder")
+	ErrInvalidReminderPayload  = errors.New("invalid notification payload")
+	ErrMissingNextTrigger      = errors.New("reminder has no trigger time")
+	ErrInvalidRelativeSchedule = errors.New("invalid relative schedule")
 )
 
-// NewReminder creates a new Reminder with validation
-func NewReminder(noteID, userID int64, title string, scheduledAt time.Time) (*Reminder, error) {
+// MaxSnoozeDuration is the longest a reminder can be snoozed in one go
+const MaxSnoozeDuration = 30 * 24 * time.Hour
+
+// DefaultScheduleTolerance is how far in the past a reminder may be scheduled,
+// to allow for clock skew between client and server
+const DefaultScheduleTolerance = 60 * time.Second
+
+// NewReminder creates a new Reminder with validation. A schedule up to
+// tolerance in the past is accepted and fires straight away.
+func NewReminder(noteID, userID int64, title string, scheduledAt time.Time, tolerance time.Duration, now time.Time) (*Reminder, error) {
 	if title == "" {
 		return nil, ErrInvalidReminderTitle
 	}
-	if scheduledAt.Before(time.Now()) {
-		return nil, ErrInvalidScheduleTime
+	if err := checkScheduleTime(scheduledAt, tolerance, now); err != nil {
+		return nil, err
 	}
 
-	now := time.Now()
 	return &Reminder{
 		NoteID:        noteID,
 		UserID:        userID,
@@ -78,6 +195,14 @@ func NewReminder(noteID, userID int64, title string, scheduledAt time.Time) (*Re
 	}, nil
 }
 
+// checkScheduleTime rejects schedules more than tolerance before now
+func checkScheduleTime(scheduledAt time.Time, tolerance time.Duration, now time.Time) error {
+	if scheduledAt.Before(now.Add(-tolerance)) {
+		return ErrInvalidScheduleTime
+	}
+	return nil
+}
+
 // IsValidRepeatType checks if a repeat type is valid
 func IsValidRepeatType(repeatType RepeatType) bool {
 	switch repeatType {
@@ -107,6 +232,11 @@ func (r *Reminder) SetRepeat(repeatType RepeatType, config *RepeatConfig, endAt
 		}
 	}
 
+	// Relative reminders follow a single date, so they can't repeat
+	if r.Relative != nil && repeatType != RepeatTypeOnce {
+		return ErrInvalidRelativeSchedule
+	}
+
 	if repeatType == RepeatTypeMonthly {
 		if config == nil {
 			return ErrInvalidRepeatConfig
@@ -208,44 +338,38 @@ func (r *Reminder) calculateNextMonthly(from time.Time) time.Time {
 
 	// Get the time of day from the scheduled time
 	hour, min, sec := r.ScheduledAt.Clock()
-
-	// Start from the next month
-	nextMonth := from.AddDate(0, 1, 0)
-	year, month := nextMonth.Year(), nextMonth.Month()
-
-	var targetDay int
-	if r.RepeatConfig.Day == -1 {
-		// Last day of month
-		targetDay = lastDayOfMonth(year, month)
-	} else {
-		targetDay = r.RepeatConfig.Day
-		// Adjust if day doesn't exist in target month
-		lastDay := lastDayOfMonth(year, month)
-		if targetDay > lastDay {
+	loc := r.ScheduledAt.Location()
+
+	// Step from the first of the month so adding months can't overflow
+	// (January 31 plus one month would otherwise land in March)
+	from = from.In(loc)
+	firstOfMonth := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, loc)
+
+	// A day is missing from at most a few months in a row, so a year is plenty
+	for offset := 1; offset <= 12; offset++ {
+		month := firstOfMonth.AddDate(0, offset, 0)
+		year, m := month.Year(), month.Month()
+
+		lastDay := lastDayOfMonth(year, m)
+		targetDay := r.RepeatConfig.Day
+		if targetDay == -1 {
+			// Last day of month
+			targetDay = lastDay
+		} else if targetDay > lastDay {
+			if r.RepeatConfig.SkipMissingDay {
+				continue
+			}
+			// Clamp to the last day the month has
 			targetDay = lastDay
 		}
-	}
-
-	next := time.Date(year, month, targetDay, hour, min, sec, 0, r.ScheduledAt.Location())
 
-	// If the calculated time is still in the past, move to next month
-	if !next.After(from) {
-		nextMonth = nextMonth.AddDate(0, 1, 0)
-		year, month = nextMonth.Year(), nextMonth.Month()
-
-		if r.RepeatConfig.Day == -1 {
-			targetDay = lastDayOfMonth(year, month)
-		} else {
-			targetDay = r.RepeatConfig.Day
-			lastDay := lastDayOfMonth(year, month)
-			if targetDay > lastDay {
-				targetDay = lastDay
-			}
+		next := time.Date(year, m, targetDay, hour, min, sec, 0, loc)
+		if next.After(from) {
+			return next
 		}
-		next = time.Date(year, month, targetDay, hour, min, sec, 0, r.ScheduledAt.Location())
 	}
 
-	return next
+	return r.ScheduledAt
 }
 
 // lastDayOfMonth returns the last day of the given month
@@ -257,10 +381,11 @@ func lastDayOfMonth(year int, month time.Month) int {
 }
 
 // UpdateNextTrigger updates the next trigger time after a successful trigger
-func (r *Reminder) UpdateNextTrigger() {
-	now := time.Now()
+// at now
+func (r *Reminder) UpdateNextTrigger(now time.Time) {
 	r.LastTriggeredAt = &now
 	r.TriggerCount++
+	r.SnoozeCount = 0
 	r.UpdatedAt = now
 
 	if r.RepeatType == RepeatTypeOnce {
@@ -277,6 +402,61 @@ func (r *Reminder) UpdateNextTrigger() {
 	}
 }
 
+// NextOccurrences returns up to count upcoming trigger times, starting with
+// NextTriggerAt and following the repeat rule until RepeatEndAt. The first
+// occurrence is always included, as the reminder fires at least once.
+func (r *Reminder) NextOccurrences(count int) []time.Time {
+	return r.occurrences(count, time.Time{})
+}
+
+// OccurrencesUntil returns the trigger times from NextTriggerAt up to and
+// including until, at most limit of them
+func (r *Reminder) OccurrencesUntil(until time.Time, limit int) []time.Time {
+	if r.NextTriggerAt.After(until) {
+		return []time.Time{}
+	}
+	return r.occurrences(limit, until)
+}
+
+// occurrences walks the repeat rule from NextTriggerAt, stopping after count
+// times, at RepeatEndAt, or past until when it is set
+func (r *Reminder) occurrences(count int, until time.Time) []time.Time {
+	occurrences := make([]time.Time, 0, count)
+	next := r.NextTriggerAt
+	for len(occurrences) < count {
+		occurrences = append(occurrences, next)
+		if r.RepeatType == RepeatTypeOnce {
+			break
+		}
+
+		following := r.CalculateNextTrigger(next)
+		if !following.After(next) || (r.RepeatEndAt != nil && following.After(*r.RepeatEndAt)) {
+			break
+		}
+		if !until.IsZero() && following.After(until) {
+			break
+		}
+		next = following
+	}
+	return occurrences
+}
+
+// SkipOccurrence advances the reminder past its current occurrence without
+// recording a trigger. One-time reminders are disabled.
+func (r *Reminder) SkipOccurrence(now time.Time) {
+	r.UpdatedAt = now
+
+	if r.RepeatType == RepeatTypeOnce {
+		r.IsEnabled = false
+		return
+	}
+
+	r.NextTriggerAt = r.CalculateNextTrigger(now)
+	if r.RepeatEndAt != nil && r.NextTriggerAt.After(*r.RepeatEndAt) {
+		r.IsEnabled = false
+	}
+}
+
 // Enable enables the reminder
 func (r *Reminder) Enable() {
 	r.IsEnabled = true
@@ -295,10 +475,21 @@ func (r *Reminder) Toggle() {
 	r.UpdatedAt = time.Now()
 }
 
-// Snooze delays the next trigger by the specified duration
-func (r *Reminder) Snooze(duration time.Duration) {
-	r.NextTriggerAt = time.Now().Add(duration)
-	r.UpdatedAt = time.Now()
+// Snooze delays the next trigger to the specified duration after now.
+// maxSnoozes limits how many times the reminder can be snoozed before it
+// fires again; zero or less means unlimited.
+func (r *Reminder) Snooze(duration time.Duration, maxSnoozes int, now time.Time) error {
+	if duration <= 0 || duration > MaxSnoozeDuration {
+		return ErrInvalidSnoozeDuration
+	}
+	if maxSnoozes > 0 && r.SnoozeCount >= maxSnoozes {
+		return ErrSnoozeLimitReached
+	}
+
+	r.NextTriggerAt = now.Add(duration)
+	r.SnoozeCount++
+	r.UpdatedAt = now
+	return nil
 }
 
 // UpdateTitle updates the reminder title
@@ -317,20 +508,114 @@ func (r *Reminder) UpdateMessage(message string) {
 	r.UpdatedAt = time.Now()
 }
 
+// SetPayload validates and sets the notification payload; nil or an empty
+// payload restores the defaults
+func (r *Reminder) SetPayload(payload *ReminderPayload) error {
+	if payload != nil {
+		if err := payload.Validate(); err != nil {
+			return err
+		}
+		if payload.IsEmpty() {
+			payload = nil
+		}
+	}
+	r.Payload = payload
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
 // UpdateScheduledAt updates the scheduled time and recalculates next trigger
-func (r *Reminder) UpdateScheduledAt(scheduledAt time.Time) error {
-	if scheduledAt.Before(time.Now()) {
-		return ErrInvalidScheduleTime
+func (r *Reminder) UpdateScheduledAt(scheduledAt time.Time, tolerance time.Duration, now time.Time) error {
+	if err := checkScheduleTime(scheduledAt, tolerance, now); err != nil {
+		return err
 	}
 	r.ScheduledAt = scheduledAt
 	r.NextTriggerAt = scheduledAt
+	r.UpdatedAt = now
+	return nil
+}
+
+// SetRelative makes a one-time reminder follow a date property of its note.
+// Nil or an empty property ID makes it absolute again, firing at ScheduledAt.
+// Call ResolveRelative with the note to compute the trigger time.
+func (r *Reminder) SetRelative(schedule *RelativeSchedule) error {
+	if schedule == nil || schedule.PropertyID == "" {
+		if r.Relative != nil {
+			r.Relative = nil
+			r.NextTriggerAt = r.ScheduledAt
+		}
+		r.UpdatedAt = time.Now()
+		return nil
+	}
+
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+	if r.RepeatType != RepeatTypeOnce {
+		return ErrInvalidRelativeSchedule
+	}
+
+	r.Relative = schedule
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// ResolveRelative points a relative reminder's next trigger at its note's
+// date property, falling back to ScheduledAt when the note has no such date.
+// Disabled or snoozed reminders, and reminders without a loaded note, are left
+// alone. It reports whether the next trigger changed.
+func (r *Reminder) ResolveRelative(note *Note) bool {
+	if r.Relative == nil || note == nil || !r.IsEnabled || r.SnoozeCount > 0 {
+		return false
+	}
+
+	next, ok := r.Relative.Resolve(note.Properties)
+	if !ok {
+		next = r.ScheduledAt
+	}
+	if next.Equal(r.NextTriggerAt) {
+		return false
+	}
+
+	r.NextTriggerAt = next
 	r.UpdatedAt = time.Now()
+	return true
+}
+
+// EnsureNextTrigger fills in a missing NextTriggerAt from ScheduledAt, so a
+// saved reminder never has a zero trigger time
+func (r *Reminder) EnsureNextTrigger() error {
+	if !r.NextTriggerAt.IsZero() {
+		return nil
+	}
+	if r.ScheduledAt.IsZero() {
+		return ErrMissingNextTrigger
+	}
+	r.NextTriggerAt = r.ScheduledAt
 	return nil
 }
 
-// IsDue returns true if the reminder is due for triggering
-func (r *Reminder) IsDue() bool {
-	return r.IsEnabled && time.Now().After(r.NextTriggerAt)
+// IsDue returns true if the reminder is due for triggering at now
+func (r *Reminder) IsDue(now time.Time) bool {
+	return r.IsEnabled && now.After(r.NextTriggerAt)
+}
+
+// IsNoteActive returns false if the reminder's note is archived, deleted or
+// gone. Reminders loaded without their note are treated as active.
+func (r *Reminder) IsNoteActive() bool {
+	if r.Note == nil {
+		return !r.NoteLoaded
+	}
+	return !r.Note.IsArchived && !r.Note.IsDeleted
+}
+
+// IsOwnerActive returns false if the reminder's owner has deactivated their account.
+// Reminders loaded without their owner are treated as active.
+func (r *Reminder) IsOwnerActive() bool {
+	if r.User == nil {
+		return true
+	}
+	return r.User.IsActive
 }
 
 // IsExpired returns true if the reminder has reached its end date
@@ -0,0 +1,438 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestReminder(t *testing.T) *Reminder {
+	t.Helper()
+	reminder, err := NewReminder(1, 1, "Check in", time.Now().Add(time.Hour), 0, time.Now())
+	require.NoError(t, err)
+	return reminder
+}
+
+func TestReminder_Snooze(t *testing.T) {
+	tests := []struct {
+		name        string
+		duration    time.Duration
+		snoozeCount int
+		maxSnoozes  int
+		expectedErr error
+	}{
+		{
+			name:       "valid snooze",
+			duration:   10 * time.Minute,
+			maxSnoozes: 3,
+		},
+		{
+			name:        "unlimited snoozes",
+			duration:    time.Hour,
+			snoozeCount: 100,
+			maxSnoozes:  0,
+		},
+		{
+			name:        "zero duration",
+			duration:    0,
+			maxSnoozes:  3,
+			expectedErr: ErrInvalidSnoozeDuration,
+		},
+		{
+			name:        "negative duration",
+			duration:    -time.Minute,
+			maxSnoozes:  3,
+			expectedErr: ErrInvalidSnoozeDuration,
+		},
+		{
+			name:        "duration above maximum",
+			duration:    MaxSnoozeDuration + time.Minute,
+			maxSnoozes:  3,
+			expectedErr: ErrInvalidSnoozeDuration,
+		},
+		{
+			name:        "limit reached",
+			duration:    10 * time.Minute,
+			snoozeCount: 3,
+			maxSnoozes:  3,
+			expectedErr: ErrSnoozeLimitReached,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reminder := newTestReminder(t)
+			reminder.SnoozeCount = tt.snoozeCount
+			previousTrigger := reminder.NextTriggerAt
+			now := time.Date(2025, time.March, 10, 9, 0, 0, 0, time.UTC)
+
+			err := reminder.Snooze(tt.duration, tt.maxSnoozes, now)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Equal(t, tt.snoozeCount, reminder.SnoozeCount)
+				assert.Equal(t, previousTrigger, reminder.NextTriggerAt)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.snoozeCount+1, reminder.SnoozeCount)
+			assert.Equal(t, now.Add(tt.duration), reminder.NextTriggerAt)
+			assert.Equal(t, now, reminder.UpdatedAt)
+		})
+	}
+}
+
+func TestReminder_UpdateNextTrigger_ResetsSnoozeCount(t *testing.T) {
+	reminder := newTestReminder(t)
+	require.NoError(t, reminder.SetRepeat(RepeatTypeDaily, nil, nil))
+	now := time.Now()
+	require.NoError(t, reminder.Snooze(time.Minute, 2, now))
+	require.NoError(t, reminder.Snooze(time.Minute, 2, now))
+	assert.ErrorIs(t, reminder.Snooze(time.Minute, 2, now), ErrSnoozeLimitReached)
+
+	reminder.UpdateNextTrigger(now)
+
+	assert.Equal(t, 0, reminder.SnoozeCount)
+	assert.NoError(t, reminder.Snooze(time.Minute, 2, now))
+}
+
+func TestReminder_UpdateNextTrigger_Recurrence(t *testing.T) {
+	// Monday 10 March 2025, 09:00 UTC
+	scheduled := time.Date(2025, time.March, 10, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		repeatType RepeatType
+		config     *RepeatConfig
+		triggers   []time.Time
+		expected   []time.Time
+	}{
+		{
+			name:       "daily",
+			repeatType: RepeatTypeDaily,
+			triggers: []time.Time{
+				scheduled,
+				scheduled.AddDate(0, 0, 1).Add(30 * time.Second),
+			},
+			expected: []time.Time{
+				scheduled.AddDate(0, 0, 1),
+				scheduled.AddDate(0, 0, 2),
+			},
+		},
+		{
+			name:       "weekly on monday and thursday",
+			repeatType: RepeatTypeWeekly,
+			config:     &RepeatConfig{Days: []int{1, 4}},
+			triggers: []time.Time{
+				scheduled,
+				scheduled.AddDate(0, 0, 3),
+			},
+			expected: []time.Time{
+				scheduled.AddDate(0, 0, 3),
+				scheduled.AddDate(0, 0, 7),
+			},
+		},
+		{
+			name:       "monthly on the last day",
+			repeatType: RepeatTypeMonthly,
+			config:     &RepeatConfig{Day: -1},
+			triggers: []time.Time{
+				time.Date(2025, time.March, 31, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, time.April, 30, 9, 0, 0, 0, time.UTC),
+			},
+			expected: []time.Time{
+				time.Date(2025, time.April, 30, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, time.May, 31, 9, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reminder := newTestReminder(t)
+			reminder.ScheduledAt = scheduled
+			reminder.NextTriggerAt = scheduled
+			reminder.RepeatType = tt.repeatType
+			reminder.RepeatConfig = tt.config
+
+			for i, now := range tt.triggers {
+				reminder.UpdateNextTrigger(now)
+
+				assert.Equal(t, tt.expected[i], reminder.NextTriggerAt)
+				require.NotNil(t, reminder.LastTriggeredAt)
+				assert.Equal(t, now, *reminder.LastTriggeredAt)
+				assert.True(t, reminder.IsEnabled)
+			}
+			assert.Equal(t, len(tt.triggers), reminder.TriggerCount)
+		})
+	}
+}
+
+func TestReminder_UpdateNextTrigger_DisablesPastRepeatEnd(t *testing.T) {
+	scheduled := time.Date(2025, time.March, 10, 9, 0, 0, 0, time.UTC)
+	end := scheduled.AddDate(0, 0, 1).Add(-time.Minute)
+
+	reminder := newTestReminder(t)
+	reminder.ScheduledAt = scheduled
+	reminder.NextTriggerAt = scheduled
+	reminder.RepeatType = RepeatTypeDaily
+	reminder.RepeatEndAt = &end
+
+	reminder.UpdateNextTrigger(scheduled)
+
+	assert.False(t, reminder.IsEnabled)
+}
+
+func TestReminder_IsDue(t *testing.T) {
+	reminder := newTestReminder(t)
+	trigger := time.Date(2025, time.March, 10, 9, 0, 0, 0, time.UTC)
+	reminder.NextTriggerAt = trigger
+
+	assert.False(t, reminder.IsDue(trigger.Add(-time.Second)))
+	assert.False(t, reminder.IsDue(trigger))
+	assert.True(t, reminder.IsDue(trigger.Add(time.Second)))
+
+	reminder.Disable()
+	assert.False(t, reminder.IsDue(trigger.Add(time.Second)))
+}
+
+func TestNewReminder_ScheduleTolerance(t *testing.T) {
+	now := time.Date(2025, time.March, 1, 9, 0, 0, 0, time.UTC)
+
+	reminder, err := NewReminder(1, 1, "Skewed", now.Add(-5*time.Second), DefaultScheduleTolerance, now)
+	require.NoError(t, err)
+	assert.True(t, reminder.IsDue(now))
+	assert.Equal(t, now, reminder.CreatedAt)
+
+	_, err = NewReminder(1, 1, "Stale", now.Add(-time.Hour), DefaultScheduleTolerance, now)
+	assert.ErrorIs(t, err, ErrInvalidScheduleTime)
+
+	_, err = NewReminder(1, 1, "Strict", now.Add(-5*time.Second), 0, now)
+	assert.ErrorIs(t, err, ErrInvalidScheduleTime)
+
+	// The check is against the caller's clock, not the wall clock
+	_, err = NewReminder(1, 1, "Frozen", now.Add(time.Hour), 0, now)
+	assert.NoError(t, err)
+}
+
+func TestReminder_UpdateScheduledAt_ScheduleTolerance(t *testing.T) {
+	reminder := newTestReminder(t)
+	now := time.Date(2025, time.March, 1, 9, 0, 0, 0, time.UTC)
+
+	skewed := now.Add(-5 * time.Second)
+	require.NoError(t, reminder.UpdateScheduledAt(skewed, DefaultScheduleTolerance, now))
+	assert.Equal(t, skewed, reminder.NextTriggerAt)
+	assert.Equal(t, now, reminder.UpdatedAt)
+
+	err := reminder.UpdateScheduledAt(now.Add(-time.Hour), DefaultScheduleTolerance, now)
+	assert.ErrorIs(t, err, ErrInvalidScheduleTime)
+	assert.Equal(t, skewed, reminder.ScheduledAt)
+}
+
+func TestReminder_EnsureNextTrigger(t *testing.T) {
+	reminder := newTestReminder(t)
+	reminder.NextTriggerAt = time.Time{}
+
+	require.NoError(t, reminder.EnsureNextTrigger())
+	assert.Equal(t, reminder.ScheduledAt, reminder.NextTriggerAt)
+
+	reminder.NextTriggerAt = time.Time{}
+	reminder.ScheduledAt = time.Time{}
+	assert.ErrorIs(t, reminder.EnsureNextTrigger(), ErrMissingNextTrigger)
+}
+
+func TestReminder_NextOccurrences(t *testing.T) {
+	start := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	t.Run("once", func(t *testing.T) {
+		reminder, err := NewReminder(1, 1, "Once", start, 0, time.Now())
+		require.NoError(t, err)
+
+		assert.Equal(t, []time.Time{start}, reminder.NextOccurrences(5))
+	})
+
+	t.Run("daily", func(t *testing.T) {
+		reminder, err := NewReminder(1, 1, "Daily", start, 0, time.Now())
+		require.NoError(t, err)
+		require.NoError(t, reminder.SetRepeat(RepeatTypeDaily, nil, nil))
+
+		assert.Equal(t, []time.Time{start, start.AddDate(0, 0, 1), start.AddDate(0, 0, 2)}, reminder.NextOccurrences(3))
+	})
+
+	t.Run("stops at end date", func(t *testing.T) {
+		reminder, err := NewReminder(1, 1, "Daily", start, 0, time.Now())
+		require.NoError(t, err)
+		end := start.AddDate(0, 0, 1)
+		require.NoError(t, reminder.SetRepeat(RepeatTypeDaily, nil, &end))
+
+		assert.Equal(t, []time.Time{start, end}, reminder.NextOccurrences(5))
+	})
+
+	t.Run("monthly", func(t *testing.T) {
+		jan15 := time.Date(time.Now().Year()+1, time.January, 15, 9, 0, 0, 0, time.UTC)
+		reminder, err := NewReminder(1, 1, "Rent", jan15, 0, time.Now())
+		require.NoError(t, err)
+		require.NoError(t, reminder.SetRepeat(RepeatTypeMonthly, &RepeatConfig{Day: 15}, nil))
+
+		assert.Equal(t, []time.Time{jan15, jan15.AddDate(0, 1, 0), jan15.AddDate(0, 2, 0)}, reminder.NextOccurrences(3))
+	})
+}
+
+func TestReminder_CalculateNextMonthly_MissingDay(t *testing.T) {
+	jan31 := time.Date(2025, time.January, 31, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		config   RepeatConfig
+		from     time.Time
+		expected time.Time
+	}{
+		{
+			name:     "clamps to the end of February by default",
+			config:   RepeatConfig{Day: 31},
+			from:     jan31,
+			expected: time.Date(2025, time.February, 28, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "returns to day 31 after a clamped month",
+			config:   RepeatConfig{Day: 31},
+			from:     time.Date(2025, time.February, 28, 9, 0, 0, 0, time.UTC),
+			expected: time.Date(2025, time.March, 31, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "skips February when asked",
+			config:   RepeatConfig{Day: 31, SkipMissingDay: true},
+			from:     jan31,
+			expected: time.Date(2025, time.March, 31, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "skips 30-day months when asked",
+			config:   RepeatConfig{Day: 31, SkipMissingDay: true},
+			from:     time.Date(2025, time.March, 31, 9, 0, 0, 0, time.UTC),
+			expected: time.Date(2025, time.May, 31, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "leap year February has day 29",
+			config:   RepeatConfig{Day: 29, SkipMissingDay: true},
+			from:     time.Date(2024, time.January, 29, 9, 0, 0, 0, time.UTC),
+			expected: time.Date(2024, time.February, 29, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "last day ignores skipping",
+			config:   RepeatConfig{Day: -1, SkipMissingDay: true},
+			from:     jan31,
+			expected: time.Date(2025, time.February, 28, 9, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			reminder := &Reminder{ScheduledAt: jan31, RepeatType: RepeatTypeMonthly, RepeatConfig: &config}
+
+			assert.Equal(t, tt.expected, reminder.CalculateNextTrigger(tt.from))
+		})
+	}
+}
+
+func TestReminder_OccurrencesUntil(t *testing.T) {
+	start := time.Now().Add(time.Hour).Truncate(time.Second)
+	reminder, err := NewReminder(1, 1, "Daily", start, 0, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, reminder.SetRepeat(RepeatTypeDaily, nil, nil))
+
+	week := start.AddDate(0, 0, 7)
+	assert.Len(t, reminder.OccurrencesUntil(week, 100), 8, "both ends of the window are included")
+	assert.Len(t, reminder.OccurrencesUntil(week, 3), 3)
+	assert.Empty(t, reminder.OccurrencesUntil(start.Add(-time.Minute), 100))
+}
+
+func TestReminder_SetPayload(t *testing.T) {
+	badge := func(n int) *int { return &n }
+
+	valid := []*ReminderPayload{
+		{ClickURL: "/notes?id=5"},
+		{ClickURL: "https://app.example.com/notes/5"},
+		{ClickURL: "notinote://notes/5"},
+		{Sound: "chime.wav"},
+		{Sound: SoundNone},
+		{Badge: badge(0)},
+		{Badge: badge(MaxReminderBadge)},
+	}
+	for _, payload := range valid {
+		reminder := newTestReminder(t)
+		assert.NoError(t, reminder.SetPayload(payload), "%+v", payload)
+		assert.Equal(t, payload, reminder.Payload)
+	}
+
+	invalid := []*ReminderPayload{
+		{ClickURL: "//evil.example.com"},
+		{ClickURL: "javascript:alert(1)"},
+		{ClickURL: "notes/5"},
+		{Sound: "../chime"},
+		{Badge: badge(-1)},
+		{Badge: badge(MaxReminderBadge + 1)},
+	}
+	for _, payload := range invalid {
+		reminder := newTestReminder(t)
+		assert.ErrorIs(t, reminder.SetPayload(payload), ErrInvalidReminderPayload, "%+v", payload)
+		assert.Nil(t, reminder.Payload)
+	}
+
+	reminder := newTestReminder(t)
+	require.NoError(t, reminder.SetPayload(&ReminderPayload{Sound: "chime"}))
+	require.NoError(t, reminder.SetPayload(&ReminderPayload{}))
+	assert.Nil(t, reminder.Payload, "an empty payload restores the defaults")
+}
+
+func TestRelativeSchedule_Validate(t *testing.T) {
+	assert.NoError(t, (&RelativeSchedule{PropertyID: "due", OffsetMinutes: -24 * 60}).Validate())
+	assert.ErrorIs(t, (&RelativeSchedule{OffsetMinutes: -60}).Validate(), ErrInvalidRelativeSchedule)
+	assert.ErrorIs(t, (&RelativeSchedule{PropertyID: "due", OffsetMinutes: MaxRelativeOffsetMinutes + 1}).Validate(), ErrInvalidRelativeSchedule)
+}
+
+func TestReminder_SetRelative_OneTimeOnly(t *testing.T) {
+	reminder := newTestReminder(t)
+	require.NoError(t, reminder.SetRelative(&RelativeSchedule{PropertyID: "due"}))
+	assert.ErrorIs(t, reminder.SetRepeat(RepeatTypeDaily, nil, nil), ErrInvalidRelativeSchedule)
+
+	require.NoError(t, reminder.SetRelative(nil))
+	require.NoError(t, reminder.SetRepeat(RepeatTypeDaily, nil, nil))
+	assert.ErrorIs(t, reminder.SetRelative(&RelativeSchedule{PropertyID: "due"}), ErrInvalidRelativeSchedule)
+}
+
+func TestReminder_ResolveRelative(t *testing.T) {
+	reminder := newTestReminder(t)
+	fallback := reminder.ScheduledAt
+	require.NoError(t, reminder.SetRelative(&RelativeSchedule{PropertyID: "due", OffsetMinutes: -24 * 60}))
+	note := &Note{ID: 1, Properties: map[string]interface{}{"due": "2030-03-20T09:00:00Z"}}
+
+	assert.True(t, reminder.ResolveRelative(note))
+	assert.Equal(t, time.Date(2030, time.March, 19, 9, 0, 0, 0, time.UTC), reminder.NextTriggerAt)
+	assert.False(t, reminder.ResolveRelative(note), "unchanged date")
+
+	// Date-only values are midnight UTC
+	note.Properties["due"] = "2030-04-01"
+	assert.True(t, reminder.ResolveRelative(note))
+	assert.Equal(t, time.Date(2030, time.March, 31, 0, 0, 0, 0, time.UTC), reminder.NextTriggerAt)
+
+	// Without a date, the absolute schedule applies
+	delete(note.Properties, "due")
+	assert.True(t, reminder.ResolveRelative(note))
+	assert.Equal(t, fallback, reminder.NextTriggerAt)
+
+	note.Properties["due"] = "not a date"
+	assert.False(t, reminder.ResolveRelative(note))
+
+	// Snoozes and disabled reminders are left alone
+	note.Properties["due"] = "2030-03-20T09:00:00Z"
+	reminder.SnoozeCount = 1
+	assert.False(t, reminder.ResolveRelative(note))
+	reminder.SnoozeCount = 0
+	reminder.Disable()
+	assert.False(t, reminder.ResolveRelative(note))
+	assert.Equal(t, fallback, reminder.NextTriggerAt)
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// SharePermission is the access level granted by a shared link
+type SharePermission string
+
+const (
+	SharePermissionRead SharePermission = "read"
+)
+
+// SharedLink grants access to a note through an unguessable token, without requiring login
+type SharedLink struct {
+	ID         int64           `json:"id"`
+	Token      string          `json:"token"`
+	NoteID     int64           `json:"note_id"`
+	UserID     int64           `json:"-"` // Owner who created the link
+	Permission SharePermission `json:"permission"`
+	ExpiresAt  *time.Time      `json:"expires_at,omitempty"`
+	RevokedAt  *time.Time      `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// Shared link errors
+var (
+	ErrSharedLinkNotFound     = errors.New("shared link not found")
+	ErrSharedLinkExpired      = errors.New("shared link has expired or been revoked")
+	ErrInvalidSharePermission = errors.New("invalid share permission")
+	ErrInvalidShareExpiry     = errors.New("share expiry must be in the future")
+)
+
+// IsValidSharePermission checks if the permission is supported
+func IsValidSharePermission(p SharePermission) bool {
+	return p == SharePermissionRead
+}
+
+// NewSharedLink creates a new SharedLink with validation
+func NewSharedLink(noteID, userID int64, token string, permission SharePermission, expiresAt *time.Time) (*SharedLink, error) {
+	if permission == "" {
+		permission = SharePermissionRead
+	}
+	if !IsValidSharePermission(permission) {
+		return nil, ErrInvalidSharePermission
+	}
+
+	now := time.Now()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, ErrInvalidShareExpiry
+	}
+
+	return &SharedLink{
+		Token:      token,
+		NoteID:     noteID,
+		UserID:     userID,
+		Permission: permission,
+		ExpiresAt:  expiresAt,
+		CreatedAt:  now,
+	}, nil
+}
+
+// IsActive returns true if the link has not been revoked and has not expired
+func (l *SharedLink) IsActive(now time.Time) bool {
+	if l.RevokedAt != nil {
+		return false
+	}
+	return l.ExpiresAt == nil || now.Before(*l.ExpiresAt)
+}
+
+// Revoke marks the link as revoked
+func (l *SharedLink) Revoke() {
+	now := time.Now()
+	l.RevokedAt = &now
+}
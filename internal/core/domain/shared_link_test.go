@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSharedLink(t *testing.T) {
+	link, err := NewSharedLink(1, 2, "token", "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, SharePermissionRead, link.Permission)
+	assert.True(t, link.IsActive(time.Now()))
+
+	_, err = NewSharedLink(1, 2, "token", "write", nil)
+	assert.ErrorIs(t, err, ErrInvalidSharePermission)
+
+	past := time.Now().Add(-time.Minute)
+	_, err = NewSharedLink(1, 2, "token", SharePermissionRead, &past)
+	assert.ErrorIs(t, err, ErrInvalidShareExpiry)
+}
+
+func TestSharedLink_IsActive(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	link, err := NewSharedLink(1, 2, "token", SharePermissionRead, &expiresAt)
+	require.NoError(t, err)
+
+	assert.True(t, link.IsActive(time.Now()))
+	assert.False(t, link.IsActive(expiresAt.Add(time.Second)))
+
+	link.Revoke()
+	assert.False(t, link.IsActive(time.Now()))
+}
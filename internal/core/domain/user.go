@@ -2,6 +2,7 @@ package domain
 
 import (
 	"errors"
+	"net/url"
 	"regexp"
 	"time"
 )
@@ -15,6 +16,14 @@ const (
 	AuthProviderFacebook AuthProvider = "facebook"
 )
 
+// Role determines what a user may do beyond managing their own data
+type Role string
+
+const (
+	RoleUser  Role = "user"
+	RoleAdmin Role = "admin"
+)
+
 // User represents a user entity in the domain
 type User struct {
 	ID           int64        `json:"id"`
@@ -24,6 +33,9 @@ type User struct {
 	Provider     AuthProvider `json:"provider"`
 	ProviderID   string       `json:"provider_id,omitempty"` // OAuth provider user ID
 	AvatarURL    string       `json:"avatar_url,omitempty"`
+	NamePinned   bool         `json:"name_pinned"` // Keep the local name instead of the OAuth provider's
+	Language     string       `json:"language"`    // Language of notifications, e.g. "en" or "th"
+	Role         Role         `json:"role"`
 	IsActive     bool         `json:"is_active"`
 	CreatedAt    time.Time    `json:"created_at"`
 	UpdatedAt    time.Time    `json:"updated_at"`
@@ -39,12 +51,20 @@ type OAuthUserInfo struct {
 }
 
 var (
-	ErrInvalidEmail    = errors.New("invalid email format")
-	ErrInvalidName     = errors.New("name must be between 1 and 255 characters")
-	ErrPasswordTooWeak = errors.New("password must be at least 8 characters and contain uppercase, lowercase, number, and special character")
-	ErrEmailRequired   = errors.New("email is required")
+	ErrInvalidEmail        = errors.New("invalid email format")
+	ErrInvalidName         = errors.New("name must be between 1 and 255 characters")
+	ErrPasswordTooWeak     = errors.New("password must be at least 8 characters and contain uppercase, lowercase, number, and special character")
+	ErrEmailRequired       = errors.New("email is required")
+	ErrInvalidAvatarURL    = errors.New("avatar URL must be an absolute http(s) URL of at most 500 characters")
+	ErrUnsupportedLanguage = errors.New("unsupported language")
 )
 
+// DefaultLanguage is the language of users who haven't chosen one
+const DefaultLanguage = "en"
+
+// MaxAvatarURLLength matches the users.avatar_url column size
+const MaxAvatarURLLength = 500
+
 // emailRegex validates email format
 var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
 
@@ -64,6 +84,8 @@ func NewUser(email, name, passwordHash string) (*User, error) {
 		Name:         name,
 		PasswordHash: passwordHash,
 		Provider:     AuthProviderEmail,
+		Language:     DefaultLanguage,
+		Role:         RoleUser,
 		IsActive:     true,
 		CreatedAt:    now,
 		UpdatedAt:    now,
@@ -95,6 +117,8 @@ func NewOAuthUser(info *OAuthUserInfo) (*User, error) {
 		Provider:   info.Provider,
 		ProviderID: info.ProviderID,
 		AvatarURL:  info.AvatarURL,
+		Language:   DefaultLanguage,
+		Role:       RoleUser,
 		IsActive:   true,
 		CreatedAt:  now,
 		UpdatedAt:  now,
@@ -122,6 +146,24 @@ func ValidateName(name string) error {
 	return nil
 }
 
+// ValidateAvatarURL validates an avatar URL. An empty URL is valid and clears the avatar.
+func ValidateAvatarURL(avatarURL string) error {
+	if avatarURL == "" {
+		return nil
+	}
+
+	if len(avatarURL) > MaxAvatarURLLength {
+		return ErrInvalidAvatarURL
+	}
+
+	parsed, err := url.Parse(avatarURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return ErrInvalidAvatarURL
+	}
+
+	return nil
+}
+
 // ValidatePassword validates password strength
 func ValidatePassword(password string) error {
 	if len(password) < 8 {
@@ -142,12 +184,23 @@ func ValidatePassword(password string) error {
 	return nil
 }
 
+// SetLanguage sets the language notifications are sent in. Callers check it
+// is supported.
+func (u *User) SetLanguage(language string) {
+	u.Language = language
+	u.UpdatedAt = time.Now()
+}
+
 // UpdateProfile updates user profile information
 func (u *User) UpdateProfile(name, avatarURL string) error {
 	if err := ValidateName(name); err != nil {
 		return err
 	}
 
+	if err := ValidateAvatarURL(avatarURL); err != nil {
+		return err
+	}
+
 	u.Name = name
 	u.AvatarURL = avatarURL
 	u.UpdatedAt = time.Now()
@@ -155,6 +208,28 @@ func (u *User) UpdateProfile(name, avatarURL string) error {
 	return nil
 }
 
+// ApplyOAuthProfile copies the provider's name and avatar onto the user,
+// keeping a pinned name. It returns true if anything changed.
+func (u *User) ApplyOAuthProfile(info *OAuthUserInfo) bool {
+	changed := false
+
+	if !u.NamePinned && info.Name != "" && u.Name != info.Name {
+		u.Name = info.Name
+		changed = true
+	}
+
+	if u.AvatarURL != info.AvatarURL {
+		u.AvatarURL = info.AvatarURL
+		changed = true
+	}
+
+	if changed {
+		u.UpdatedAt = time.Now()
+	}
+
+	return changed
+}
+
 // Deactivate marks user as inactive
 func (u *User) Deactivate() {
 	u.IsActive = false
@@ -171,3 +246,15 @@ func (u *User) Activate() {
 func (u *User) IsOAuthUser() bool {
 	return u.Provider != AuthProviderEmail
 }
+
+// IsAdmin returns true if the user has the admin role
+func (u *User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
+// IsEmailVerified returns true if the user's email address has been verified.
+// OAuth providers only hand out verified addresses; there is no verification
+// flow for email registrations yet, so those are reported as unverified.
+func (u *User) IsEmailVerified() bool {
+	return u.IsOAuthUser()
+}
@@ -0,0 +1,270 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Apply filters and sorts the view's rows (the database note's children)
+// using each row's Properties. Rows keep their input order where the sorts
+// don't distinguish them, and rows missing a sorted property go last.
+func (m *ViewMetadata) Apply(rows []*Note) []*Note {
+	matched := make([]*Note, 0, len(rows))
+	for _, row := range rows {
+		if m.matches(row) {
+			matched = append(matched, row)
+		}
+	}
+
+	if len(m.Sorts) == 0 {
+		return matched
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		for _, s := range m.Sorts {
+			property := m.Property(s.PropertyID)
+			if property == nil {
+				continue
+			}
+
+			a, b := matched[i].Properties[s.PropertyID], matched[j].Properties[s.PropertyID]
+			aEmpty, bEmpty := isEmptyValue(a), isEmptyValue(b)
+			if aEmpty || bEmpty {
+				if aEmpty == bEmpty {
+					continue
+				}
+				return bEmpty
+			}
+
+			cmp, ok := compareValues(property.Type, a, b)
+			if !ok || cmp == 0 {
+				continue
+			}
+			if s.Direction == "desc" {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return false
+	})
+
+	return matched
+}
+
+// matches returns true if the row passes every filter
+func (m *ViewMetadata) matches(row *Note) bool {
+	for _, filter := range m.Filters {
+		property := m.Property(filter.PropertyID)
+		if property == nil {
+			return false
+		}
+		if !filter.matches(property.Type, row.Properties[filter.PropertyID]) {
+			return false
+		}
+	}
+	return true
+}
+
+// matches returns true if value satisfies the filter for a property of the given type
+func (f ViewFilter) matches(propertyType PropertyType, value interface{}) bool {
+	switch f.Operator {
+	case FilterIsEmpty:
+		return isEmptyValue(value)
+	case FilterIsNotEmpty:
+		return !isEmptyValue(value)
+	case FilterEquals:
+		return valuesEqual(propertyType, value, f.Value)
+	case FilterNotEquals:
+		return !valuesEqual(propertyType, value, f.Value)
+	case FilterContains:
+		return containsValue(propertyType, value, f.Value)
+	case FilterNotContains:
+		return !containsValue(propertyType, value, f.Value)
+	}
+
+	if isEmptyValue(value) {
+		return false
+	}
+	cmp, ok := compareValues(propertyType, value, f.Value)
+	if !ok {
+		return false
+	}
+
+	switch f.Operator {
+	case FilterGreaterThan:
+		return cmp > 0
+	case FilterGreaterThanOrEqual:
+		return cmp >= 0
+	case FilterLessThan:
+		return cmp < 0
+	case FilterLessThanOrEqual:
+		return cmp <= 0
+	}
+	return false
+}
+
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []interface{}:
+		return len(v) == 0
+	case []string:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+func valuesEqual(propertyType PropertyType, value, target interface{}) bool {
+	if isEmptyValue(value) || target == nil {
+		return isEmptyValue(value) && isEmptyValue(target)
+	}
+
+	switch propertyType {
+	case PropertyTypeCheckbox:
+		a, okA := toBool(value)
+		b, okB := toBool(target)
+		return okA && okB && a == b
+	case PropertyTypeNumber, PropertyTypeDate:
+		cmp, ok := compareValues(propertyType, value, target)
+		return ok && cmp == 0
+	}
+	return strings.EqualFold(toText(value), toText(target))
+}
+
+// containsValue matches a substring for text properties and an option for multi-selects
+func containsValue(propertyType PropertyType, value, target interface{}) bool {
+	if isEmptyValue(value) {
+		return false
+	}
+
+	if propertyType == PropertyTypeMultiSelect {
+		for _, option := range toList(value) {
+			if strings.EqualFold(option, toText(target)) {
+				return true
+			}
+		}
+		return false
+	}
+	return strings.Contains(strings.ToLower(toText(value)), strings.ToLower(toText(target)))
+}
+
+// compareValues orders a and b for a property type, returning false if either
+// can't be read as that type
+func compareValues(propertyType PropertyType, a, b interface{}) (int, bool) {
+	switch propertyType {
+	case PropertyTypeNumber:
+		x, okA := toFloat(a)
+		y, okB := toFloat(b)
+		if !okA || !okB {
+			return 0, false
+		}
+		return compareOrdered(x, y), true
+	case PropertyTypeDate:
+		x, okA := toTime(a)
+		y, okB := toTime(b)
+		if !okA || !okB {
+			return 0, false
+		}
+		return x.Compare(y), true
+	case PropertyTypeCheckbox:
+		x, okA := toBool(a)
+		y, okB := toBool(b)
+		if !okA || !okB {
+			return 0, false
+		}
+		if x == y {
+			return 0, true
+		}
+		if !x {
+			return -1, true
+		}
+		return 1, true
+	case PropertyTypeMultiSelect:
+		return strings.Compare(strings.ToLower(strings.Join(toList(a), ",")), strings.ToLower(strings.Join(toList(b), ","))), true
+	}
+	return strings.Compare(strings.ToLower(toText(a)), strings.ToLower(toText(b))), true
+}
+
+func compareOrdered(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+func toText(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+func toList(value interface{}) []string {
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = toText(item)
+		}
+		return items
+	}
+	return []string{toText(value)}
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func toBool(value interface{}) (bool, bool) {
+	switch v := value.(type) {
+	case bool:
+		return v, true
+	case string:
+		b, err := strconv.ParseBool(v)
+		return b, err == nil
+	}
+	return false, false
+}
+
+// toTime reads dates stored either as RFC 3339 timestamps or as plain days
+func toTime(value interface{}) (time.Time, bool) {
+	s, ok := value.(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func viewRowIDs(rows []*Note) []int64 {
+	ids := make([]int64, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	return ids
+}
+
+func TestViewMetadata_Apply(t *testing.T) {
+	rows := []*Note{
+		{ID: 1, Properties: map[string]interface{}{"name": "Write spec", "estimate": float64(3), "due": "2024-03-01", "done": true, "tags": []interface{}{"work"}}},
+		{ID: 2, Properties: map[string]interface{}{"name": "buy milk", "estimate": float64(1), "due": "2024-01-15", "done": false, "tags": []interface{}{"home", "errand"}}},
+		{ID: 3, Properties: map[string]interface{}{"name": "Review spec", "estimate": float64(5), "done": false}},
+		{ID: 4, Properties: nil},
+	}
+	properties := []ViewProperty{
+		{ID: "name", Type: PropertyTypeText},
+		{ID: "estimate", Type: PropertyTypeNumber},
+		{ID: "due", Type: PropertyTypeDate},
+		{ID: "done", Type: PropertyTypeCheckbox},
+		{ID: "tags", Type: PropertyTypeMultiSelect},
+	}
+
+	tests := []struct {
+		name    string
+		filters []ViewFilter
+		sorts   []ViewSort
+		want    []int64
+	}{
+		{"no filters keeps order", nil, nil, []int64{1, 2, 3, 4}},
+		{"text contains ignores case", []ViewFilter{{PropertyID: "name", Operator: FilterContains, Value: "SPEC"}}, nil, []int64{1, 3}},
+		{"number greater than", []ViewFilter{{PropertyID: "estimate", Operator: FilterGreaterThan, Value: float64(2)}}, nil, []int64{1, 3}},
+		{"date before", []ViewFilter{{PropertyID: "due", Operator: FilterLessThan, Value: "2024-02-01"}}, nil, []int64{2}},
+		{"checkbox equals", []ViewFilter{{PropertyID: "done", Operator: FilterEquals, Value: false}}, nil, []int64{2, 3}},
+		{"multi-select contains", []ViewFilter{{PropertyID: "tags", Operator: FilterContains, Value: "home"}}, nil, []int64{2}},
+		{"is empty", []ViewFilter{{PropertyID: "due", Operator: FilterIsEmpty}}, nil, []int64{3, 4}},
+		{"filters combine", []ViewFilter{
+			{PropertyID: "name", Operator: FilterContains, Value: "spec"},
+			{PropertyID: "done", Operator: FilterEquals, Value: false},
+		}, nil, []int64{3}},
+		{"sort number desc", nil, []ViewSort{{PropertyID: "estimate", Direction: "desc"}}, []int64{3, 1, 2, 4}},
+		{"sort date asc puts empty last", nil, []ViewSort{{PropertyID: "due", Direction: "asc"}}, []int64{2, 1, 3, 4}},
+		{"sort text ignores case", nil, []ViewSort{{PropertyID: "name", Direction: "asc"}}, []int64{2, 3, 1, 4}},
+		{"secondary sort breaks ties", nil, []ViewSort{
+			{PropertyID: "done", Direction: "asc"},
+			{PropertyID: "estimate", Direction: "desc"},
+		}, []int64{3, 2, 1, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			view := ViewMetadata{ViewType: ViewTypeTable, Properties: properties, Filters: tt.filters, Sorts: tt.sorts}
+			assert.Equal(t, tt.want, viewRowIDs(view.Apply(rows)))
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Webhook is a user-registered URL that receives reminder events as signed JSON POSTs
+type Webhook struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"-"` // HMAC signing secret, only returned once on creation
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Webhook-specific domain errors
+var (
+	ErrWebhookNotFound      = errors.New("webhook not found")
+	ErrInvalidWebhookURL    = errors.New("webhook URL must be an absolute http or https URL")
+	ErrWebhookURLNotAllowed = errors.New("webhook URL must not point to a local or private network address")
+)
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), which some clouds
+// use for metadata endpoints
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// IsInternalIP reports whether ip is a loopback, private, link-local (including
+// cloud metadata endpoints) or unspecified address that webhooks must not reach
+func IsInternalIP(ip net.IP) bool {
+	return ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsUnspecified() ||
+		sharedAddressSpace.Contains(ip)
+}
+
+// NewWebhook creates a new Webhook with validation
+func NewWebhook(userID int64, rawURL, secret string) (*Webhook, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return nil, ErrInvalidWebhookURL
+	}
+
+	// Hostnames are checked again against their resolved address when delivering
+	host := strings.ToLower(strings.TrimSuffix(parsed.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return nil, ErrWebhookURLNotAllowed
+	}
+	if ip := net.ParseIP(host); ip != nil && IsInternalIP(ip) {
+		return nil, ErrWebhookURLNotAllowed
+	}
+
+	now := time.Now()
+	return &Webhook{
+		UserID:    userID,
+		URL:       parsed.String(),
+		Secret:    secret,
+		IsActive:  true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
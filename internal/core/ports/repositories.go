@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"slices"
 	"time"
 
 	"github.com/yourusername/notinoteapp/internal/core/domain"
@@ -33,15 +34,69 @@ type UserRepository interface {
 
 // NoteFilters represents filtering options for notes
 type NoteFilters struct {
-	ParentID    *int64
-	IsArchived  *bool
-	ViewType    *domain.ViewType
-	Properties  map[string]interface{} // Filter by custom properties
-	SearchQuery string                 // Full-text search on title
-	Limit       int
-	Offset      int
-	SortBy      string // "created_at", "updated_at", "title", "position"
-	SortOrder   string // "asc", "desc"
+	ParentID     *int64
+	IsArchived   *bool
+	ViewType     *domain.ViewType
+	Properties   map[string]interface{} // Filter by custom properties
+	SearchQuery  string                 // Full-text search on title
+	Fuzzy        bool                   // Search: fall back to trigram similarity when full-text finds nothing
+	WithinNoteID *int64                 // Only this note and its descendants
+	SummaryOnly  bool                   // Load only list metadata, leaving blocks and other JSON unset
+
+	// Date ranges; each bound is inclusive and optional
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+
+	Limit     int
+	Offset    int
+	SortBy    string // One of NoteSortFields
+	SortOrder string // "asc", "desc"
+}
+
+// NoteSortFields are the fields notes can be sorted by. They are written into
+// ORDER BY, so anything else must be rejected rather than passed through.
+var NoteSortFields = []string{"created_at", "updated_at", "title", "position", "is_favorite"}
+
+// IsValidNoteSortField reports whether notes can be sorted by field
+func IsValidNoteSortField(field string) bool {
+	return slices.Contains(NoteSortFields, field)
+}
+
+// NoteCounts holds a user's note counts broken down by state
+type NoteCounts struct {
+	Total     int64 `json:"total"`     // Notes not in the trash, including archived
+	Archived  int64 `json:"archived"`  // Archived notes not in the trash
+	Favorites int64 `json:"favorites"` // Favorite notes not in the trash
+	Trashed   int64 `json:"trashed"`   // Soft-deleted notes
+}
+
+// NoteUsage is how much storage a user's notes take up, including the trash
+type NoteUsage struct {
+	Notes      int64 `json:"notes"`
+	BlockBytes int64 `json:"block_bytes"`
+}
+
+// PathRebuildResult reports what rebuilding a user's note paths changed
+type PathRebuildResult struct {
+	Checked     int     // Notes examined
+	Corrected   []int64 // Notes whose path or depth was stale and has been fixed
+	Unreachable []int64 // Notes whose parent chain never reaches a root (a missing parent or a cycle), left as they are
+}
+
+// OrphanNote is a note whose parent_id doesn't lead to a live parent
+type OrphanNote struct {
+	ID            int64
+	UserID        int64
+	ParentID      int64
+	ParentTrashed bool // the parent exists but is in the trash; otherwise it's gone or another user's
+}
+
+// TagUsage is a tag with the number of notes using it
+type TagUsage struct {
+	domain.Tag
+	NoteCount int64 // Notes tagged with it, not counting the trash
 }
 
 // NoteRepository defines the interface for note data persistence
@@ -49,20 +104,47 @@ type NoteRepository interface {
 	// Basic CRUD operations
 	Create(ctx context.Context, note *domain.Note) error
 	FindByID(ctx context.Context, id int64) (*domain.Note, error)
+	FindByIDs(ctx context.Context, userID int64, ids []int64) ([]*domain.Note, error)
 	Update(ctx context.Context, note *domain.Note) (*domain.Note, error)
 	Delete(ctx context.Context, id int64) error
+	// Restore brings a trashed note back. Returns ErrNoteNotFound if the user
+	// has no trashed note with the given ID.
+	Restore(ctx context.Context, id, userID int64) error
 
 	// User notes with filtering
 	FindByUserID(ctx context.Context, userID int64, filters NoteFilters) ([]*domain.Note, int64, error)
+	CountByUser(ctx context.Context, userID int64) (*NoteCounts, error)
+	UsageByUser(ctx context.Context, userID int64) (*NoteUsage, error)
 
 	// Hierarchy operations
 	FindChildren(ctx context.Context, parentID int64) ([]*domain.Note, error)
 	FindDescendants(ctx context.Context, parentID int64) ([]*domain.Note, error)
+
+	// FindDescendantsPage finds one page of a note's descendants in path order,
+	// loading summary fields only, along with how many descendants there are
+	FindDescendantsPage(ctx context.Context, parentID int64, limit, offset int) ([]*domain.Note, int64, error)
+	// CountDescendants counts a note's live descendants at every level
+	CountDescendants(ctx context.Context, parentID int64) (int64, error)
 	FindAncestors(ctx context.Context, noteID int64) ([]*domain.Note, error)
 	MoveNote(ctx context.Context, noteID int64, newParentID *int64, newPosition int) error
+	// RebuildPaths recomputes path and depth for all of a user's notes from
+	// their parent links, for trees written without the hierarchy trigger
+	RebuildPaths(ctx context.Context, userID int64) (*PathRebuildResult, error)
+	// FindOrphans lists notes, including trashed ones, whose parent no longer
+	// exists or belongs to another user, and live notes under a trashed parent
+	FindOrphans(ctx context.Context) ([]OrphanNote, error)
+	// DetachNotes makes notes top-level without any other checks
+	DetachNotes(ctx context.Context, noteIDs []int64) error
 
 	// Block operations
 	UpdateBlocks(ctx context.Context, noteID int64, blocks []domain.Block) error
+	// UpdateBlockContent replaces the content of a single top-level block in place,
+	// leaving concurrent edits to other blocks intact. Returns ErrBlockNotFound if
+	// no top-level block has the given ID.
+	UpdateBlockContent(ctx context.Context, noteID int64, blockID string, content *domain.BlockContent) error
+	// UpdateBlockText replaces only the rich text of a single top-level block,
+	// keeping the rest of its content. Returns ErrBlockNotFound like UpdateBlockContent.
+	UpdateBlockText(ctx context.Context, noteID int64, blockID string, richText []domain.RichTextSegment) error
 
 	// Search and filter
 	Search(ctx context.Context, userID int64, query string, filters NoteFilters) ([]*domain.Note, int64, error)
@@ -70,6 +152,9 @@ type NoteRepository interface {
 	// Bulk operations
 	BulkArchive(ctx context.Context, noteIDs []int64) error
 	BulkDelete(ctx context.Context, noteIDs []int64) error
+	// SoftDeleteSubtree trashes a note and all its live descendants in one
+	// transaction, returning the IDs it trashed
+	SoftDeleteSubtree(ctx context.Context, noteID int64) ([]int64, error)
 
 	// Permission check (for ownership)
 	CheckOwnership(ctx context.Context, noteID, userID int64) (bool, error)
@@ -78,6 +163,21 @@ type NoteRepository interface {
 	AddTag(ctx context.Context, noteID int64, tagID string) error
 	RemoveTag(ctx context.Context, noteID int64, tagID string) error
 	GetNoteTags(ctx context.Context, noteID int64) ([]domain.Tag, error)
+	// ListTags returns a page of a user's tags by name, including unused ones,
+	// and how many tags they have in total. A zero limit returns them all.
+	ListTags(ctx context.Context, userID int64, limit, offset int) ([]TagUsage, int64, error)
+	// FindTag returns ErrTagNotFound if no tag has the given ID
+	FindTag(ctx context.Context, tagID string) (*domain.Tag, error)
+	FindTagByName(ctx context.Context, userID int64, name string) (*domain.Tag, error)
+	UpdateTag(ctx context.Context, tag *domain.Tag) error
+	// FindNoteIDsByTag returns the IDs of all notes with the tag, including the trash
+	FindNoteIDsByTag(ctx context.Context, tagID string) ([]int64, error)
+	// MergeTags moves every note from the source tag to the target and deletes
+	// the source, in one transaction
+	MergeTags(ctx context.Context, sourceTagID, targetTagID string) error
+	// BulkAddTag and BulkRemoveTag apply to all the notes or none
+	BulkAddTag(ctx context.Context, noteIDs []int64, tagID string) error
+	BulkRemoveTag(ctx context.Context, noteIDs []int64, tagID string) error
 }
 
 // NotificationRepository defines the interface for notification data persistence
@@ -138,15 +238,22 @@ type DeviceRepository interface {
 
 	// DeactivateStaleDevices deactivates devices not used since the given time
 	DeactivateStaleDevices(ctx context.Context, before time.Time) (int64, error)
+
+	// DeleteInactiveDevices deletes inactive devices not used since the given time
+	DeleteInactiveDevices(ctx context.Context, before time.Time) (int64, error)
 }
 
 // ReminderQueryParams represents filtering options for reminders
 type ReminderQueryParams struct {
-	IsEnabled *bool
-	FromDate  *time.Time
-	ToDate    *time.Time
-	Limit     int
-	Offset    int
+	IsEnabled  *bool
+	NoteID     *int64
+	RepeatType *domain.RepeatType
+	FromDate   *time.Time
+	ToDate     *time.Time
+	Limit      int
+	Offset     int
+	// IncludeNote loads each reminder's note (title and state) with it
+	IncludeNote bool
 }
 
 // ReminderRepository defines the interface for reminder data persistence
@@ -163,6 +270,10 @@ type ReminderRepository interface {
 	// FindByUserID finds all reminders for a user with filters
 	FindByUserID(ctx context.Context, userID int64, params *ReminderQueryParams) ([]*domain.Reminder, error)
 
+	// CountByUserID counts a user's reminders matching params, ignoring its
+	// limit and offset
+	CountByUserID(ctx context.Context, userID int64, params *ReminderQueryParams) (int64, error)
+
 	// FindDueReminders finds all enabled reminders that are due (next_trigger_at <= until)
 	FindDueReminders(ctx context.Context, until time.Time, limit int) ([]*domain.Reminder, error)
 
@@ -181,10 +292,119 @@ type ReminderRepository interface {
 	// IncrementTriggerCount increments the trigger count for a reminder
 	IncrementTriggerCount(ctx context.Context, id int64) error
 
+	// MarkTriggered records a trigger in one statement: it sets the next trigger
+	// time and enabled state, stamps the last trigger time with triggeredAt,
+	// increments the trigger count and clears the snooze count
+	MarkTriggered(ctx context.Context, id int64, nextTrigger time.Time, isEnabled bool, triggeredAt time.Time) error
+
+	// MarkTriggeredBatch marks several reminders triggered at triggeredAt in one
+	// transaction, using each reminder's NextTriggerAt and IsEnabled. Reminders
+	// that no longer exist are skipped and their IDs returned.
+	MarkTriggeredBatch(ctx context.Context, reminders []*domain.Reminder, triggeredAt time.Time) ([]int64, error)
+
 	// CheckOwnership checks if a reminder belongs to a user
 	CheckOwnership(ctx context.Context, reminderID, userID int64) (bool, error)
 }
 
+// NotificationPreferencesRepository defines the interface for notification preferences persistence
+type NotificationPreferencesRepository interface {
+	// FindByUserID finds the preferences for a user
+	FindByUserID(ctx context.Context, userID int64) (*domain.NotificationPreferences, error)
+
+	// Upsert creates or replaces the preferences for a user
+	Upsert(ctx context.Context, prefs *domain.NotificationPreferences) error
+}
+
+// WebhookRepository defines the interface for webhook data persistence
+type WebhookRepository interface {
+	// Create creates a new webhook
+	Create(ctx context.Context, webhook *domain.Webhook) error
+
+	// FindByID finds a webhook by ID
+	FindByID(ctx context.Context, id int64) (*domain.Webhook, error)
+
+	// FindActiveByUserID finds all active webhooks for a user
+	FindActiveByUserID(ctx context.Context, userID int64) ([]*domain.Webhook, error)
+}
+
+// CalendarFeedRepository defines the interface for calendar feed token persistence
+type CalendarFeedRepository interface {
+	// Save stores the user's calendar feed, replacing any previous token
+	Save(ctx context.Context, feed *domain.CalendarFeed) error
+
+	// FindByTokenHash finds the calendar feed with the given token hash
+	FindByTokenHash(ctx context.Context, tokenHash string) (*domain.CalendarFeed, error)
+
+	// DeleteByUserID removes the user's calendar feed, if any
+	DeleteByUserID(ctx context.Context, userID int64) error
+}
+
+// SharedLinkRepository defines the interface for shared link data persistence
+type SharedLinkRepository interface {
+	// Create creates a new shared link
+	Create(ctx context.Context, link *domain.SharedLink) error
+
+	// FindByID finds a shared link by ID
+	FindByID(ctx context.Context, id int64) (*domain.SharedLink, error)
+
+	// FindByToken finds a shared link by its token
+	FindByToken(ctx context.Context, token string) (*domain.SharedLink, error)
+
+	// FindByNoteID finds all shared links for a note, including revoked ones
+	FindByNoteID(ctx context.Context, noteID int64) ([]*domain.SharedLink, error)
+
+	// Revoke marks a shared link as revoked
+	Revoke(ctx context.Context, id int64) error
+}
+
+// NoteCollaboratorRepository defines the interface for note collaborator persistence
+type NoteCollaboratorRepository interface {
+	// Upsert creates a collaborator or updates the role of an existing one
+	Upsert(ctx context.Context, collaborator *domain.NoteCollaborator) error
+
+	// Delete removes a collaborator from a note
+	Delete(ctx context.Context, noteID, userID int64) error
+
+	// FindByNoteID finds all collaborators on a note
+	FindByNoteID(ctx context.Context, noteID int64) ([]*domain.NoteCollaborator, error)
+
+	// FindByUserAndNoteIDs finds the user's collaborator grants on any of the given notes
+	FindByUserAndNoteIDs(ctx context.Context, userID int64, noteIDs []int64) ([]*domain.NoteCollaborator, error)
+}
+
+// DeadLetterQueryParams represents filtering options for dead letters
+type DeadLetterQueryParams struct {
+	UserID     *int64
+	Channel    *domain.NotificationChannel
+	Unresolved bool // only dead letters that have not been delivered by a replay
+	Limit      int
+	Offset     int
+}
+
+// DeadLetterRepository defines the interface for persisting undeliverable notifications
+type DeadLetterRepository interface {
+	// Create stores a new dead letter
+	Create(ctx context.Context, letter *domain.DeadLetter) error
+
+	// FindByID finds a dead letter by ID
+	FindByID(ctx context.Context, id int64) (*domain.DeadLetter, error)
+
+	// List finds dead letters matching the filters, newest first, returning the total match count
+	List(ctx context.Context, params *DeadLetterQueryParams) ([]*domain.DeadLetter, int64, error)
+
+	// UpdateReplay saves the outcome of a replay attempt
+	UpdateReplay(ctx context.Context, letter *domain.DeadLetter) error
+}
+
+// NotificationLogQueryParams represents filtering options for notification logs
+type NotificationLogQueryParams struct {
+	Status   *domain.NotificationStatus
+	FromDate *time.Time
+	ToDate   *time.Time
+	Limit    int
+	Offset   int
+}
+
 // NotificationLogRepository defines the interface for notification log data persistence
 type NotificationLogRepository interface {
 	// Create creates a new notification log entry
@@ -196,6 +416,9 @@ type NotificationLogRepository interface {
 	// FindByUserID finds log entries for a user
 	FindByUserID(ctx context.Context, userID int64, limit, offset int) ([]*domain.NotificationLog, int64, error)
 
+	// ListByUser finds log entries for a user with filters, returning the total match count
+	ListByUser(ctx context.Context, userID int64, params *NotificationLogQueryParams) ([]*domain.NotificationLog, int64, error)
+
 	// FindByReminderID finds log entries for a reminder
 	FindByReminderID(ctx context.Context, reminderID int64) ([]*domain.NotificationLog, error)
 
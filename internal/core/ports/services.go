@@ -2,17 +2,19 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/yourusername/notinoteapp/internal/core/domain"
 )
 
 // OAuthProvider defines the interface for OAuth authentication providers
 type OAuthProvider interface {
-	// GetAuthURL generates the OAuth authorization URL with state
-	GetAuthURL(state string) string
+	// GetAuthURL generates the OAuth authorization URL with state and an S256 PKCE code challenge
+	GetAuthURL(state, codeChallenge string) string
 
-	// ExchangeCode exchanges authorization code for access token and retrieves user info
-	ExchangeCode(ctx context.Context, code string) (*domain.OAuthUserInfo, error)
+	// ExchangeCode exchanges authorization code for access token, proving
+	// possession of the PKCE code verifier, and retrieves user info
+	ExchangeCode(ctx context.Context, code, codeVerifier string) (*domain.OAuthUserInfo, error)
 
 	// GetProviderName returns the provider name (google, facebook, etc.)
 	GetProviderName() domain.AuthProvider
@@ -25,6 +27,10 @@ type PasswordHasher interface {
 
 	// CheckPassword compares a plain text password with a hash
 	CheckPassword(password, hash string) bool
+
+	// NeedsRehash returns true if the hash was made with weaker settings than
+	// the hasher currently uses
+	NeedsRehash(hash string) bool
 }
 
 // TokenService defines the interface for JWT token operations
@@ -32,6 +38,9 @@ type TokenService interface {
 	// GenerateToken generates a JWT token for a user
 	GenerateToken(userID int64, email string) (string, error)
 
+	// GenerateTokenWithRole generates a JWT token that also carries the user's role
+	GenerateTokenWithRole(userID int64, email, role string) (string, error)
+
 	// GenerateRefreshToken generates a refresh token
 	GenerateRefreshToken(userID int64, email string) (string, error)
 
@@ -40,6 +49,9 @@ type TokenService interface {
 
 	// RefreshToken generates a new access token from a refresh token
 	RefreshToken(refreshToken string) (string, error)
+
+	// AccessTokenExpiry returns how long generated access tokens are valid
+	AccessTokenExpiry() time.Duration
 }
 
 // StateGenerator defines the interface for OAuth state generation and validation
@@ -50,11 +62,15 @@ type StateGenerator interface {
 	// ValidateState validates that a state matches expected value
 	ValidateState(state, expected string) bool
 
-	// StoreState temporarily stores state (e.g., in Redis) with expiration
-	StoreState(ctx context.Context, state string, ttl int) error
+	// GenerateCodeVerifier generates a random PKCE code verifier
+	GenerateCodeVerifier() (string, error)
 
-	// GetState retrieves and deletes stored state (one-time use)
-	GetState(ctx context.Context, state string) (bool, error)
+	// StoreState temporarily stores state (e.g., in Redis) and its PKCE code verifier with expiration
+	StoreState(ctx context.Context, state, codeVerifier string, ttl int) error
+
+	// GetState retrieves and deletes stored state (one-time use), returning its
+	// code verifier and whether the state was found
+	GetState(ctx context.Context, state string) (codeVerifier string, found bool, err error)
 }
 
 // EmailService defines the interface for sending emails
@@ -93,6 +109,25 @@ type CacheService interface {
 	Exists(ctx context.Context, key string) (bool, error)
 }
 
+// NoteCache defines the interface for a read-through note cache
+type NoteCache interface {
+	// GetOrLoad returns the cached note, or calls load and caches the result.
+	// Cache failures fall back to load rather than failing the read.
+	GetOrLoad(ctx context.Context, noteID int64, load func(ctx context.Context) (*domain.Note, error)) (*domain.Note, error)
+
+	// Invalidate drops the cached copy of a note
+	Invalidate(ctx context.Context, noteID int64)
+}
+
+// ReminderRescheduler recomputes reminders that follow a note's date
+// properties (see domain.RelativeSchedule)
+type ReminderRescheduler interface {
+	// RescheduleForNote re-resolves the note's relative reminders after its
+	// properties changed. Failures are logged rather than returned, since the
+	// note itself was saved.
+	RescheduleForNote(ctx context.Context, note *domain.Note)
+}
+
 // QueueService defines the interface for queue operations
 type QueueService interface {
 	// Push adds an item to the queue
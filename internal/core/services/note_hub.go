@@ -0,0 +1,96 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+)
+
+// NoteChangeType identifies the kind of change made to a note
+type NoteChangeType string
+
+const (
+	NoteChangeBlockAdded      NoteChangeType = "block_added"
+	NoteChangeBlockUpdated    NoteChangeType = "block_updated"
+	NoteChangeBlockDeleted    NoteChangeType = "block_deleted"
+	NoteChangeBlocksReordered NoteChangeType = "blocks_reordered"
+	NoteChangeBlocksReplaced  NoteChangeType = "blocks_replaced"
+)
+
+// noteChangeBuffer is the per-subscriber channel size. Events published while
+// a subscriber's buffer is full are dropped for that subscriber.
+const noteChangeBuffer = 32
+
+// NoteChangeEvent describes a change to a note's blocks
+type NoteChangeEvent struct {
+	Type      NoteChangeType `json:"type"`
+	NoteID    int64          `json:"note_id"`
+	UserID    int64          `json:"user_id"` // User who made the change
+	BlockID   string         `json:"block_id,omitempty"`
+	Block     *domain.Block  `json:"block,omitempty"`
+	Blocks    []domain.Block `json:"blocks,omitempty"`
+	ChangedAt time.Time      `json:"changed_at"`
+}
+
+// NoteHub is an in-process pub/sub that fans note change events out to every
+// client viewing the same note
+type NoteHub struct {
+	mu          sync.RWMutex
+	subscribers map[int64]map[chan NoteChangeEvent]struct{}
+}
+
+// NewNoteHub creates a new note hub
+func NewNoteHub() *NoteHub {
+	return &NoteHub{
+		subscribers: make(map[int64]map[chan NoteChangeEvent]struct{}),
+	}
+}
+
+// Subscribe registers a subscription for the note's change events. The
+// returned function removes the subscription and closes the channel.
+func (h *NoteHub) Subscribe(noteID int64) (<-chan NoteChangeEvent, func()) {
+	ch := make(chan NoteChangeEvent, noteChangeBuffer)
+
+	h.mu.Lock()
+	if h.subscribers[noteID] == nil {
+		h.subscribers[noteID] = make(map[chan NoteChangeEvent]struct{})
+	}
+	h.subscribers[noteID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subscribers[noteID], ch)
+			if len(h.subscribers[noteID]) == 0 {
+				delete(h.subscribers, noteID)
+			}
+			h.mu.Unlock()
+			close(ch)
+		})
+	}
+
+	return ch, unsubscribe
+}
+
+// Publish sends the event to all subscribers of the note without blocking
+func (h *NoteHub) Publish(event NoteChangeEvent) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for ch := range h.subscribers[event.NoteID] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// SubscriberCount returns the number of clients viewing the note
+func (h *NoteHub) SubscriberCount(noteID int64) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.subscribers[noteID])
+}
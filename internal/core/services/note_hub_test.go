@@ -0,0 +1,42 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoteHub_PublishToNoteSubscribersOnly(t *testing.T) {
+	hub := NewNoteHub()
+
+	first, unsubscribeFirst := hub.Subscribe(1)
+	defer unsubscribeFirst()
+	second, unsubscribeSecond := hub.Subscribe(1)
+	defer unsubscribeSecond()
+	other, unsubscribeOther := hub.Subscribe(2)
+	defer unsubscribeOther()
+
+	hub.Publish(NoteChangeEvent{Type: NoteChangeBlockDeleted, NoteID: 1, BlockID: "block_1"})
+
+	require.Len(t, first, 1)
+	require.Len(t, second, 1)
+	assert.Equal(t, "block_1", (<-first).BlockID)
+	assert.Equal(t, NoteChangeBlockDeleted, (<-second).Type)
+	assert.Len(t, other, 0)
+}
+
+func TestNoteHub_UnsubscribeCleansUp(t *testing.T) {
+	hub := NewNoteHub()
+
+	events, unsubscribe := hub.Subscribe(1)
+	assert.Equal(t, 1, hub.SubscriberCount(1))
+
+	unsubscribe()
+	unsubscribe()
+
+	assert.Equal(t, 0, hub.SubscriberCount(1))
+	_, ok := <-events
+	assert.False(t, ok, "channel should be closed")
+	hub.Publish(NoteChangeEvent{Type: NoteChangeBlockAdded, NoteID: 1})
+}
@@ -2,6 +2,9 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,18 +14,199 @@ import (
 
 // NoteService implements business logic for note operations
 type NoteService struct {
-	noteRepo ports.NoteRepository
+	noteRepo         ports.NoteRepository
+	sharedLinkRepo   ports.SharedLinkRepository
+	collaboratorRepo ports.NoteCollaboratorRepository
+	userRepo         ports.UserRepository
+	hub              *NoteHub                  // Optional, see SetHub
+	cache            ports.NoteCache           // Optional, see SetCache
+	reminders        ports.ReminderRescheduler // Optional, see SetReminderRescheduler
+	limits           domain.NoteLimits
+	quota            domain.StorageQuota // Unlimited unless set, see SetQuota
 }
 
 // NewNoteService creates a new NoteService instance
-func NewNoteService(noteRepo ports.NoteRepository) *NoteService {
+func NewNoteService(
+	noteRepo ports.NoteRepository,
+	sharedLinkRepo ports.SharedLinkRepository,
+	collaboratorRepo ports.NoteCollaboratorRepository,
+	userRepo ports.UserRepository,
+) *NoteService {
 	return &NoteService{
-		noteRepo: noteRepo,
+		noteRepo:         noteRepo,
+		sharedLinkRepo:   sharedLinkRepo,
+		collaboratorRepo: collaboratorRepo,
+		userRepo:         userRepo,
+		limits:           domain.DefaultNoteLimits(),
 	}
 }
 
+// SetLimits overrides the default note size limits
+func (s *NoteService) SetLimits(limits domain.NoteLimits) {
+	s.limits = limits
+}
+
+// Limits returns the note size limits enforced by the service
+func (s *NoteService) Limits() domain.NoteLimits {
+	return s.limits
+}
+
+// SetQuota sets the per-user storage quota
+func (s *NoteService) SetQuota(quota domain.StorageQuota) {
+	s.quota = quota
+}
+
+// Quota returns the per-user storage quota enforced by the service
+func (s *NoteService) Quota() domain.StorageQuota {
+	return s.quota
+}
+
+// Usage returns how much of their quota a user has used
+func (s *NoteService) Usage(ctx context.Context, userID int64) (*ports.NoteUsage, error) {
+	return s.noteRepo.UsageByUser(ctx, userID)
+}
+
+// checkNoteQuota returns ErrQuotaExceeded if the user may not create another note
+func (s *NoteService) checkNoteQuota(ctx context.Context, userID int64) error {
+	if s.quota.MaxNotes <= 0 {
+		return nil
+	}
+
+	usage, err := s.noteRepo.UsageByUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check quota: %w", err)
+	}
+	return s.quota.CheckNewNote(usage.Notes)
+}
+
+// checkBlockQuota returns ErrQuotaExceeded if growing the owner's block
+// storage by delta bytes would exceed their quota
+func (s *NoteService) checkBlockQuota(ctx context.Context, ownerID int64, delta int64) error {
+	if s.quota.MaxBlockBytes <= 0 || delta <= 0 {
+		return nil
+	}
+
+	usage, err := s.noteRepo.UsageByUser(ctx, ownerID)
+	if err != nil {
+		return fmt.Errorf("failed to check quota: %w", err)
+	}
+	return s.quota.CheckBlockGrowth(usage.BlockBytes, delta)
+}
+
+// noteAccess is the level of access a user has to a note
+type noteAccess int
+
+const (
+	accessNone noteAccess = iota
+	accessView            // Owner or any collaborator
+	accessEdit            // Owner or editor: may modify content
+	accessOwner           // Owner only: may delete, move, and share
+)
+
+// SetCache enables a read-through cache for note lookups. Every write made
+// through the service invalidates the affected notes.
+func (s *NoteService) SetCache(cache ports.NoteCache) {
+	s.cache = cache
+}
+
+// findNote loads a note by ID, going through the cache when one is configured
+func (s *NoteService) findNote(ctx context.Context, noteID int64) (*domain.Note, error) {
+	if s.cache == nil {
+		return s.noteRepo.FindByID(ctx, noteID)
+	}
+	return s.cache.GetOrLoad(ctx, noteID, func(ctx context.Context) (*domain.Note, error) {
+		return s.noteRepo.FindByID(ctx, noteID)
+	})
+}
+
+// invalidate drops cached copies of the given notes
+func (s *NoteService) invalidate(ctx context.Context, noteIDs ...int64) {
+	if s.cache == nil {
+		return
+	}
+	for _, id := range noteIDs {
+		s.cache.Invalidate(ctx, id)
+	}
+}
+
+// saveNote persists note fields and invalidates its cached copy
+func (s *NoteService) saveNote(ctx context.Context, note *domain.Note) (*domain.Note, error) {
+	updated, err := s.noteRepo.Update(ctx, note)
+	s.invalidate(ctx, note.ID)
+	return updated, err
+}
+
+// saveBlocks persists a note's blocks and invalidates its cached copy
+func (s *NoteService) saveBlocks(ctx context.Context, noteID int64, blocks []domain.Block) error {
+	err := s.noteRepo.UpdateBlocks(ctx, noteID, blocks)
+	s.invalidate(ctx, noteID)
+	return err
+}
+
+// SetReminderRescheduler lets property changes move the note's relative
+// reminders
+func (s *NoteService) SetReminderRescheduler(reminders ports.ReminderRescheduler) {
+	s.reminders = reminders
+}
+
+// propertiesChanged reschedules reminders that follow the note's properties
+func (s *NoteService) propertiesChanged(ctx context.Context, note *domain.Note) {
+	if s.reminders == nil {
+		return
+	}
+	s.reminders.RescheduleForNote(ctx, note)
+}
+
+// SetHub enables publishing block change events to clients viewing a note
+func (s *NoteService) SetHub(hub *NoteHub) {
+	s.hub = hub
+}
+
+// SubscribeToNote verifies the user can view the note and subscribes to its change events.
+// The returned function must be called to release the subscription.
+func (s *NoteService) SubscribeToNote(ctx context.Context, noteID, userID int64) (<-chan NoteChangeEvent, func(), error) {
+	if s.hub == nil {
+		return nil, nil, domain.ErrStreamUnavailable
+	}
+
+	if _, err := s.GetNote(ctx, noteID, userID); err != nil {
+		return nil, nil, err
+	}
+
+	events, unsubscribe := s.hub.Subscribe(noteID)
+	return events, unsubscribe, nil
+}
+
+// publishChange notifies clients viewing the note of a change
+func (s *NoteService) publishChange(event NoteChangeEvent) {
+	if s.hub == nil {
+		return
+	}
+	event.ChangedAt = time.Now().UTC()
+	s.hub.Publish(event)
+}
+
+// findBlock returns a copy of the block with the given ID, or nil
+func findBlock(note *domain.Note, blockID string) *domain.Block {
+	for _, block := range note.Blocks {
+		if block.ID == blockID {
+			b := block
+			return &b
+		}
+	}
+	return nil
+}
+
 // CreateNote creates a new note with validation
 func (s *NoteService) CreateNote(ctx context.Context, userID int64, title string, parentID *int64) (*domain.Note, error) {
+	if err := s.limits.ValidateTitle(title); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkNoteQuota(ctx, userID); err != nil {
+		return nil, err
+	}
+
 	// Create new note using domain factory
 	note, err := domain.NewNote(userID, title)
 	if err != nil {
@@ -38,7 +222,7 @@ func (s *NoteService) CreateNote(ctx context.Context, userID int64, title string
 		}
 
 		if parent.UserID != userID {
-			return nil, domain.ErrUnauthorizedAccess
+			return nil, fmt.Errorf("parent note not found: %w", domain.ErrNoteNotFound)
 		}
 
 		// Check nesting depth
@@ -60,122 +244,208 @@ func (s *NoteService) CreateNote(ctx context.Context, userID int64, title string
 	return note, nil
 }
 
-// GetNote retrieves a note by ID with ownership validation
+// GetNote retrieves a note by ID, allowing the owner and any collaborator
 func (s *NoteService) GetNote(ctx context.Context, noteID, userID int64) (*domain.Note, error) {
-	note, err := s.noteRepo.FindByID(ctx, noteID)
+	return s.authorize(ctx, noteID, userID, accessView)
+}
+
+// getEditableNote retrieves a note the user may modify (owner or editor)
+func (s *NoteService) getEditableNote(ctx context.Context, noteID, userID int64) (*domain.Note, error) {
+	return s.authorize(ctx, noteID, userID, accessEdit)
+}
+
+// getOwnedNote retrieves a note only if the user owns it
+func (s *NoteService) getOwnedNote(ctx context.Context, noteID, userID int64) (*domain.Note, error) {
+	return s.authorize(ctx, noteID, userID, accessOwner)
+}
+
+// authorize loads a note and verifies the user has at least the required access
+func (s *NoteService) authorize(ctx context.Context, noteID, userID int64, required noteAccess) (*domain.Note, error) {
+	note, err := s.findNote(ctx, noteID)
 	if err != nil {
 		return nil, fmt.Errorf("note not found: %w", err)
 	}
 
-	// Verify ownership
-	if note.UserID != userID {
+	if note.UserID == userID {
+		return note, nil
+	}
+
+	grants, err := s.collaboratorGrants(ctx, note, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	access := accessNone
+	for _, grant := range grants {
+		if grant.CanEdit() {
+			access = accessEdit
+			break
+		}
+		access = accessView
+	}
+
+	// Notes the user can't see at all are reported as missing rather than
+	// forbidden, so their existence isn't leaked
+	if access == accessNone {
+		return nil, domain.ErrNoteNotFound
+	}
+	if access < required {
 		return nil, domain.ErrUnauthorizedAccess
 	}
 
 	return note, nil
 }
 
+// collaboratorGrants returns the user's grants on the note or any of its
+// ancestors, since a grant covers the whole subtree
+func (s *NoteService) collaboratorGrants(ctx context.Context, note *domain.Note, userID int64) ([]*domain.NoteCollaborator, error) {
+	if s.collaboratorRepo == nil {
+		return nil, nil
+	}
+
+	grants, err := s.collaboratorRepo.FindByUserAndNoteIDs(ctx, userID, note.PathIDs())
+	if err != nil {
+		return nil, fmt.Errorf("failed to check collaborator access: %w", err)
+	}
+	return grants, nil
+}
+
+// MaxBatchGetNotes is the maximum number of notes that can be fetched in one request
+const MaxBatchGetNotes = 100
+
+// GetNotesByIDs retrieves several of the user's notes at once, in the requested order.
+// IDs that don't exist or belong to other users are silently dropped.
+func (s *NoteService) GetNotesByIDs(ctx context.Context, userID int64, ids []int64) ([]*domain.Note, error) {
+	unique := uniqueIDs(ids)
+	if len(unique) > MaxBatchGetNotes {
+		return nil, domain.ErrTooManyNoteIDs
+	}
+
+	found, err := s.noteRepo.FindByIDs(ctx, userID, unique)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int64]*domain.Note, len(found))
+	for _, note := range found {
+		byID[note.ID] = note
+	}
+
+	notes := make([]*domain.Note, 0, len(found))
+	for _, id := range unique {
+		if note, ok := byID[id]; ok {
+			notes = append(notes, note)
+		}
+	}
+
+	return notes, nil
+}
+
+// uniqueIDs drops repeated IDs, keeping the first occurrence's position
+func uniqueIDs(ids []int64) []int64 {
+	seen := make(map[int64]bool, len(ids))
+	unique := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// NotePatch is a partial update to a note. Nil fields are left unchanged, so
+// a pointer to "" clears a field; Properties is merged into the existing ones
+// (see domain.Note.MergeProperties).
+type NotePatch struct {
+	Title      *string
+	Icon       *string
+	CoverImage *string
+	Properties map[string]interface{}
+}
+
 // UpdateNote updates an existing note with validation
 func (s *NoteService) UpdateNote(ctx context.Context, noteID, userID int64, title *string, icon *string, coverImage *string) (*domain.Note, error) {
+	return s.PatchNote(ctx, noteID, userID, NotePatch{Title: title, Icon: icon, CoverImage: coverImage})
+}
+
+// PatchNote applies a partial update to a note, touching only supplied fields
+func (s *NoteService) PatchNote(ctx context.Context, noteID, userID int64, patch NotePatch) (*domain.Note, error) {
 	// Retrieve existing note
-	note, err := s.GetNote(ctx, noteID, userID)
+	note, err := s.getEditableNote(ctx, noteID, userID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Update fields if provided
-	if title != nil {
-		if len(*title) == 0 || len(*title) > 500 {
-			return nil, domain.ErrInvalidNoteTitle
+	if patch.Title != nil {
+		if err := s.limits.ValidateTitle(*patch.Title); err != nil {
+			return nil, err
 		}
-		note.Title = *title
+		note.Title = *patch.Title
 	}
 
-	if icon != nil {
-		note.Icon = *icon
+	if patch.Icon != nil {
+		note.Icon = *patch.Icon
 	}
 
-	if coverImage != nil {
-		note.CoverImage = *coverImage
+	if patch.CoverImage != nil {
+		note.CoverImage = *patch.CoverImage
 	}
 
+	note.MergeProperties(patch.Properties)
+
 	// Save changes and get the fresh state from the DB
-	updatedNote, err := s.noteRepo.Update(ctx, note)
+	updatedNote, err := s.saveNote(ctx, note)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update note: %w", err)
 	}
 
+	if patch.Properties != nil {
+		s.propertiesChanged(ctx, updatedNote)
+	}
+
 	// Returning updatedNote allows the API to send a 200 OK with the full body
-return updatedNote, nil 
+	return updatedNote, nil
 }
 
 // DeleteNote soft deletes a note and all its descendants
 func (s *NoteService) DeleteNote(ctx context.Context, noteID, userID int64) error {
 	// Verify ownership
-	note, err := s.GetNote(ctx, noteID, userID)
-	if err != nil {
+	if _, err := s.getOwnedNote(ctx, noteID, userID); err != nil {
 		return err
 	}
 
-	// Soft delete the note
-	note.SoftDelete()
-
-	// Get all descendants and soft delete them
-	descendants, err := s.noteRepo.FindDescendants(ctx, noteID)
+	// The note and its descendants are trashed together or not at all
+	deletedIDs, err := s.noteRepo.SoftDeleteSubtree(ctx, noteID)
 	if err != nil {
-		return fmt.Errorf("failed to get descendants: %w", err)
-	}
-
-	// Collect IDs for bulk delete
-	descendantIDs := make([]int64, len(descendants))
-	for i, desc := range descendants {
-		descendantIDs[i] = desc.ID
-	}
-
-	// Bulk soft delete descendants
-	if len(descendantIDs) > 0 {
-		if err := s.noteRepo.BulkDelete(ctx, descendantIDs); err != nil {
-			return fmt.Errorf("failed to delete descendants: %w", err)
-		}
-	}
-
-	// Update the parent note
-	if _, err := s.noteRepo.Update(ctx, note); err != nil {
 		return fmt.Errorf("failed to delete note: %w", err)
 	}
+	s.invalidate(ctx, deletedIDs...)
 
 	return nil
 }
 
 // RestoreNote restores a soft-deleted note
 func (s *NoteService) RestoreNote(ctx context.Context, noteID, userID int64) (*domain.Note, error) {
-	// Get the deleted note
-	note, err := s.noteRepo.FindByID(ctx, noteID)
-	if err != nil {
+	// The repository only matches the user's own trashed notes
+	if err := s.noteRepo.Restore(ctx, noteID, userID); err != nil {
 		return nil, fmt.Errorf("note not found: %w", err)
 	}
+	s.invalidate(ctx, noteID)
 
-	// Verify ownership
-	if note.UserID != userID {
-		return nil, domain.ErrUnauthorizedAccess
-	}
-
-	// Restore the note
-	note.Restore()
-
-	// Save changes and get the fresh state from the DB
-	updatedNote, err := s.noteRepo.Update(ctx, note)
+	// Return the fresh state from the DB
+	note, err := s.noteRepo.FindByID(ctx, noteID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update note: %w", err)
+		return nil, fmt.Errorf("failed to get restored note: %w", err)
 	}
 
-	// Returning updatedNote allows the API to send a 200 OK with the full body
-	return updatedNote, nil 
+	return note, nil
 }
 
 // ArchiveNote archives a note
 func (s *NoteService) ArchiveNote(ctx context.Context, noteID, userID int64) (*domain.Note, error) {
-	note, err := s.GetNote(ctx, noteID, userID)
+	note, err := s.getOwnedNote(ctx, noteID, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -183,7 +453,7 @@ func (s *NoteService) ArchiveNote(ctx context.Context, noteID, userID int64) (*d
 	note.Archive()
 
 	// Save changes and get the fresh state from the DB
-	updatedNote, err := s.noteRepo.Update(ctx, note)
+	updatedNote, err := s.saveNote(ctx, note)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update note: %w", err)
 	}
@@ -200,13 +470,13 @@ func (s *NoteService) UnarchiveNote(ctx context.Context, noteID, userID int64) (
 	}
 
 	if note.UserID != userID {
-		return nil, domain.ErrUnauthorizedAccess
+		return nil, domain.ErrNoteNotFound
 	}
 
 	note.IsArchived = false
 
 	// Save changes and get the fresh state from the DB
-	updatedNote, err := s.noteRepo.Update(ctx, note)
+	updatedNote, err := s.saveNote(ctx, note)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update note: %w", err)
 	}
@@ -217,12 +487,43 @@ func (s *NoteService) UnarchiveNote(ctx context.Context, noteID, userID int64) (
 
 // ListNotes retrieves notes with filtering and pagination
 func (s *NoteService) ListNotes(ctx context.Context, userID int64, filters ports.NoteFilters) ([]*domain.Note, int64, error) {
+	if err := checkDateRanges(filters); err != nil {
+		return nil, 0, err
+	}
+	if err := s.checkWithin(ctx, userID, filters); err != nil {
+		return nil, 0, err
+	}
 	return s.noteRepo.FindByUserID(ctx, userID, filters)
 }
 
+// checkDateRanges rejects date ranges whose lower bound is after their upper bound
+func checkDateRanges(filters ports.NoteFilters) error {
+	if filters.CreatedAfter != nil && filters.CreatedBefore != nil && filters.CreatedAfter.After(*filters.CreatedBefore) {
+		return fmt.Errorf("%w: created_after must be before created_before", domain.ErrValidation)
+	}
+	if filters.UpdatedAfter != nil && filters.UpdatedBefore != nil && filters.UpdatedAfter.After(*filters.UpdatedBefore) {
+		return fmt.Errorf("%w: updated_after must be before updated_before", domain.ErrValidation)
+	}
+	return nil
+}
+
+// checkWithin verifies the user owns the subtree a listing is scoped to
+func (s *NoteService) checkWithin(ctx context.Context, userID int64, filters ports.NoteFilters) error {
+	if filters.WithinNoteID == nil {
+		return nil
+	}
+	_, err := s.getOwnedNote(ctx, *filters.WithinNoteID, userID)
+	return err
+}
+
+// CountNotes returns the user's note counts by state
+func (s *NoteService) CountNotes(ctx context.Context, userID int64) (*ports.NoteCounts, error) {
+	return s.noteRepo.CountByUser(ctx, userID)
+}
+
 // GetChildren retrieves direct children of a note
 func (s *NoteService) GetChildren(ctx context.Context, parentID, userID int64) ([]*domain.Note, error) {
-	// Verify parent ownership
+	// Verify parent access
 	if _, err := s.GetNote(ctx, parentID, userID); err != nil {
 		return nil, err
 	}
@@ -232,7 +533,7 @@ func (s *NoteService) GetChildren(ctx context.Context, parentID, userID int64) (
 
 // GetDescendants retrieves all descendants of a note
 func (s *NoteService) GetDescendants(ctx context.Context, parentID, userID int64) ([]*domain.Note, error) {
-	// Verify parent ownership
+	// Verify parent access
 	if _, err := s.GetNote(ctx, parentID, userID); err != nil {
 		return nil, err
 	}
@@ -240,33 +541,109 @@ func (s *NoteService) GetDescendants(ctx context.Context, parentID, userID int64
 	return s.noteRepo.FindDescendants(ctx, parentID)
 }
 
-// GetAncestors retrieves all ancestors of a note (breadcrumb trail)
-func (s *NoteService) GetAncestors(ctx context.Context, noteID, userID int64) ([]*domain.Note, error) {
-	// Verify note ownership
+// ListDescendants retrieves one page of a note's descendants as summaries,
+// with the total number of descendants
+func (s *NoteService) ListDescendants(ctx context.Context, noteID, userID int64, limit, offset int) ([]*domain.Note, int64, error) {
+	// Verify note access
+	if _, err := s.GetNote(ctx, noteID, userID); err != nil {
+		return nil, 0, err
+	}
+
+	return s.noteRepo.FindDescendantsPage(ctx, noteID, limit, offset)
+}
+
+// GetSubtreeSize returns how many notes sit below a note, i.e. how many more
+// DeleteNote would trash along with it
+func (s *NoteService) GetSubtreeSize(ctx context.Context, noteID, userID int64) (int64, error) {
+	// Verify note access
 	if _, err := s.GetNote(ctx, noteID, userID); err != nil {
+		return 0, err
+	}
+
+	count, err := s.noteRepo.CountDescendants(ctx, noteID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count descendants: %w", err)
+	}
+	return count, nil
+}
+
+// GetAncestors retrieves all ancestors of a note (breadcrumb trail).
+// Collaborators only see ancestors within the subtree shared with them.
+func (s *NoteService) GetAncestors(ctx context.Context, noteID, userID int64) ([]*domain.Note, error) {
+	// Verify note access
+	note, err := s.GetNote(ctx, noteID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.visibleAncestors(ctx, note, userID)
+}
+
+// GetNoteWithAncestors retrieves a note together with its breadcrumb trail,
+// checking access once for both
+func (s *NoteService) GetNoteWithAncestors(ctx context.Context, noteID, userID int64) (*domain.Note, []*domain.Note, error) {
+	note, err := s.GetNote(ctx, noteID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ancestors, err := s.visibleAncestors(ctx, note, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return note, ancestors, nil
+}
+
+// visibleAncestors returns the ancestors of a note the user may see
+func (s *NoteService) visibleAncestors(ctx context.Context, note *domain.Note, userID int64) ([]*domain.Note, error) {
+	ancestors, err := s.noteRepo.FindAncestors(ctx, note.ID)
+	if err != nil || note.UserID == userID {
+		return ancestors, err
+	}
+
+	grants, err := s.collaboratorGrants(ctx, note, userID)
+	if err != nil {
 		return nil, err
 	}
 
-	return s.noteRepo.FindAncestors(ctx, noteID)
+	// The shared subtree starts at the highest granted note in the path
+	granted := make(map[int64]bool, len(grants))
+	for _, grant := range grants {
+		granted[grant.NoteID] = true
+	}
+	visible := make(map[int64]bool)
+	for _, id := range note.PathIDs() {
+		if granted[id] || len(visible) > 0 {
+			visible[id] = true
+		}
+	}
+
+	filtered := make([]*domain.Note, 0, len(ancestors))
+	for _, ancestor := range ancestors {
+		if visible[ancestor.ID] {
+			filtered = append(filtered, ancestor)
+		}
+	}
+	return filtered, nil
 }
 
 // MoveNote moves a note to a new parent with validation
 func (s *NoteService) MoveNote(ctx context.Context, noteID, userID int64, newParentID *int64, newPosition int) error {
 	// Verify ownership of the note being moved
-	note, err := s.GetNote(ctx, noteID, userID)
+	note, err := s.getOwnedNote(ctx, noteID, userID)
 	if err != nil {
 		return err
 	}
 
 	// If new parent is provided, verify ownership and nesting depth
 	if newParentID != nil {
-		parent, err := s.GetNote(ctx, *newParentID, userID)
+		parent, err := s.getOwnedNote(ctx, *newParentID, userID)
 		if err != nil {
 			return fmt.Errorf("new parent not found: %w", err)
 		}
 
-		// Check if moving would exceed max depth
-		// Get descendants count to estimate new depth
+		// Check if moving would exceed max depth. Depths are taken from the
+		// paths, which are what the subtree is found by.
 		descendants, err := s.noteRepo.FindDescendants(ctx, noteID)
 		if err != nil {
 			return fmt.Errorf("failed to check descendants: %w", err)
@@ -274,29 +651,121 @@ func (s *NoteService) MoveNote(ctx context.Context, noteID, userID int64, newPar
 
 		maxDescendantDepth := 0
 		for _, desc := range descendants {
-			relativeDepth := desc.Depth - note.Depth
+			relativeDepth := desc.PathDepth() - note.PathDepth()
 			if relativeDepth > maxDescendantDepth {
 				maxDescendantDepth = relativeDepth
 			}
 		}
 
-		newDepth := parent.Depth + 1 + maxDescendantDepth
-		if newDepth > 10 {
+		newDepth := parent.PathDepth() + 1 + maxDescendantDepth
+		if newDepth > domain.MaxNestingDepth {
 			return domain.ErrMaxDepthExceeded
 		}
 	}
 
 	// Perform the move
 	if err := s.noteRepo.MoveNote(ctx, noteID, newParentID, newPosition); err != nil {
+		s.invalidate(ctx, noteID)
 		return fmt.Errorf("failed to move note: %w", err)
 	}
 
+	// Moving rewrites the path of the note and all of its descendants
+	s.invalidate(ctx, noteID)
+	if s.cache != nil {
+		descendants, err := s.noteRepo.FindDescendants(ctx, noteID)
+		if err != nil {
+			return fmt.Errorf("failed to invalidate moved descendants: %w", err)
+		}
+		for _, desc := range descendants {
+			s.invalidate(ctx, desc.ID)
+		}
+	}
+
 	return nil
 }
 
+// MoveNoteToRoot moves a note out of all its parents to the top level and
+// returns it as reloaded, with the path and depth the hierarchy trigger set
+func (s *NoteService) MoveNoteToRoot(ctx context.Context, noteID, userID int64, position int) (*domain.Note, error) {
+	if err := s.MoveNote(ctx, noteID, userID, nil, position); err != nil {
+		return nil, err
+	}
+	return s.GetNote(ctx, noteID, userID)
+}
+
+// RebuildPaths recomputes the materialized path and depth of every note a user
+// owns from the parent links, evicting corrected notes from the cache
+func (s *NoteService) RebuildPaths(ctx context.Context, userID int64) (*ports.PathRebuildResult, error) {
+	result, err := s.noteRepo.RebuildPaths(ctx, userID)
+	if result != nil {
+		s.invalidate(ctx, result.Corrected...)
+	}
+	return result, err
+}
+
+// OrphanAction is what RepairOrphans does with an orphaned note
+type OrphanAction string
+
+const (
+	// OrphanMovedToRoot notes had a parent that no longer exists and are now top-level
+	OrphanMovedToRoot OrphanAction = "moved_to_root"
+	// OrphanFlagged notes are live under a trashed parent. They're left in place
+	// so restoring the parent brings them back with it.
+	OrphanFlagged OrphanAction = "flagged"
+)
+
+// OrphanRepair reports one orphaned note and what was done with it
+type OrphanRepair struct {
+	NoteID   int64
+	UserID   int64
+	ParentID int64
+	Action   OrphanAction
+}
+
+// RepairOrphans finds notes across all users whose parent is missing or trashed.
+// Notes with a missing parent are moved to the top level and their subtrees'
+// paths rebuilt; notes under a trashed parent are only reported. With dryRun
+// nothing is changed and the report says what would be done.
+func (s *NoteService) RepairOrphans(ctx context.Context, dryRun bool) ([]OrphanRepair, error) {
+	orphans, err := s.noteRepo.FindOrphans(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	repairs := make([]OrphanRepair, len(orphans))
+	var detach []int64
+	var users []int64
+	for i, orphan := range orphans {
+		repairs[i] = OrphanRepair{NoteID: orphan.ID, UserID: orphan.UserID, ParentID: orphan.ParentID, Action: OrphanFlagged}
+		if !orphan.ParentTrashed {
+			repairs[i].Action = OrphanMovedToRoot
+			detach = append(detach, orphan.ID)
+			users = append(users, orphan.UserID)
+		}
+	}
+	if dryRun || len(detach) == 0 {
+		return repairs, nil
+	}
+
+	err = s.noteRepo.DetachNotes(ctx, detach)
+	s.invalidate(ctx, detach...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Descendants of the detached notes still carry the old path prefix
+	for _, userID := range uniqueIDs(users) {
+		if _, err := s.RebuildPaths(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	return repairs, nil
+}
+
 // AddBlock adds a new block to a note
 func (s *NoteService) AddBlock(ctx context.Context, noteID, userID int64, blockType domain.BlockType, content *domain.BlockContent) (*domain.Note, error) {
-	note, err := s.GetNote(ctx, noteID, userID)
+	note, err := s.getEditableNote(ctx, noteID, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -308,6 +777,9 @@ func (s *NoteService) AddBlock(ctx context.Context, noteID, userID int64, blockT
 	if content == nil {
 		return nil, fmt.Errorf("block content is required")
 	}
+	if err := s.limits.ValidateBlockCount(len(note.Blocks) + 1); err != nil {
+		return nil, err
+	}
 
 	// Create block with generated ID
 	block := domain.Block{
@@ -317,15 +789,27 @@ func (s *NoteService) AddBlock(ctx context.Context, noteID, userID int64, blockT
 	}
 
 	// Add block using domain method
+	sizeBefore := domain.BlocksSize(note.Blocks)
 	if err := note.AddBlock(block); err != nil {
 		return nil, fmt.Errorf("failed to add block: %w", err)
 	}
+	if err := s.checkBlockQuota(ctx, note.UserID, domain.BlocksSize(note.Blocks)-sizeBefore); err != nil {
+		return nil, err
+	}
 
 	// Save updated blocks
-	if err := s.noteRepo.UpdateBlocks(ctx, noteID, note.Blocks); err != nil {
+	if err := s.saveBlocks(ctx, noteID, note.Blocks); err != nil {
 		return nil, fmt.Errorf("failed to save blocks: %w", err)
 	}
 
+	s.publishChange(NoteChangeEvent{
+		Type:    NoteChangeBlockAdded,
+		NoteID:  noteID,
+		UserID:  userID,
+		BlockID: block.ID,
+		Block:   findBlock(note, block.ID),
+	})
+
 	return note, nil
 }
 
@@ -336,27 +820,105 @@ func generateBlockID() string {
 
 // UpdateBlock updates an existing block
 func (s *NoteService) UpdateBlock(ctx context.Context, noteID, userID int64, blockID string, content *domain.BlockContent) (*domain.Note, error) {
-	note, err := s.GetNote(ctx, noteID, userID)
+	note, err := s.getEditableNote(ctx, noteID, userID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Update block using domain method
+	sizeBefore := domain.BlocksSize(note.Blocks)
 	if err := note.UpdateBlock(blockID, content); err != nil {
 		return nil, fmt.Errorf("failed to update block: %w", err)
 	}
+	if err := s.checkBlockQuota(ctx, note.UserID, domain.BlocksSize(note.Blocks)-sizeBefore); err != nil {
+		return nil, err
+	}
 
-	// Save updated blocks
-	if err := s.noteRepo.UpdateBlocks(ctx, noteID, note.Blocks); err != nil {
+	// Patch just this block so concurrent edits to other blocks aren't lost
+	err = s.noteRepo.UpdateBlockContent(ctx, noteID, blockID, content)
+	s.invalidate(ctx, noteID)
+	if errors.Is(err, domain.ErrBlockNotFound) {
+		// The block moved between our read and the patch (e.g. a concurrent
+		// reorder). Retry against the latest blocks, which fails if it was deleted.
+		note, err = s.rewriteBlock(ctx, noteID, func(note *domain.Note) error {
+			return note.UpdateBlock(blockID, content)
+		})
+	}
+	if err != nil {
 		return nil, fmt.Errorf("failed to save blocks: %w", err)
 	}
 
+	s.publishChange(NoteChangeEvent{
+		Type:    NoteChangeBlockUpdated,
+		NoteID:  noteID,
+		UserID:  userID,
+		BlockID: blockID,
+		Block:   findBlock(note, blockID),
+	})
+
+	return note, nil
+}
+
+// UpdateBlockText replaces just the rich text of a block. It backs editor
+// auto-save, so the payload and the database patch stay as small as possible.
+func (s *NoteService) UpdateBlockText(ctx context.Context, noteID, userID int64, blockID string, richText []domain.RichTextSegment) (*domain.Note, error) {
+	note, err := s.getEditableNote(ctx, noteID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sizeBefore := domain.BlocksSize(note.Blocks)
+	if err := note.UpdateBlockText(blockID, richText); err != nil {
+		return nil, fmt.Errorf("failed to update block: %w", err)
+	}
+	if err := s.checkBlockQuota(ctx, note.UserID, domain.BlocksSize(note.Blocks)-sizeBefore); err != nil {
+		return nil, err
+	}
+
+	err = s.noteRepo.UpdateBlockText(ctx, noteID, blockID, richText)
+	s.invalidate(ctx, noteID)
+	if errors.Is(err, domain.ErrBlockNotFound) {
+		note, err = s.rewriteBlock(ctx, noteID, func(note *domain.Note) error {
+			return note.UpdateBlockText(blockID, richText)
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save blocks: %w", err)
+	}
+
+	s.publishChange(NoteChangeEvent{
+		Type:    NoteChangeBlockUpdated,
+		NoteID:  noteID,
+		UserID:  userID,
+		BlockID: blockID,
+		Block:   findBlock(note, blockID),
+	})
+
+	return note, nil
+}
+
+// rewriteBlock applies a block edit to a fresh read of the note and rewrites its
+// full blocks array
+func (s *NoteService) rewriteBlock(ctx context.Context, noteID int64, apply func(note *domain.Note) error) (*domain.Note, error) {
+	note, err := s.noteRepo.FindByID(ctx, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := apply(note); err != nil {
+		return nil, err
+	}
+
+	if err := s.saveBlocks(ctx, noteID, note.Blocks); err != nil {
+		return nil, err
+	}
+
 	return note, nil
 }
 
 // DeleteBlock removes a block from a note
 func (s *NoteService) DeleteBlock(ctx context.Context, noteID, userID int64, blockID string) (*domain.Note, error) {
-	note, err := s.GetNote(ctx, noteID, userID)
+	note, err := s.getEditableNote(ctx, noteID, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -367,16 +929,23 @@ func (s *NoteService) DeleteBlock(ctx context.Context, noteID, userID int64, blo
 	}
 
 	// Save updated blocks
-	if err := s.noteRepo.UpdateBlocks(ctx, noteID, note.Blocks); err != nil {
+	if err := s.saveBlocks(ctx, noteID, note.Blocks); err != nil {
 		return nil, fmt.Errorf("failed to save blocks: %w", err)
 	}
 
+	s.publishChange(NoteChangeEvent{
+		Type:    NoteChangeBlockDeleted,
+		NoteID:  noteID,
+		UserID:  userID,
+		BlockID: blockID,
+	})
+
 	return note, nil
 }
 
 // ReorderBlocks changes the order of blocks
 func (s *NoteService) ReorderBlocks(ctx context.Context, noteID, userID int64, blockOrder []string) (*domain.Note, error) {
-	note, err := s.GetNote(ctx, noteID, userID)
+	note, err := s.getEditableNote(ctx, noteID, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -393,20 +962,31 @@ func (s *NoteService) ReorderBlocks(ctx context.Context, noteID, userID int64, b
 	}
 
 	// Save updated blocks
-	if err := s.noteRepo.UpdateBlocks(ctx, noteID, note.Blocks); err != nil {
+	if err := s.saveBlocks(ctx, noteID, note.Blocks); err != nil {
 		return nil, fmt.Errorf("failed to save blocks: %w", err)
 	}
 
+	s.publishChange(NoteChangeEvent{
+		Type:   NoteChangeBlocksReordered,
+		NoteID: noteID,
+		UserID: userID,
+		Blocks: note.Blocks,
+	})
+
 	return note, nil
 }
 
 // ReplaceBlocks replaces all blocks in a note
 func (s *NoteService) ReplaceBlocks(ctx context.Context, noteID, userID int64, blocks []domain.Block) (*domain.Note, error) {
-	note, err := s.GetNote(ctx, noteID, userID)
+	note, err := s.getEditableNote(ctx, noteID, userID)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.limits.ValidateBlockCount(len(blocks)); err != nil {
+		return nil, err
+	}
+
 	// Validate all blocks
 	for i, block := range blocks {
 		if block.Type == "" {
@@ -421,41 +1001,53 @@ func (s *NoteService) ReplaceBlocks(ctx context.Context, noteID, userID int64, b
 		}
 	}
 
+	if err := s.checkBlockQuota(ctx, note.UserID, domain.BlocksSize(blocks)-domain.BlocksSize(note.Blocks)); err != nil {
+		return nil, err
+	}
+
 	note.Blocks = blocks
 
 	// Save updated blocks
-	if err := s.noteRepo.UpdateBlocks(ctx, noteID, note.Blocks); err != nil {
+	if err := s.saveBlocks(ctx, noteID, note.Blocks); err != nil {
 		return nil, fmt.Errorf("failed to save blocks: %w", err)
 	}
 
+	s.publishChange(NoteChangeEvent{
+		Type:   NoteChangeBlocksReplaced,
+		NoteID: noteID,
+		UserID: userID,
+		Blocks: note.Blocks,
+	})
+
 	return note, nil
 }
 
-// SearchNotes searches notes by query
+// SearchNotes searches notes by query, optionally within one of the user's subtrees
 func (s *NoteService) SearchNotes(ctx context.Context, userID int64, query string, filters ports.NoteFilters) ([]*domain.Note, int64, error) {
+	if err := s.checkWithin(ctx, userID, filters); err != nil {
+		return nil, 0, err
+	}
 	return s.noteRepo.Search(ctx, userID, query, filters)
 }
 
 // UpdateViewMetadata updates the view metadata for a note
 func (s *NoteService) UpdateViewMetadata(ctx context.Context, noteID, userID int64, viewMetadata *domain.ViewMetadata) (*domain.Note, error) {
-	note, err := s.GetNote(ctx, noteID, userID)
+	note, err := s.getEditableNote(ctx, noteID, userID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Validate view metadata
 	if viewMetadata != nil {
-		if viewMetadata.ViewType != domain.ViewTypeTable &&
-			viewMetadata.ViewType != domain.ViewTypeBoard &&
-			viewMetadata.ViewType != domain.ViewTypeList {
-			return nil, domain.ErrInvalidViewType
+		if err := viewMetadata.Validate(); err != nil {
+			return nil, err
 		}
 	}
 
 	note.ViewMetadata = viewMetadata
 
 	// Save changes and get the fresh state from the DB
-	updatedNote, err := s.noteRepo.Update(ctx, note)
+	updatedNote, err := s.saveNote(ctx, note)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update note: %w", err)
 	}
@@ -464,28 +1056,65 @@ func (s *NoteService) UpdateViewMetadata(ctx context.Context, noteID, userID int
 	return updatedNote, nil 
 }
 
-// UpdateProperties updates custom properties for a note
-func (s *NoteService) UpdateProperties(ctx context.Context, noteID, userID int64, properties map[string]interface{}) (*domain.Note, error) {
+// QueryView returns a page of a database-view note's rows (its children) with
+// the view's filters and sorts applied, along with the number of matching rows
+func (s *NoteService) QueryView(ctx context.Context, noteID, userID int64, limit, offset int) ([]*domain.Note, int64, error) {
 	note, err := s.GetNote(ctx, noteID, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if note.ViewMetadata == nil {
+		return nil, 0, domain.ErrNoteNotView
+	}
+
+	children, err := s.noteRepo.FindChildren(ctx, noteID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to load view rows: %w", err)
+	}
+
+	rows := note.ViewMetadata.Apply(children)
+	total := int64(len(rows))
+
+	if offset >= len(rows) {
+		return []*domain.Note{}, total, nil
+	}
+	rows = rows[offset:]
+	if limit > 0 && limit < len(rows) {
+		rows = rows[:limit]
+	}
+	return rows, total, nil
+}
+
+// UpdateProperties updates custom properties for a note. With merge, the
+// given properties are merged into the existing ones (a nil value removes a
+// key); otherwise they replace the whole map.
+func (s *NoteService) UpdateProperties(ctx context.Context, noteID, userID int64, properties map[string]interface{}, merge bool) (*domain.Note, error) {
+	note, err := s.getEditableNote(ctx, noteID, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	note.Properties = properties
+	if merge {
+		note.MergeProperties(properties)
+	} else {
+		note.Properties = properties
+	}
 
 	// Save changes and get the fresh state from the DB
-	updatedNote, err := s.noteRepo.Update(ctx, note)
+	updatedNote, err := s.saveNote(ctx, note)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update note: %w", err)
 	}
 
+	s.propertiesChanged(ctx, updatedNote)
+
 	// Returning updatedNote allows the API to send a 200 OK with the full body
 	return updatedNote, nil 
 }
 
 // ToggleFavorite toggles the favorite status of a note
 func (s *NoteService) ToggleFavorite(ctx context.Context, noteID, userID int64) (*domain.Note, error) {
-	note, err := s.GetNote(ctx, noteID, userID)
+	note, err := s.getOwnedNote(ctx, noteID, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -494,7 +1123,7 @@ func (s *NoteService) ToggleFavorite(ctx context.Context, noteID, userID int64)
 	note.ToggleFavorite()
 
 	// Save changes and get the fresh state from the DB
-	updatedNote, err := s.noteRepo.Update(ctx, note)
+	updatedNote, err := s.saveNote(ctx, note)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update note: %w", err)
 	}
@@ -506,13 +1135,15 @@ func (s *NoteService) ToggleFavorite(ctx context.Context, noteID, userID int64)
 // AddTag adds a tag to a note
 func (s *NoteService) AddTag(ctx context.Context, noteID, userID int64, tagID string) (*domain.Note, error) {
 	// Verify note ownership
-	_, err := s.GetNote(ctx, noteID, userID)
+	_, err := s.getOwnedNote(ctx, noteID, userID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Add tag via repository
-	if err := s.noteRepo.AddTag(ctx, noteID, tagID); err != nil {
+	err = s.noteRepo.AddTag(ctx, noteID, tagID)
+	s.invalidate(ctx, noteID)
+	if err != nil {
 		return nil, fmt.Errorf("failed to add tag: %w", err)
 	}
 
@@ -528,13 +1159,15 @@ func (s *NoteService) AddTag(ctx context.Context, noteID, userID int64, tagID st
 // RemoveTag removes a tag from a note
 func (s *NoteService) RemoveTag(ctx context.Context, noteID, userID int64, tagID string) (*domain.Note, error) {
 	// Verify note ownership
-	_, err := s.GetNote(ctx, noteID, userID)
+	_, err := s.getOwnedNote(ctx, noteID, userID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Remove tag via repository
-	if err := s.noteRepo.RemoveTag(ctx, noteID, tagID); err != nil {
+	err = s.noteRepo.RemoveTag(ctx, noteID, tagID)
+	s.invalidate(ctx, noteID)
+	if err != nil {
 		return nil, fmt.Errorf("failed to remove tag: %w", err)
 	}
 
@@ -546,3 +1179,304 @@ func (s *NoteService) RemoveTag(ctx context.Context, noteID, userID int64, tagID
 
 	return updatedNote, nil
 }
+
+// ListTags returns a page of the user's tags with how many notes use each,
+// and the user's total tag count
+func (s *NoteService) ListTags(ctx context.Context, userID int64, limit, offset int) ([]ports.TagUsage, int64, error) {
+	return s.noteRepo.ListTags(ctx, userID, limit, offset)
+}
+
+// getOwnedTag retrieves a tag only if the user owns it
+func (s *NoteService) getOwnedTag(ctx context.Context, tagID string, userID int64) (*domain.Tag, error) {
+	tag, err := s.noteRepo.FindTag(ctx, tagID)
+	if err != nil {
+		return nil, err
+	}
+	if tag.UserID != userID {
+		// Other users' tags are indistinguishable from missing ones
+		return nil, domain.ErrTagNotFound
+	}
+	return tag, nil
+}
+
+// taggedNoteIDs returns the notes with a tag whose cached copies a tag change
+// makes stale, since cached notes include their tags. It skips the lookup
+// when there is no cache.
+func (s *NoteService) taggedNoteIDs(ctx context.Context, tagID string) ([]int64, error) {
+	if s.cache == nil {
+		return nil, nil
+	}
+	return s.noteRepo.FindNoteIDsByTag(ctx, tagID)
+}
+
+// UpdateTag renames and/or recolors one of the user's tags. Nil fields are left
+// unchanged. Returns ErrTagNameTaken if the user has another tag with the name.
+func (s *NoteService) UpdateTag(ctx context.Context, userID int64, tagID string, name, color *string) (*domain.Tag, error) {
+	tag, err := s.getOwnedTag(ctx, tagID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if name != nil {
+		if err := tag.Rename(*name); err != nil {
+			return nil, err
+		}
+
+		existing, err := s.noteRepo.FindTagByName(ctx, userID, tag.Name)
+		if err != nil && !errors.Is(err, domain.ErrTagNotFound) {
+			return nil, err
+		}
+		if existing != nil && existing.ID != tag.ID {
+			return nil, domain.ErrTagNameTaken
+		}
+	}
+
+	if color != nil {
+		if err := tag.SetColor(*color); err != nil {
+			return nil, err
+		}
+	}
+
+	stale, err := s.taggedNoteIDs(ctx, tag.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update tag: %w", err)
+	}
+
+	err = s.noteRepo.UpdateTag(ctx, tag)
+	s.invalidate(ctx, stale...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update tag: %w", err)
+	}
+
+	return tag, nil
+}
+
+// MergeTags moves all notes from one of the user's tags to another, for
+// cleaning up near-duplicates, then deletes the source tag. Notes that already
+// have both tags end up with just the target. Returns the target tag.
+func (s *NoteService) MergeTags(ctx context.Context, userID int64, sourceTagID, targetTagID string) (*domain.Tag, error) {
+	if sourceTagID == targetTagID {
+		return nil, domain.ErrMergeTagIntoItself
+	}
+
+	if _, err := s.getOwnedTag(ctx, sourceTagID, userID); err != nil {
+		return nil, err
+	}
+	target, err := s.getOwnedTag(ctx, targetTagID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The source's notes are all the ones whose tags change
+	stale, err := s.taggedNoteIDs(ctx, sourceTagID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge tags: %w", err)
+	}
+
+	err = s.noteRepo.MergeTags(ctx, sourceTagID, targetTagID)
+	s.invalidate(ctx, stale...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge tags: %w", err)
+	}
+
+	return target, nil
+}
+
+// MaxBulkTagNotes is the maximum number of notes that can be tagged in one request
+const MaxBulkTagNotes = 100
+
+// TagResult reports whether a bulk tag operation applied to one note
+type TagResult struct {
+	NoteID int64
+	Err    error // ErrNoteNotFound for notes that don't exist or aren't the user's
+}
+
+// BulkAddTag adds one of the user's tags to many of their notes at once.
+// Notes the user doesn't own are skipped and reported in the results; the
+// rest are tagged in a single transaction.
+func (s *NoteService) BulkAddTag(ctx context.Context, userID int64, noteIDs []int64, tagID string) ([]TagResult, error) {
+	return s.bulkTag(ctx, userID, noteIDs, tagID, s.noteRepo.BulkAddTag)
+}
+
+// BulkRemoveTag removes one of the user's tags from many of their notes at once,
+// reporting per-note results like BulkAddTag
+func (s *NoteService) BulkRemoveTag(ctx context.Context, userID int64, noteIDs []int64, tagID string) ([]TagResult, error) {
+	return s.bulkTag(ctx, userID, noteIDs, tagID, s.noteRepo.BulkRemoveTag)
+}
+
+func (s *NoteService) bulkTag(
+	ctx context.Context,
+	userID int64,
+	noteIDs []int64,
+	tagID string,
+	apply func(ctx context.Context, noteIDs []int64, tagID string) error,
+) ([]TagResult, error) {
+	unique := uniqueIDs(noteIDs)
+	if len(unique) > MaxBulkTagNotes {
+		return nil, domain.ErrTooManyNoteIDs
+	}
+
+	if _, err := s.getOwnedTag(ctx, tagID, userID); err != nil {
+		return nil, err
+	}
+
+	owned, err := s.noteRepo.FindByIDs(ctx, userID, unique)
+	if err != nil {
+		return nil, err
+	}
+	isOwned := make(map[int64]bool, len(owned))
+	for _, note := range owned {
+		isOwned[note.ID] = true
+	}
+
+	results := make([]TagResult, len(unique))
+	ownedIDs := make([]int64, 0, len(owned))
+	for i, id := range unique {
+		results[i] = TagResult{NoteID: id}
+		if !isOwned[id] {
+			results[i].Err = domain.ErrNoteNotFound
+			continue
+		}
+		ownedIDs = append(ownedIDs, id)
+	}
+
+	err = apply(ctx, ownedIDs, tagID)
+	s.invalidate(ctx, ownedIDs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update tags: %w", err)
+	}
+
+	return results, nil
+}
+
+// shareTokenBytes is the amount of randomness in a shared link token
+const shareTokenBytes = 32
+
+// generateShareToken generates an unguessable URL-safe token
+func generateShareToken() (string, error) {
+	buf := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// CreateSharedLink mints a link that grants access to the note without login
+func (s *NoteService) CreateSharedLink(ctx context.Context, noteID, userID int64, permission domain.SharePermission, expiresAt *time.Time) (*domain.SharedLink, error) {
+	if _, err := s.getOwnedNote(ctx, noteID, userID); err != nil {
+		return nil, err
+	}
+
+	token, err := generateShareToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate share token: %w", err)
+	}
+
+	link, err := domain.NewSharedLink(noteID, userID, token, permission, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.sharedLinkRepo.Create(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to save shared link: %w", err)
+	}
+
+	return link, nil
+}
+
+// ListSharedLinks returns all links for a note, including revoked and expired ones
+func (s *NoteService) ListSharedLinks(ctx context.Context, noteID, userID int64) ([]*domain.SharedLink, error) {
+	if _, err := s.getOwnedNote(ctx, noteID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.sharedLinkRepo.FindByNoteID(ctx, noteID)
+}
+
+// RevokeSharedLink revokes a link so it no longer grants access
+func (s *NoteService) RevokeSharedLink(ctx context.Context, noteID, linkID, userID int64) error {
+	if _, err := s.getOwnedNote(ctx, noteID, userID); err != nil {
+		return err
+	}
+
+	link, err := s.sharedLinkRepo.FindByID(ctx, linkID)
+	if err != nil {
+		return err
+	}
+	if link.NoteID != noteID {
+		return domain.ErrSharedLinkNotFound
+	}
+
+	return s.sharedLinkRepo.Revoke(ctx, linkID)
+}
+
+// GetSharedNote returns the note a shared link points to, bypassing the ownership check.
+// Callers must only expose fields that are safe to show to anonymous viewers.
+func (s *NoteService) GetSharedNote(ctx context.Context, token string) (*domain.Note, *domain.SharedLink, error) {
+	link, err := s.sharedLinkRepo.FindByToken(ctx, token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !link.IsActive(time.Now()) {
+		return nil, nil, domain.ErrSharedLinkExpired
+	}
+
+	note, err := s.noteRepo.FindByID(ctx, link.NoteID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Trashed notes stop being shared without revoking their links
+	if note.IsDeleted {
+		return nil, nil, domain.ErrSharedLinkNotFound
+	}
+
+	return note, link, nil
+}
+
+// AddCollaborator grants another registered user access to the note and its descendants.
+// Adding an existing collaborator updates their role.
+func (s *NoteService) AddCollaborator(ctx context.Context, noteID, userID int64, email string, role domain.CollaboratorRole) (*domain.NoteCollaborator, error) {
+	note, err := s.getOwnedNote(ctx, noteID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	collaboratorUser, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if collaboratorUser.ID == note.UserID {
+		return nil, domain.ErrCannotCollaborateOwn
+	}
+
+	collaborator, err := domain.NewNoteCollaborator(noteID, collaboratorUser.ID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.collaboratorRepo.Upsert(ctx, collaborator); err != nil {
+		return nil, fmt.Errorf("failed to save collaborator: %w", err)
+	}
+
+	return collaborator, nil
+}
+
+// ListCollaborators returns the collaborators granted access directly on the note
+func (s *NoteService) ListCollaborators(ctx context.Context, noteID, userID int64) ([]*domain.NoteCollaborator, error) {
+	if _, err := s.getOwnedNote(ctx, noteID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.collaboratorRepo.FindByNoteID(ctx, noteID)
+}
+
+// RemoveCollaborator revokes a collaborator's access to the note
+func (s *NoteService) RemoveCollaborator(ctx context.Context, noteID, userID, collaboratorUserID int64) error {
+	if _, err := s.getOwnedNote(ctx, noteID, userID); err != nil {
+		return err
+	}
+
+	return s.collaboratorRepo.Delete(ctx, noteID, collaboratorUserID)
+}
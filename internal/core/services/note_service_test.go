@@ -0,0 +1,808 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/notinoteapp/internal/core/domain"
+	"github.com/yourusername/notinoteapp/internal/core/ports"
+)
+
+// stubNoteRepository serves notes from memory; unimplemented methods panic
+type stubNoteRepository struct {
+	ports.NoteRepository
+	notes map[int64]*domain.Note
+}
+
+func (r *stubNoteRepository) FindByID(ctx context.Context, id int64) (*domain.Note, error) {
+	note, ok := r.notes[id]
+	if !ok {
+		return nil, domain.ErrNoteNotFound
+	}
+	copied := *note
+	return &copied, nil
+}
+
+func (r *stubNoteRepository) FindByIDs(ctx context.Context, userID int64, ids []int64) ([]*domain.Note, error) {
+	var notes []*domain.Note
+	for _, note := range r.notes {
+		for _, id := range ids {
+			if note.ID == id && note.UserID == userID && !note.IsDeleted {
+				notes = append(notes, note)
+			}
+		}
+	}
+	return notes, nil
+}
+
+type stubSharedLinkRepository struct {
+	links []*domain.SharedLink
+}
+
+func (r *stubSharedLinkRepository) Create(ctx context.Context, link *domain.SharedLink) error {
+	link.ID = int64(len(r.links) + 1)
+	r.links = append(r.links, link)
+	return nil
+}
+
+func (r *stubSharedLinkRepository) FindByID(ctx context.Context, id int64) (*domain.SharedLink, error) {
+	for _, link := range r.links {
+		if link.ID == id {
+			return link, nil
+		}
+	}
+	return nil, domain.ErrSharedLinkNotFound
+}
+
+func (r *stubSharedLinkRepository) FindByToken(ctx context.Context, token string) (*domain.SharedLink, error) {
+	for _, link := range r.links {
+		if link.Token == token {
+			return link, nil
+		}
+	}
+	return nil, domain.ErrSharedLinkNotFound
+}
+
+func (r *stubSharedLinkRepository) FindByNoteID(ctx context.Context, noteID int64) ([]*domain.SharedLink, error) {
+	var links []*domain.SharedLink
+	for _, link := range r.links {
+		if link.NoteID == noteID {
+			links = append(links, link)
+		}
+	}
+	return links, nil
+}
+
+func (r *stubSharedLinkRepository) Revoke(ctx context.Context, id int64) error {
+	link, err := r.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	link.Revoke()
+	return nil
+}
+
+type stubCollaboratorRepository struct {
+	collaborators []*domain.NoteCollaborator
+}
+
+func (r *stubCollaboratorRepository) Upsert(ctx context.Context, collaborator *domain.NoteCollaborator) error {
+	for _, existing := range r.collaborators {
+		if existing.NoteID == collaborator.NoteID && existing.UserID == collaborator.UserID {
+			existing.Role = collaborator.Role
+			return nil
+		}
+	}
+	r.collaborators = append(r.collaborators, collaborator)
+	return nil
+}
+
+func (r *stubCollaboratorRepository) Delete(ctx context.Context, noteID, userID int64) error {
+	for i, existing := range r.collaborators {
+		if existing.NoteID == noteID && existing.UserID == userID {
+			r.collaborators = append(r.collaborators[:i], r.collaborators[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrCollaboratorNotFound
+}
+
+func (r *stubCollaboratorRepository) FindByNoteID(ctx context.Context, noteID int64) ([]*domain.NoteCollaborator, error) {
+	var collaborators []*domain.NoteCollaborator
+	for _, existing := range r.collaborators {
+		if existing.NoteID == noteID {
+			collaborators = append(collaborators, existing)
+		}
+	}
+	return collaborators, nil
+}
+
+func (r *stubCollaboratorRepository) FindByUserAndNoteIDs(ctx context.Context, userID int64, noteIDs []int64) ([]*domain.NoteCollaborator, error) {
+	var collaborators []*domain.NoteCollaborator
+	for _, existing := range r.collaborators {
+		for _, noteID := range noteIDs {
+			if existing.UserID == userID && existing.NoteID == noteID {
+				collaborators = append(collaborators, existing)
+			}
+		}
+	}
+	return collaborators, nil
+}
+
+func newShareTestService() (*NoteService, *stubNoteRepository) {
+	noteRepo := &stubNoteRepository{notes: map[int64]*domain.Note{
+		1: {ID: 1, UserID: 7, Title: "Shared"},
+	}}
+	return NewNoteService(noteRepo, &stubSharedLinkRepository{}, &stubCollaboratorRepository{}, nil), noteRepo
+}
+
+func TestNoteService_SharedLinkLifecycle(t *testing.T) {
+	service, _ := newShareTestService()
+	ctx := context.Background()
+
+	link, err := service.CreateSharedLink(ctx, 1, 7, "", nil)
+	require.NoError(t, err)
+	assert.Len(t, link.Token, 43)
+	assert.Equal(t, domain.SharePermissionRead, link.Permission)
+
+	note, got, err := service.GetSharedNote(ctx, link.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "Shared", note.Title)
+	assert.Equal(t, link.ID, got.ID)
+
+	require.NoError(t, service.RevokeSharedLink(ctx, 1, link.ID, 7))
+
+	_, _, err = service.GetSharedNote(ctx, link.Token)
+	assert.ErrorIs(t, err, domain.ErrSharedLinkExpired)
+}
+
+func TestNoteService_CreateSharedLink_RequiresOwnership(t *testing.T) {
+	service, _ := newShareTestService()
+
+	_, err := service.CreateSharedLink(context.Background(), 1, 8, "", nil)
+
+	assert.ErrorIs(t, err, domain.ErrNoteNotFound, "other users' notes look missing")
+}
+
+func TestNoteService_GetSharedNote_ExpiredOrDeleted(t *testing.T) {
+	service, noteRepo := newShareTestService()
+	ctx := context.Background()
+
+	expiresAt := time.Now().Add(time.Hour)
+	link, err := service.CreateSharedLink(ctx, 1, 7, domain.SharePermissionRead, &expiresAt)
+	require.NoError(t, err)
+
+	past := time.Now().Add(-time.Minute)
+	link.ExpiresAt = &past
+	_, _, err = service.GetSharedNote(ctx, link.Token)
+	assert.ErrorIs(t, err, domain.ErrSharedLinkExpired)
+
+	link.ExpiresAt = nil
+	noteRepo.notes[1].IsDeleted = true
+	_, _, err = service.GetSharedNote(ctx, link.Token)
+	assert.ErrorIs(t, err, domain.ErrSharedLinkNotFound)
+
+	_, _, err = service.GetSharedNote(ctx, "unknown")
+	assert.ErrorIs(t, err, domain.ErrSharedLinkNotFound)
+}
+
+// newCollaborationTestService returns a service over the tree 1 > 2 > 3 owned by user 7
+func newCollaborationTestService(collaborators ...*domain.NoteCollaborator) *NoteService {
+	noteRepo := &stubCollaborationNoteRepository{stubNoteRepository{notes: map[int64]*domain.Note{
+		1: {ID: 1, UserID: 7, Title: "Root", Path: "/1/"},
+		2: {ID: 2, UserID: 7, Title: "Project", Path: "/1/2/"},
+		3: {ID: 3, UserID: 7, Title: "Task", Path: "/1/2/3/"},
+	}}}
+	return NewNoteService(noteRepo, &stubSharedLinkRepository{}, &stubCollaboratorRepository{collaborators: collaborators}, nil)
+}
+
+type stubCollaborationNoteRepository struct {
+	stubNoteRepository
+}
+
+func (r *stubCollaborationNoteRepository) UpdateBlocks(ctx context.Context, noteID int64, blocks []domain.Block) error {
+	return nil
+}
+
+func (r *stubCollaborationNoteRepository) FindAncestors(ctx context.Context, noteID int64) ([]*domain.Note, error) {
+	note := r.notes[noteID]
+	var ancestors []*domain.Note
+	for _, id := range note.PathIDs() {
+		if id != noteID {
+			ancestors = append(ancestors, r.notes[id])
+		}
+	}
+	return ancestors, nil
+}
+
+func TestNoteService_CollaboratorAccessCoversSubtree(t *testing.T) {
+	service := newCollaborationTestService(&domain.NoteCollaborator{NoteID: 2, UserID: 8, Role: domain.CollaboratorRoleViewer})
+	ctx := context.Background()
+
+	note, err := service.GetNote(ctx, 3, 8)
+	require.NoError(t, err)
+	assert.Equal(t, "Task", note.Title)
+
+	_, err = service.GetNote(ctx, 1, 8)
+	assert.ErrorIs(t, err, domain.ErrNoteNotFound, "grant does not extend to ancestors")
+
+	_, err = service.AddBlock(ctx, 3, 8, domain.BlockTypeParagraph, &domain.BlockContent{})
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess, "viewers cannot modify blocks")
+
+	ancestors, err := service.GetAncestors(ctx, 3, 8)
+	require.NoError(t, err)
+	require.Len(t, ancestors, 1)
+	assert.Equal(t, int64(2), ancestors[0].ID)
+}
+
+func TestNoteService_GetNoteWithAncestors(t *testing.T) {
+	ctx := context.Background()
+
+	owner := newCollaborationTestService()
+	note, ancestors, err := owner.GetNoteWithAncestors(ctx, 3, 7)
+	require.NoError(t, err)
+	assert.Equal(t, "Task", note.Title)
+	require.Len(t, ancestors, 2)
+	assert.Equal(t, int64(1), ancestors[0].ID)
+
+	// Collaborators only see the part of the trail shared with them
+	collaborator := newCollaborationTestService(&domain.NoteCollaborator{NoteID: 2, UserID: 8, Role: domain.CollaboratorRoleViewer})
+	_, ancestors, err = collaborator.GetNoteWithAncestors(ctx, 3, 8)
+	require.NoError(t, err)
+	require.Len(t, ancestors, 1)
+	assert.Equal(t, int64(2), ancestors[0].ID)
+
+	_, _, err = collaborator.GetNoteWithAncestors(ctx, 1, 8)
+	assert.ErrorIs(t, err, domain.ErrNoteNotFound)
+}
+
+func TestNoteService_EditorCanModifyButNotDeleteOrShare(t *testing.T) {
+	service := newCollaborationTestService(&domain.NoteCollaborator{NoteID: 2, UserID: 8, Role: domain.CollaboratorRoleEditor})
+	ctx := context.Background()
+
+	_, err := service.AddBlock(ctx, 3, 8, domain.BlockTypeParagraph, &domain.BlockContent{})
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, service.DeleteNote(ctx, 3, 8), domain.ErrUnauthorizedAccess)
+
+	_, err = service.CreateSharedLink(ctx, 3, 8, domain.SharePermissionRead, nil)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+
+	_, err = service.ListCollaborators(ctx, 2, 8)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+}
+
+func TestNoteService_GetNotesByIDs_PreservesOrderAndDropsOthers(t *testing.T) {
+	noteRepo := &stubNoteRepository{notes: map[int64]*domain.Note{
+		1: {ID: 1, UserID: 7, Title: "First"},
+		2: {ID: 2, UserID: 7, Title: "Second"},
+		3: {ID: 3, UserID: 8, Title: "Someone else's"},
+	}}
+	service := NewNoteService(noteRepo, nil, nil, nil)
+
+	notes, err := service.GetNotesByIDs(context.Background(), 7, []int64{2, 3, 99, 1, 2})
+
+	require.NoError(t, err)
+	require.Len(t, notes, 2)
+	assert.Equal(t, int64(2), notes[0].ID)
+	assert.Equal(t, int64(1), notes[1].ID)
+}
+
+func TestNoteService_GetNotesByIDs_Cap(t *testing.T) {
+	service := NewNoteService(&stubNoteRepository{}, nil, nil, nil)
+
+	ids := make([]int64, MaxBatchGetNotes+1)
+	for i := range ids {
+		ids[i] = int64(i + 1)
+	}
+
+	_, err := service.GetNotesByIDs(context.Background(), 7, ids)
+
+	assert.ErrorIs(t, err, domain.ErrTooManyNoteIDs)
+}
+
+// stubNoteCache is an in-memory ports.NoteCache
+type stubNoteCache struct {
+	notes map[int64]*domain.Note
+}
+
+func (c *stubNoteCache) GetOrLoad(ctx context.Context, noteID int64, load func(ctx context.Context) (*domain.Note, error)) (*domain.Note, error) {
+	if note, ok := c.notes[noteID]; ok {
+		copied := *note
+		return &copied, nil
+	}
+	note, err := load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.notes[noteID] = note
+	return note, nil
+}
+
+func (c *stubNoteCache) Invalidate(ctx context.Context, noteID int64) {
+	delete(c.notes, noteID)
+}
+
+func TestNoteService_CacheInvalidatedOnWrite(t *testing.T) {
+	noteRepo := &stubCollaborationNoteRepository{stubNoteRepository{notes: map[int64]*domain.Note{
+		1: {ID: 1, UserID: 7, Title: "Before", Path: "/1/"},
+	}}}
+	service := NewNoteService(noteRepo, nil, &stubCollaboratorRepository{}, nil)
+	service.SetCache(&stubNoteCache{notes: make(map[int64]*domain.Note)})
+	ctx := context.Background()
+
+	_, err := service.GetNote(ctx, 1, 7)
+	require.NoError(t, err)
+
+	noteRepo.notes[1].Title = "After"
+	note, err := service.GetNote(ctx, 1, 7)
+	require.NoError(t, err)
+	assert.Equal(t, "Before", note.Title, "second read is served from the cache")
+
+	_, err = service.AddBlock(ctx, 1, 7, domain.BlockTypeParagraph, &domain.BlockContent{})
+	require.NoError(t, err)
+
+	note, err = service.GetNote(ctx, 1, 7)
+	require.NoError(t, err)
+	assert.Equal(t, "After", note.Title, "writes drop the cached copy")
+}
+
+func TestNoteService_BlockLimit(t *testing.T) {
+	noteRepo := &stubCollaborationNoteRepository{stubNoteRepository{notes: map[int64]*domain.Note{
+		1: {ID: 1, UserID: 7, Title: "Full", Path: "/1/"},
+	}}}
+	service := NewNoteService(noteRepo, nil, &stubCollaboratorRepository{}, nil)
+	service.SetLimits(domain.NewNoteLimits(0, 1))
+	ctx := context.Background()
+
+	note, err := service.AddBlock(ctx, 1, 7, domain.BlockTypeParagraph, &domain.BlockContent{})
+	require.NoError(t, err)
+	noteRepo.notes[1].Blocks = note.Blocks
+
+	_, err = service.AddBlock(ctx, 1, 7, domain.BlockTypeParagraph, &domain.BlockContent{})
+	assert.ErrorIs(t, err, domain.ErrTooManyBlocks)
+
+	_, err = service.ReplaceBlocks(ctx, 1, 7, []domain.Block{
+		{Type: domain.BlockTypeParagraph, Content: &domain.BlockContent{}},
+		{Type: domain.BlockTypeParagraph, Content: &domain.BlockContent{}},
+	})
+	assert.ErrorIs(t, err, domain.ErrTooManyBlocks)
+}
+
+// stubQuotaNoteRepository reports a fixed storage usage
+type stubQuotaNoteRepository struct {
+	stubCollaborationNoteRepository
+	usage ports.NoteUsage
+}
+
+func (r *stubQuotaNoteRepository) UsageByUser(ctx context.Context, userID int64) (*ports.NoteUsage, error) {
+	usage := r.usage
+	return &usage, nil
+}
+
+func TestNoteService_StorageQuota(t *testing.T) {
+	noteRepo := &stubQuotaNoteRepository{
+		stubCollaborationNoteRepository: stubCollaborationNoteRepository{stubNoteRepository{notes: map[int64]*domain.Note{
+			1: {ID: 1, UserID: 7, Title: "Mine", Path: "/1/", Blocks: []domain.Block{
+				{ID: "b1", Type: domain.BlockTypeParagraph, Content: &domain.BlockContent{Code: "some text"}},
+			}},
+		}}},
+		usage: ports.NoteUsage{Notes: 3, BlockBytes: 990},
+	}
+	service := NewNoteService(noteRepo, nil, &stubCollaboratorRepository{}, nil)
+	service.SetQuota(domain.StorageQuota{MaxNotes: 3, MaxBlockBytes: 1000})
+	ctx := context.Background()
+
+	_, err := service.CreateNote(ctx, 7, "One too many", nil)
+	assert.ErrorIs(t, err, domain.ErrQuotaExceeded)
+
+	_, err = service.AddBlock(ctx, 1, 7, domain.BlockTypeParagraph, &domain.BlockContent{Code: "more text"})
+	assert.ErrorIs(t, err, domain.ErrQuotaExceeded)
+
+	// Shrinking a note is allowed even when over quota
+	noteRepo.usage.BlockBytes = 2000
+	_, err = service.ReplaceBlocks(ctx, 1, 7, []domain.Block{})
+	assert.NoError(t, err)
+}
+
+// stubBlockNoteRepository records how block updates are persisted
+type stubBlockNoteRepository struct {
+	stubNoteRepository
+	patchErr  error
+	patched   int
+	rewritten int
+}
+
+func (r *stubBlockNoteRepository) UpdateBlockContent(ctx context.Context, noteID int64, blockID string, content *domain.BlockContent) error {
+	if r.patchErr != nil {
+		return r.patchErr
+	}
+	r.patched++
+	return nil
+}
+
+func (r *stubBlockNoteRepository) UpdateBlockText(ctx context.Context, noteID int64, blockID string, richText []domain.RichTextSegment) error {
+	if r.patchErr != nil {
+		return r.patchErr
+	}
+	r.patched++
+	return nil
+}
+
+func (r *stubBlockNoteRepository) UpdateBlocks(ctx context.Context, noteID int64, blocks []domain.Block) error {
+	r.rewritten++
+	return nil
+}
+
+func newBlockTestService(patchErr error) (*NoteService, *stubBlockNoteRepository) {
+	noteRepo := &stubBlockNoteRepository{
+		stubNoteRepository: stubNoteRepository{notes: map[int64]*domain.Note{
+			1: {ID: 1, UserID: 7, Title: "Doc", Blocks: []domain.Block{
+				{ID: "a", Type: domain.BlockTypeParagraph, Content: &domain.BlockContent{}},
+				{ID: "b", Type: domain.BlockTypeCode, Content: &domain.BlockContent{Code: "x"}},
+			}},
+		}},
+		patchErr: patchErr,
+	}
+	return NewNoteService(noteRepo, nil, &stubCollaboratorRepository{}, nil), noteRepo
+}
+
+func TestNoteService_UpdateBlock_PatchesInPlace(t *testing.T) {
+	service, noteRepo := newBlockTestService(nil)
+
+	_, err := service.UpdateBlock(context.Background(), 1, 7, "a", &domain.BlockContent{Code: "x"})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, noteRepo.patched)
+	assert.Zero(t, noteRepo.rewritten)
+}
+
+func TestNoteService_UpdateBlock_FallsBackToRewrite(t *testing.T) {
+	service, noteRepo := newBlockTestService(domain.ErrBlockNotFound)
+
+	note, err := service.UpdateBlock(context.Background(), 1, 7, "a", &domain.BlockContent{Code: "x"})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, noteRepo.rewritten)
+	assert.Equal(t, "x", note.Blocks[0].Content.Code)
+}
+
+func TestNoteService_UpdateBlockText(t *testing.T) {
+	service, noteRepo := newBlockTestService(nil)
+	checked := true
+	noteRepo.notes[1].Blocks[0].Content.Checked = &checked
+
+	note, err := service.UpdateBlockText(context.Background(), 1, 7, "a", []domain.RichTextSegment{{Text: "hello"}})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, noteRepo.patched)
+	assert.Equal(t, "hello", note.Blocks[0].Content.RichText[0].Text)
+	assert.Same(t, &checked, note.Blocks[0].Content.Checked)
+
+	_, err = service.UpdateBlockText(context.Background(), 1, 7, "b", []domain.RichTextSegment{{Text: "hello"}})
+	assert.ErrorIs(t, err, domain.ErrBlockNotText)
+}
+
+func TestNoteService_UpdateBlockText_FallsBackToRewrite(t *testing.T) {
+	service, noteRepo := newBlockTestService(domain.ErrBlockNotFound)
+
+	note, err := service.UpdateBlockText(context.Background(), 1, 7, "a", []domain.RichTextSegment{{Text: "hello"}})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, noteRepo.rewritten)
+	assert.Equal(t, "hello", note.Blocks[0].Content.RichText[0].Text)
+}
+
+// stubTagNoteRepository serves tags from memory and records bulk tagging
+type stubTagNoteRepository struct {
+	stubNoteRepository
+	tags   map[string]*domain.Tag
+	tagged []int64
+}
+
+func (r *stubTagNoteRepository) FindTag(ctx context.Context, tagID string) (*domain.Tag, error) {
+	tag, ok := r.tags[tagID]
+	if !ok {
+		return nil, domain.ErrTagNotFound
+	}
+	found := *tag // callers modify what they load, as with the real repository
+	return &found, nil
+}
+
+func (r *stubTagNoteRepository) FindTagByName(ctx context.Context, userID int64, name string) (*domain.Tag, error) {
+	for _, tag := range r.tags {
+		if tag.UserID == userID && tag.Name == name {
+			return tag, nil
+		}
+	}
+	return nil, domain.ErrTagNotFound
+}
+
+func (r *stubTagNoteRepository) UpdateTag(ctx context.Context, tag *domain.Tag) error {
+	r.tags[tag.ID] = tag
+	return nil
+}
+
+func (r *stubTagNoteRepository) MergeTags(ctx context.Context, sourceTagID, targetTagID string) error {
+	delete(r.tags, sourceTagID)
+	return nil
+}
+
+func (r *stubTagNoteRepository) BulkAddTag(ctx context.Context, noteIDs []int64, tagID string) error {
+	r.tagged = append(r.tagged, noteIDs...)
+	return nil
+}
+
+func TestNoteService_BulkAddTag(t *testing.T) {
+	noteRepo := &stubTagNoteRepository{
+		stubNoteRepository: stubNoteRepository{notes: map[int64]*domain.Note{
+			1: {ID: 1, UserID: 7, Title: "Mine"},
+			2: {ID: 2, UserID: 8, Title: "Theirs"},
+			3: {ID: 3, UserID: 7, Title: "Also mine"},
+		}},
+		tags: map[string]*domain.Tag{
+			"tag-work":  {ID: "tag-work", UserID: 7},
+			"tag-other": {ID: "tag-other", UserID: 8},
+		},
+	}
+	service := NewNoteService(noteRepo, nil, nil, nil)
+	ctx := context.Background()
+
+	results, err := service.BulkAddTag(ctx, 7, []int64{1, 2, 3, 1, 99}, "tag-work")
+
+	require.NoError(t, err)
+	assert.Equal(t, []TagResult{
+		{NoteID: 1},
+		{NoteID: 2, Err: domain.ErrNoteNotFound},
+		{NoteID: 3},
+		{NoteID: 99, Err: domain.ErrNoteNotFound},
+	}, results)
+	assert.Equal(t, []int64{1, 3}, noteRepo.tagged)
+
+	_, err = service.BulkAddTag(ctx, 7, []int64{1}, "tag-other")
+	assert.ErrorIs(t, err, domain.ErrTagNotFound)
+
+	tooMany := make([]int64, MaxBulkTagNotes+1)
+	for i := range tooMany {
+		tooMany[i] = int64(i + 1)
+	}
+	_, err = service.BulkAddTag(ctx, 7, tooMany, "tag-work")
+	assert.ErrorIs(t, err, domain.ErrTooManyNoteIDs)
+}
+
+func TestNoteService_UpdateTag(t *testing.T) {
+	noteRepo := &stubTagNoteRepository{tags: map[string]*domain.Tag{
+		"tag-work": {ID: "tag-work", UserID: 7, Name: "Work", Color: "blue"},
+		"tag-home": {ID: "tag-home", UserID: 7, Name: "Home", Color: "green"},
+	}}
+	service := NewNoteService(noteRepo, nil, nil, nil)
+	ctx := context.Background()
+	name := func(s string) *string { return &s }
+
+	tag, err := service.UpdateTag(ctx, 7, "tag-work", name("  Office "), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Office", tag.Name)
+	assert.Equal(t, "blue", tag.Color)
+
+	_, err = service.UpdateTag(ctx, 7, "tag-work", name("Home"), nil)
+	assert.ErrorIs(t, err, domain.ErrTagNameTaken)
+
+	_, err = service.UpdateTag(ctx, 7, "tag-work", name(" "), nil)
+	assert.ErrorIs(t, err, domain.ErrInvalidTagName)
+
+	_, err = service.UpdateTag(ctx, 8, "tag-work", name("Mine"), nil)
+	assert.ErrorIs(t, err, domain.ErrTagNotFound)
+}
+
+func TestNoteService_MergeTags(t *testing.T) {
+	noteRepo := &stubTagNoteRepository{tags: map[string]*domain.Tag{
+		"tag-work":   {ID: "tag-work", UserID: 7, Name: "Work"},
+		"tag-wrk":    {ID: "tag-wrk", UserID: 7, Name: "Wrk"},
+		"tag-theirs": {ID: "tag-theirs", UserID: 8, Name: "Work"},
+	}}
+	service := NewNoteService(noteRepo, nil, nil, nil)
+	ctx := context.Background()
+
+	_, err := service.MergeTags(ctx, 7, "tag-work", "tag-work")
+	assert.ErrorIs(t, err, domain.ErrMergeTagIntoItself)
+
+	_, err = service.MergeTags(ctx, 7, "tag-theirs", "tag-work")
+	assert.ErrorIs(t, err, domain.ErrTagNotFound)
+
+	_, err = service.MergeTags(ctx, 7, "tag-wrk", "tag-theirs")
+	assert.ErrorIs(t, err, domain.ErrTagNotFound)
+
+	target, err := service.MergeTags(ctx, 7, "tag-wrk", "tag-work")
+	require.NoError(t, err)
+	assert.Equal(t, "tag-work", target.ID)
+	assert.NotContains(t, noteRepo.tags, "tag-wrk")
+	assert.Contains(t, noteRepo.tags, "tag-theirs")
+}
+
+// stubOrphanNoteRepository reports fixed orphans and records repairs
+type stubOrphanNoteRepository struct {
+	stubNoteRepository
+	orphans  []ports.OrphanNote
+	detached []int64
+	rebuilt  []int64
+}
+
+func (r *stubOrphanNoteRepository) FindOrphans(ctx context.Context) ([]ports.OrphanNote, error) {
+	return r.orphans, nil
+}
+
+func (r *stubOrphanNoteRepository) DetachNotes(ctx context.Context, noteIDs []int64) error {
+	r.detached = append(r.detached, noteIDs...)
+	return nil
+}
+
+func (r *stubOrphanNoteRepository) RebuildPaths(ctx context.Context, userID int64) (*ports.PathRebuildResult, error) {
+	r.rebuilt = append(r.rebuilt, userID)
+	return &ports.PathRebuildResult{}, nil
+}
+
+func TestNoteService_RepairOrphans(t *testing.T) {
+	noteRepo := &stubOrphanNoteRepository{orphans: []ports.OrphanNote{
+		{ID: 2, UserID: 7, ParentID: 1},
+		{ID: 4, UserID: 7, ParentID: 3, ParentTrashed: true},
+		{ID: 6, UserID: 7, ParentID: 5},
+		{ID: 9, UserID: 8, ParentID: 1},
+	}}
+	service := NewNoteService(noteRepo, nil, nil, nil)
+	ctx := context.Background()
+
+	expected := []OrphanRepair{
+		{NoteID: 2, UserID: 7, ParentID: 1, Action: OrphanMovedToRoot},
+		{NoteID: 4, UserID: 7, ParentID: 3, Action: OrphanFlagged},
+		{NoteID: 6, UserID: 7, ParentID: 5, Action: OrphanMovedToRoot},
+		{NoteID: 9, UserID: 8, ParentID: 1, Action: OrphanMovedToRoot},
+	}
+
+	repairs, err := service.RepairOrphans(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, expected, repairs)
+	assert.Empty(t, noteRepo.detached, "a dry run shouldn't change anything")
+
+	repairs, err = service.RepairOrphans(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, expected, repairs)
+	assert.Equal(t, []int64{2, 6, 9}, noteRepo.detached)
+	assert.Equal(t, []int64{7, 8}, noteRepo.rebuilt)
+}
+
+// stubMoveNoteRepository finds descendants by path and records moves
+type stubMoveNoteRepository struct {
+	stubNoteRepository
+	moved []int64
+}
+
+func (r *stubMoveNoteRepository) FindDescendants(ctx context.Context, parentID int64) ([]*domain.Note, error) {
+	parent := r.notes[parentID]
+	var descendants []*domain.Note
+	for _, note := range r.notes {
+		if note.ID != parentID && strings.HasPrefix(note.Path, parent.Path) {
+			descendants = append(descendants, note)
+		}
+	}
+	return descendants, nil
+}
+
+func (r *stubMoveNoteRepository) MoveNote(ctx context.Context, noteID int64, newParentID *int64, newPosition int) error {
+	r.moved = append(r.moved, noteID)
+	return nil
+}
+
+func TestNoteService_MoveNote_DepthFromPaths(t *testing.T) {
+	// The subtree's stored depths are stale from an earlier move; its paths
+	// put the moved note at depth 1 with two levels below it
+	notes := map[int64]*domain.Note{
+		20: {ID: 20, UserID: 7, Path: "/19/20/", Depth: 0},
+		21: {ID: 21, UserID: 7, Path: "/19/20/21/", Depth: 1},
+		22: {ID: 22, UserID: 7, Path: "/19/20/21/22/", Depth: 1},
+	}
+	deep := "/"
+	for id := int64(1); id <= 9; id++ {
+		deep += fmt.Sprintf("%d/", id)
+		notes[id] = &domain.Note{ID: id, UserID: 7, Path: deep, Depth: int(id - 1)}
+	}
+	noteRepo := &stubMoveNoteRepository{stubNoteRepository: stubNoteRepository{notes: notes}}
+	service := NewNoteService(noteRepo, nil, nil, nil)
+	ctx := context.Background()
+
+	// Under depth 7 the deepest descendant lands at the limit
+	parentID := int64(8)
+	require.NoError(t, service.MoveNote(ctx, 20, 7, &parentID, 0))
+
+	// One level deeper would put it past the limit
+	parentID = 9
+	err := service.MoveNote(ctx, 20, 7, &parentID, 0)
+	assert.ErrorIs(t, err, domain.ErrMaxDepthExceeded)
+	assert.Equal(t, []int64{20}, noteRepo.moved)
+}
+
+// stubSubtreeNoteRepository reports a fixed descendant count
+type stubSubtreeNoteRepository struct {
+	stubNoteRepository
+	descendants int64
+}
+
+func (r *stubSubtreeNoteRepository) CountDescendants(ctx context.Context, parentID int64) (int64, error) {
+	return r.descendants, nil
+}
+
+func TestNoteService_GetSubtreeSize(t *testing.T) {
+	noteRepo := &stubSubtreeNoteRepository{
+		stubNoteRepository: stubNoteRepository{notes: map[int64]*domain.Note{
+			1: {ID: 1, UserID: 7, Title: "Parent", Path: "/1/"},
+		}},
+		descendants: 3,
+	}
+	service := NewNoteService(noteRepo, nil, &stubCollaboratorRepository{}, nil)
+	ctx := context.Background()
+
+	count, err := service.GetSubtreeSize(ctx, 1, 7)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	_, err = service.GetSubtreeSize(ctx, 1, 8)
+	assert.Error(t, err, "other users can't see the subtree")
+}
+
+func TestNoteService_ListNotes_RejectsReversedDateRange(t *testing.T) {
+	service := NewNoteService(&stubNoteRepository{}, nil, nil, nil)
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+
+	_, _, err := service.ListNotes(context.Background(), 7, ports.NoteFilters{CreatedAfter: &now, CreatedBefore: &earlier})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+
+	_, _, err = service.ListNotes(context.Background(), 7, ports.NoteFilters{UpdatedAfter: &now, UpdatedBefore: &earlier})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
+
+type stubPropertiesNoteRepository struct {
+	stubNoteRepository
+}
+
+func (r *stubPropertiesNoteRepository) Update(ctx context.Context, note *domain.Note) (*domain.Note, error) {
+	r.notes[note.ID] = note
+	return note, nil
+}
+
+type recordingRescheduler struct {
+	notes []*domain.Note
+}
+
+func (r *recordingRescheduler) RescheduleForNote(ctx context.Context, note *domain.Note) {
+	r.notes = append(r.notes, note)
+}
+
+func TestNoteService_PropertyChangesRescheduleReminders(t *testing.T) {
+	noteRepo := &stubPropertiesNoteRepository{stubNoteRepository{notes: map[int64]*domain.Note{
+		1: {ID: 1, UserID: 7, Title: "Launch"},
+	}}}
+	service := NewNoteService(noteRepo, &stubSharedLinkRepository{}, &stubCollaboratorRepository{}, nil)
+	rescheduler := &recordingRescheduler{}
+	service.SetReminderRescheduler(rescheduler)
+	ctx := context.Background()
+
+	_, err := service.UpdateProperties(ctx, 1, 7, map[string]interface{}{"due": "2030-03-20"}, true)
+	require.NoError(t, err)
+	require.Len(t, rescheduler.notes, 1)
+	assert.Equal(t, "2030-03-20", rescheduler.notes[0].Properties["due"])
+
+	title := "Launch day"
+	_, err = service.PatchNote(ctx, 1, 7, NotePatch{Title: &title})
+	require.NoError(t, err)
+	assert.Len(t, rescheduler.notes, 1, "title changes leave reminders alone")
+
+	_, err = service.PatchNote(ctx, 1, 7, NotePatch{Properties: map[string]interface{}{"due": nil}})
+	require.NoError(t, err)
+	require.Len(t, rescheduler.notes, 2)
+	assert.NotContains(t, rescheduler.notes[1].Properties, "due")
+}
@@ -0,0 +1,24 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Date(2025, time.March, 10, 9, 0, 0, 0, time.UTC)
+	fake := NewFake(start)
+	assert.Equal(t, start, fake.Now())
+
+	fake.Advance(90 * time.Minute)
+	assert.Equal(t, start.Add(90*time.Minute), fake.Now())
+
+	fake.Set(start)
+	assert.Equal(t, start, fake.Now())
+}
+
+func TestReal(t *testing.T) {
+	assert.WithinDuration(t, time.Now(), Real{}.Now(), time.Second)
+}
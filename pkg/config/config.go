@@ -1,7 +1,10 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -14,17 +17,34 @@ type Config struct {
 	Database     DatabaseConfig
 	Redis        RedisConfig
 	JWT          JWTConfig
+	Password     PasswordConfig
 	OAuth        OAuthConfig
 	CORS         CORSConfig
 	RateLimit    RateLimitConfig
 	Notification NotificationConfig
+	Device       DeviceConfig
+	Note         NoteConfig
 	FCM          FCMConfig
+	SMTP         SMTPConfig
 	Log          LogConfig
 }
 
 // FCMConfig holds Firebase Cloud Messaging configuration
 type FCMConfig struct {
 	CredentialsFile string
+	CredentialsJSON string // service account JSON, for when mounting a file is awkward
+}
+
+// SMTPConfig holds SMTP configuration for email notifications
+type SMTPConfig struct {
+	Host        string
+	Port        int
+	Username    string
+	Password    string
+	From        string
+	TLSMode     string // "starttls", "tls" (implicit TLS) or "none"
+	DryRun      bool
+	LinkBaseURL string
 }
 
 // ServerConfig holds server configuration
@@ -33,6 +53,20 @@ type ServerConfig struct {
 	Mode         string
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
+	// IdleTimeout closes keep-alive connections left unused this long;
+	// ReadHeaderTimeout bounds how long a client may take to send headers
+	IdleTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+
+	// MaxBodyBytes limits request body size; MaxBlocksBodyBytes applies to
+	// full block replacement, which carries a whole note's content
+	MaxBodyBytes       int64
+	MaxBlocksBodyBytes int64
+
+	// GzipEnabled compresses responses of at least GzipMinBytes for clients
+	// that accept gzip
+	GzipEnabled  bool
+	GzipMinBytes int
 }
 
 // DatabaseConfig holds database configuration
@@ -46,6 +80,30 @@ type DatabaseConfig struct {
 	MaxOpenConns    int
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
+
+	// LogLevel is the GORM log level (silent, error, warn or info), separate
+	// from the application log level so debug logging doesn't include every query
+	LogLevel string
+	// SlowQueryThreshold is how long a query may run before it is logged as
+	// slow; zero disables slow query logging
+	SlowQueryThreshold time.Duration
+	// PoolMonitorInterval is how often the connection pool is checked for
+	// exhaustion; zero disables the check
+	PoolMonitorInterval time.Duration
+
+	// ReplicaDSN is the connection string of an optional read replica that
+	// note listings, searches and lookups are sent to
+	ReplicaDSN string
+	// ReplicaMaxLag is how long after a write reads of the written notes keep
+	// going to the primary, to allow for replication lag
+	ReplicaMaxLag time.Duration
+
+	// TitleCollation is an optional database collation, such as "en-US-x-icu",
+	// for sorting notes by title; by default titles are compared lowercased
+	TitleCollation string
+
+	// AutoMigrate applies pending migrations at startup
+	AutoMigrate bool
 }
 
 // RedisConfig holds Redis configuration
@@ -55,6 +113,10 @@ type RedisConfig struct {
 	Password string
 	DB       int
 	PoolSize int
+
+	// NoteCacheEnabled turns on the read-through cache for notes
+	NoteCacheEnabled bool
+	NoteCacheTTL     time.Duration
 }
 
 // JWTConfig holds JWT configuration
@@ -62,6 +124,43 @@ type JWTConfig struct {
 	Secret            string
 	Expiration        time.Duration
 	RefreshExpiration time.Duration
+
+	// Keys enables key rotation: key ID -> secret. Tokens are signed with
+	// CurrentKeyID and validated with the key named in their kid header.
+	// When set, Secret is not used.
+	Keys         map[string]string
+	CurrentKeyID string
+
+	// Algorithm is HS256 (shared secret) or RS256 (PEM key pair). With RS256,
+	// CurrentKeyID, if set, is sent as the kid header.
+	Algorithm      string
+	PrivateKeyPath string
+	PublicKeyPath  string
+
+	RefreshCookie RefreshCookieConfig
+}
+
+// RefreshCookieConfig controls handing refresh tokens to browsers in an
+// HttpOnly cookie instead of the JSON body
+type RefreshCookieConfig struct {
+	Enabled  bool
+	Name     string
+	Path     string
+	Domain   string
+	Secure   bool
+	SameSite string // "strict", "lax" or "none"
+}
+
+// SameSiteMode returns the cookie's SameSite attribute
+func (c RefreshCookieConfig) SameSiteMode() http.SameSite {
+	switch strings.ToLower(c.SameSite) {
+	case "lax":
+		return http.SameSiteLaxMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteStrictMode
+	}
 }
 
 // OAuthConfig holds OAuth configuration
@@ -69,6 +168,10 @@ type OAuthConfig struct {
 	Google   OAuthProviderConfig
 	Facebook OAuthProviderConfig
 	State    StateConfig
+
+	// FrontendURL receives browsers at the end of the redirect flow; empty
+	// keeps the callbacks answering with JSON
+	FrontendURL string
 }
 
 // OAuthProviderConfig holds OAuth provider configuration
@@ -81,8 +184,16 @@ type OAuthProviderConfig struct {
 // StateConfig holds OAuth state configuration
 type StateConfig struct {
 	Secret string
+	TTL    time.Duration
 }
 
+// Bounds for OAuth state lifetime: long enough for slow consent screens,
+// short enough that a leaked state can't be replayed much later
+const (
+	MinOAuthStateTTL = time.Minute
+	MaxOAuthStateTTL = time.Hour
+)
+
 // CORSConfig holds CORS configuration
 type CORSConfig struct {
 	AllowedOrigins []string
@@ -99,15 +210,58 @@ type RateLimitConfig struct {
 // NotificationConfig holds notification system configuration
 type NotificationConfig struct {
 	SchedulerInterval time.Duration
+	LookAhead         time.Duration // claim reminders due this far ahead; each is still sent at its exact time
+	BatchSize         int           // most reminders claimed per scheduler tick
 	WorkerCount       int
 	MaxRetries        int
 	RetryBackoff      time.Duration
+	MaxSnoozes        int
+	ScheduleTolerance time.Duration // reminders scheduled this far in the past are accepted, to allow for clock skew
+}
+
+// DeviceConfig holds settings for the background job that reaps unused devices
+type DeviceConfig struct {
+	ReaperInterval time.Duration
+	StaleAfter     time.Duration // unused this long: deactivated, so no more pushes are sent to it
+	DeleteAfter    time.Duration // unused this long and inactive: deleted
+}
+
+// NoteConfig holds note size limits and per-user storage quotas (0 = unlimited)
+type NoteConfig struct {
+	MaxTitleLength     int
+	MaxBlocksPerNote   int
+	QuotaMaxNotes      int
+	QuotaMaxBlockBytes int
+}
+
+// PasswordConfig holds password hashing configuration. Changing Algorithm or
+// raising its cost upgrades existing hashes as their users sign in.
+type PasswordConfig struct {
+	Algorithm         string // bcrypt or argon2id
+	BcryptCost        int
+	Argon2Memory      int // KiB
+	Argon2Iterations  int
+	Argon2Parallelism int
 }
 
 // LogConfig holds logging configuration
 type LogConfig struct {
-	Level  string
-	Format string
+	Level       string
+	Format      string
+	Output      string // stdout, file or both
+	FilePath    string
+	ForceColors bool
+
+	// RedactFields are field keys masked in every log line
+	RedactFields []string
+
+	// AccessLogSkipPaths are request paths left out of the access log
+	AccessLogSkipPaths []string
+
+	// Rotation of the log file; zero disables each limit
+	MaxSizeMB  int
+	MaxBackups int
+	MaxAgeDays int
 }
 
 // Load loads configuration from environment variables
@@ -118,17 +272,33 @@ func Load() (*Config, error) {
 			Mode:         getEnv("GIN_MODE", "debug"),
 			ReadTimeout:  parseDuration(getEnv("SERVER_READ_TIMEOUT", "30s"), 30*time.Second),
 			WriteTimeout: parseDuration(getEnv("SERVER_WRITE_TIMEOUT", "30s"), 30*time.Second),
+
+			IdleTimeout:       parseDuration(getEnv("SERVER_IDLE_TIMEOUT", "120s"), 120*time.Second),
+			ReadHeaderTimeout: parseDuration(getEnv("SERVER_READ_HEADER_TIMEOUT", "10s"), 10*time.Second),
+
+			MaxBodyBytes:       int64(parseInt(getEnv("SERVER_MAX_BODY_BYTES", "1048576"), 1<<20)),
+			MaxBlocksBodyBytes: int64(parseInt(getEnv("SERVER_MAX_BLOCKS_BODY_BYTES", "8388608"), 8<<20)),
+
+			GzipEnabled:  parseBool(getEnv("SERVER_GZIP_ENABLED", "true"), true),
+			GzipMinBytes: parseInt(getEnv("SERVER_GZIP_MIN_BYTES", "1024"), 1024),
 		},
 		Database: DatabaseConfig{
-			Host:            getEnv("DB_HOST", "localhost"),
-			Port:            getEnv("DB_PORT", "5432"),
-			Name:            getEnv("DB_NAME", "notinoteapp"),
-			User:            getEnv("DB_USER", "postgres"),
-			Password:        getEnv("DB_PASSWORD", ""),
-			SSLMode:         getEnv("DB_SSL_MODE", "disable"),
-			MaxOpenConns:    parseInt(getEnv("DB_MAX_OPEN_CONNS", "25"), 25),
-			MaxIdleConns:    parseInt(getEnv("DB_MAX_IDLE_CONNS", "5"), 5),
-			ConnMaxLifetime: parseDuration(getEnv("DB_CONN_MAX_LIFETIME", "5m"), 5*time.Minute),
+			Host:                getEnv("DB_HOST", "localhost"),
+			Port:                getEnv("DB_PORT", "5432"),
+			Name:                getEnv("DB_NAME", "notinoteapp"),
+			User:                getEnv("DB_USER", "postgres"),
+			Password:            getEnv("DB_PASSWORD", ""),
+			SSLMode:             getEnv("DB_SSL_MODE", "disable"),
+			MaxOpenConns:        parseInt(getEnv("DB_MAX_OPEN_CONNS", "25"), 25),
+			MaxIdleConns:        parseInt(getEnv("DB_MAX_IDLE_CONNS", "5"), 5),
+			ConnMaxLifetime:     parseDuration(getEnv("DB_CONN_MAX_LIFETIME", "5m"), 5*time.Minute),
+			LogLevel:            strings.ToLower(getEnv("DB_LOG_LEVEL", "warn")),
+			SlowQueryThreshold:  parseDuration(getEnv("DB_SLOW_QUERY_THRESHOLD", "200ms"), 200*time.Millisecond),
+			PoolMonitorInterval: parseDuration(getEnv("DB_POOL_MONITOR_INTERVAL", "1m"), time.Minute),
+			ReplicaDSN:          getEnv("DB_REPLICA_DSN", ""),
+			ReplicaMaxLag:       parseDuration(getEnv("DB_REPLICA_MAX_LAG", "5s"), 5*time.Second),
+			TitleCollation:      getEnv("DB_TITLE_COLLATION", ""),
+			AutoMigrate:         parseBool(getEnv("DB_AUTO_MIGRATE", "false"), false),
 		},
 		Redis: RedisConfig{
 			Host:     getEnv("REDIS_HOST", "localhost"),
@@ -136,11 +306,34 @@ func Load() (*Config, error) {
 			Password: getEnv("REDIS_PASSWORD", ""),
 			DB:       parseInt(getEnv("REDIS_DB", "0"), 0),
 			PoolSize: parseInt(getEnv("REDIS_POOL_SIZE", "10"), 10),
+
+			NoteCacheEnabled: parseBool(getEnv("REDIS_NOTE_CACHE_ENABLED", "false"), false),
+			NoteCacheTTL:     parseDuration(getEnv("REDIS_NOTE_CACHE_TTL", "5m"), 5*time.Minute),
 		},
 		JWT: JWTConfig{
 			Secret:            getEnv("JWT_SECRET", "change_this_secret_key"),
 			Expiration:        parseDuration(getEnv("JWT_EXPIRATION", "24h"), 24*time.Hour),
 			RefreshExpiration: parseDuration(getEnv("JWT_REFRESH_EXPIRATION", "168h"), 168*time.Hour),
+			Keys:              parseKeyValues(getEnv("JWT_KEYS", "")),
+			CurrentKeyID:      getEnv("JWT_CURRENT_KEY_ID", ""),
+			Algorithm:         strings.ToUpper(getEnv("JWT_ALGORITHM", "HS256")),
+			PrivateKeyPath:    getEnv("JWT_PRIVATE_KEY_PATH", ""),
+			PublicKeyPath:     getEnv("JWT_PUBLIC_KEY_PATH", ""),
+			RefreshCookie: RefreshCookieConfig{
+				Enabled:  parseBool(getEnv("AUTH_REFRESH_COOKIE_ENABLED", "false"), false),
+				Name:     getEnv("AUTH_REFRESH_COOKIE_NAME", "refresh_token"),
+				Path:     getEnv("AUTH_REFRESH_COOKIE_PATH", "/api/v1/auth"),
+				Domain:   getEnv("AUTH_REFRESH_COOKIE_DOMAIN", ""),
+				Secure:   parseBool(getEnv("AUTH_REFRESH_COOKIE_SECURE", "true"), true),
+				SameSite: strings.ToLower(getEnv("AUTH_REFRESH_COOKIE_SAMESITE", "strict")),
+			},
+		},
+		Password: PasswordConfig{
+			Algorithm:         getEnv("PASSWORD_ALGORITHM", "bcrypt"),
+			BcryptCost:        parseInt(getEnv("PASSWORD_BCRYPT_COST", "10"), 10),
+			Argon2Memory:      parseInt(getEnv("PASSWORD_ARGON2_MEMORY", "65536"), 65536),
+			Argon2Iterations:  parseInt(getEnv("PASSWORD_ARGON2_ITERATIONS", "3"), 3),
+			Argon2Parallelism: parseInt(getEnv("PASSWORD_ARGON2_PARALLELISM", "4"), 4),
 		},
 		OAuth: OAuthConfig{
 			Google: OAuthProviderConfig{
@@ -155,11 +348,13 @@ func Load() (*Config, error) {
 			},
 			State: StateConfig{
 				Secret: getEnv("OAUTH_STATE_SECRET", "change_this_state_secret"),
+				TTL:    parseDuration(getEnv("OAUTH_STATE_TTL", "600s"), 10*time.Minute),
 			},
+			FrontendURL: getEnv("FRONTEND_URL", ""),
 		},
 		CORS: CORSConfig{
 			AllowedOrigins: parseStringSlice(getEnv("CORS_ALLOWED_ORIGINS", "http://localhost:3000,http://localhost:8080")),
-			AllowedMethods: parseStringSlice(getEnv("CORS_ALLOWED_METHODS", "GET,POST,PUT,DELETE,OPTIONS")),
+			AllowedMethods: parseStringSlice(getEnv("CORS_ALLOWED_METHODS", "GET,POST,PUT,PATCH,DELETE,OPTIONS")),
 			AllowedHeaders: parseStringSlice(getEnv("CORS_ALLOWED_HEADERS", "Authorization,Content-Type")),
 		},
 		RateLimit: RateLimitConfig{
@@ -168,16 +363,53 @@ func Load() (*Config, error) {
 		},
 		Notification: NotificationConfig{
 			SchedulerInterval: parseDuration(getEnv("NOTIFICATION_SCHEDULER_INTERVAL", "30s"), 30*time.Second),
+			LookAhead:         parseDuration(getEnv("NOTIFICATION_LOOK_AHEAD", "0s"), 0),
+			BatchSize:         parseInt(getEnv("NOTIFICATION_BATCH_SIZE", "100"), 100),
 			WorkerCount:       parseInt(getEnv("NOTIFICATION_WORKER_COUNT", "5"), 5),
 			MaxRetries:        parseInt(getEnv("NOTIFICATION_MAX_RETRIES", "3"), 3),
 			RetryBackoff:      parseDuration(getEnv("NOTIFICATION_RETRY_BACKOFF", "1m"), 1*time.Minute),
+			MaxSnoozes:        parseInt(getEnv("NOTIFICATION_MAX_SNOOZES", "5"), 5),
+			ScheduleTolerance: parseDuration(getEnv("NOTIFICATION_SCHEDULE_TOLERANCE", "60s"), 60*time.Second),
+		},
+		Device: DeviceConfig{
+			ReaperInterval: parseDuration(getEnv("DEVICE_REAPER_INTERVAL", "1h"), time.Hour),
+			StaleAfter:     parseDuration(getEnv("DEVICE_STALE_AFTER", "720h"), 30*24*time.Hour),
+			DeleteAfter:    parseDuration(getEnv("DEVICE_DELETE_AFTER", "2160h"), 90*24*time.Hour),
+		},
+		Note: NoteConfig{
+			MaxTitleLength:     parseInt(getEnv("NOTE_MAX_TITLE_LENGTH", "500"), 500),
+			MaxBlocksPerNote:   parseInt(getEnv("NOTE_MAX_BLOCKS_PER_NOTE", "1000"), 1000),
+			QuotaMaxNotes:      parseInt(getEnv("NOTE_QUOTA_MAX_NOTES", "0"), 0),
+			QuotaMaxBlockBytes: parseInt(getEnv("NOTE_QUOTA_MAX_BLOCK_BYTES", "0"), 0),
 		},
 		FCM: FCMConfig{
 			CredentialsFile: getEnv("FCM_CREDENTIALS_FILE", ""),
+			CredentialsJSON: getEnv("FCM_CREDENTIALS_JSON", ""),
+		},
+		SMTP: SMTPConfig{
+			Host:        getEnv("SMTP_HOST", ""),
+			Port:        parseInt(getEnv("SMTP_PORT", "587"), 587),
+			Username:    getEnv("SMTP_USERNAME", ""),
+			Password:    getEnv("SMTP_PASSWORD", ""),
+			From:        getEnv("SMTP_FROM", "NotiNote <no-reply@localhost>"),
+			TLSMode:     getEnv("SMTP_TLS_MODE", "starttls"),
+			DryRun:      parseBool(getEnv("SMTP_DRY_RUN", "false"), false),
+			LinkBaseURL: getEnv("SMTP_LINK_BASE_URL", "http://localhost:3000"),
 		},
 		Log: LogConfig{
-			Level:  getEnv("LOG_LEVEL", "info"),
-			Format: getEnv("LOG_FORMAT", "json"),
+			Level:       getEnv("LOG_LEVEL", "info"),
+			Format:      getEnv("LOG_FORMAT", "json"),
+			Output:      getEnv("LOG_OUTPUT", "stdout"),
+			FilePath:    getEnv("LOG_FILE_PATH", "logs/notinoteapp.log"),
+			ForceColors: parseBool(getEnv("LOG_FORCE_COLORS", "false"), false),
+
+			RedactFields: parseStringSlice(getEnv("LOG_REDACT_FIELDS", "password,token,authorization,refresh_token")),
+
+			AccessLogSkipPaths: parseStringSlice(getEnv("LOG_ACCESS_SKIP_PATHS", "/health,/metrics")),
+
+			MaxSizeMB:  parseInt(getEnv("LOG_FILE_MAX_SIZE_MB", "100"), 100),
+			MaxBackups: parseInt(getEnv("LOG_FILE_MAX_BACKUPS", "5"), 5),
+			MaxAgeDays: parseInt(getEnv("LOG_FILE_MAX_AGE_DAYS", "30"), 30),
 		},
 	}
 
@@ -191,12 +423,129 @@ func Load() (*Config, error) {
 
 // Validate validates the configuration
 func (c *Config) Validate() error {
-	if c.JWT.Secret == "change_this_secret_key" {
-		return fmt.Errorf("JWT_SECRET must be set to a secure value")
+	if c.Server.ReadTimeout <= 0 || c.Server.WriteTimeout <= 0 || c.Server.IdleTimeout <= 0 || c.Server.ReadHeaderTimeout <= 0 {
+		return fmt.Errorf("SERVER_READ_TIMEOUT, SERVER_WRITE_TIMEOUT, SERVER_IDLE_TIMEOUT and SERVER_READ_HEADER_TIMEOUT must be positive")
+	}
+	if c.Server.ReadHeaderTimeout > c.Server.ReadTimeout {
+		return fmt.Errorf("SERVER_READ_HEADER_TIMEOUT (%s) must not exceed SERVER_READ_TIMEOUT (%s)", c.Server.ReadHeaderTimeout, c.Server.ReadTimeout)
+	}
+	switch c.JWT.Algorithm {
+	case "RS256":
+		if c.JWT.PrivateKeyPath == "" {
+			return fmt.Errorf("JWT_PRIVATE_KEY_PATH must be set when JWT_ALGORITHM is RS256")
+		}
+	case "HS256":
+		if len(c.JWT.Keys) > 0 {
+			if _, ok := c.JWT.Keys[c.JWT.CurrentKeyID]; !ok {
+				return fmt.Errorf("JWT_CURRENT_KEY_ID must name one of the keys in JWT_KEYS")
+			}
+		} else if c.JWT.Secret == "change_this_secret_key" {
+			return fmt.Errorf("JWT_SECRET must be set to a secure value")
+		}
+	default:
+		return fmt.Errorf("JWT_ALGORITHM must be HS256 or RS256, got %q", c.JWT.Algorithm)
 	}
 	if c.Database.Password == "" {
 		return fmt.Errorf("DB_PASSWORD must be set")
 	}
+	switch c.Database.LogLevel {
+	case "silent", "error", "warn", "info":
+	default:
+		return fmt.Errorf("DB_LOG_LEVEL must be silent, error, warn or info, got %q", c.Database.LogLevel)
+	}
+	if c.Database.SlowQueryThreshold < 0 {
+		return fmt.Errorf("DB_SLOW_QUERY_THRESHOLD must not be negative, got %s", c.Database.SlowQueryThreshold)
+	}
+	if c.Database.MaxOpenConns < 0 || c.Database.MaxIdleConns < 0 {
+		return fmt.Errorf("DB_MAX_OPEN_CONNS and DB_MAX_IDLE_CONNS must not be negative")
+	}
+	// 0 open connections means no limit
+	if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		return fmt.Errorf("DB_MAX_IDLE_CONNS (%d) must not exceed DB_MAX_OPEN_CONNS (%d)", c.Database.MaxIdleConns, c.Database.MaxOpenConns)
+	}
+	if c.Database.PoolMonitorInterval < 0 {
+		return fmt.Errorf("DB_POOL_MONITOR_INTERVAL must not be negative, got %s", c.Database.PoolMonitorInterval)
+	}
+	if c.Database.ReplicaDSN != "" && c.Database.ReplicaMaxLag <= 0 {
+		return fmt.Errorf("DB_REPLICA_MAX_LAG must be positive when DB_REPLICA_DSN is set, got %s", c.Database.ReplicaMaxLag)
+	}
+	if strings.ContainsAny(c.Database.TitleCollation, "\"\x00") {
+		return fmt.Errorf("DB_TITLE_COLLATION must be a collation name, got %q", c.Database.TitleCollation)
+	}
+	switch c.Password.Algorithm {
+	case "bcrypt":
+		if c.Password.BcryptCost < 10 || c.Password.BcryptCost > 31 {
+			return fmt.Errorf("PASSWORD_BCRYPT_COST must be between 10 and 31, got %d", c.Password.BcryptCost)
+		}
+	case "argon2id":
+		if c.Password.Argon2Memory < 19*1024 || c.Password.Argon2Memory > 4*1024*1024 {
+			return fmt.Errorf("PASSWORD_ARGON2_MEMORY must be between 19456 and 4194304 KiB, got %d", c.Password.Argon2Memory)
+		}
+		if c.Password.Argon2Iterations < 1 || c.Password.Argon2Iterations > 100 {
+			return fmt.Errorf("PASSWORD_ARGON2_ITERATIONS must be between 1 and 100, got %d", c.Password.Argon2Iterations)
+		}
+		if c.Password.Argon2Parallelism < 1 || c.Password.Argon2Parallelism > 255 {
+			return fmt.Errorf("PASSWORD_ARGON2_PARALLELISM must be between 1 and 255, got %d", c.Password.Argon2Parallelism)
+		}
+	default:
+		return fmt.Errorf("PASSWORD_ALGORITHM must be bcrypt or argon2id, got %q", c.Password.Algorithm)
+	}
+	if c.OAuth.State.TTL < MinOAuthStateTTL || c.OAuth.State.TTL > MaxOAuthStateTTL {
+		return fmt.Errorf("OAUTH_STATE_TTL must be between %s and %s, got %s", MinOAuthStateTTL, MaxOAuthStateTTL, c.OAuth.State.TTL)
+	}
+	if c.JWT.RefreshCookie.Enabled {
+		switch c.JWT.RefreshCookie.SameSite {
+		case "strict", "lax":
+		case "none":
+			// Browsers drop SameSite=None cookies that aren't Secure
+			if !c.JWT.RefreshCookie.Secure {
+				return fmt.Errorf("AUTH_REFRESH_COOKIE_SECURE must be true when AUTH_REFRESH_COOKIE_SAMESITE is none")
+			}
+		default:
+			return fmt.Errorf("AUTH_REFRESH_COOKIE_SAMESITE must be strict, lax or none, got %q", c.JWT.RefreshCookie.SameSite)
+		}
+	}
+	if c.OAuth.FrontendURL != "" {
+		u, err := url.Parse(c.OAuth.FrontendURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("FRONTEND_URL must be an absolute http(s) URL, got %q", c.OAuth.FrontendURL)
+		}
+	}
+	if c.Notification.LookAhead < 0 || c.Notification.LookAhead >= c.Notification.SchedulerInterval {
+		return fmt.Errorf("NOTIFICATION_LOOK_AHEAD must be at least 0 and less than NOTIFICATION_SCHEDULER_INTERVAL (%s), got %s", c.Notification.SchedulerInterval, c.Notification.LookAhead)
+	}
+	if c.Notification.BatchSize < 1 {
+		return fmt.Errorf("NOTIFICATION_BATCH_SIZE must be at least 1, got %d", c.Notification.BatchSize)
+	}
+	if c.Notification.ScheduleTolerance < 0 {
+		return fmt.Errorf("NOTIFICATION_SCHEDULE_TOLERANCE must not be negative, got %s", c.Notification.ScheduleTolerance)
+	}
+	if c.Device.ReaperInterval <= 0 || c.Device.StaleAfter <= 0 {
+		return fmt.Errorf("DEVICE_REAPER_INTERVAL and DEVICE_STALE_AFTER must be positive")
+	}
+	if c.Device.DeleteAfter <= c.Device.StaleAfter {
+		return fmt.Errorf("DEVICE_DELETE_AFTER (%s) must be longer than DEVICE_STALE_AFTER (%s)", c.Device.DeleteAfter, c.Device.StaleAfter)
+	}
+	if c.FCM.CredentialsJSON != "" {
+		if c.FCM.CredentialsFile != "" {
+			return fmt.Errorf("set only one of FCM_CREDENTIALS_FILE and FCM_CREDENTIALS_JSON")
+		}
+		if !json.Valid([]byte(c.FCM.CredentialsJSON)) {
+			return fmt.Errorf("FCM_CREDENTIALS_JSON must be valid JSON")
+		}
+	}
+	switch c.Log.Output {
+	case "stdout":
+	case "file", "both":
+		if c.Log.FilePath == "" {
+			return fmt.Errorf("LOG_FILE_PATH must be set when LOG_OUTPUT is %s", c.Log.Output)
+		}
+		if c.Log.MaxSizeMB < 0 || c.Log.MaxBackups < 0 || c.Log.MaxAgeDays < 0 {
+			return fmt.Errorf("LOG_FILE_MAX_SIZE_MB, LOG_FILE_MAX_BACKUPS and LOG_FILE_MAX_AGE_DAYS must not be negative")
+		}
+	default:
+		return fmt.Errorf("LOG_OUTPUT must be stdout, file or both, got %q", c.Log.Output)
+	}
 	return nil
 }
 
@@ -216,6 +565,13 @@ func parseInt(s string, defaultValue int) int {
 	return defaultValue
 }
 
+func parseBool(s string, defaultValue bool) bool {
+	if v, err := strconv.ParseBool(s); err == nil {
+		return v
+	}
+	return defaultValue
+}
+
 func parseDuration(s string, defaultValue time.Duration) time.Duration {
 	if d, err := time.ParseDuration(s); err == nil {
 		return d
@@ -236,3 +592,17 @@ func parseStringSlice(s string) []string {
 	}
 	return result
 }
+
+// parseKeyValues parses a comma-separated list of id:value pairs. The id may be
+// empty; everything after the first colon is the value.
+func parseKeyValues(s string) map[string]string {
+	result := make(map[string]string)
+	for _, pair := range parseStringSlice(s) {
+		id, value, ok := strings.Cut(pair, ":")
+		if !ok || value == "" {
+			continue
+		}
+		result[strings.TrimSpace(id)] = value
+	}
+	return result
+}
@@ -0,0 +1,84 @@
+// Package i18n holds the translations for the fixed text the app sends to
+// users, such as the wording around reminder notifications. Users' own
+// content is never translated.
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Supported languages, as ISO 639-1 codes
+const (
+	English = "en"
+	Thai    = "th"
+)
+
+// Default is used for users without a language and for missing translations
+const Default = English
+
+// Message keys
+const (
+	// ReminderSubject wraps a reminder's title in email subjects; takes the title
+	ReminderSubject = "reminder.subject"
+	// ReminderDefaultBody is the notification body for reminders without a message
+	ReminderDefaultBody = "reminder.default_body"
+	// EmailOpenNote labels the link to the note in emails
+	EmailOpenNote = "email.open_note"
+	// EmailFooter explains why the email was sent
+	EmailFooter = "email.footer"
+)
+
+var catalog = map[string]map[string]string{
+	English: {
+		ReminderSubject:     "Reminder: %s",
+		ReminderDefaultBody: "You have a reminder for this note",
+		EmailOpenNote:       "Open note",
+		EmailFooter:         "You are receiving this because email notifications are enabled for your NotiNote account.",
+	},
+	Thai: {
+		ReminderSubject:     "เตือนความจำ: %s",
+		ReminderDefaultBody: "คุณมีการเตือนความจำสำหรับโน้ตนี้",
+		EmailOpenNote:       "เปิดโน้ต",
+		EmailFooter:         "คุณได้รับอีเมลนี้เนื่องจากเปิดการแจ้งเตือนทางอีเมลไว้ในบัญชี NotiNote ของคุณ",
+	},
+}
+
+// Languages returns the supported language codes, sorted
+func Languages() []string {
+	languages := make([]string, 0, len(catalog))
+	for language := range catalog {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Match returns the supported language for a language tag such as "th" or
+// "th-TH", and whether there is one
+func Match(tag string) (string, bool) {
+	base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	base, _, _ = strings.Cut(base, "_")
+	if _, ok := catalog[base]; !ok {
+		return "", false
+	}
+	return base, true
+}
+
+// T returns the message for key in language, formatted with args if any.
+// Unsupported languages and missing translations fall back to Default, and
+// unknown keys to the key itself.
+func T(language, key string, args ...interface{}) string {
+	message, ok := catalog[language][key]
+	if !ok {
+		message, ok = catalog[Default][key]
+	}
+	if !ok {
+		message = key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+	return message
+}
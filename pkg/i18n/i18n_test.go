@@ -0,0 +1,36 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestT(t *testing.T) {
+	assert.Equal(t, "Reminder: Stand-up", T(English, ReminderSubject, "Stand-up"))
+	assert.Equal(t, "เตือนความจำ: Stand-up", T(Thai, ReminderSubject, "Stand-up"))
+	assert.Equal(t, "Open note", T("", EmailOpenNote), "no language falls back to the default")
+	assert.Equal(t, "Open note", T("fr", EmailOpenNote), "unsupported languages fall back to the default")
+	assert.Equal(t, "missing.key", T(Thai, "missing.key"))
+}
+
+func TestCatalog_Complete(t *testing.T) {
+	for language, messages := range catalog {
+		for key := range catalog[Default] {
+			assert.Contains(t, messages, key, "%s is missing %s", language, key)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	for tag, expected := range map[string]string{"th": Thai, "TH": Thai, "th-TH": Thai, "en_US": English, " en ": English} {
+		language, ok := Match(tag)
+		assert.True(t, ok, tag)
+		assert.Equal(t, expected, language, tag)
+	}
+
+	for _, tag := range []string{"", "fr", "english"} {
+		_, ok := Match(tag)
+		assert.False(t, ok, tag)
+	}
+}
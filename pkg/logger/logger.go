@@ -5,18 +5,57 @@ import (
 	"io"
 	"os"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
+	"golang.org/x/term"
 )
 
-var log *logrus.Logger
+var (
+	log     *logrus.Logger
+	logFile io.Closer
+)
 
-// CustomTextFormatter provides colorful, human-readable log formatting
+// Log output destinations
+const (
+	OutputStdout = "stdout"
+	OutputFile   = "file"
+	OutputBoth   = "both"
+)
+
+// Options configures the logger
+type Options struct {
+	Level    string
+	Format   string
+	Output   string // stdout (default), file or both
+	FilePath string // required when Output is file or both
+
+	// ForceColors colors text output even when stdout isn't detected as a
+	// terminal. It never applies to file output.
+	ForceColors bool
+
+	// RedactFields are field keys whose values are logged as ***, even when
+	// nested. Nil uses DefaultRedactFields; an empty slice disables redaction.
+	RedactFields []string
+
+	// File rotation; zero disables each limit
+	MaxSizeMB  int
+	MaxBackups int
+	MaxAgeDays int
+}
+
+// CustomTextFormatter provides colorful, human-readable log formatting.
+// Colors are used when the logger writes to a terminal, unless DisableColors
+// is set; ForceColors uses them regardless.
 type CustomTextFormatter struct {
 	TimestampFormat string
 	ForceColors     bool
 	DisableColors   bool
 	FullTimestamp   bool
+
+	terminalOnce sync.Once
+	terminal     bool
 }
 
 // ANSI color codes
@@ -43,7 +82,7 @@ func (f *CustomTextFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 	var b strings.Builder
 
 	// Check if we should use colors
-	useColors := f.ForceColors || (!f.DisableColors && isTerminal())
+	useColors := f.ForceColors || (!f.DisableColors && f.isTerminal(entry))
 
 	// Timestamp
 	if f.FullTimestamp {
@@ -221,33 +260,61 @@ func extractFileName(fullPath string) string {
 	return fullPath
 }
 
-// isTerminal checks if the output is a terminal
-func isTerminal() bool {
-	// On Windows, always enable colors for modern terminals (Windows Terminal, VS Code, etc.)
-	// The os.ModeCharDevice check doesn't work reliably on Windows
-	return true
+// isTerminal checks once whether the entry's logger writes to a terminal
+func (f *CustomTextFormatter) isTerminal(entry *logrus.Entry) bool {
+	f.terminalOnce.Do(func() {
+		if entry.Logger != nil {
+			f.terminal = isTerminal(entry.Logger.Out)
+		}
+	})
+	return f.terminal
 }
 
-// Init initializes the logger
+// isTerminal checks if the output is a terminal. Consoles that can't render
+// ANSI codes (older Windows ones) may still report true, and some that can
+// aren't detected; LOG_FORCE_COLORS covers the latter.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// Init initializes the logger, writing to stdout
 func Init(level, format string) *logrus.Logger {
+	// Stdout output can't fail to open
+	l, _ := InitWithOptions(Options{Level: level, Format: format})
+	return l
+}
+
+// InitWithOptions initializes the logger, opening the log file if the output
+// includes one. Call Close on shutdown to release it.
+func InitWithOptions(opts Options) (*logrus.Logger, error) {
+	output, file, err := openOutput(opts)
+	if err != nil {
+		return nil, err
+	}
+	toFile := file != nil
+
+	Close()
+	logFile = file
 	log = logrus.New()
 
 	// Set output
-	log.SetOutput(os.Stdout)
+	log.SetOutput(output)
 
 	// Enable caller information for better debugging
 	log.SetReportCaller(true)
 
 	// Set log level
-	logLevel, err := logrus.ParseLevel(level)
+	logLevel, err := logrus.ParseLevel(opts.Level)
 	if err != nil {
 		logLevel = logrus.InfoLevel
 	}
 	log.SetLevel(logLevel)
 
 	// Set formatter
-	if format == "json" {
-		log.SetFormatter(&logrus.JSONFormatter{
+	var formatter logrus.Formatter
+	if opts.Format == "json" {
+		formatter = &logrus.JSONFormatter{
 			TimestampFormat: "2006-01-02T15:04:05.000Z07:00",
 			FieldMap: logrus.FieldMap{
 				logrus.FieldKeyTime:  "timestamp",
@@ -255,17 +322,65 @@ func Init(level, format string) *logrus.Logger {
 				logrus.FieldKeyMsg:   "message",
 				logrus.FieldKeyFunc:  "caller",
 			},
-		})
+		}
 	} else {
-		// Use custom colorful formatter for text output
-		log.SetFormatter(&CustomTextFormatter{
+		// Use custom colorful formatter for text output. A file (even alongside
+		// stdout) shares the formatter, so it would fill with escape codes.
+		formatter = &CustomTextFormatter{
 			FullTimestamp:   true,
 			TimestampFormat: "2006-01-02 15:04:05",
-			ForceColors:     isTerminal(), // Enable colors for all terminals
-		})
+			ForceColors:     opts.ForceColors && !toFile,
+			DisableColors:   toFile,
+		}
 	}
 
-	return log
+	redactFields := opts.RedactFields
+	if redactFields == nil {
+		redactFields = DefaultRedactFields
+	}
+	log.SetFormatter(NewRedactingFormatter(formatter, redactFields))
+
+	return log, nil
+}
+
+// openOutput returns the writer for the configured destination, and the log
+// file it writes to, if any
+func openOutput(opts Options) (io.Writer, io.WriteCloser, error) {
+	switch opts.Output {
+	case "", OutputStdout:
+		return os.Stdout, nil, nil
+	case OutputFile, OutputBoth:
+	default:
+		return nil, nil, fmt.Errorf("unknown log output %q", opts.Output)
+	}
+
+	if opts.FilePath == "" {
+		return nil, nil, fmt.Errorf("log output %q requires a file path", opts.Output)
+	}
+	file, err := NewRotatingFile(
+		opts.FilePath,
+		int64(opts.MaxSizeMB)*1024*1024,
+		opts.MaxBackups,
+		time.Duration(opts.MaxAgeDays)*24*time.Hour,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if opts.Output == OutputBoth {
+		return io.MultiWriter(os.Stdout, file), file, nil
+	}
+	return file, file, nil
+}
+
+// Close closes the log file, if any
+func Close() error {
+	if logFile == nil {
+		return nil
+	}
+	err := logFile.Close()
+	logFile = nil
+	return err
 }
 
 // Get returns the logger instance
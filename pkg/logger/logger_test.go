@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitWithOptions_FileOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "app.log")
+
+	_, err := InitWithOptions(Options{Level: "info", Format: "text", Output: OutputFile, FilePath: path})
+	require.NoError(t, err)
+	t.Cleanup(func() { Close() })
+
+	WithField("user_id", 7).Info("hello file")
+	require.NoError(t, Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "[INFO]")
+	assert.Contains(t, string(data), "hello file {user_id=7}")
+	assert.NotContains(t, string(data), "\033[", "file output should not be colored")
+}
+
+func TestInitWithOptions_InvalidOutput(t *testing.T) {
+	_, err := InitWithOptions(Options{Output: "syslog"})
+	assert.Error(t, err)
+
+	_, err = InitWithOptions(Options{Output: OutputBoth})
+	assert.Error(t, err)
+}
+
+func TestCustomTextFormatter_ColorsOnlyForTerminals(t *testing.T) {
+	var out bytes.Buffer
+	l := logrus.New()
+	l.SetOutput(&out)
+
+	l.SetFormatter(&CustomTextFormatter{})
+	l.Info("piped")
+	assert.NotContains(t, out.String(), "\033[", "non-terminal output should not be colored")
+
+	out.Reset()
+	l.SetFormatter(&CustomTextFormatter{ForceColors: true})
+	l.Info("forced")
+	assert.Contains(t, out.String(), "\033[")
+}
@@ -0,0 +1,163 @@
+package logger
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// redactedValue replaces the value of every sensitive field
+const redactedValue = "***"
+
+// maxRedactDepth bounds how far nested values are searched, so cyclic
+// structures can't recurse forever
+const maxRedactDepth = 8
+
+// DefaultRedactFields are the field keys masked when no others are configured
+var DefaultRedactFields = []string{"password", "token", "authorization", "refresh_token"}
+
+// RedactingFormatter masks sensitive fields before handing entries to the
+// wrapped formatter. Keys match case-insensitively, at the top level and inside
+// nested maps, structs (by JSON name or field name) and slices.
+type RedactingFormatter struct {
+	Formatter logrus.Formatter
+	keys      map[string]struct{}
+}
+
+// NewRedactingFormatter wraps formatter, masking the given field keys
+func NewRedactingFormatter(formatter logrus.Formatter, keys []string) *RedactingFormatter {
+	set := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		if key = strings.TrimSpace(key); key != "" {
+			set[strings.ToLower(key)] = struct{}{}
+		}
+	}
+	return &RedactingFormatter{Formatter: formatter, keys: set}
+}
+
+// Format renders a copy of the entry with sensitive fields masked
+func (f *RedactingFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	if len(entry.Data) == 0 || len(f.keys) == 0 {
+		return f.Formatter.Format(entry)
+	}
+
+	data := make(logrus.Fields, len(entry.Data))
+	for k, v := range entry.Data {
+		if f.sensitive(k) {
+			data[k] = redactedValue
+			continue
+		}
+		data[k] = f.redact(v)
+	}
+
+	redacted := *entry
+	redacted.Data = data
+	return f.Formatter.Format(&redacted)
+}
+
+func (f *RedactingFormatter) sensitive(key string) bool {
+	_, ok := f.keys[strings.ToLower(key)]
+	return ok
+}
+
+// redact returns v with sensitive nested fields masked. Values with nothing to
+// mask are returned unchanged, so they keep their type and formatting.
+func (f *RedactingFormatter) redact(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	if redacted, changed := f.redactValue(reflect.ValueOf(v), 0); changed {
+		return redacted
+	}
+	return v
+}
+
+func (f *RedactingFormatter) redactValue(v reflect.Value, depth int) (interface{}, bool) {
+	if depth > maxRedactDepth {
+		return nil, false
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil, false
+		}
+		return f.redactValue(v.Elem(), depth+1)
+
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		out := make(map[string]interface{}, v.Len())
+		changed := false
+		iter := v.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			if f.sensitive(key) {
+				out[key] = redactedValue
+				changed = true
+				continue
+			}
+			out[key] = f.nested(iter.Value(), depth, &changed)
+		}
+		return out, changed
+
+	case reflect.Struct:
+		t := v.Type()
+		out := make(map[string]interface{}, t.NumField())
+		changed := false
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := fieldName(field)
+			if name == "-" {
+				continue
+			}
+			if f.sensitive(name) || f.sensitive(field.Name) {
+				out[name] = redactedValue
+				changed = true
+				continue
+			}
+			out[name] = f.nested(v.Field(i), depth, &changed)
+		}
+		return out, changed
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			return nil, false // []byte
+		}
+		out := make([]interface{}, v.Len())
+		changed := false
+		for i := 0; i < v.Len(); i++ {
+			out[i] = f.nested(v.Index(i), depth, &changed)
+		}
+		return out, changed
+	}
+
+	return nil, false
+}
+
+// nested redacts a value inside a container, recording whether it changed
+func (f *RedactingFormatter) nested(v reflect.Value, depth int, changed *bool) interface{} {
+	if redacted, ok := f.redactValue(v, depth+1); ok {
+		*changed = true
+		return redacted
+	}
+	if !v.CanInterface() {
+		return nil
+	}
+	return v.Interface()
+}
+
+// fieldName returns the name a struct field is serialized under
+func fieldName(field reflect.StructField) string {
+	if tag, ok := field.Tag.Lookup("json"); ok {
+		if name, _, _ := strings.Cut(tag, ","); name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type loginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+func newRedactingLogger(formatter logrus.Formatter) (*logrus.Logger, *bytes.Buffer) {
+	var out bytes.Buffer
+	l := logrus.New()
+	l.SetOutput(&out)
+	l.SetFormatter(NewRedactingFormatter(formatter, DefaultRedactFields))
+	return l, &out
+}
+
+func TestRedactingFormatter_JSON(t *testing.T) {
+	l, out := newRedactingLogger(&logrus.JSONFormatter{})
+
+	l.WithFields(logrus.Fields{
+		"password":      "hunter2",
+		"Authorization": "Bearer abc",
+		"user_id":       7,
+		"body":          loginRequest{Email: "user@example.com", Password: "hunter2"},
+		"nested":        map[string]interface{}{"refresh_token": "xyz", "items": []interface{}{map[string]string{"token": "t"}}},
+	}).Info("login")
+
+	var logged map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &logged))
+
+	assert.Equal(t, "***", logged["password"])
+	assert.Equal(t, "***", logged["Authorization"])
+	assert.Equal(t, float64(7), logged["user_id"])
+	assert.Equal(t, map[string]interface{}{"email": "user@example.com", "password": "***"}, logged["body"])
+	assert.Equal(t, map[string]interface{}{
+		"refresh_token": "***",
+		"items":         []interface{}{map[string]interface{}{"token": "***"}},
+	}, logged["nested"])
+	assert.NotContains(t, out.String(), "hunter2")
+}
+
+func TestRedactingFormatter_Text(t *testing.T) {
+	l, out := newRedactingLogger(&CustomTextFormatter{DisableColors: true})
+
+	l.WithField("password", "hunter2").WithField("body", &loginRequest{Password: "hunter2"}).Info("login")
+
+	assert.Contains(t, out.String(), "password=***")
+	assert.NotContains(t, out.String(), "hunter2")
+}
+
+func TestRedactingFormatter_LeavesOtherValuesAlone(t *testing.T) {
+	l, out := newRedactingLogger(&CustomTextFormatter{DisableColors: true})
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	l.WithError(errors.New("boom")).WithField("at", at).Info("event")
+
+	assert.Contains(t, out.String(), "error=boom")
+	assert.Contains(t, out.String(), "at="+at.String())
+}
@@ -0,0 +1,175 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat stamps rotated files; it sorts lexically in time order
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotatingFile is an io.WriteCloser that appends to a log file and, once the
+// file would grow past maxSize, renames it to a timestamped backup next to it
+// (app.log becomes app-2006-01-02T15-04-05.000.log) and starts a new one.
+// Backups beyond maxBackups or older than maxAge are deleted. Zero limits
+// disable the corresponding check.
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	maxAge     time.Duration
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+	now  func() time.Time
+}
+
+// NewRotatingFile opens (or creates) the log file at path
+func NewRotatingFile(path string, maxSize int64, maxBackups int, maxAge time.Duration) (*RotatingFile, error) {
+	r := &RotatingFile{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+		maxAge:     maxAge,
+		now:        time.Now,
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Write appends p to the current file, rotating first if p would push it past maxSize
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Close closes the current file
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+func (r *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	r.file = file
+	r.size = info.Size()
+	return nil
+}
+
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	r.file = nil
+
+	now := r.now()
+	prefix, ext := r.backupPrefix()
+	backup := prefix + now.UTC().Format(backupTimeFormat) + ext
+	if err := os.Rename(r.path, backup); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+
+	if err := r.open(); err != nil {
+		return err
+	}
+
+	// A backup that can't be pruned shouldn't stop logging
+	_ = r.prune(now)
+	return nil
+}
+
+// backupPrefix splits the log path into the parts a backup name goes between
+func (r *RotatingFile) backupPrefix() (string, string) {
+	ext := filepath.Ext(r.path)
+	return strings.TrimSuffix(r.path, ext) + "-", ext
+}
+
+// prune deletes the oldest backups past maxBackups and any older than maxAge
+func (r *RotatingFile) prune(now time.Time) error {
+	if r.maxBackups <= 0 && r.maxAge <= 0 {
+		return nil
+	}
+
+	prefix, ext := r.backupPrefix()
+	matches, err := filepath.Glob(prefix + "*" + ext)
+	if err != nil {
+		return err
+	}
+
+	type backup struct {
+		path string
+		at   time.Time
+	}
+	var backups []backup
+	for _, match := range matches {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(match, prefix), ext)
+		at, err := time.Parse(backupTimeFormat, stamp)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{path: match, at: at})
+	}
+
+	// Newest first
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].at.After(backups[j].at)
+	})
+
+	cutoff := now.Add(-r.maxAge)
+	var firstErr error
+	for i, b := range backups {
+		tooMany := r.maxBackups > 0 && i >= r.maxBackups
+		tooOld := r.maxAge > 0 && b.at.Before(cutoff)
+		if !tooMany && !tooOld {
+			continue
+		}
+		if err := os.Remove(b.path); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
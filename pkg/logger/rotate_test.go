@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFile_RotatesAndPrunes(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	r, err := NewRotatingFile(path, 10, 2, 0)
+	require.NoError(t, err)
+	defer r.Close()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	r.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	for i := 0; i < 5; i++ {
+		_, err := r.Write([]byte("12345678\n"))
+		require.NoError(t, err)
+	}
+
+	// Each write fills a file, so four rotations happened and two backups survive
+	backups, err := filepath.Glob(filepath.Join(dir, "app-*.log"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "app-2024-01-01T00-00-03.000.log"),
+		filepath.Join(dir, "app-2024-01-01T00-00-04.000.log"),
+	}, backups)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "12345678\n", string(data))
+}
+
+func TestRotatingFile_PrunesByAge(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	stale := filepath.Join(dir, "app-2023-12-01T00-00-00.000.log")
+	require.NoError(t, os.WriteFile(stale, []byte("old\n"), 0o644))
+
+	r, err := NewRotatingFile(path, 4, 0, 7*24*time.Hour)
+	require.NoError(t, err)
+	defer r.Close()
+	r.now = func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	_, err = r.Write([]byte("abc\n"))
+	require.NoError(t, err)
+	_, err = r.Write([]byte("def\n"))
+	require.NoError(t, err)
+
+	assert.NoFileExists(t, stale)
+	assert.FileExists(t, filepath.Join(dir, "app-2024-01-01T00-00-00.000.log"))
+}
+
+func TestRotatingFile_AppendsToExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	require.NoError(t, os.WriteFile(path, []byte("1234567\n"), 0o644))
+
+	r, err := NewRotatingFile(path, 10, 0, 0)
+	require.NoError(t, err)
+	defer r.Close()
+
+	// The existing 8 bytes count towards the limit
+	_, err = r.Write([]byte("abc\n"))
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "abc\n", string(data))
+}
@@ -0,0 +1,137 @@
+// Package ical renders events as an iCalendar (RFC 5545) feed that calendar
+// apps can subscribe to.
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// dateTimeFormat is the UTC DATE-TIME form, e.g. 20250131T090000Z
+const dateTimeFormat = "20060102T150405Z"
+
+// maxLineOctets is the longest a content line may be before it is folded
+const maxLineOctets = 75
+
+// Calendar is a named collection of events
+type Calendar struct {
+	ProductID string // e.g. "-//NotiNote//Reminders//EN"
+	Name      string
+	Events    []Event
+}
+
+// Event is a single VEVENT. Events without an end are instantaneous.
+type Event struct {
+	UID         string
+	Start       time.Time
+	Stamp       time.Time // when the event was last modified
+	Summary     string
+	Description string
+	RRule       string // recurrence rule without the "RRULE:" prefix; empty for one-off events
+	Alarm       bool   // add a display alarm at the start time
+}
+
+// Write renders the calendar to w
+func (c *Calendar) Write(w io.Writer) error {
+	lw := &lineWriter{w: w}
+
+	lw.line("BEGIN:VCALENDAR")
+	lw.line("VERSION:2.0")
+	lw.line("PRODID:" + c.ProductID)
+	lw.line("CALSCALE:GREGORIAN")
+	lw.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		lw.line("X-WR-CALNAME:" + Escape(c.Name))
+	}
+
+	for _, event := range c.Events {
+		lw.line("BEGIN:VEVENT")
+		lw.line("UID:" + event.UID)
+		lw.line("DTSTAMP:" + FormatTime(event.Stamp))
+		lw.line("DTSTART:" + FormatTime(event.Start))
+		lw.line("SUMMARY:" + Escape(event.Summary))
+		if event.Description != "" {
+			lw.line("DESCRIPTION:" + Escape(event.Description))
+		}
+		if event.RRule != "" {
+			lw.line("RRULE:" + event.RRule)
+		}
+		if event.Alarm {
+			lw.line("BEGIN:VALARM")
+			lw.line("ACTION:DISPLAY")
+			lw.line("TRIGGER:PT0S")
+			lw.line("DESCRIPTION:" + Escape(event.Summary))
+			lw.line("END:VALARM")
+		}
+		lw.line("END:VEVENT")
+	}
+
+	lw.line("END:VCALENDAR")
+	return lw.err
+}
+
+// FormatTime formats t as a UTC DATE-TIME value
+func FormatTime(t time.Time) string {
+	return t.UTC().Format(dateTimeFormat)
+}
+
+// Escape escapes a TEXT value
+func Escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(s)
+}
+
+// lineWriter writes CRLF-terminated content lines, folding long ones and
+// remembering the first error
+type lineWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (lw *lineWriter) line(s string) {
+	if lw.err != nil {
+		return
+	}
+	_, lw.err = io.WriteString(lw.w, fold(s)+"\r\n")
+}
+
+// fold splits a line into chunks of at most maxLineOctets octets, continuing
+// each with a space, without splitting UTF-8 characters
+func fold(s string) string {
+	if len(s) <= maxLineOctets {
+		return s
+	}
+
+	var b strings.Builder
+	limit := maxLineOctets
+	start := 0
+	for i, r := range s {
+		if i+utf8.RuneLen(r)-start > limit {
+			b.WriteString(s[start:i])
+			b.WriteString("\r\n ")
+			start = i
+			limit = maxLineOctets - 1 // the leading space counts
+		}
+	}
+	b.WriteString(s[start:])
+	return b.String()
+}
+
+// Weekday returns the two-letter BYDAY code for a weekday
+func Weekday(day time.Weekday) string {
+	return [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}[day]
+}
+
+// Until formats an UNTIL rule part
+func Until(t time.Time) string {
+	return fmt.Sprintf("UNTIL=%s", FormatTime(t))
+}
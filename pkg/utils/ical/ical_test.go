@@ -0,0 +1,70 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendar_Write(t *testing.T) {
+	start := time.Date(2025, time.January, 31, 9, 0, 0, 0, time.FixedZone("UTC+7", 7*60*60))
+	cal := Calendar{
+		ProductID: "-//NotiNote//Test//EN",
+		Name:      "Reminders",
+		Events: []Event{{
+			UID:         "reminder-1@notinote",
+			Start:       start,
+			Stamp:       start,
+			Summary:     "Groceries; milk, eggs",
+			Description: "Line one\nLine two",
+			RRule:       "FREQ=WEEKLY;BYDAY=MO,WE",
+			Alarm:       true,
+		}},
+	}
+
+	var out strings.Builder
+	require.NoError(t, cal.Write(&out))
+
+	assert.Equal(t, strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//NotiNote//Test//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:Reminders",
+		"BEGIN:VEVENT",
+		"UID:reminder-1@notinote",
+		"DTSTAMP:20250131T020000Z",
+		"DTSTART:20250131T020000Z",
+		`SUMMARY:Groceries\; milk\, eggs`,
+		`DESCRIPTION:Line one\nLine two`,
+		"RRULE:FREQ=WEEKLY;BYDAY=MO,WE",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"TRIGGER:PT0S",
+		`DESCRIPTION:Groceries\; milk\, eggs`,
+		"END:VALARM",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n"), out.String())
+}
+
+func TestFold(t *testing.T) {
+	line := "SUMMARY:" + strings.Repeat("é", 80)
+
+	folded := fold(line)
+
+	lines := strings.Split(folded, "\r\n")
+	require.Greater(t, len(lines), 1)
+	for i, l := range lines {
+		assert.LessOrEqual(t, len(l), maxLineOctets)
+		if i > 0 {
+			assert.True(t, strings.HasPrefix(l, " "))
+		}
+	}
+	assert.Equal(t, line, strings.ReplaceAll(folded, "\r\n ", ""))
+}
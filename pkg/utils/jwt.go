@@ -1,7 +1,10 @@
 package utils
 
 import (
+	"crypto/rsa"
 	"errors"
+	"fmt"
+	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -10,93 +13,247 @@ import (
 var (
 	ErrInvalidToken = errors.New("invalid token")
 	ErrExpiredToken = errors.New("token has expired")
+	ErrUnknownKeyID = fmt.Errorf("%w: signed with unknown key", ErrInvalidToken)
+
+	// ErrSigningUnavailable is returned when issuing tokens from a verify-only service
+	ErrSigningUnavailable = errors.New("token signing key not configured")
 )
 
+// KeyIDHeader is the JWT header naming the key a token was signed with
+const KeyIDHeader = "kid"
+
 // JWTClaims represents the JWT claims
 type JWTClaims struct {
 	UserID int64  `json:"user_id"`
 	Email  string `json:"email"`
+	Role   string `json:"role,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// SigningKey is an HMAC secret identified by the kid header of the tokens it signs
+type SigningKey struct {
+	ID     string
+	Secret string
+}
+
 // JWTService handles JWT token operations
 type JWTService struct {
-	secret              string
-	issuer              string
-	accessTokenExpiry   time.Duration
-	refreshTokenExpiry  time.Duration
+	method             jwt.SigningMethod      // the only algorithm accepted when validating
+	secret             string                 // current HMAC signing secret
+	signingKey         interface{}            // key passed to SignedString; nil if verify-only
+	keyID              string                 // kid of the current signing key
+	verifyKeys         map[string]interface{} // kid -> key, for validation
+	issuer             string
+	accessTokenExpiry  time.Duration
+	refreshTokenExpiry time.Duration
 }
 
-// NewJWTService creates a new JWT service
+// NewJWTService creates a new HS256 JWT service with a single signing secret.
+// Its tokens carry no kid header.
 func NewJWTService(secret, issuer string, accessExpiry, refreshExpiry time.Duration) *JWTService {
 	return &JWTService{
-		secret:              secret,
-		issuer:              issuer,
-		accessTokenExpiry:   accessExpiry,
-		refreshTokenExpiry:  refreshExpiry,
+		method:             jwt.SigningMethodHS256,
+		secret:             secret,
+		signingKey:         []byte(secret),
+		verifyKeys:         map[string]interface{}{"": []byte(secret)},
+		issuer:             issuer,
+		accessTokenExpiry:  accessExpiry,
+		refreshTokenExpiry: refreshExpiry,
+	}
+}
+
+// NewJWTServiceWithKeys creates an HS256 JWT service backed by a keyring, so the
+// signing secret can be rotated without invalidating issued tokens. Tokens are signed
+// with the key named by currentKeyID and validated with whichever key their kid
+// header names. A key with an empty ID validates tokens issued without a kid.
+func NewJWTServiceWithKeys(keys []SigningKey, currentKeyID, issuer string, accessExpiry, refreshExpiry time.Duration) (*JWTService, error) {
+	keyring := make(map[string]interface{}, len(keys))
+	var secret string
+	for _, key := range keys {
+		if key.Secret == "" {
+			return nil, fmt.Errorf("signing key %q has an empty secret", key.ID)
+		}
+		if _, exists := keyring[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate signing key %q", key.ID)
+		}
+		keyring[key.ID] = []byte(key.Secret)
+		if key.ID == currentKeyID {
+			secret = key.Secret
+		}
+	}
+
+	if _, ok := keyring[currentKeyID]; !ok {
+		return nil, fmt.Errorf("current signing key %q is not in the keyring", currentKeyID)
+	}
+
+	return &JWTService{
+		method:             jwt.SigningMethodHS256,
+		secret:             secret,
+		signingKey:         []byte(secret),
+		keyID:              currentKeyID,
+		verifyKeys:         keyring,
+		issuer:             issuer,
+		accessTokenExpiry:  accessExpiry,
+		refreshTokenExpiry: refreshExpiry,
+	}, nil
+}
+
+// NewRS256JWTService creates a JWT service that signs with an RSA private key and
+// verifies with the matching public key, so other services can verify tokens
+// without being able to mint them. privateKey may be nil for a verify-only service;
+// publicKey defaults to the private key's public half. Tokens carry keyID as their
+// kid header when it is set.
+func NewRS256JWTService(privateKey *rsa.PrivateKey, publicKey *rsa.PublicKey, keyID, issuer string, accessExpiry, refreshExpiry time.Duration) (*JWTService, error) {
+	if publicKey == nil {
+		if privateKey == nil {
+			return nil, errors.New("RS256 requires a private or public key")
+		}
+		publicKey = &privateKey.PublicKey
+	}
+
+	var signingKey interface{}
+	if privateKey != nil {
+		signingKey = privateKey
 	}
+
+	return &JWTService{
+		method:             jwt.SigningMethodRS256,
+		signingKey:         signingKey,
+		keyID:              keyID,
+		verifyKeys:         map[string]interface{}{keyID: publicKey},
+		issuer:             issuer,
+		accessTokenExpiry:  accessExpiry,
+		refreshTokenExpiry: refreshExpiry,
+	}, nil
+}
+
+// LoadRSAKeys reads PEM-encoded RSA keys from disk. Either path may be empty.
+func LoadRSAKeys(privateKeyPath, publicKeyPath string) (*rsa.PrivateKey, *rsa.PublicKey, error) {
+	var privateKey *rsa.PrivateKey
+	var publicKey *rsa.PublicKey
+
+	if privateKeyPath != "" {
+		data, err := os.ReadFile(privateKeyPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read private key: %w", err)
+		}
+		privateKey, err = jwt.ParseRSAPrivateKeyFromPEM(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse private key: %w", err)
+		}
+	}
+
+	if publicKeyPath != "" {
+		data, err := os.ReadFile(publicKeyPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read public key: %w", err)
+		}
+		publicKey, err = jwt.ParseRSAPublicKeyFromPEM(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+	}
+
+	if privateKey != nil && publicKey != nil && !privateKey.PublicKey.Equal(publicKey) {
+		return nil, nil, errors.New("public key does not match private key")
+	}
+
+	return privateKey, publicKey, nil
 }
 
 // GenerateToken generates a JWT access token for a user
 func (j *JWTService) GenerateToken(userID int64, email string) (string, error) {
-	now := time.Now()
-	claims := JWTClaims{
-		UserID: userID,
-		Email:  email,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(now.Add(j.accessTokenExpiry)),
-			IssuedAt:  jwt.NewNumericDate(now),
-			NotBefore: jwt.NewNumericDate(now),
-			Issuer:    j.issuer,
-		},
-	}
+	return j.sign(userID, email, "", j.accessTokenExpiry)
+}
+
+// GenerateTokenWithRole generates a JWT access token carrying the user's role
+func (j *JWTService) GenerateTokenWithRole(userID int64, email, role string) (string, error) {
+	return j.sign(userID, email, role, j.accessTokenExpiry)
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(j.secret))
+// AccessTokenExpiry returns how long access tokens are valid
+func (j *JWTService) AccessTokenExpiry() time.Duration {
+	return j.accessTokenExpiry
 }
 
 // GenerateRefreshToken generates a JWT refresh token
 func (j *JWTService) GenerateRefreshToken(userID int64, email string) (string, error) {
+	return j.sign(userID, email, "", j.refreshTokenExpiry)
+}
+
+// sign issues a token for the user with the current signing key
+func (j *JWTService) sign(userID int64, email, role string, expiry time.Duration) (string, error) {
+	if j.signingKey == nil {
+		return "", ErrSigningUnavailable
+	}
+
 	now := time.Now()
 	claims := JWTClaims{
 		UserID: userID,
 		Email:  email,
+		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(now.Add(j.refreshTokenExpiry)),
+			ExpiresAt: jwt.NewNumericDate(now.Add(expiry)),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
 			Issuer:    j.issuer,
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(j.secret))
+	token := jwt.NewWithClaims(j.method, claims)
+	if j.keyID != "" {
+		token.Header[KeyIDHeader] = j.keyID
+	}
+	return token.SignedString(j.signingKey)
 }
 
 // ValidateToken validates a JWT token and returns claims
 func (j *JWTService) ValidateToken(tokenString string) (userID int64, email string, err error) {
+	claims, err := j.ParseToken(tokenString)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return claims.UserID, claims.Email, nil
+}
+
+// ParseToken validates a JWT token and returns all of its claims
+func (j *JWTService) ParseToken(tokenString string) (*JWTClaims, error) {
 	claims := &JWTClaims{}
 
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		// Validate signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrInvalidToken
+		// Pick the key named by kid; tokens without one use the unnamed key
+		keyID := ""
+		if raw, present := token.Header[KeyIDHeader]; present {
+			id, ok := raw.(string)
+			if !ok {
+				return nil, ErrUnknownKeyID
+			}
+			keyID = id
 		}
-		return []byte(j.secret), nil
-	})
+
+		key, ok := j.verifyKeys[keyID]
+		if !ok {
+			return nil, ErrUnknownKeyID
+		}
+		return key, nil
+	}, jwt.WithValidMethods([]string{j.method.Alg()}))
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
-			return 0, "", ErrExpiredToken
+			return nil, ErrExpiredToken
+		}
+		if errors.Is(err, ErrUnknownKeyID) {
+			return nil, ErrUnknownKeyID
 		}
-		return 0, "", ErrInvalidToken
+		return nil, ErrInvalidToken
 	}
 
 	if !token.Valid {
-		return 0, "", ErrInvalidToken
+		return nil, ErrInvalidToken
 	}
 
-	return claims.UserID, claims.Email, nil
+	return claims, nil
 }
 
 // RefreshToken generates a new access token from a refresh token
@@ -1,6 +1,12 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -318,8 +324,6 @@ func TestJWTService_TokenUniqueness(t *testing.T) {
 }
 
 func TestJWTService_WrongAlgorithm(t *testing.T) {
-	t.Skip("JWT library behavior with different HMAC algorithms is complex - skipping")
-
 	service := NewJWTService("test-secret", "test-issuer", 24*time.Hour, 7*24*time.Hour)
 
 	// Create a token with a different signing method
@@ -382,3 +386,162 @@ func BenchmarkJWTService_ValidateToken(b *testing.B) {
 		_, _, _ = service.ValidateToken(token)
 	}
 }
+
+func TestJWTService_KeyRotation(t *testing.T) {
+	legacy := NewJWTService("old-secret", "test-issuer", time.Hour, time.Hour)
+	legacyToken, err := legacy.GenerateToken(1, "legacy@example.com")
+	require.NoError(t, err)
+
+	before, err := NewJWTServiceWithKeys([]SigningKey{
+		{ID: "", Secret: "old-secret"},
+		{ID: "k1", Secret: "secret-1"},
+	}, "k1", "test-issuer", time.Hour, time.Hour)
+	require.NoError(t, err)
+	k1Token, err := before.GenerateToken(2, "k1@example.com")
+	require.NoError(t, err)
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(k1Token, &JWTClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "k1", parsed.Header[KeyIDHeader])
+
+	// Rotate: k2 signs, k1 and the unnamed legacy key still validate
+	after, err := NewJWTServiceWithKeys([]SigningKey{
+		{ID: "", Secret: "old-secret"},
+		{ID: "k1", Secret: "secret-1"},
+		{ID: "k2", Secret: "secret-2"},
+	}, "k2", "test-issuer", time.Hour, time.Hour)
+	require.NoError(t, err)
+
+	userID, _, err := after.ValidateToken(k1Token)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), userID)
+
+	userID, _, err = after.ValidateToken(legacyToken)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), userID)
+
+	// Retire k1 and the legacy key
+	retired, err := NewJWTServiceWithKeys([]SigningKey{{ID: "k2", Secret: "secret-2"}}, "k2", "test-issuer", time.Hour, time.Hour)
+	require.NoError(t, err)
+
+	_, _, err = retired.ValidateToken(k1Token)
+	assert.ErrorIs(t, err, ErrUnknownKeyID)
+	_, _, err = retired.ValidateToken(legacyToken)
+	assert.ErrorIs(t, err, ErrUnknownKeyID)
+}
+
+func TestJWTService_UnknownKeyID(t *testing.T) {
+	service, err := NewJWTServiceWithKeys([]SigningKey{{ID: "k1", Secret: "secret-1"}}, "k1", "test-issuer", time.Hour, time.Hour)
+	require.NoError(t, err)
+
+	// Same secret, but the kid names a key the service doesn't know
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, JWTClaims{
+		UserID: 1,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	token.Header[KeyIDHeader] = "k9"
+	tokenString, err := token.SignedString([]byte("secret-1"))
+	require.NoError(t, err)
+
+	_, _, err = service.ValidateToken(tokenString)
+	assert.ErrorIs(t, err, ErrUnknownKeyID)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestNewJWTServiceWithKeys_RequiresCurrentKey(t *testing.T) {
+	_, err := NewJWTServiceWithKeys([]SigningKey{{ID: "k1", Secret: "secret-1"}}, "k2", "test-issuer", time.Hour, time.Hour)
+	assert.Error(t, err)
+}
+
+func TestJWTService_RS256(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	signer, err := NewRS256JWTService(privateKey, nil, "rsa-1", "test-issuer", time.Hour, time.Hour)
+	require.NoError(t, err)
+	verifier, err := NewRS256JWTService(nil, &privateKey.PublicKey, "rsa-1", "test-issuer", time.Hour, time.Hour)
+	require.NoError(t, err)
+
+	token, err := signer.GenerateToken(42, "user@example.com")
+	require.NoError(t, err)
+
+	userID, email, err := verifier.ValidateToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), userID)
+	assert.Equal(t, "user@example.com", email)
+
+	_, err = verifier.GenerateToken(42, "user@example.com")
+	assert.ErrorIs(t, err, ErrSigningUnavailable)
+}
+
+func TestJWTService_RS256_RejectsAlgorithmConfusion(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	verifier, err := NewRS256JWTService(nil, &privateKey.PublicKey, "", "test-issuer", time.Hour, time.Hour)
+	require.NoError(t, err)
+
+	// An HS256 token "signed" with the public key, which attackers can obtain
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: mustMarshalPublicKey(t, &privateKey.PublicKey)})
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, JWTClaims{
+		UserID: 1,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	tokenString, err := forged.SignedString(publicPEM)
+	require.NoError(t, err)
+
+	_, _, err = verifier.ValidateToken(tokenString)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	// And HS256 services reject RS256 tokens
+	signer, err := NewRS256JWTService(privateKey, nil, "", "test-issuer", time.Hour, time.Hour)
+	require.NoError(t, err)
+	rsToken, err := signer.GenerateToken(1, "user@example.com")
+	require.NoError(t, err)
+
+	_, _, err = NewJWTService("test-secret", "test-issuer", time.Hour, time.Hour).ValidateToken(rsToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestLoadRSAKeys(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	privatePath := filepath.Join(dir, "private.pem")
+	publicPath := filepath.Join(dir, "public.pem")
+	require.NoError(t, os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+	}), 0o600))
+	require.NoError(t, os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: mustMarshalPublicKey(t, &privateKey.PublicKey),
+	}), 0o644))
+
+	loadedPrivate, loadedPublic, err := LoadRSAKeys(privatePath, publicPath)
+	require.NoError(t, err)
+	assert.True(t, privateKey.Equal(loadedPrivate))
+	assert.True(t, privateKey.PublicKey.Equal(loadedPublic))
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: mustMarshalPublicKey(t, &otherKey.PublicKey),
+	}), 0o644))
+
+	_, _, err = LoadRSAKeys(privatePath, publicPath)
+	assert.Error(t, err, "mismatched key pair")
+}
+
+func mustMarshalPublicKey(t *testing.T, key *rsa.PublicKey) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+	return der
+}
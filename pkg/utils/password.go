@@ -1,9 +1,25 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// bcryptMaxPasswordLength is the number of bytes bcrypt reads from a password
+const bcryptMaxPasswordLength = 72
+
+// argon2idPrefix starts every hash produced by Argon2idPasswordHasher, and is
+// how either hasher tells the two formats apart
+const argon2idPrefix = "$argon2id$"
+
 // BcryptPasswordHasher implements password hashing using bcrypt
 type BcryptPasswordHasher struct {
 	cost int
@@ -16,17 +32,205 @@ func NewBcryptPasswordHasher() *BcryptPasswordHasher {
 	}
 }
 
+// NewBcryptPasswordHasherWithCost creates a bcrypt password hasher with the
+// given cost, clamped to the range bcrypt accepts
+func NewBcryptPasswordHasherWithCost(cost int) *BcryptPasswordHasher {
+	if cost < bcrypt.MinCost {
+		cost = bcrypt.MinCost
+	}
+	if cost > bcrypt.MaxCost {
+		cost = bcrypt.MaxCost
+	}
+	return &BcryptPasswordHasher{cost: cost}
+}
+
 // HashPassword hashes a plain text password using bcrypt
 func (h *BcryptPasswordHasher) HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	bytes, err := bcrypt.GenerateFromPassword(bcryptInput(password), h.cost)
 	if err != nil {
 		return "", err
 	}
 	return string(bytes), nil
 }
 
-// CheckPassword compares a plain text password with a bcrypt hash
+// CheckPassword compares a plain text password with a bcrypt hash. Argon2id
+// hashes are verified too, so switching algorithms doesn't lock anyone out.
 func (h *BcryptPasswordHasher) CheckPassword(password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	return err == nil
+	if strings.HasPrefix(hash, argon2idPrefix) {
+		return checkArgon2id(password, hash)
+	}
+	return checkBcrypt(password, hash)
+}
+
+// NeedsRehash returns true if the hash's cost is below the hasher's cost, or
+// if it is an argon2id hash. Unreadable hashes are left alone; they can't have
+// matched a password anyway.
+func (h *BcryptPasswordHasher) NeedsRehash(hash string) bool {
+	if strings.HasPrefix(hash, argon2idPrefix) {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	if err != nil {
+		return false
+	}
+	return cost < h.cost
+}
+
+// bcryptInput returns the bytes bcrypt should hash for a password. bcrypt
+// rejects passwords over 72 bytes, so longer ones are pre-hashed with SHA-256;
+// the digest is base64 encoded since bcrypt stops at a NUL byte. Shorter
+// passwords are used as-is, which keeps every existing hash valid.
+func bcryptInput(password string) []byte {
+	if len(password) <= bcryptMaxPasswordLength {
+		return []byte(password)
+	}
+	digest := sha256.Sum256([]byte(password))
+	return []byte(base64.StdEncoding.EncodeToString(digest[:]))
+}
+
+// checkBcrypt verifies a password against a bcrypt hash
+func checkBcrypt(password, hash string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), bcryptInput(password)) == nil
+}
+
+// Argon2idParams are the argon2id cost parameters. Memory is in KiB.
+type Argon2idParams struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2idParams returns the parameters recommended by RFC 9106 for
+// memory-constrained environments
+func DefaultArgon2idParams() Argon2idParams {
+	return Argon2idParams{
+		Memory:      64 * 1024,
+		Iterations:  3,
+		Parallelism: 4,
+		SaltLength:  16,
+		KeyLength:   32,
+	}
+}
+
+// Argon2idPasswordHasher implements password hashing using argon2id. Hashes are
+// stored in the PHC string format, e.g.
+// $argon2id$v=19$m=65536,t=3,p=4$<salt>$<key>, so they carry their own parameters.
+type Argon2idPasswordHasher struct {
+	params Argon2idParams
+}
+
+// NewArgon2idPasswordHasher creates a new argon2id password hasher. Zero
+// parameters fall back to their defaults.
+func NewArgon2idPasswordHasher(params Argon2idParams) *Argon2idPasswordHasher {
+	defaults := DefaultArgon2idParams()
+	if params.Memory == 0 {
+		params.Memory = defaults.Memory
+	}
+	if params.Iterations == 0 {
+		params.Iterations = defaults.Iterations
+	}
+	if params.Parallelism == 0 {
+		params.Parallelism = defaults.Parallelism
+	}
+	if params.SaltLength == 0 {
+		params.SaltLength = defaults.SaltLength
+	}
+	if params.KeyLength == 0 {
+		params.KeyLength = defaults.KeyLength
+	}
+	return &Argon2idPasswordHasher{params: params}
+}
+
+// HashPassword hashes a plain text password using argon2id
+func (h *Argon2idPasswordHasher) HashPassword(password string) (string, error) {
+	salt := make([]byte, h.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.params.Iterations, h.params.Memory, h.params.Parallelism, h.params.KeyLength)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix, argon2.Version,
+		h.params.Memory, h.params.Iterations, h.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// CheckPassword compares a plain text password with an argon2id hash. Bcrypt
+// hashes are verified too, so existing users can still sign in after switching.
+func (h *Argon2idPasswordHasher) CheckPassword(password, hash string) bool {
+	if !strings.HasPrefix(hash, argon2idPrefix) {
+		return checkBcrypt(password, hash)
+	}
+	return checkArgon2id(password, hash)
+}
+
+// NeedsRehash returns true for bcrypt hashes and for argon2id hashes whose
+// memory, iterations or key length are below the hasher's
+func (h *Argon2idPasswordHasher) NeedsRehash(hash string) bool {
+	if !strings.HasPrefix(hash, argon2idPrefix) {
+		_, err := bcrypt.Cost([]byte(hash))
+		return err == nil
+	}
+
+	params, _, key, err := decodeArgon2idHash(hash)
+	if err != nil {
+		return false
+	}
+	return params.Memory < h.params.Memory ||
+		params.Iterations < h.params.Iterations ||
+		uint32(len(key)) < h.params.KeyLength
+}
+
+// checkArgon2id verifies a password against an argon2id hash using the
+// parameters encoded in the hash
+func checkArgon2id(password, hash string) bool {
+	params, salt, key, err := decodeArgon2idHash(hash)
+	if err != nil {
+		return false
+	}
+
+	candidate := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	return subtle.ConstantTimeCompare(candidate, key) == 1
+}
+
+var errInvalidArgon2idHash = errors.New("invalid argon2id hash")
+
+// decodeArgon2idHash parses a PHC-formatted argon2id hash
+func decodeArgon2idHash(hash string) (Argon2idParams, []byte, []byte, error) {
+	var params Argon2idParams
+
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, key
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, nil, nil, errInvalidArgon2idHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, errInvalidArgon2idHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, errInvalidArgon2idHash
+	}
+	if params.Memory == 0 || params.Iterations == 0 || params.Parallelism == 0 {
+		return params, nil, nil, errInvalidArgon2idHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, errInvalidArgon2idHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, errInvalidArgon2idHash
+	}
+
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	return params, salt, key, nil
 }
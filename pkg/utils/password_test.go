@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -35,7 +36,7 @@ func TestBcryptPasswordHasher_HashPassword(t *testing.T) {
 		{
 			name:     "long password",
 			password: string(make([]byte, 100)),
-			wantErr:  true, // bcrypt has 72-byte limit
+			wantErr:  false, // pre-hashed past bcrypt's 72-byte limit
 		},
 		{
 			name:     "empty password",
@@ -229,6 +230,40 @@ func TestBcryptPasswordHasher_CostFactor(t *testing.T) {
 	assert.Equal(t, bcrypt.DefaultCost, cost)
 }
 
+func TestBcryptPasswordHasher_LongPassword(t *testing.T) {
+	hasher := NewBcryptPasswordHasherWithCost(bcrypt.MinCost)
+	long := strings.Repeat("a", 100)
+
+	hash, err := hasher.HashPassword(long)
+	require.NoError(t, err)
+	assert.True(t, hasher.CheckPassword(long, hash))
+
+	// Every byte counts, not just the first 72
+	assert.False(t, hasher.CheckPassword(long[:72], hash))
+	assert.False(t, hasher.CheckPassword(long[:99]+"b", hash))
+
+	// Passwords at the limit are hashed unchanged
+	atLimit := strings.Repeat("a", 72)
+	hash, err = hasher.HashPassword(atLimit)
+	require.NoError(t, err)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(hash), []byte(atLimit)))
+}
+
+func TestBcryptPasswordHasher_NeedsRehash(t *testing.T) {
+	weak := NewBcryptPasswordHasherWithCost(bcrypt.MinCost)
+	strong := NewBcryptPasswordHasherWithCost(bcrypt.MinCost + 1)
+
+	hash, err := weak.HashPassword("TestPassword123!")
+	require.NoError(t, err)
+
+	assert.False(t, weak.NeedsRehash(hash))
+	assert.True(t, strong.NeedsRehash(hash))
+	assert.False(t, strong.NeedsRehash("not-a-bcrypt-hash"))
+
+	assert.Equal(t, bcrypt.MinCost, NewBcryptPasswordHasherWithCost(1).cost)
+	assert.Equal(t, bcrypt.MaxCost, NewBcryptPasswordHasherWithCost(99).cost)
+}
+
 func TestBcryptPasswordHasher_PerformanceBaseline(t *testing.T) {
 	// This is not a strict performance test, just a baseline check
 	// to ensure hashing doesn't take an unreasonable amount of time
@@ -265,3 +300,82 @@ func BenchmarkBcryptPasswordHasher_CheckPassword(b *testing.B) {
 		_ = hasher.CheckPassword(password, hash)
 	}
 }
+
+func newTestArgon2idHasher() *Argon2idPasswordHasher {
+	return NewArgon2idPasswordHasher(Argon2idParams{Memory: 1024, Iterations: 1, Parallelism: 1})
+}
+
+func TestArgon2idPasswordHasher_HashAndCheck(t *testing.T) {
+	hasher := newTestArgon2idHasher()
+	password := "TestPassword123!"
+
+	hash, err := hasher.HashPassword(password)
+	require.NoError(t, err)
+	assert.Regexp(t, `^\$argon2id\$v=19\$m=1024,t=1,p=1\$[A-Za-z0-9+/]+\$[A-Za-z0-9+/]+$`, hash)
+
+	assert.True(t, hasher.CheckPassword(password, hash))
+	assert.False(t, hasher.CheckPassword("WrongPassword123!", hash))
+
+	other, err := hasher.HashPassword(password)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, other, "salts should differ")
+}
+
+func TestArgon2idPasswordHasher_LongPassword(t *testing.T) {
+	hasher := newTestArgon2idHasher()
+	long := strings.Repeat("a", 100)
+
+	hash, err := hasher.HashPassword(long)
+	require.NoError(t, err)
+	assert.True(t, hasher.CheckPassword(long, hash))
+	assert.False(t, hasher.CheckPassword(long[:72], hash))
+}
+
+func TestPasswordHashers_VerifyEitherAlgorithm(t *testing.T) {
+	argon := newTestArgon2idHasher()
+	bcryptHasher := NewBcryptPasswordHasherWithCost(bcrypt.MinCost)
+	password := "TestPassword123!"
+
+	argonHash, err := argon.HashPassword(password)
+	require.NoError(t, err)
+	bcryptHash, err := bcryptHasher.HashPassword(password)
+	require.NoError(t, err)
+
+	assert.True(t, argon.CheckPassword(password, bcryptHash))
+	assert.True(t, bcryptHasher.CheckPassword(password, argonHash))
+	assert.False(t, argon.CheckPassword("WrongPassword123!", bcryptHash))
+	assert.False(t, bcryptHasher.CheckPassword("WrongPassword123!", argonHash))
+
+	// Each hasher migrates the other's hashes
+	assert.True(t, argon.NeedsRehash(bcryptHash))
+	assert.True(t, bcryptHasher.NeedsRehash(argonHash))
+	assert.False(t, argon.NeedsRehash(argonHash))
+}
+
+func TestArgon2idPasswordHasher_NeedsRehash(t *testing.T) {
+	weak := newTestArgon2idHasher()
+	strong := NewArgon2idPasswordHasher(Argon2idParams{Memory: 2048, Iterations: 1, Parallelism: 1})
+
+	hash, err := weak.HashPassword("TestPassword123!")
+	require.NoError(t, err)
+
+	assert.True(t, strong.NeedsRehash(hash))
+	assert.False(t, strong.NeedsRehash("$argon2id$v=19$garbage"))
+}
+
+func TestArgon2idPasswordHasher_CheckPassword_InvalidHash(t *testing.T) {
+	hasher := newTestArgon2idHasher()
+
+	tests := []string{
+		"",
+		"$argon2id$",
+		"$argon2id$v=19$m=1024,t=1,p=1$c2FsdA$",
+		"$argon2id$v=16$m=1024,t=1,p=1$c2FsdHNhbHQ$a2V5a2V5",
+		"$argon2id$v=19$m=0,t=1,p=1$c2FsdHNhbHQ$a2V5a2V5",
+		"$argon2id$v=19$m=1024,t=1,p=1$!!!$a2V5a2V5",
+	}
+
+	for _, hash := range tests {
+		assert.False(t, hasher.CheckPassword("TestPassword123!", hash), hash)
+	}
+}
@@ -33,17 +33,28 @@ func (s *RedisStateGenerator) GenerateState() (string, error) {
 	return base64.URLEncoding.EncodeToString(b), nil
 }
 
+// GenerateCodeVerifier generates a PKCE code verifier (RFC 7636): 43
+// characters from the unreserved URL alphabet
+func (s *RedisStateGenerator) GenerateCodeVerifier() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate code verifier: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
 // ValidateState validates that a state matches expected value
 func (s *RedisStateGenerator) ValidateState(state, expected string) bool {
 	return state == expected && state != ""
 }
 
-// StoreState temporarily stores state in Redis with expiration (TTL in seconds)
-func (s *RedisStateGenerator) StoreState(ctx context.Context, state string, ttl int) error {
+// StoreState temporarily stores state in Redis with expiration (TTL in seconds).
+// The code verifier is the stored value, so it leaves Redis with the state.
+func (s *RedisStateGenerator) StoreState(ctx context.Context, state, codeVerifier string, ttl int) error {
 	key := s.prefix + state
 	duration := time.Duration(ttl) * time.Second
 
-	err := s.redis.Set(ctx, key, "1", duration).Err()
+	err := s.redis.Set(ctx, key, codeVerifier, duration).Err()
 	if err != nil {
 		return fmt.Errorf("failed to store state in redis: %w", err)
 	}
@@ -52,23 +63,18 @@ func (s *RedisStateGenerator) StoreState(ctx context.Context, state string, ttl
 }
 
 // GetState retrieves and deletes stored state (one-time use)
-// Returns true if state exists and was deleted, false otherwise
-func (s *RedisStateGenerator) GetState(ctx context.Context, state string) (bool, error) {
+// Returns the state's code verifier and true if the state existed, false otherwise
+func (s *RedisStateGenerator) GetState(ctx context.Context, state string) (string, bool, error) {
 	key := s.prefix + state
 
-	// Get the value
-	val, err := s.redis.Get(ctx, key).Result()
+	// Get and delete in one step so a state can't be redeemed twice
+	codeVerifier, err := s.redis.GetDel(ctx, key).Result()
 	if err == redis.Nil {
-		return false, nil // State doesn't exist
+		return "", false, nil // State doesn't exist
 	}
 	if err != nil {
-		return false, fmt.Errorf("failed to get state from redis: %w", err)
-	}
-
-	// Delete the state (one-time use)
-	if err := s.redis.Del(ctx, key).Err(); err != nil {
-		return false, fmt.Errorf("failed to delete state from redis: %w", err)
+		return "", false, fmt.Errorf("failed to get state from redis: %w", err)
 	}
 
-	return val == "1", nil
+	return codeVerifier, true, nil
 }
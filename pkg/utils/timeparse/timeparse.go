@@ -0,0 +1,260 @@
+// Package timeparse resolves human-friendly time expressions such as
+// "in 2 hours", "tomorrow 9am", "next monday" or "1w" into absolute times.
+package timeparse
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultHour is the hour of day used when an expression names a day but no time
+const DefaultHour = 9
+
+// Parse errors
+var (
+	ErrEmptyInput   = errors.New("time expression is empty")
+	ErrUnrecognized = errors.New("unrecognized time expression")
+	ErrAmbiguous    = errors.New("ambiguous time expression")
+	ErrInvalidTime  = errors.New("invalid time of day")
+	ErrInPast       = errors.New("time expression resolves to a time in the past")
+)
+
+var (
+	amountUnitPattern = regexp.MustCompile(`^(\d+|an?)\s*([a-z]+)$`)
+	numberPattern     = regexp.MustCompile(`^\d+$`)
+	clockPattern      = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?\s*(am|pm)?$`)
+)
+
+var durationUnits = map[string]time.Duration{
+	"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hrs": time.Hour, "hour": time.Hour, "hours": time.Hour,
+	"d": 24 * time.Hour, "day": 24 * time.Hour, "days": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour, "wk": 7 * 24 * time.Hour, "week": 7 * 24 * time.Hour, "weeks": 7 * 24 * time.Hour,
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// ParseWhen resolves input relative to now in the given location and returns
+// a time strictly after now. Supported forms:
+//
+//   - durations: "10m", "1h30m", "1d", "1w", "2 hours", "in 30 minutes"
+//   - days: "today", "tomorrow", "monday", "next monday", "next week"
+//   - a day followed by a time: "tomorrow 9am", "friday at 17:30"
+//   - a time alone: "9am", "noon" (today, or tomorrow if already passed)
+//   - RFC3339 timestamps
+//
+// Days without a time resolve to DefaultHour. A nil loc uses now's location.
+func ParseWhen(input string, now time.Time, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = now.Location()
+	}
+	now = now.In(loc)
+
+	expr := strings.Join(strings.Fields(strings.ToLower(input)), " ")
+	if expr == "" {
+		return time.Time{}, ErrEmptyInput
+	}
+
+	if t, err := time.Parse(time.RFC3339, strings.TrimSpace(input)); err == nil {
+		return ensureFuture(t, now, input)
+	}
+
+	if rest, ok := strings.CutPrefix(expr, "in "); ok {
+		d, err := parseDuration(rest)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: %q", err, input)
+		}
+		return now.Add(d), nil
+	}
+
+	if d, err := parseDuration(expr); err == nil {
+		return now.Add(d), nil
+	} else if errors.Is(err, ErrAmbiguous) {
+		return time.Time{}, fmt.Errorf("%w: %q needs a unit such as m, h, d or w", err, input)
+	}
+
+	t, err := parseDayAndTime(expr, now, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %q", err, input)
+	}
+	return ensureFuture(t, now, input)
+}
+
+// parseDuration parses Go durations plus day/week units and spelled-out units
+func parseDuration(expr string) (time.Duration, error) {
+	if d, err := time.ParseDuration(expr); err == nil {
+		if d <= 0 {
+			return 0, ErrUnrecognized
+		}
+		return d, nil
+	}
+
+	if numberPattern.MatchString(expr) {
+		return 0, ErrAmbiguous
+	}
+
+	m := amountUnitPattern.FindStringSubmatch(expr)
+	if m == nil {
+		return 0, ErrUnrecognized
+	}
+
+	unit, ok := durationUnits[m[2]]
+	if !ok {
+		return 0, ErrUnrecognized
+	}
+
+	amount := 1
+	if m[1] != "a" && m[1] != "an" {
+		n, err := strconv.Atoi(m[1])
+		if err != nil || n <= 0 {
+			return 0, ErrUnrecognized
+		}
+		amount = n
+	}
+
+	return time.Duration(amount) * unit, nil
+}
+
+// parseDayAndTime handles "<day> [at] <time>", "<day>" and "<time>" forms
+func parseDayAndTime(expr string, now time.Time, loc *time.Location) (time.Time, error) {
+	tokens := strings.Fields(expr)
+
+	day, consumed, err := parseDay(tokens, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	rest := tokens[consumed:]
+	if len(rest) > 0 && rest[0] == "at" {
+		rest = rest[1:]
+		if len(rest) == 0 {
+			return time.Time{}, ErrUnrecognized
+		}
+	}
+
+	if consumed == 0 {
+		// Time only: today if still ahead, otherwise tomorrow
+		hour, minute, err := parseClock(strings.Join(rest, " "))
+		if err != nil {
+			return time.Time{}, err
+		}
+		t := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, loc)
+		if !t.After(now) {
+			t = time.Date(now.Year(), now.Month(), now.Day()+1, hour, minute, 0, 0, loc)
+		}
+		return t, nil
+	}
+
+	hour, minute := DefaultHour, 0
+	if len(rest) > 0 {
+		hour, minute, err = parseClock(strings.Join(rest, " "))
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, loc), nil
+}
+
+// parseDay returns the day named by the leading tokens and how many tokens it used.
+// A zero count means no day was named.
+func parseDay(tokens []string, now time.Time) (time.Time, int, error) {
+	switch tokens[0] {
+	case "today":
+		return now, 1, nil
+	case "tomorrow":
+		return now.AddDate(0, 0, 1), 1, nil
+	case "next":
+		if len(tokens) < 2 {
+			return time.Time{}, 0, ErrAmbiguous
+		}
+		if tokens[1] == "week" {
+			return now.AddDate(0, 0, 7), 2, nil
+		}
+		if weekday, ok := weekdays[tokens[1]]; ok {
+			return nextWeekday(now, weekday), 2, nil
+		}
+		return time.Time{}, 0, ErrUnrecognized
+	}
+
+	if weekday, ok := weekdays[tokens[0]]; ok {
+		return nextWeekday(now, weekday), 1, nil
+	}
+
+	return time.Time{}, 0, nil
+}
+
+// nextWeekday returns the next occurrence of weekday strictly after now's date
+func nextWeekday(now time.Time, weekday time.Weekday) time.Time {
+	days := (int(weekday) - int(now.Weekday()) + 7) % 7
+	if days == 0 {
+		days = 7
+	}
+	return now.AddDate(0, 0, days)
+}
+
+// parseClock parses "9am", "9:30 pm", "17:00", "noon" and "midnight"
+func parseClock(expr string) (int, int, error) {
+	switch expr {
+	case "noon":
+		return 12, 0, nil
+	case "midnight":
+		return 0, 0, nil
+	}
+
+	m := clockPattern.FindStringSubmatch(expr)
+	if m == nil {
+		return 0, 0, ErrUnrecognized
+	}
+
+	hour, _ := strconv.Atoi(m[1])
+	minute := 0
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+	if minute > 59 {
+		return 0, 0, ErrInvalidTime
+	}
+
+	switch m[3] {
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return 0, 0, ErrInvalidTime
+		}
+		if hour == 12 {
+			hour = 0
+		}
+		if m[3] == "pm" {
+			hour += 12
+		}
+	default:
+		// Without am/pm only 24-hour "HH:MM" is accepted; a bare "9" could mean either
+		if m[2] == "" {
+			return 0, 0, ErrAmbiguous
+		}
+		if hour > 23 {
+			return 0, 0, ErrInvalidTime
+		}
+	}
+
+	return hour, minute, nil
+}
+
+func ensureFuture(t, now time.Time, input string) (time.Time, error) {
+	if !t.After(now) {
+		return time.Time{}, fmt.Errorf("%w: %q", ErrInPast, input)
+	}
+	return t, nil
+}
@@ -0,0 +1,100 @@
+package timeparse
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Wednesday, 14 October 2026 10:15 UTC
+var testNow = time.Date(2026, time.October, 14, 10, 15, 0, 0, time.UTC)
+
+func TestParseWhen(t *testing.T) {
+	require.Equal(t, time.Wednesday, testNow.Weekday())
+
+	tests := []struct {
+		name     string
+		input    string
+		expected time.Time
+	}{
+		{"go duration", "10m", testNow.Add(10 * time.Minute)},
+		{"compound go duration", "1h30m", testNow.Add(90 * time.Minute)},
+		{"days", "1d", testNow.Add(24 * time.Hour)},
+		{"week", "1w", testNow.Add(7 * 24 * time.Hour)},
+		{"spelled out unit", "2 hours", testNow.Add(2 * time.Hour)},
+		{"in hours", "in 2 hours", testNow.Add(2 * time.Hour)},
+		{"in an hour", "in an hour", testNow.Add(time.Hour)},
+		{"in minutes", "In 30  Minutes", testNow.Add(30 * time.Minute)},
+		{"tomorrow default hour", "tomorrow", time.Date(2026, 10, 15, DefaultHour, 0, 0, 0, time.UTC)},
+		{"tomorrow 9am", "tomorrow 9am", time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)},
+		{"tomorrow at 5:30 pm", "tomorrow at 5:30 pm", time.Date(2026, 10, 15, 17, 30, 0, 0, time.UTC)},
+		{"today 24h clock", "today 18:45", time.Date(2026, 10, 14, 18, 45, 0, 0, time.UTC)},
+		{"next monday", "next monday", time.Date(2026, 10, 19, DefaultHour, 0, 0, 0, time.UTC)},
+		{"weekday with time", "fri 8am", time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)},
+		{"same weekday is next week", "wednesday", time.Date(2026, 10, 21, DefaultHour, 0, 0, 0, time.UTC)},
+		{"next week", "next week", time.Date(2026, 10, 21, DefaultHour, 0, 0, 0, time.UTC)},
+		{"time later today", "noon", time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)},
+		{"time already passed rolls to tomorrow", "9am", time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)},
+		{"midnight", "midnight", time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)},
+		{"12am", "tomorrow 12am", time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)},
+		{"12pm", "tomorrow 12pm", time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)},
+		{"rfc3339", "2026-10-20T08:00:00Z", time.Date(2026, 10, 20, 8, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseWhen(tt.input, testNow, time.UTC)
+			require.NoError(t, err)
+			assert.True(t, tt.expected.Equal(got), "expected %s, got %s", tt.expected, got)
+		})
+	}
+}
+
+func TestParseWhen_Location(t *testing.T) {
+	bangkok := time.FixedZone("ICT", 7*60*60)
+
+	// 10:15 UTC is 17:15 in Bangkok, so "9am" is tomorrow morning local time
+	got, err := ParseWhen("9am", testNow, bangkok)
+	require.NoError(t, err)
+	assert.True(t, time.Date(2026, 10, 15, 9, 0, 0, 0, bangkok).Equal(got))
+
+	got, err = ParseWhen("tomorrow 9am", testNow, nil)
+	require.NoError(t, err)
+	assert.Equal(t, time.UTC, got.Location())
+}
+
+func TestParseWhen_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expectedErr error
+	}{
+		{"empty", "", ErrEmptyInput},
+		{"whitespace", "   ", ErrEmptyInput},
+		{"bare number", "5", ErrAmbiguous},
+		{"in bare number", "in 5", ErrAmbiguous},
+		{"bare hour", "tomorrow 9", ErrAmbiguous},
+		{"next without day", "next", ErrAmbiguous},
+		{"unknown unit", "3 fortnights", ErrUnrecognized},
+		{"gibberish", "whenever", ErrUnrecognized},
+		{"next unknown", "next year", ErrUnrecognized},
+		{"negative duration", "-10m", ErrUnrecognized},
+		{"zero duration", "0m", ErrUnrecognized},
+		{"dangling at", "tomorrow at", ErrUnrecognized},
+		{"invalid 12h hour", "tomorrow 13pm", ErrInvalidTime},
+		{"invalid 24h hour", "tomorrow 25:00", ErrInvalidTime},
+		{"invalid minute", "tomorrow 9:75am", ErrInvalidTime},
+		{"earlier today", "today 8am", ErrInPast},
+		{"past timestamp", "2020-01-01T00:00:00Z", ErrInPast},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseWhen(tt.input, testNow, time.UTC)
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.True(t, got.IsZero())
+		})
+	}
+}